@@ -31,6 +31,7 @@ const (
 	AssessTypeObjectTypeCompatible = "OBJECT_TYPE_COMPATIBLE"
 	AssessTypeObjectTypeCheck      = "OBJECT_TYPE_CHECK"
 	AssessTypeObjectTypeRelated    = "OBJECT_TYPE_RELATED"
+	AssessTypeSQLWorkload          = "SQL_WORKLOAD"
 )
 
 // Assess Name
@@ -47,6 +48,8 @@ const (
 	AssessNamePartitionTypeCompatible      = "PARTITION_TYPE_COMPATIBLE"
 	AssessNameSubPartitionTypeCompatible   = "SUBPARTITION_TYPE_COMPATIBLE"
 	AssessNameTemporaryTableTypeCompatible = "TEMPORARY_TABLE_TYPE_COMPATIBLE"
+	AssessNameCharsetCompatible            = "CHARSET_COMPATIBLE"
+	AssessNameFeatureCompatible            = "FEATURE_COMPATIBLE"
 
 	AssessNamePartitionTableCountsCheck = "PARTITION_TABLE_COUNTS_CHECK"
 	AssessNameTableColumnCountsCheck    = "TABLE_COLUMN_COUNTS_CHECK"
@@ -60,13 +63,178 @@ const (
 	AssessNameIndexNameLengthCheck    = "INDEX_NAME_LENGTH_CHECK"
 	AssessNameViewNameLengthCheck     = "VIEW_NAME_LENGTH_CHECK"
 	AssessNameSequenceNameLengthCheck = "SEQUENCE_NAME_LENGTH_CHECK"
+	AssessNameIdentifierCheck         = "IDENTIFIER_CHECK"
 
 	AssessNameSchemaDataSizeRelated             = "SCHEMA_DATA_SIZE_RELATED"
 	AssessNameSchemaActiveSessionRelated        = "SCHEMA_ACTIVE_SESSION_RELATED"
 	AssessNameSchemaTableRowsTopRelated         = "SCHEMA_TABLE_ROWS_TOP_RELATED"
 	AssessNameSchemaCodeObjectRelated           = "SCHEMA_CODE_OBJECT_RELATED"
+	AssessNameSchemaCodeInventoryRelated        = "SCHEMA_CODE_INVENTORY_RELATED"
 	AssessNameSchemaSynonymObjectRelated        = "SCHEMA_SYNONYM_OBJECT_RELATED"
 	AssessNameSchemaMaterializedViewRelated     = "SCHEMA_MATERIALIZED_VIEW_OBJECT_RELATED"
 	AssessNameSchemaTableAvgRowLengthTopRelated = "SCHEMA_TABLE_AVG_ROW_LENGTH_TOP_RELATED"
 	AssessNameSchemaTableNumberTypeEqual0       = "SCHEMA_TABLE_NUMBER_TYPE_EQUAL0"
+
+	AssessNameSQLWorkloadCompatible = "SQL_WORKLOAD_COMPATIBLE"
+)
+
+// Assess SQL Workload Source
+const (
+	AssessSQLSourceSQLArea = "SQLAREA"
+	AssessSQLSourceAWR     = "AWR"
+	AssessSQLSourceNone    = "NONE"
+
+	AssessSQLTopN = 100
+	// 报告 SQL 文本最大输出长度
+	AssessSQLTextMaxLength = 1024
+)
+
+// Assess Identifier Check
+// Oracle 12.2 以下版本标识符最大长度 30 bytes，12.2 及以上版本 128 bytes，按 30 bytes 评估
+const (
+	AssessOracleIdentifierMaxLength = 30
+
+	AssessIdentifierCheckLengthOver    = "LENGTH_OVER_30"
+	AssessIdentifierCheckReservedWords = "ORACLE_RESERVED_WORD"
+)
+
+// Oracle SQL 保留字，https://docs.oracle.com/en/database/oracle/oracle-database/19/sqlrf/Oracle-SQL-Reserved-Words.html
+var AssessOracleReservedWords = []string{
+	"ACCESS", "ADD", "ALL", "ALTER", "AND", "ANY", "AS", "ASC", "AUDIT", "BETWEEN", "BY", "CHAR", "CHECK", "CLUSTER",
+	"COLUMN", "COMMENT", "COMPRESS", "CONNECT", "CREATE", "CURRENT", "DATE", "DECIMAL", "DEFAULT", "DELETE", "DESC",
+	"DISTINCT", "DROP", "ELSE", "EXCLUSIVE", "EXISTS", "FILE", "FLOAT", "FOR", "FROM", "GRANT", "GROUP", "HAVING",
+	"IDENTIFIED", "IMMEDIATE", "IN", "INCREMENT", "INDEX", "INITIAL", "INSERT", "INTEGER", "INTERSECT", "INTO", "IS",
+	"LEVEL", "LIKE", "LOCK", "LONG", "MAXEXTENTS", "MINUS", "MLSLABEL", "MODE", "MODIFY", "NOAUDIT", "NOCOMPRESS", "NOT",
+	"NOWAIT", "NULL", "NUMBER", "OF", "OFFLINE", "ON", "ONLINE", "OPTION", "OR", "ORDER", "PCTFREE", "PRIOR", "PUBLIC",
+	"RAW", "RENAME", "RESOURCE", "REVOKE", "ROW", "ROWID", "ROWNUM", "ROWS", "SELECT", "SESSION", "SET", "SHARE", "SIZE",
+	"SMALLINT", "START", "SUCCESSFUL", "SYNONYM", "SYSDATE", "TABLE", "THEN", "TO", "TRIGGER", "UID", "UNION", "UNIQUE",
+	"UPDATE", "USER", "VALIDATE", "VALUES", "VARCHAR", "VARCHAR2", "VIEW", "WHENEVER", "WHERE", "WITH",
+}
+
+// Assess Report Format
+const (
+	AssessReportFormatHTML     = "HTML"
+	AssessReportFormatJSON     = "JSON"
+	AssessReportFormatMarkdown = "MARKDOWN"
+)
+
+// 评估报告输出格式文件后缀
+var AssessReportFormatFileSuffix = map[string]string{
+	AssessReportFormatHTML:     "html",
+	AssessReportFormatJSON:     "json",
+	AssessReportFormatMarkdown: "md",
+}
+
+// Assess Cost Type
+// 迁移工作量评估模型，权重单位：人天
+const (
+	// 不兼容对象（表类型、约束、索引、视图、对象类型、临时表），每个对象权重
+	AssessCostTypeObjectType = "OBJECT_TYPE"
+	// 非等价字段类型，每个字段权重
+	AssessCostTypeDatatype = "DATATYPE"
+	// PL/SQL 代码对象，每行代码权重
+	AssessCostTypeCodeLine = "CODE_LINE"
+	// 不兼容分区以及子分区类型，每张分区表权重
+	AssessCostTypePartitionType    = "PARTITION_TYPE"
+	AssessCostTypeSubPartitionType = "SUBPARTITION_TYPE"
+	// 迁移难度等级，权重代表对应等级工作量上限
+	AssessCostTypeDifficulty = "DIFFICULTY"
+
+	// 未单独配置权重的默认规则名
+	AssessCostNameDefault = "*"
 )
+
+// Assess Difficulty
+const (
+	AssessDifficultyLow     = "LOW"
+	AssessDifficultyMedium  = "MEDIUM"
+	AssessDifficultyHigh    = "HIGH"
+	AssessDifficultyExtreme = "EXTREME"
+)
+
+// 迁移难度等级由低到高
+var AssessDifficultyLevels = []string{AssessDifficultyLow, AssessDifficultyMedium, AssessDifficultyHigh}
+
+// 迁移工作量评估内置默认权重 O2M/O2T
+var BuildInAssessCostO2MTDefaultWeight = map[string]map[string]float64{
+	AssessCostTypeObjectType: {
+		AssessCostNameDefault: 0.5,
+		"PACKAGE":             2,
+		"PACKAGE BODY":        1,
+		"TYPE":                1,
+		"TYPE BODY":           1,
+		"TRIGGER":             0.5,
+		"DATABASE LINK":       1,
+		"MATERIALIZED VIEW":   1,
+		"JAVA CLASS":          2,
+		"JAVA SOURCE":         2,
+		"QUEUE":               2,
+		"SYNONYM":             0.05,
+		"SEQUENCE":            0.05,
+	},
+	AssessCostTypeDatatype: {
+		AssessCostNameDefault: 0.02,
+		"BFILE":               0.1,
+		"LONG":                0.05,
+		"LONG RAW":            0.05,
+		"XMLTYPE":             0.1,
+		"SDO_GEOMETRY":        0.1,
+	},
+	AssessCostTypeCodeLine: {
+		AssessCostNameDefault: 0.002,
+		"PACKAGE BODY":        0.003,
+		"TYPE BODY":           0.003,
+		"TRIGGER":             0.003,
+	},
+	AssessCostTypePartitionType: {
+		AssessCostNameDefault: 0.5,
+	},
+	AssessCostTypeSubPartitionType: {
+		AssessCostNameDefault: 0.5,
+	},
+	AssessCostTypeDifficulty: {
+		AssessDifficultyLow:    20,
+		AssessDifficultyMedium: 100,
+		AssessDifficultyHigh:   500,
+	},
+}
+
+// 迁移工作量评估内置默认权重 M2O/T2O
+var BuildInAssessCostMT2ODefaultWeight = map[string]map[string]float64{
+	AssessCostTypeObjectType: {
+		AssessCostNameDefault:         0.5,
+		"PROCEDURE":                   1,
+		"FUNCTION":                    0.5,
+		"TRIGGER":                     0.5,
+		"EVENT":                       1,
+		"FULLTEXT":                    1,
+		"SPATIAL":                     1,
+		"ENUM":                        0.05,
+		"SET":                         0.1,
+		"UNSIGNED":                    0.01,
+		"AUTO_INCREMENT":              0.05,
+		"VIRTUAL GENERATED":           0.05,
+		"STORED GENERATED":            0.1,
+		"ON UPDATE CURRENT_TIMESTAMP": 0.1,
+	},
+	AssessCostTypeDatatype: {
+		AssessCostNameDefault: 0.02,
+		"JSON":                0.1,
+		"GEOMETRY":            0.1,
+	},
+	AssessCostTypeCodeLine: {
+		AssessCostNameDefault: 0.002,
+		"TRIGGER":             0.003,
+	},
+	AssessCostTypePartitionType: {
+		AssessCostNameDefault: 0.5,
+	},
+	AssessCostTypeSubPartitionType: {
+		AssessCostNameDefault: 0.5,
+	},
+	AssessCostTypeDifficulty: {
+		AssessDifficultyLow:    20,
+		AssessDifficultyMedium: 100,
+		AssessDifficultyHigh:   500,
+	},
+}
@@ -124,6 +124,11 @@ const (
 	// INTERVAL DAY(p) TO SECOND(s) 组合过多，代码内匹配处理
 	// p and s value max 9 -> INTERVAL DAY(9) TO SECOND(9)
 	BuildInOracleDatatypeIntervalDay = "INTERVAL DAY"
+
+	// SDO_GEOMETRY 空间类型，数据迁移以 WKT 文本输出
+	BuildInOracleDatatypeSdoGeometry = "SDO_GEOMETRY"
+	// 自定义对象类型、VARRAY 以及嵌套表数据类型名不固定，统一规则名，数据迁移转换 JSON
+	BuildInOracleDatatypeObject = "OBJECT"
 )
 
 // Oracle 数据类型名映射规则 O2M
@@ -200,6 +205,8 @@ var BuildInOracleO2MDatatypeNameMap = map[string]string{
 	BuildInOracleDatatypeTimestampWithLocalTimeZone8: "DATETIME",
 	BuildInOracleDatatypeTimestampWithLocalTimeZone9: "DATETIME",
 	BuildInOracleDatatypeIntervalDay:                 "VARCHAR",
+	BuildInOracleDatatypeSdoGeometry:                 "GEOMETRY",
+	BuildInOracleDatatypeObject:                      "JSON",
 }
 
 // MySQL 数据类型名
@@ -366,3 +373,73 @@ const (
 	BuildInOracleTemporaryTypeSession     = "SYS$SESSION"
 	BuildInOracleTemporaryTypeTransaction = "SYS$TRANSACTION"
 )
+
+// MySQL 字符集
+const (
+	BuildInMySQLCharacterSetUTF8MB4 = "UTF8MB4"
+	BuildInMySQLCharacterSetUTF8    = "UTF8"
+	BuildInMySQLCharacterSetGBK     = "GBK"
+	BuildInMySQLCharacterSetGB18030 = "GB18030"
+	BuildInMySQLCharacterSetBIG5    = "BIG5"
+	BuildInMySQLCharacterSetLatin1  = "LATIN1"
+)
+
+// MySQL 对象类型名字，表类型基于存储引擎
+const (
+	BuildInMySQLTableTypeInnoDB    = "INNODB"
+	BuildInMySQLTableTypeMyISAM    = "MYISAM"
+	BuildInMySQLTableTypeMemory    = "MEMORY"
+	BuildInMySQLTableTypeArchive   = "ARCHIVE"
+	BuildInMySQLTableTypeCSV       = "CSV"
+	BuildInMySQLTableTypeFederated = "FEDERATED"
+	BuildInMySQLTableTypeBlackhole = "BLACKHOLE"
+	BuildInMySQLTableTypeMerge     = "MRG_MYISAM"
+
+	BuildInMySQLConstraintTypePrimary = "PRIMARY KEY"
+	BuildInMySQLConstraintTypeUnique  = "UNIQUE"
+	BuildInMySQLConstraintTypeForeign = "FOREIGN KEY"
+	BuildInMySQLConstraintTypeCheck   = "CHECK"
+
+	BuildInMySQLIndexTypeBtree    = "BTREE"
+	BuildInMySQLIndexTypeHash     = "HASH"
+	BuildInMySQLIndexTypeFulltext = "FULLTEXT"
+	BuildInMySQLIndexTypeSpatial  = "SPATIAL"
+
+	BuildInMySQLViewTypeView = "VIEW"
+
+	BuildInMySQLCodeTypeProcedure = "PROCEDURE"
+	BuildInMySQLCodeTypeFunction  = "FUNCTION"
+	BuildInMySQLCodeTypeTrigger   = "TRIGGER"
+	BuildInMySQLCodeTypeEvent     = "EVENT"
+
+	BuildInMySQLPartitionTypeRange        = "RANGE"
+	BuildInMySQLPartitionTypeList         = "LIST"
+	BuildInMySQLPartitionTypeHash         = "HASH"
+	BuildInMySQLPartitionTypeKey          = "KEY"
+	BuildInMySQLPartitionTypeRangeColumns = "RANGE COLUMNS"
+	BuildInMySQLPartitionTypeListColumns  = "LIST COLUMNS"
+	BuildInMySQLPartitionTypeLinearHash   = "LINEAR HASH"
+	BuildInMySQLPartitionTypeLinearKey    = "LINEAR KEY"
+)
+
+// MySQL 特性，Oracle 无等价实现
+const (
+	BuildInMySQLFeatureEnum             = "ENUM"
+	BuildInMySQLFeatureSet              = "SET"
+	BuildInMySQLFeatureUnsigned         = "UNSIGNED"
+	BuildInMySQLFeatureAutoIncrement    = "AUTO_INCREMENT"
+	BuildInMySQLFeatureVirtualGenerated = "VIRTUAL GENERATED"
+	BuildInMySQLFeatureStoredGenerated  = "STORED GENERATED"
+	BuildInMySQLFeatureOnUpdate         = "ON UPDATE CURRENT_TIMESTAMP"
+)
+
+// MySQL 特性评估顺序
+var BuildInMySQLFeatures = []string{
+	BuildInMySQLFeatureEnum,
+	BuildInMySQLFeatureSet,
+	BuildInMySQLFeatureUnsigned,
+	BuildInMySQLFeatureAutoIncrement,
+	BuildInMySQLFeatureVirtualGenerated,
+	BuildInMySQLFeatureStoredGenerated,
+	BuildInMySQLFeatureOnUpdate,
+}
@@ -19,6 +19,12 @@ package common
 // 要求 oracle 11g 及以上
 const RequireOracleDBVersion = "11"
 
+// Oracle 12c 及以上 logminer 需要 LOGMINING 权限
+const RequireOracleLogminingVersion = "12"
+
+// 下游 MySQL 版本要求，低于要求版本 precheck 告警
+const RequireMySQLDBVersion = "5.7"
+
 // Oracle Redo 同步操作类型
 const (
 	MigrateOperationUpdate   = "UPDATE"
@@ -36,3 +42,56 @@ const (
 // 当值 == 0 启用 filterOracleIncrRecord 大于或者等于逻辑
 // 当值 == 1 启用 filterOracleIncrRecord 大于逻辑，避免已被消费得日志一直被重复消费
 var MigrateCurrentResetFlag = 0
+
+// 二进制字段（BLOB/BFILE/RAW/LONG RAW）csv 编码格式，full 以及 all 统一按十六进制 0x 写入
+const (
+	BinaryEncodingHex    = "HEX"
+	BinaryEncodingBase64 = "BASE64"
+)
+
+// csv 模式数据文件格式，parquet 字段统一按 UTF8 字符串写入，NULL 写入 parquet null
+const (
+	FileFormatCSV     = "CSV"
+	FileFormatParquet = "PARQUET"
+)
+
+// LOB 字段分片读取大小，适用于 full、csv 以及 all 全量
+const LOBReadPieceSize = 1 << 20
+
+// Oracle ADG 备库数据抽取，等待备库应用 SCN 追上主库 SCN 默认超时时间以及检查间隔，单位秒
+const (
+	StandbyApplyWaitTimeout  = 600
+	StandbyApplyWaitInterval = 5
+)
+
+// Oracle ADG 备库角色以及打开模式
+const (
+	StandbyDatabaseRole = "PHYSICAL STANDBY"
+	StandbyOpenModeRead = "READ ONLY"
+)
+
+// 数据迁移字段转换（脱敏）规则类型，适用于 full、csv 以及 all 增量
+const (
+	// sha256(盐值 + 原值) 十六进制
+	TransformRuleHash = "HASH"
+	// 保留前后指定字符数，其余字符掩码
+	TransformRuleMask = "MASK"
+	// 固定值
+	TransformRuleFixed = "FIXED"
+	// 保留格式替换，数字替换为数字、字母替换为同大小写字母，其他字符不变
+	TransformRuleFPE = "FPE"
+	// 置空 NULL
+	TransformRuleNullify = "NULLIFY"
+	// 简单表达式模板
+	TransformRuleExpr = "EXPR"
+)
+
+// 数据迁移以及校验字段映射规则类型，适用于 reverse、full、csv、all 以及 compare
+const (
+	// 字段不迁移
+	MappingRuleExclude = "EXCLUDE"
+	// 字段重命名
+	MappingRuleRename = "RENAME"
+	// 源端 SQL 表达式计算字段值
+	MappingRuleExpr = "EXPR"
+)
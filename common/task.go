@@ -30,16 +30,75 @@ const ChannelBufferSize = 1024
 
 // 任务模式
 const (
-	TaskModePrepare = "PREPARE"
-	TaskModeAssess  = "ASSESS"
-	TaskModeReverse = "REVERSE"
-	TaskModeCheck   = "CHECK"
-	TaskModeCompare = "COMPARE"
-	TaskModeCSV     = "CSV"
-	TaskModeFull    = "FULL"
-	TaskModeAll     = "ALL"
+	TaskModePrepare  = "PREPARE"
+	TaskModeAssess   = "ASSESS"
+	TaskModeReverse  = "REVERSE"
+	TaskModeCheck    = "CHECK"
+	TaskModeCompare  = "COMPARE"
+	TaskModeCSV      = "CSV"
+	TaskModeFull     = "FULL"
+	TaskModeAll      = "ALL"
+	TaskModeVerify   = "VERIFY"
+	TaskModeRepair   = "REPAIR"
+	TaskModeList     = "LIST"
+	TaskModePrecheck = "PRECHECK"
 )
 
+// 任务修复动作 -> 适用于 repair 模式
+const (
+	// 列出失败表、失败 chunk 以及错误详情
+	RepairActionList = "LIST"
+	// 重置失败 chunk（指定 chunk、表或者整个任务），断点续传
+	RepairActionReset = "RESET"
+	// 清理目标表数据以及表元数据，全量重跑
+	RepairActionTruncate = "TRUNCATE"
+	// 人工确认表数据已处理完成，标记 SUCCESS
+	RepairActionDone = "DONE"
+)
+
+// 预检查结果状态 -> 适用于 precheck 模式
+const (
+	PrecheckStatusPass = "PASS"
+	PrecheckStatusWarn = "WARN"
+	PrecheckStatusFail = "FAIL"
+)
+
+// 执行计划表处理方式 -> 适用于 full/all 模式 dry-run
+const (
+	// 初始化 chunk 并全量迁移
+	PlanActionFull = "FULL"
+	// 断点续传未完成 chunk
+	PlanActionResume = "RESUME"
+	// 全量已完成，跳过
+	PlanActionSkip = "SKIP"
+	// 存在失败记录，需 repair 模式处理
+	PlanActionFailed = "FAILED"
+	// 基于增量元数据 SCN 增量同步（all 模式）
+	PlanActionIncr = "INCR"
+)
+
+// 表对象类型 -> 适用于表选择 source-include-object-type/source-exclude-object-type
+const (
+	// 分区表
+	TableObjectTypePartitioned = "PARTITIONED"
+	// 临时表（ORACLE ONLY）
+	TableObjectTypeTemporary = "TEMPORARY"
+	// 索引组织表（ORACLE ONLY）
+	TableObjectTypeIOT = "IOT"
+	// 存在 LOB 字段（MySQL/TiDB BLOB/TEXT/JSON）
+	TableObjectTypeHasLOB = "HAS_LOB"
+	// 不存在主键
+	TableObjectTypeNoPK = "NO_PK"
+)
+
+var TableObjectTypes = []string{
+	TableObjectTypePartitioned,
+	TableObjectTypeTemporary,
+	TableObjectTypeIOT,
+	TableObjectTypeHasLOB,
+	TableObjectTypeNoPK,
+}
+
 // 任务状态
 const (
 	TaskStatusWaiting = "WAITING"
@@ -48,6 +107,16 @@ const (
 	TaskStatusFailed  = "FAILED"
 )
 
+// 任务错误分类 -> 适用于 full/all 模式 chunk 写入
+const (
+	// 瞬时错误（死锁、锁等待超时、TiDB schema 变更、连接断开等），按指数退避重试，重新运行自动重置失败 chunk
+	ErrorClassRetryable = "RETRYABLE"
+	// 数据错误（非空约束、数据超长、类型不匹配、字符集转换等），不重试
+	ErrorClassData = "DATA"
+	// 其他错误，不重试
+	ErrorClassFatal = "FATAL"
+)
+
 // 任务初始值
 const (
 	// 值 0 代表源端表未进行初始化 -> 适用于 full/csv/all 模式
@@ -56,6 +125,16 @@ const (
 	TaskTableDefaultSplitChunkNums = -1
 )
 
+// 表 chunk 切分策略
+const (
+	// 基于 DBMS_PARALLEL_EXECUTE ROWID 切分（默认）
+	ChunkStrategyRowID = "ROWID"
+	// 基于主键或者唯一索引字段 NTILE 切分，不依赖 DBMS_PARALLEL_EXECUTE
+	ChunkStrategyPK = "PK"
+	// 基于 TiDB 表 Region 边界切分（源端 TiDB 默认）
+	ChunkStrategyRegion = "REGION"
+)
+
 // 任务 DB 类型
 const (
 	DatabaseTypeOracle = "ORACLE"
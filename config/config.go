@@ -22,34 +22,74 @@ import (
 	"github.com/BurntSushi/toml"
 	"github.com/wentaojin/transferdb/common"
 	"os"
+	"strings"
 )
 
 // 程序配置文件
 type Config struct {
-	*flag.FlagSet `json:"-"`
-	AppConfig     AppConfig     `toml:"app" json:"app"`
-	ReverseConfig ReverseConfig `toml:"reverse" json:"reverse"`
-	CheckConfig   CheckConfig   `toml:"check" json:"check"`
-	FullConfig    FullConfig    `toml:"full" json:"full"`
-	CSVConfig     CSVConfig     `toml:"csv" json:"csv"`
-	AllConfig     AllConfig     `toml:"all" json:"all"`
-	SchemaConfig  SchemaConfig  `toml:"schema-config" json:"schema-config"`
-	OracleConfig  OracleConfig  `toml:"oracle" json:"oracle"`
-	MySQLConfig   MySQLConfig   `toml:"mysql" json:"mysql"`
-	MetaConfig    MetaConfig    `toml:"meta" json:"meta"`
-	LogConfig     LogConfig     `toml:"log" json:"log"`
-	DiffConfig    DiffConfig    `toml:"compare" json:"compare"`
-	ConfigFile    string        `json:"config-file"`
-	PrintVersion  bool
-	TaskMode      string `json:"task-mode"`
-	DBTypeS       string `json:"db-type-s"`
-	DBTypeT       string `json:"db-type-t"`
+	*flag.FlagSet  `json:"-"`
+	AppConfig      AppConfig      `toml:"app" json:"app"`
+	ReverseConfig  ReverseConfig  `toml:"reverse" json:"reverse"`
+	CheckConfig    CheckConfig    `toml:"check" json:"check"`
+	AssessConfig   AssessConfig   `toml:"assess" json:"assess"`
+	FullConfig     FullConfig     `toml:"full" json:"full"`
+	CSVConfig      CSVConfig      `toml:"csv" json:"csv"`
+	AllConfig      AllConfig      `toml:"all" json:"all"`
+	SchemaConfig   SchemaConfig   `toml:"schema-config" json:"schema-config"`
+	OracleConfig   OracleConfig   `toml:"oracle" json:"oracle"`
+	StandbyConfig  StandbyConfig  `toml:"oracle-standby" json:"oracle-standby"`
+	MySQLConfig    MySQLConfig    `toml:"mysql" json:"mysql"`
+	MetaConfig     MetaConfig     `toml:"meta" json:"meta"`
+	LogConfig      LogConfig      `toml:"log" json:"log"`
+	DiffConfig     DiffConfig     `toml:"compare" json:"compare"`
+	StorageConfig  StorageConfig  `toml:"storage" json:"storage"`
+	ThrottleConfig ThrottleConfig `toml:"throttle" json:"throttle"`
+	ConfigFile     string         `json:"config-file"`
+	PrintVersion   bool
+	EncryptSecret  bool           `json:"-"`
+	TaskMode       string         `json:"task-mode"`
+	DBTypeS        string         `json:"db-type-s"`
+	DBTypeT        string         `json:"db-type-t"`
+	DryRun         bool           `toml:"-" json:"dry-run"`
+	RepairConfig   RepairConfig   `toml:"-" json:"repair"`
+	BaselineConfig BaselineConfig `toml:"-" json:"baseline"`
+	PrecheckConfig PrecheckConfig `toml:"-" json:"precheck"`
+	// 配置文件未识别配置项，用于 precheck 配置校验
+	UndecodedKeys []string `toml:"-" json:"-"`
+}
+
+// RepairConfig repair 模式命令行参数
+type RepairConfig struct {
+	TaskMode    string `json:"repair-mode"`
+	Action      string `json:"repair-action"`
+	TableNameS  string `json:"repair-table"`
+	ChunkDetail string `json:"repair-chunk"`
+}
+
+// PrecheckConfig precheck 模式命令行参数
+type PrecheckConfig struct {
+	TaskMode string `json:"precheck-mode"`
+}
+
+// BaselineConfig assess 模式评估结果对比命令行参数
+type BaselineConfig struct {
+	BaseRunID   string `json:"baseline-run"`
+	TargetRunID string `json:"target-run"`
 }
 
 type AppConfig struct {
 	InsertBatchSize  int    `toml:"insert-batch-size" json:"insert-batch-size"`
+	InsertBatchBytes int    `toml:"insert-batch-bytes" json:"insert-batch-bytes"`
+	MaxLOBSize       int64  `toml:"max-lob-size" json:"max-lob-size"`
 	SlowlogThreshold int    `toml:"slowlog-threshold" json:"slowlog-threshold"`
 	PprofPort        string `toml:"pprof-port" json:"pprof-port"`
+	SecretKeyFile    string `toml:"secret-key-file" json:"secret-key-file"`
+}
+
+type AssessConfig struct {
+	ReportFormat []string `toml:"report-format" json:"report-format"`
+	SQLSource    string   `toml:"sql-source" json:"sql-source"`
+	SQLTopN      int      `toml:"sql-top-n" json:"sql-top-n"`
 }
 
 type DiffConfig struct {
@@ -81,6 +121,8 @@ type CSVConfig struct {
 	Charset          string `toml:"charset" json:"charset"`
 	Delimiter        string `toml:"delimiter" json:"delimiter"`
 	EscapeBackslash  bool   `toml:"escape-backslash" json:"escape-backslash"`
+	BlobEncoding     string `toml:"blob-encoding" json:"blob-encoding"`
+	FileFormat       string `toml:"file-format" json:"file-format"`
 	Rows             int    `toml:"rows" json:"rows"`
 	OutputDir        string `toml:"output-dir" json:"output-dir"`
 	TaskThreads      int    `toml:"task-threads" json:"task-threads"`
@@ -91,29 +133,73 @@ type CSVConfig struct {
 	SQLHint          string `toml:"sql-hint" json:"sql-hint"`
 }
 
+type StorageConfig struct {
+	Endpoint       string `toml:"endpoint" json:"endpoint"`
+	Region         string `toml:"region" json:"region"`
+	AccessKey      string `toml:"access-key" json:"access-key"`
+	SecretKey      string `toml:"secret-key" json:"secret-key"`
+	SessionToken   string `toml:"session-token" json:"session-token"`
+	ForcePathStyle bool   `toml:"force-path-style" json:"force-path-style"`
+	PartSize       int64  `toml:"part-size" json:"part-size"`
+	Concurrency    int    `toml:"concurrency" json:"concurrency"`
+}
+
+type ThrottleConfig struct {
+	RowsPerSecond       int64              `toml:"rows-per-second" json:"rows-per-second"`
+	BytesPerSecond      int64              `toml:"bytes-per-second" json:"bytes-per-second"`
+	TableRowsPerSecond  int64              `toml:"table-rows-per-second" json:"table-rows-per-second"`
+	TableBytesPerSecond int64              `toml:"table-bytes-per-second" json:"table-bytes-per-second"`
+	EnableAdaptive      bool               `toml:"enable-adaptive" json:"enable-adaptive"`
+	SampleInterval      int                `toml:"sample-interval" json:"sample-interval"`
+	MaxActiveSessions   int                `toml:"max-active-sessions" json:"max-active-sessions"`
+	MaxWaitTime         int                `toml:"max-wait-time" json:"max-wait-time"`
+	MinRatio            float64            `toml:"min-ratio" json:"min-ratio"`
+	Schedule            []ThrottleSchedule `toml:"schedule" json:"schedule"`
+}
+
+type ThrottleSchedule struct {
+	Start          string `toml:"start" json:"start"`
+	End            string `toml:"end" json:"end"`
+	RowsPerSecond  int64  `toml:"rows-per-second" json:"rows-per-second"`
+	BytesPerSecond int64  `toml:"bytes-per-second" json:"bytes-per-second"`
+}
+
 type FullConfig struct {
-	ChunkSize        int    `toml:"chunk-size" json:"chunk-size"`
-	TaskThreads      int    `toml:"task-threads" json:"task-threads"`
-	TableThreads     int    `toml:"table-threads" json:"table-threads"`
-	SQLThreads       int    `toml:"sql-threads" json:"sql-threads"`
-	ApplyThreads     int    `toml:"apply-threads" json:"apply-threads"`
-	EnableCheckpoint bool   `toml:"enable-checkpoint" json:"enable-checkpoint"`
-	ConsistentRead   bool   `toml:"consistent-read" json:"consistent-read"`
-	SQLHint          string `toml:"sql-hint" json:"sql-hint"`
+	ChunkSize         int    `toml:"chunk-size" json:"chunk-size"`
+	TaskThreads       int    `toml:"task-threads" json:"task-threads"`
+	TableThreads      int    `toml:"table-threads" json:"table-threads"`
+	SQLThreads        int    `toml:"sql-threads" json:"sql-threads"`
+	ApplyThreads      int    `toml:"apply-threads" json:"apply-threads"`
+	EnableCheckpoint  bool   `toml:"enable-checkpoint" json:"enable-checkpoint"`
+	ConsistentRead    bool   `toml:"consistent-read" json:"consistent-read"`
+	SQLHint           string `toml:"sql-hint" json:"sql-hint"`
+	EnableQuarantine  bool   `toml:"enable-quarantine" json:"enable-quarantine"`
+	MaxQuarantineRows int64  `toml:"max-quarantine-rows" json:"max-quarantine-rows"`
+	RetryTimes        int    `toml:"retry-times" json:"retry-times"`
+	RetryBackoff      int    `toml:"retry-backoff" json:"retry-backoff"`
 }
 
 type AllConfig struct {
-	LogminerQueryTimeout int `toml:"logminer-query-timeout" json:"logminer-query-timeout"`
-	FilterThreads        int `toml:"filter-threads" json:"filter-threads"`
-	ApplyThreads         int `toml:"apply-threads" json:"apply-threads"`
-	WorkerQueue          int `toml:"worker-queue" json:"worker-queue"`
-	WorkerThreads        int `toml:"worker-threads" json:"worker-threads"`
+	LogminerQueryTimeout int    `toml:"logminer-query-timeout" json:"logminer-query-timeout"`
+	FilterThreads        int    `toml:"filter-threads" json:"filter-threads"`
+	ApplyThreads         int    `toml:"apply-threads" json:"apply-threads"`
+	WorkerQueue          int    `toml:"worker-queue" json:"worker-queue"`
+	WorkerThreads        int    `toml:"worker-threads" json:"worker-threads"`
+	IncrSourceDir        string `toml:"incr-source-dir" json:"incr-source-dir"`
 }
 
 type SchemaConfig struct {
-	SourceSchema       string          `toml:"source-schema" json:"source-schema"`
-	SourceIncludeTable []string        `toml:"source-include-table" json:"source-include-table"`
-	SourceExcludeTable []string        `toml:"source-exclude-table" json:"source-exclude-table"`
+	SourceSchema       string   `toml:"source-schema" json:"source-schema"`
+	SourceIncludeTable []string `toml:"source-include-table" json:"source-include-table"`
+	SourceExcludeTable []string `toml:"source-exclude-table" json:"source-exclude-table"`
+	// 表对象类型过滤，支持 PARTITIONED、TEMPORARY、IOT、HAS_LOB、NO_PK
+	SourceIncludeObjectType []string `toml:"source-include-object-type" json:"source-include-object-type"`
+	SourceExcludeObjectType []string `toml:"source-exclude-object-type" json:"source-exclude-object-type"`
+	// 表大小（MB，基于段大小或者 DATA_LENGTH）以及行数（基于统计信息）过滤，0 代表不限制
+	SourceMinTableSize float64         `toml:"source-min-table-size" json:"source-min-table-size"`
+	SourceMaxTableSize float64         `toml:"source-max-table-size" json:"source-max-table-size"`
+	SourceMinTableRows int64           `toml:"source-min-table-rows" json:"source-min-table-rows"`
+	SourceMaxTableRows int64           `toml:"source-max-table-rows" json:"source-max-table-rows"`
 	TargetSchema       string          `toml:"target-schema" json:"target-schema"`
 	CompareConfig      []CompareConfig `toml:"compare-config" json:"compare-config"`
 	MigrateConfig      []MigrateConfig `toml:"migrate-config" json:"migrate-config"`
@@ -126,10 +212,15 @@ type CompareConfig struct {
 }
 
 type MigrateConfig struct {
-	SourceTable string `toml:"source-table" json:"source-table"`
-	EnableSplit bool   `toml:"enable-split" json:"enable-split"`
-	Range       string `toml:"range" json:"range"`
-	SQLHint     string `toml:"sql-hint" json:"sql-hint"`
+	SourceTable   string `toml:"source-table" json:"source-table"`
+	EnableSplit   bool   `toml:"enable-split" json:"enable-split"`
+	Range         string `toml:"range" json:"range"`
+	SQLHint       string `toml:"sql-hint" json:"sql-hint"`
+	ChunkStrategy string `toml:"chunk-strategy" json:"chunk-strategy"`
+	ChunkColumn   string `toml:"chunk-column" json:"chunk-column"`
+	// 表级别限速，覆盖 throttle table-rows-per-second/table-bytes-per-second
+	RowsPerSecond  int64 `toml:"rows-per-second" json:"rows-per-second"`
+	BytesPerSecond int64 `toml:"bytes-per-second" json:"bytes-per-second"`
 }
 
 type OracleConfig struct {
@@ -143,25 +234,60 @@ type OracleConfig struct {
 	LibDir        string   `toml:"lib-dir" json:"lib-dir"`
 	ConnectParams string   `toml:"connect-params" json:"connect-params"`
 	SessionParams []string `toml:"session-params" json:"session-params"`
+	// Oracle Wallet 或者操作系统外部认证，开启后忽略 username/password
+	ExternalAuth bool `toml:"external-auth" json:"external-auth"`
+	// sqlnet.ora、tnsnames.ora 以及 wallet 所在目录（TNS_ADMIN）
+	ConfigDir string `toml:"config-dir" json:"config-dir"`
+	// 连接串（例如 tnsnames.ora 别名），不为空优先于 host、port 以及 service-name
+	ConnectString string `toml:"connect-string" json:"connect-string"`
+}
+
+// StandbyConfig Oracle Active Data Guard 备库，用于 full、csv、all 全量阶段以及 compare 数据抽取
+// logminer 以及 SCN 获取基于 [oracle] 主库，username/password 为空使用主库用户
+type StandbyConfig struct {
+	Enable        bool     `toml:"enable" json:"enable"`
+	Username      string   `toml:"username" json:"username"`
+	Password      string   `toml:"password" json:"password"`
+	Host          string   `toml:"host" json:"host"`
+	Port          int      `toml:"port" json:"port"`
+	ServiceName   string   `toml:"service-name" json:"service-name"`
+	PDBName       string   `toml:"pdb-name" json:"pdb-name"`
+	ConnectParams string   `toml:"connect-params" json:"connect-params"`
+	SessionParams []string `toml:"session-params" json:"session-params"`
+	// 连接串（例如 tnsnames.ora 别名），不为空优先于 host、port 以及 service-name，外部认证以及 config-dir 与主库一致
+	ConnectString string `toml:"connect-string" json:"connect-string"`
+	// 等待备库应用 SCN 追上主库 SCN 超时时间以及检查间隔，单位秒
+	ApplyWaitTimeout  int `toml:"apply-wait-timeout" json:"apply-wait-timeout"`
+	ApplyWaitInterval int `toml:"apply-wait-interval" json:"apply-wait-interval"`
 }
 
 type MySQLConfig struct {
-	Username      string `toml:"username" json:"username"`
-	Password      string `toml:"password" json:"password"`
-	Host          string `toml:"host" json:"host"`
-	Port          int    `toml:"port" json:"port"`
-	Charset       string `toml:"charset" json:"charset"`
-	ConnectParams string `toml:"connect-params" json:"connect-params"`
-	TableOption   string `toml:"table-option" json:"table-option"`
-	Overwrite     bool   `toml:"overwrite" json:"overwrite"`
+	Username      string    `toml:"username" json:"username"`
+	Password      string    `toml:"password" json:"password"`
+	Host          string    `toml:"host" json:"host"`
+	Port          int       `toml:"port" json:"port"`
+	Charset       string    `toml:"charset" json:"charset"`
+	ConnectParams string    `toml:"connect-params" json:"connect-params"`
+	TableOption   string    `toml:"table-option" json:"table-option"`
+	Overwrite     bool      `toml:"overwrite" json:"overwrite"`
+	TLS           TLSConfig `toml:"tls" json:"tls"`
 }
 
 type MetaConfig struct {
-	Username   string `toml:"username" json:"username"`
-	Password   string `toml:"password" json:"password"`
-	Host       string `toml:"host" json:"host"`
-	Port       int    `toml:"port" json:"port"`
-	MetaSchema string `toml:"meta-schema" json:"meta-schema"`
+	Username   string    `toml:"username" json:"username"`
+	Password   string    `toml:"password" json:"password"`
+	Host       string    `toml:"host" json:"host"`
+	Port       int       `toml:"port" json:"port"`
+	MetaSchema string    `toml:"meta-schema" json:"meta-schema"`
+	TLS        TLSConfig `toml:"tls" json:"tls"`
+}
+
+// TLSConfig MySQL/TiDB 以及元数据库 TLS 连接配置
+type TLSConfig struct {
+	SSLCA         string `toml:"ssl-ca" json:"ssl-ca"`
+	SSLCert       string `toml:"ssl-cert" json:"ssl-cert"`
+	SSLKey        string `toml:"ssl-key" json:"ssl-key"`
+	SSLSkipVerify bool   `toml:"ssl-skip-verify" json:"ssl-skip-verify"`
 }
 
 type LogConfig struct {
@@ -183,9 +309,18 @@ func NewConfig() *Config {
 	}
 	fs.BoolVar(&cfg.PrintVersion, "V", false, "print version information and exit")
 	fs.StringVar(&cfg.ConfigFile, "config", "./config.toml", "path to the configuration file")
-	fs.StringVar(&cfg.TaskMode, "mode", "", "specify the program running mode: [prepare assess reverse full csv all check compare]")
+	fs.StringVar(&cfg.TaskMode, "mode", "", "specify the program running mode: [prepare assess reverse full csv verify all check compare repair list precheck]")
 	fs.StringVar(&cfg.DBTypeS, "source", "oracle", "specify the source db type")
 	fs.StringVar(&cfg.DBTypeT, "target", "mysql", "specify the target db type")
+	fs.StringVar(&cfg.RepairConfig.TaskMode, "repair-mode", "full", "specify the repair task mode, only used by mode repair: [full csv all]")
+	fs.StringVar(&cfg.RepairConfig.Action, "repair-action", "list", "specify the repair action, only used by mode repair: [list reset truncate done]")
+	fs.StringVar(&cfg.RepairConfig.TableNameS, "repair-table", "", "specify the repair source table, only used by mode repair, null represent all failed tables of the task")
+	fs.StringVar(&cfg.RepairConfig.ChunkDetail, "repair-chunk", "", "specify the repair table chunk detail_s, only used by mode repair action reset")
+	fs.BoolVar(&cfg.DryRun, "dry-run", false, "only plan the task and print the plan report without truncating or writing target and meta database, only used by mode full and all")
+	fs.StringVar(&cfg.PrecheckConfig.TaskMode, "precheck-mode", "full", "specify the precheck task mode, only used by mode precheck: [assess reverse check compare full csv all]")
+	fs.StringVar(&cfg.BaselineConfig.BaseRunID, "baseline-run", "", "specify the baseline assess run id, only used by mode assess, compare the current (or target-run) assess result with it")
+	fs.BoolVar(&cfg.EncryptSecret, "encrypt", false, "read the plaintext secret from stdin (no echo when stdin is a terminal), encrypt it with [app] secret-key-file or environment variable TRANSFERDB_SECRET_KEY, print the encrypted config value and exit")
+	fs.StringVar(&cfg.BaselineConfig.TargetRunID, "target-run", "", "specify the target assess run id, only used by mode assess with baseline-run, compare two saved assess results without running assess")
 	return cfg
 }
 
@@ -212,6 +347,23 @@ func (c *Config) Parse(args []string) error {
 		return fmt.Errorf("no config file")
 	}
 
+	if c.EncryptSecret {
+		secretKey, err := LoadSecretKey(c.AppConfig.SecretKeyFile)
+		if err != nil {
+			return err
+		}
+		plainText, err := ReadSecret(os.Stdin, os.Stderr)
+		if err != nil {
+			return err
+		}
+		encrypted, err := EncryptSecret(plainText, secretKey)
+		if err != nil {
+			return err
+		}
+		fmt.Println(encrypted)
+		os.Exit(0)
+	}
+
 	err = c.AdjustConfig()
 	if err != nil {
 		return err
@@ -222,26 +374,122 @@ func (c *Config) Parse(args []string) error {
 
 // 加载配置文件并解析
 func (c *Config) configFromFile(file string) error {
-	if _, err := toml.DecodeFile(file, c); err != nil {
+	md, err := toml.DecodeFile(file, c)
+	if err != nil {
 		return fmt.Errorf("failed decode toml config file %s: %v", file, err)
 	}
+	for _, key := range md.Undecoded() {
+		c.UndecodedKeys = append(c.UndecodedKeys, key.String())
+	}
 	return nil
 }
 
 func (c *Config) AdjustConfig() error {
+	// 敏感配置解析：环境变量、文件以及本地密钥加密密文
+	if err := c.resolveSecrets(); err != nil {
+		return err
+	}
+
 	c.DBTypeS = common.StringUPPER(c.DBTypeS)
 	c.DBTypeT = common.StringUPPER(c.DBTypeT)
 	c.TaskMode = common.StringUPPER(c.TaskMode)
+	c.RepairConfig.TaskMode = common.StringUPPER(c.RepairConfig.TaskMode)
+	c.PrecheckConfig.TaskMode = common.StringUPPER(c.PrecheckConfig.TaskMode)
+	c.RepairConfig.Action = common.StringUPPER(c.RepairConfig.Action)
+	c.RepairConfig.TableNameS = common.StringUPPER(c.RepairConfig.TableNameS)
 	c.OracleConfig.PDBName = common.StringUPPER(c.OracleConfig.PDBName)
+	c.StandbyConfig.PDBName = common.StringUPPER(c.StandbyConfig.PDBName)
+
+	// dry-run 仅支持 full/all 模式，其他模式显式报错，避免误以为未执行写入
+	if c.DryRun && c.TaskMode != common.TaskModeFull && c.TaskMode != common.TaskModeAll {
+		return fmt.Errorf("flag [dry-run] isn't support mode [%s], only support mode [full all]", c.TaskMode)
+	}
 
 	c.SchemaConfig.SourceSchema = common.StringUPPER(c.SchemaConfig.SourceSchema)
 	c.SchemaConfig.TargetSchema = common.StringUPPER(c.SchemaConfig.TargetSchema)
 
+	for i, t := range c.SchemaConfig.SourceIncludeObjectType {
+		c.SchemaConfig.SourceIncludeObjectType[i] = common.StringUPPER(t)
+		if !common.IsContainString(common.TableObjectTypes, c.SchemaConfig.SourceIncludeObjectType[i]) {
+			return fmt.Errorf("config [schema] source-include-object-type [%s] isn't support, only support %v", t, common.TableObjectTypes)
+		}
+	}
+	for i, t := range c.SchemaConfig.SourceExcludeObjectType {
+		c.SchemaConfig.SourceExcludeObjectType[i] = common.StringUPPER(t)
+		if !common.IsContainString(common.TableObjectTypes, c.SchemaConfig.SourceExcludeObjectType[i]) {
+			return fmt.Errorf("config [schema] source-exclude-object-type [%s] isn't support, only support %v", t, common.TableObjectTypes)
+		}
+	}
+	if c.SchemaConfig.SourceMaxTableSize > 0 && c.SchemaConfig.SourceMinTableSize > c.SchemaConfig.SourceMaxTableSize {
+		return fmt.Errorf("config [schema] source-min-table-size [%v] cannot be greater than source-max-table-size [%v]", c.SchemaConfig.SourceMinTableSize, c.SchemaConfig.SourceMaxTableSize)
+	}
+	if c.SchemaConfig.SourceMaxTableRows > 0 && c.SchemaConfig.SourceMinTableRows > c.SchemaConfig.SourceMaxTableRows {
+		return fmt.Errorf("config [schema] source-min-table-rows [%v] cannot be greater than source-max-table-rows [%v]", c.SchemaConfig.SourceMinTableRows, c.SchemaConfig.SourceMaxTableRows)
+	}
+
+	if len(c.AssessConfig.ReportFormat) == 0 {
+		c.AssessConfig.ReportFormat = []string{common.AssessReportFormatHTML}
+	}
+	for i, f := range c.AssessConfig.ReportFormat {
+		c.AssessConfig.ReportFormat[i] = common.StringUPPER(f)
+		if _, ok := common.AssessReportFormatFileSuffix[c.AssessConfig.ReportFormat[i]]; !ok {
+			return fmt.Errorf("config [assess] report-format [%s] isn't support, only support html, json or markdown", f)
+		}
+	}
+	c.AssessConfig.SQLSource = common.StringUPPER(c.AssessConfig.SQLSource)
+	switch c.AssessConfig.SQLSource {
+	case "":
+		// 未配置不评估，兼容历史配置（SQLAREA、AWR 需额外视图权限，需显式开启）
+		c.AssessConfig.SQLSource = common.AssessSQLSourceNone
+	case common.AssessSQLSourceSQLArea, common.AssessSQLSourceAWR, common.AssessSQLSourceNone:
+	default:
+		return fmt.Errorf("config [assess] sql-source [%s] isn't support, only support sqlarea, awr or none", c.AssessConfig.SQLSource)
+	}
+	if c.AssessConfig.SQLTopN <= 0 {
+		c.AssessConfig.SQLTopN = common.AssessSQLTopN
+	}
+
+	if c.StandbyConfig.Enable {
+		if c.StandbyConfig.ConnectString == "" && (c.StandbyConfig.Host == "" || c.StandbyConfig.Port == 0 || c.StandbyConfig.ServiceName == "") {
+			return fmt.Errorf("config [oracle-standby] enable, connect-string or host, port and service-name can't be null")
+		}
+		if c.StandbyConfig.ApplyWaitTimeout <= 0 {
+			c.StandbyConfig.ApplyWaitTimeout = common.StandbyApplyWaitTimeout
+		}
+		if c.StandbyConfig.ApplyWaitInterval <= 0 {
+			c.StandbyConfig.ApplyWaitInterval = common.StandbyApplyWaitInterval
+		}
+	}
+
+	c.CSVConfig.BlobEncoding = common.StringUPPER(c.CSVConfig.BlobEncoding)
+	switch c.CSVConfig.BlobEncoding {
+	case "":
+		c.CSVConfig.BlobEncoding = common.BinaryEncodingHex
+	case common.BinaryEncodingHex, common.BinaryEncodingBase64:
+	default:
+		return fmt.Errorf("config [csv] blob-encoding [%s] isn't support, only support hex or base64", c.CSVConfig.BlobEncoding)
+	}
+
+	c.CSVConfig.FileFormat = common.StringUPPER(c.CSVConfig.FileFormat)
+	switch c.CSVConfig.FileFormat {
+	case "":
+		c.CSVConfig.FileFormat = common.FileFormatCSV
+	case common.FileFormatCSV:
+	case common.FileFormatParquet:
+		// parquet 字符串按 UTF8 写入
+		if !strings.EqualFold(c.CSVConfig.Charset, common.CharsetUTF8MB4) && !strings.EqualFold(c.CSVConfig.Charset, common.MYSQLCharsetUTF8) {
+			return fmt.Errorf("config [csv] file-format parquet only support charset utf8mb4 or utf8, current charset [%s]", c.CSVConfig.Charset)
+		}
+	default:
+		return fmt.Errorf("config [csv] file-format [%s] isn't support, only support csv or parquet", c.CSVConfig.FileFormat)
+	}
+
 	return nil
 }
 
+// String 配置输出，密码等敏感配置脱敏
 func (c *Config) String() string {
-	cfg, err := json.Marshal(c)
+	cfg, err := json.Marshal(c.redactSecrets())
 	if err != nil {
 		return "<nil>"
 	}
@@ -0,0 +1,216 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"golang.org/x/term"
+	"io"
+	"os"
+	"strings"
+)
+
+// 密码等敏感配置取值格式
+// - env:${ENV_NAME}   环境变量
+// - file:${PATH}      文件内容（去除首尾空白）
+// - encrypted:${TEXT} 本地密钥 AES-256-GCM 加密密文，base64 编码，密文通过 -encrypt 参数从标准输入读取明文生成
+// - 其他              明文
+const (
+	SecretPrefixEnv       = "env:"
+	SecretPrefixFile      = "file:"
+	SecretPrefixEncrypted = "encrypted:"
+)
+
+// 本地密钥文件环境变量，优先级低于 [app] secret-key-file
+const SecretKeyEnv = "TRANSFERDB_SECRET_KEY"
+
+// 日志输出敏感配置脱敏
+const secretRedacted = "******"
+
+// ResolveSecret 解析敏感配置取值，secretKey 为本地密钥，仅 encrypted: 格式需要
+func ResolveSecret(value string, secretKey []byte) (string, error) {
+	switch {
+	case strings.HasPrefix(value, SecretPrefixEnv):
+		envName := strings.TrimPrefix(value, SecretPrefixEnv)
+		envValue, ok := os.LookupEnv(envName)
+		if !ok {
+			return "", fmt.Errorf("secret environment variable [%s] isn't exist", envName)
+		}
+		return envValue, nil
+	case strings.HasPrefix(value, SecretPrefixFile):
+		fileName := strings.TrimPrefix(value, SecretPrefixFile)
+		content, err := os.ReadFile(fileName)
+		if err != nil {
+			return "", fmt.Errorf("secret file [%s] read failed: %v", fileName, err)
+		}
+		return strings.TrimSpace(string(content)), nil
+	case strings.HasPrefix(value, SecretPrefixEncrypted):
+		if len(secretKey) == 0 {
+			return "", fmt.Errorf("secret is encrypted, but secret key isn't set, please set [app] secret-key-file or environment variable [%s]", SecretKeyEnv)
+		}
+		return DecryptSecret(strings.TrimPrefix(value, SecretPrefixEncrypted), secretKey)
+	default:
+		return value, nil
+	}
+}
+
+// LoadSecretKey 加载本地密钥，密钥文件优先，其次环境变量，均不存在返回空
+// 密钥内容经 sha256 派生 AES-256 密钥
+func LoadSecretKey(secretKeyFile string) ([]byte, error) {
+	var keyContent string
+	switch {
+	case !strings.EqualFold(secretKeyFile, ""):
+		content, err := os.ReadFile(secretKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("secret key file [%s] read failed: %v", secretKeyFile, err)
+		}
+		keyContent = strings.TrimSpace(string(content))
+	default:
+		keyContent = strings.TrimSpace(os.Getenv(SecretKeyEnv))
+	}
+	if strings.EqualFold(keyContent, "") {
+		return nil, nil
+	}
+	key := sha256.Sum256([]byte(keyContent))
+	return key[:], nil
+}
+
+// ReadSecret 读取待加密明文，避免明文出现在命令行参数以及 shell 历史
+// 标准输入为终端时提示输入且不回显，否则读取标准输入（管道或重定向）全部内容并去除末尾换行
+func ReadSecret(in *os.File, prompt io.Writer) (string, error) {
+	var (
+		plainText string
+		err       error
+	)
+	if term.IsTerminal(int(in.Fd())) {
+		fmt.Fprint(prompt, "Enter secret: ")
+		var content []byte
+		content, err = term.ReadPassword(int(in.Fd()))
+		fmt.Fprintln(prompt)
+		if err != nil {
+			return "", fmt.Errorf("secret read from terminal failed: %v", err)
+		}
+		plainText = string(content)
+	} else {
+		plainText, err = readSecret(in)
+		if err != nil {
+			return "", err
+		}
+	}
+	if plainText == "" {
+		return "", fmt.Errorf("secret is null, please input the plaintext secret from terminal or stdin")
+	}
+	return plainText, nil
+}
+
+func readSecret(r io.Reader) (string, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("secret read from stdin failed: %v", err)
+	}
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
+
+// EncryptSecret 本地密钥加密明文，返回 encrypted: 格式密文
+func EncryptSecret(plainText string, secretKey []byte) (string, error) {
+	gcm, err := newSecretCipher(secretKey)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("secret encrypt nonce generate failed: %v", err)
+	}
+	cipherText := gcm.Seal(nonce, nonce, []byte(plainText), nil)
+	return SecretPrefixEncrypted + base64.StdEncoding.EncodeToString(cipherText), nil
+}
+
+// DecryptSecret 本地密钥解密 base64 编码密文
+func DecryptSecret(cipherText string, secretKey []byte) (string, error) {
+	gcm, err := newSecretCipher(secretKey)
+	if err != nil {
+		return "", err
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(cipherText))
+	if err != nil {
+		return "", fmt.Errorf("secret decrypt base64 decode failed: %v", err)
+	}
+	if len(data) < gcm.NonceSize() {
+		return "", fmt.Errorf("secret decrypt failed: cipher text too short")
+	}
+	plainText, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", fmt.Errorf("secret decrypt failed, please check secret key: %v", err)
+	}
+	return string(plainText), nil
+}
+
+func newSecretCipher(secretKey []byte) (cipher.AEAD, error) {
+	if len(secretKey) == 0 {
+		return nil, fmt.Errorf("secret key isn't set, please set [app] secret-key-file or environment variable [%s]", SecretKeyEnv)
+	}
+	block, err := aes.NewCipher(secretKey)
+	if err != nil {
+		return nil, fmt.Errorf("secret cipher create failed: %v", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// resolveSecrets 解析配置文件所有敏感配置
+func (c *Config) resolveSecrets() error {
+	secretKey, err := LoadSecretKey(c.AppConfig.SecretKeyFile)
+	if err != nil {
+		return err
+	}
+	secrets := map[string]*string{
+		"[oracle] password":         &c.OracleConfig.Password,
+		"[oracle-standby] password": &c.StandbyConfig.Password,
+		"[mysql] password":          &c.MySQLConfig.Password,
+		"[meta] password":           &c.MetaConfig.Password,
+		"[storage] secret-key":      &c.StorageConfig.SecretKey,
+		"[storage] session-token":   &c.StorageConfig.SessionToken,
+	}
+	for name, value := range secrets {
+		v, err := ResolveSecret(*value, secretKey)
+		if err != nil {
+			return fmt.Errorf("config %s resolve failed: %v", name, err)
+		}
+		*value = v
+	}
+	return nil
+}
+
+// redactSecrets 日志输出配置敏感配置脱敏
+func (c Config) redactSecrets() Config {
+	for _, value := range []*string{
+		&c.OracleConfig.Password,
+		&c.StandbyConfig.Password,
+		&c.MySQLConfig.Password,
+		&c.MetaConfig.Password,
+		&c.StorageConfig.SecretKey,
+		&c.StorageConfig.SessionToken,
+	} {
+		if !strings.EqualFold(*value, "") {
+			*value = secretRedacted
+		}
+	}
+	return c
+}
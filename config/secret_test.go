@@ -0,0 +1,140 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package config
+
+import (
+	"crypto/sha256"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEncryptDecryptSecret(t *testing.T) {
+	key := sha256.Sum256([]byte("transferdb"))
+	otherKey := sha256.Sum256([]byte("other"))
+
+	cases := []struct {
+		name       string
+		plainText  string
+		decryptKey []byte
+		wantErr    bool
+	}{
+		{name: "ascii", plainText: "p@ssw0rd", decryptKey: key[:]},
+		{name: "multibyte", plainText: "密码 with space", decryptKey: key[:]},
+		{name: "empty", plainText: "", decryptKey: key[:]},
+		{name: "wrong key", plainText: "p@ssw0rd", decryptKey: otherKey[:], wantErr: true},
+		{name: "null key", plainText: "p@ssw0rd", decryptKey: nil, wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			encrypted, err := EncryptSecret(c.plainText, key[:])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasPrefix(encrypted, SecretPrefixEncrypted) {
+				t.Fatalf("encrypted secret %s without prefix %s", encrypted, SecretPrefixEncrypted)
+			}
+			got, err := DecryptSecret(strings.TrimPrefix(encrypted, SecretPrefixEncrypted), c.decryptKey)
+			if c.wantErr {
+				if err == nil {
+					t.Fatalf("decrypt secret want error, got %s", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != c.plainText {
+				t.Fatalf("decrypt secret %s, want %s", got, c.plainText)
+			}
+		})
+	}
+}
+
+func TestResolveSecret(t *testing.T) {
+	key := sha256.Sum256([]byte("transferdb"))
+	encrypted, err := EncryptSecret("encrypted-password", key[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	secretFile := filepath.Join(t.TempDir(), "password")
+	if err = os.WriteFile(secretFile, []byte("file-password\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TRANSFERDB_TEST_PASSWORD", "env-password")
+
+	cases := []struct {
+		name      string
+		value     string
+		secretKey []byte
+		expected  string
+		wantErr   bool
+	}{
+		{name: "plain", value: "plain-password", expected: "plain-password"},
+		{name: "env", value: SecretPrefixEnv + "TRANSFERDB_TEST_PASSWORD", expected: "env-password"},
+		{name: "env not exist", value: SecretPrefixEnv + "TRANSFERDB_TEST_NOT_EXIST", wantErr: true},
+		{name: "file", value: SecretPrefixFile + secretFile, expected: "file-password"},
+		{name: "file not exist", value: SecretPrefixFile + secretFile + ".bak", wantErr: true},
+		{name: "encrypted", value: encrypted, secretKey: key[:], expected: "encrypted-password"},
+		{name: "encrypted without key", value: encrypted, wantErr: true},
+		{name: "encrypted invalid base64", value: SecretPrefixEncrypted + "!!!", secretKey: key[:], wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := ResolveSecret(c.value, c.secretKey)
+			if c.wantErr {
+				if err == nil {
+					t.Fatalf("resolve secret want error, got %s", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != c.expected {
+				t.Fatalf("resolve secret %s, want %s", got, c.expected)
+			}
+		})
+	}
+}
+
+func TestReadSecret(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "without newline", input: "p@ssw0rd", expected: "p@ssw0rd"},
+		{name: "lf", input: "p@ssw0rd\n", expected: "p@ssw0rd"},
+		{name: "crlf", input: "p@ssw0rd\r\n", expected: "p@ssw0rd"},
+		{name: "keep space", input: " p@ss w0rd \n", expected: " p@ss w0rd "},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := readSecret(strings.NewReader(c.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != c.expected {
+				t.Fatalf("read secret %q, want %q", got, c.expected)
+			}
+		})
+	}
+}
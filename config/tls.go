@@ -0,0 +1,57 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// IsEnable ssl-ca、ssl-cert 任一配置或者 ssl-skip-verify 开启即启用 TLS
+func (t TLSConfig) IsEnable() bool {
+	return !strings.EqualFold(t.SSLCA, "") || !strings.EqualFold(t.SSLCert, "") || t.SSLSkipVerify
+}
+
+// Build 生成 TLS 配置，ssl-cert/ssl-key 用于双向认证
+func (t TLSConfig) Build(serverName string) (*tls.Config, error) {
+	tlsCfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: t.SSLSkipVerify,
+		ServerName:         serverName,
+	}
+	if !strings.EqualFold(t.SSLCA, "") {
+		caPEM, err := os.ReadFile(t.SSLCA)
+		if err != nil {
+			return nil, fmt.Errorf("tls ssl-ca [%s] read failed: %v", t.SSLCA, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("tls ssl-ca [%s] isn't valid pem certificate", t.SSLCA)
+		}
+		tlsCfg.RootCAs = pool
+	}
+	if !strings.EqualFold(t.SSLCert, "") || !strings.EqualFold(t.SSLKey, "") {
+		cert, err := tls.LoadX509KeyPair(t.SSLCert, t.SSLKey)
+		if err != nil {
+			return nil, fmt.Errorf("tls ssl-cert [%s] ssl-key [%s] load failed: %v", t.SSLCert, t.SSLKey, err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+	return tlsCfg, nil
+}
@@ -0,0 +1,72 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package meta
+
+import (
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+)
+
+// 评估运行结果，每次评估按 run_id 保存 JSON 报告，用于多次评估结果对比
+type AssessRunDetail struct {
+	ID            uint   `gorm:"primary_key;autoIncrement;comment:'自增编号'" json:"id"`
+	RunID         string `gorm:"type:varchar(30);not null;index:idx_run_id,unique;comment:'评估运行编号'" json:"run_id"`
+	DBTypeS       string `gorm:"type:varchar(30);not null;comment:'源数据库类型'" json:"db_type_s"`
+	DBTypeT       string `gorm:"type:varchar(30);not null;comment:'目标数据库类型'" json:"db_type_t"`
+	SchemaNameS   string `gorm:"type:longtext;not null;comment:'源端 schema 列表'" json:"schema_name_s"`
+	ReportVersion string `gorm:"type:varchar(30);not null;comment:'评估报告 JSON schema 版本'" json:"report_version"`
+	ReportDetail  string `gorm:"type:longtext;not null;comment:'评估报告 JSON'" json:"report_detail"`
+	*BaseModel
+}
+
+func NewAssessRunDetailModel(m *Meta) *AssessRunDetail {
+	return &AssessRunDetail{BaseModel: &BaseModel{
+		Meta: m,
+	}}
+}
+
+func (rw *AssessRunDetail) ParseSchemaTable() (string, error) {
+	stmt := &gorm.Statement{DB: rw.GormDB}
+	err := stmt.Parse(rw)
+	if err != nil {
+		return "", fmt.Errorf("parse struct [AssessRunDetail] get table_name failed: %v", err)
+	}
+	return stmt.Schema.Table, nil
+}
+
+func (rw *AssessRunDetail) CreateAssessRunDetail(ctx context.Context, createS *AssessRunDetail) error {
+	table, err := rw.ParseSchemaTable()
+	if err != nil {
+		return err
+	}
+	if err = rw.DB(ctx).Create(createS).Error; err != nil {
+		return fmt.Errorf("create table [%s] record failed: %v", table, err)
+	}
+	return nil
+}
+
+func (rw *AssessRunDetail) DetailAssessRunDetailByRunID(ctx context.Context, detailS *AssessRunDetail) ([]AssessRunDetail, error) {
+	var runDetails []AssessRunDetail
+	table, err := rw.ParseSchemaTable()
+	if err != nil {
+		return runDetails, err
+	}
+	if err = rw.DB(ctx).Where("run_id = ?", detailS.RunID).Find(&runDetails).Error; err != nil {
+		return runDetails, fmt.Errorf("detail table [%s] record failed: %v", table, err)
+	}
+	return runDetails, nil
+}
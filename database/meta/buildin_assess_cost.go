@@ -0,0 +1,123 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package meta
+
+import (
+	"context"
+	"fmt"
+	"github.com/wentaojin/transferdb/common"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 迁移工作量评估模型，权重单位：人天，可按需修改或新增规则
+type BuildinAssessCost struct {
+	ID         uint    `gorm:"primary_key;autoIncrement;comment:'自增编号'" json:"id"`
+	DBTypeS    string  `gorm:"type:varchar(30);index:idx_dbtype_st_cost,unique;comment:'源数据库类型'" json:"db_type_s"`
+	DBTypeT    string  `gorm:"type:varchar(30);index:idx_dbtype_st_cost,unique;comment:'目标数据库类型'" json:"db_type_t"`
+	CostType   string  `gorm:"type:varchar(30);index:idx_dbtype_st_cost,unique;comment:'评估类型 OBJECT_TYPE/DATATYPE/CODE_LINE/PARTITION_TYPE/SUBPARTITION_TYPE/DIFFICULTY'" json:"cost_type"`
+	CostName   string  `gorm:"type:varchar(300);index:idx_dbtype_st_cost,unique;comment:'对象类型、字段类型、代码对象类型、分区类型或者难度等级，* 代表默认'" json:"cost_name"`
+	CostWeight float64 `gorm:"type:decimal(12,4);not null;comment:'权重（人天）'" json:"cost_weight"`
+	*BaseModel
+}
+
+func NewBuildinAssessCostModel(m *Meta) *BuildinAssessCost {
+	return &BuildinAssessCost{BaseModel: &BaseModel{
+		Meta: m,
+	}}
+}
+
+func (rw *BuildinAssessCost) ParseSchemaTable() (string, error) {
+	stmt := &gorm.Statement{DB: rw.GormDB}
+	err := stmt.Parse(rw)
+	if err != nil {
+		return "", fmt.Errorf("parse struct [BuildinAssessCost] get table_name failed: %v", err)
+	}
+	return stmt.Schema.Table, nil
+}
+
+func (rw *BuildinAssessCost) BatchQueryBuildinAssessCost(ctx context.Context, detailS *BuildinAssessCost) ([]BuildinAssessCost, error) {
+	var assessCosts []BuildinAssessCost
+
+	tableName, err := rw.ParseSchemaTable()
+	if err != nil {
+		return nil, err
+	}
+	if err := rw.DB(ctx).Where("UPPER(db_type_s) = ? AND UPPER(db_type_t) = ?",
+		common.StringUPPER(detailS.DBTypeS),
+		common.StringUPPER(detailS.DBTypeT)).Find(&assessCosts).Error; err != nil {
+		return assessCosts, fmt.Errorf("batch query table [%s] record failed: %v", tableName, err)
+	}
+	return assessCosts, nil
+}
+
+func (rw *BuildinAssessCost) InitO2MTBuildinAssessCost(ctx context.Context) error {
+	var buildinAssessCosts []*BuildinAssessCost
+
+	for _, dbTypeT := range []string{common.DatabaseTypeMySQL, common.DatabaseTypeTiDB} {
+		for costType, weights := range common.BuildInAssessCostO2MTDefaultWeight {
+			for costName, weight := range weights {
+				buildinAssessCosts = append(buildinAssessCosts, &BuildinAssessCost{
+					DBTypeS:    common.DatabaseTypeOracle,
+					DBTypeT:    dbTypeT,
+					CostType:   costType,
+					CostName:   costName,
+					CostWeight: weight,
+				})
+			}
+		}
+	}
+
+	// 已存在规则不覆盖，保留用户修改的权重
+	return rw.DB(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{
+			{Name: "db_type_s"},
+			{Name: "db_type_t"},
+			{Name: "cost_type"},
+			{Name: "cost_name"},
+		},
+		DoNothing: true,
+	}).Create(buildinAssessCosts).Error
+}
+
+func (rw *BuildinAssessCost) InitMT2OBuildinAssessCost(ctx context.Context) error {
+	var buildinAssessCosts []*BuildinAssessCost
+
+	for _, dbTypeS := range []string{common.DatabaseTypeMySQL, common.DatabaseTypeTiDB} {
+		for costType, weights := range common.BuildInAssessCostMT2ODefaultWeight {
+			for costName, weight := range weights {
+				buildinAssessCosts = append(buildinAssessCosts, &BuildinAssessCost{
+					DBTypeS:    dbTypeS,
+					DBTypeT:    common.DatabaseTypeOracle,
+					CostType:   costType,
+					CostName:   costName,
+					CostWeight: weight,
+				})
+			}
+		}
+	}
+
+	// 已存在规则不覆盖，保留用户修改的权重
+	return rw.DB(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{
+			{Name: "db_type_s"},
+			{Name: "db_type_t"},
+			{Name: "cost_type"},
+			{Name: "cost_name"},
+		},
+		DoNothing: true,
+	}).Create(buildinAssessCosts).Error
+}
@@ -248,6 +248,18 @@ func (rw *BuildinDatatypeRule) InitO2MBuildinDatatypeRule(ctx context.Context) e
 		DatatypeNameS: common.BuildInOracleDatatypeXmltype,
 		DatatypeNameT: common.BuildInOracleO2MDatatypeNameMap[common.BuildInOracleDatatypeXmltype],
 	})
+	buildinDataTypeR = append(buildinDataTypeR, &BuildinDatatypeRule{
+		DBTypeS:       common.DatabaseTypeOracle,
+		DBTypeT:       common.DatabaseTypeMySQL,
+		DatatypeNameS: common.BuildInOracleDatatypeSdoGeometry,
+		DatatypeNameT: common.BuildInOracleO2MDatatypeNameMap[common.BuildInOracleDatatypeSdoGeometry],
+	})
+	buildinDataTypeR = append(buildinDataTypeR, &BuildinDatatypeRule{
+		DBTypeS:       common.DatabaseTypeOracle,
+		DBTypeT:       common.DatabaseTypeMySQL,
+		DatatypeNameS: common.BuildInOracleDatatypeObject,
+		DatatypeNameT: common.BuildInOracleO2MDatatypeNameMap[common.BuildInOracleDatatypeObject],
+	})
 
 	buildinDataTypeR = append(buildinDataTypeR, &BuildinDatatypeRule{
 		DBTypeS:       common.DatabaseTypeOracle,
@@ -702,6 +714,19 @@ func (rw *BuildinDatatypeRule) InitO2TBuildinDatatypeRule(ctx context.Context) e
 		DatatypeNameS: common.BuildInOracleDatatypeXmltype,
 		DatatypeNameT: common.BuildInOracleO2MDatatypeNameMap[common.BuildInOracleDatatypeXmltype],
 	})
+	// TiDB 不支持 GEOMETRY，以 WKT 文本存储
+	buildinDataTypeR = append(buildinDataTypeR, &BuildinDatatypeRule{
+		DBTypeS:       common.DatabaseTypeOracle,
+		DBTypeT:       common.DatabaseTypeTiDB,
+		DatatypeNameS: common.BuildInOracleDatatypeSdoGeometry,
+		DatatypeNameT: common.BuildInMySQLDatatypeLongText,
+	})
+	buildinDataTypeR = append(buildinDataTypeR, &BuildinDatatypeRule{
+		DBTypeS:       common.DatabaseTypeOracle,
+		DBTypeT:       common.DatabaseTypeTiDB,
+		DatatypeNameS: common.BuildInOracleDatatypeObject,
+		DatatypeNameT: common.BuildInOracleO2MDatatypeNameMap[common.BuildInOracleDatatypeObject],
+	})
 
 	buildinDataTypeR = append(buildinDataTypeR, &BuildinDatatypeRule{
 		DBTypeS:       common.DatabaseTypeOracle,
@@ -1150,3 +1150,597 @@ func (rw *BuildinObjectCompatible) InitO2TBuildinObjectCompatible(ctx context.Co
 
 	return rw.DB(ctx).Clauses(clause.Insert{Modifier: "IGNORE"}).Create(buildinObjComps).Error
 }
+
+func (rw *BuildinObjectCompatible) InitM2OBuildinObjectCompatible(ctx context.Context) error {
+	var buildinObjComps []*BuildinObjectCompatible
+	/*
+		M2O Build-IN Compatible Rule
+	*/
+	// mysql character set
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeMySQL,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLCharacterSetUTF8MB4,
+		IsCompatible:  common.AssessYesCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeMySQL,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLCharacterSetUTF8,
+		IsCompatible:  common.AssessYesCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeMySQL,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLCharacterSetGBK,
+		IsCompatible:  common.AssessYesCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeMySQL,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLCharacterSetGB18030,
+		IsCompatible:  common.AssessYesCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeMySQL,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLCharacterSetBIG5,
+		IsCompatible:  common.AssessYesCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeMySQL,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLCharacterSetLatin1,
+		IsCompatible:  common.AssessNoCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	// mysql table type
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeMySQL,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLTableTypeInnoDB,
+		IsCompatible:  common.AssessYesCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeMySQL,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLTableTypeMyISAM,
+		IsCompatible:  common.AssessYesCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeMySQL,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLTableTypeMemory,
+		IsCompatible:  common.AssessNoCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeMySQL,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLTableTypeArchive,
+		IsCompatible:  common.AssessNoCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeMySQL,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLTableTypeCSV,
+		IsCompatible:  common.AssessNoCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeMySQL,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLTableTypeFederated,
+		IsCompatible:  common.AssessNoCompatible,
+		IsConvertible: common.AssessNoConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeMySQL,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLTableTypeBlackhole,
+		IsCompatible:  common.AssessNoCompatible,
+		IsConvertible: common.AssessNoConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeMySQL,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLTableTypeMerge,
+		IsCompatible:  common.AssessNoCompatible,
+		IsConvertible: common.AssessNoConvertible,
+	})
+	// mysql constraint type
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeMySQL,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLConstraintTypePrimary,
+		IsCompatible:  common.AssessYesCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeMySQL,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLConstraintTypeUnique,
+		IsCompatible:  common.AssessYesCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeMySQL,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLConstraintTypeForeign,
+		IsCompatible:  common.AssessYesCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeMySQL,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLConstraintTypeCheck,
+		IsCompatible:  common.AssessYesCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	// mysql index type，HASH 与分区类型同名，InnoDB HASH 索引实际为 BTREE
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeMySQL,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLIndexTypeBtree,
+		IsCompatible:  common.AssessYesCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeMySQL,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLIndexTypeFulltext,
+		IsCompatible:  common.AssessNoCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeMySQL,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLIndexTypeSpatial,
+		IsCompatible:  common.AssessNoCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	// mysql view type
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeMySQL,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLViewTypeView,
+		IsCompatible:  common.AssessYesCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	// mysql code type
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeMySQL,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLCodeTypeProcedure,
+		IsCompatible:  common.AssessNoCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeMySQL,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLCodeTypeFunction,
+		IsCompatible:  common.AssessNoCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeMySQL,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLCodeTypeTrigger,
+		IsCompatible:  common.AssessNoCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeMySQL,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLCodeTypeEvent,
+		IsCompatible:  common.AssessNoCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	// mysql partition type
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeMySQL,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLPartitionTypeRange,
+		IsCompatible:  common.AssessYesCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeMySQL,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLPartitionTypeList,
+		IsCompatible:  common.AssessYesCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeMySQL,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLPartitionTypeHash,
+		IsCompatible:  common.AssessYesCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeMySQL,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLPartitionTypeKey,
+		IsCompatible:  common.AssessNoCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeMySQL,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLPartitionTypeRangeColumns,
+		IsCompatible:  common.AssessYesCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeMySQL,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLPartitionTypeListColumns,
+		IsCompatible:  common.AssessYesCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeMySQL,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLPartitionTypeLinearHash,
+		IsCompatible:  common.AssessNoCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeMySQL,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLPartitionTypeLinearKey,
+		IsCompatible:  common.AssessNoCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	// mysql feature
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeMySQL,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLFeatureEnum,
+		IsCompatible:  common.AssessNoCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeMySQL,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLFeatureSet,
+		IsCompatible:  common.AssessNoCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeMySQL,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLFeatureUnsigned,
+		IsCompatible:  common.AssessNoCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeMySQL,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLFeatureAutoIncrement,
+		IsCompatible:  common.AssessNoCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeMySQL,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLFeatureVirtualGenerated,
+		IsCompatible:  common.AssessYesCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeMySQL,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLFeatureStoredGenerated,
+		IsCompatible:  common.AssessNoCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeMySQL,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLFeatureOnUpdate,
+		IsCompatible:  common.AssessNoCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+
+	return rw.DB(ctx).Clauses(clause.Insert{Modifier: "IGNORE"}).Create(buildinObjComps).Error
+}
+
+func (rw *BuildinObjectCompatible) InitT2OBuildinObjectCompatible(ctx context.Context) error {
+	var buildinObjComps []*BuildinObjectCompatible
+	/*
+		T2O Build-IN Compatible Rule
+	*/
+	// mysql character set
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeTiDB,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLCharacterSetUTF8MB4,
+		IsCompatible:  common.AssessYesCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeTiDB,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLCharacterSetUTF8,
+		IsCompatible:  common.AssessYesCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeTiDB,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLCharacterSetGBK,
+		IsCompatible:  common.AssessYesCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeTiDB,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLCharacterSetLatin1,
+		IsCompatible:  common.AssessNoCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	// mysql table type
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeTiDB,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLTableTypeInnoDB,
+		IsCompatible:  common.AssessYesCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeTiDB,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLTableTypeMyISAM,
+		IsCompatible:  common.AssessYesCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeTiDB,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLTableTypeMemory,
+		IsCompatible:  common.AssessNoCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeTiDB,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLTableTypeArchive,
+		IsCompatible:  common.AssessNoCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeTiDB,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLTableTypeCSV,
+		IsCompatible:  common.AssessNoCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeTiDB,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLTableTypeFederated,
+		IsCompatible:  common.AssessNoCompatible,
+		IsConvertible: common.AssessNoConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeTiDB,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLTableTypeBlackhole,
+		IsCompatible:  common.AssessNoCompatible,
+		IsConvertible: common.AssessNoConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeTiDB,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLTableTypeMerge,
+		IsCompatible:  common.AssessNoCompatible,
+		IsConvertible: common.AssessNoConvertible,
+	})
+	// mysql constraint type
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeTiDB,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLConstraintTypePrimary,
+		IsCompatible:  common.AssessYesCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeTiDB,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLConstraintTypeUnique,
+		IsCompatible:  common.AssessYesCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeTiDB,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLConstraintTypeForeign,
+		IsCompatible:  common.AssessYesCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeTiDB,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLConstraintTypeCheck,
+		IsCompatible:  common.AssessYesCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	// mysql index type，HASH 与分区类型同名，InnoDB HASH 索引实际为 BTREE
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeTiDB,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLIndexTypeBtree,
+		IsCompatible:  common.AssessYesCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeTiDB,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLIndexTypeFulltext,
+		IsCompatible:  common.AssessNoCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeTiDB,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLIndexTypeSpatial,
+		IsCompatible:  common.AssessNoCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	// mysql view type
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeTiDB,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLViewTypeView,
+		IsCompatible:  common.AssessYesCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	// mysql code type
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeTiDB,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLCodeTypeProcedure,
+		IsCompatible:  common.AssessNoCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeTiDB,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLCodeTypeFunction,
+		IsCompatible:  common.AssessNoCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeTiDB,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLCodeTypeTrigger,
+		IsCompatible:  common.AssessNoCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeTiDB,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLCodeTypeEvent,
+		IsCompatible:  common.AssessNoCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	// mysql partition type
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeTiDB,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLPartitionTypeRange,
+		IsCompatible:  common.AssessYesCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeTiDB,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLPartitionTypeList,
+		IsCompatible:  common.AssessYesCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeTiDB,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLPartitionTypeHash,
+		IsCompatible:  common.AssessYesCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeTiDB,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLPartitionTypeKey,
+		IsCompatible:  common.AssessNoCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeTiDB,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLPartitionTypeRangeColumns,
+		IsCompatible:  common.AssessYesCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeTiDB,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLPartitionTypeListColumns,
+		IsCompatible:  common.AssessYesCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeTiDB,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLPartitionTypeLinearHash,
+		IsCompatible:  common.AssessNoCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeTiDB,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLPartitionTypeLinearKey,
+		IsCompatible:  common.AssessNoCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	// mysql feature
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeTiDB,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLFeatureEnum,
+		IsCompatible:  common.AssessNoCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeTiDB,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLFeatureSet,
+		IsCompatible:  common.AssessNoCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeTiDB,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLFeatureUnsigned,
+		IsCompatible:  common.AssessNoCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeTiDB,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLFeatureAutoIncrement,
+		IsCompatible:  common.AssessNoCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeTiDB,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLFeatureVirtualGenerated,
+		IsCompatible:  common.AssessYesCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeTiDB,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLFeatureStoredGenerated,
+		IsCompatible:  common.AssessNoCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+	buildinObjComps = append(buildinObjComps, &BuildinObjectCompatible{
+		DBTypeS:       common.DatabaseTypeTiDB,
+		DBTypeT:       common.DatabaseTypeOracle,
+		ObjectNameS:   common.BuildInMySQLFeatureOnUpdate,
+		IsCompatible:  common.AssessNoCompatible,
+		IsConvertible: common.AssessYesConvertible,
+	})
+
+	return rw.DB(ctx).Clauses(clause.Insert{Modifier: "IGNORE"}).Create(buildinObjComps).Error
+}
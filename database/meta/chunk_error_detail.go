@@ -35,6 +35,7 @@ type ChunkErrorDetail struct {
 	InfoDetail   string `gorm:"type:longtext;not null;comment:'信息详情'" json:"info_detail"`
 	ErrorSQL     string `gorm:"type:longtext;not null;comment:'错误 SQL'" json:"error_sql"`
 	ErrorDetail  string `gorm:"type:longtext;not null;comment:'错误详情'" json:"error_detail"`
+	ErrorClass   string `gorm:"type:varchar(30);comment:'错误分类'" json:"error_class"`
 	*BaseModel
 }
 
@@ -79,3 +80,37 @@ func (rw *ChunkErrorDetail) CreateChunkErrorDetail(ctx context.Context, createS
 	}
 	return nil
 }
+
+func (rw *ChunkErrorDetail) DetailChunkErrorDetailBySchemaTable(ctx context.Context, detailS *ChunkErrorDetail) ([]ChunkErrorDetail, error) {
+	var errDetails []ChunkErrorDetail
+	table, err := rw.ParseSchemaTable()
+	if err != nil {
+		return errDetails, err
+	}
+	if err = rw.DB(ctx).Where("db_type_s = ? AND db_type_t = ? AND schema_name_s = ? AND table_name_s = ? AND task_mode = ?",
+		common.StringUPPER(detailS.DBTypeS),
+		common.StringUPPER(detailS.DBTypeT),
+		common.StringUPPER(detailS.SchemaNameS),
+		common.StringUPPER(detailS.TableNameS),
+		detailS.TaskMode).Find(&errDetails).Error; err != nil {
+		return errDetails, fmt.Errorf("detail table [%s] record failed: %v", table, err)
+	}
+	return errDetails, nil
+}
+
+func (rw *ChunkErrorDetail) DeleteChunkErrorDetailBySchemaTable(ctx context.Context, deleteS *ChunkErrorDetail) error {
+	table, err := rw.ParseSchemaTable()
+	if err != nil {
+		return err
+	}
+	err = rw.DB(ctx).Where("db_type_s = ? AND db_type_t = ? AND schema_name_s = ? AND table_name_s = ? AND task_mode = ?",
+		common.StringUPPER(deleteS.DBTypeS),
+		common.StringUPPER(deleteS.DBTypeT),
+		common.StringUPPER(deleteS.SchemaNameS),
+		common.StringUPPER(deleteS.TableNameS),
+		deleteS.TaskMode).Delete(&ChunkErrorDetail{}).Error
+	if err != nil {
+		return fmt.Errorf("delete table [%s] reocrd failed: %v", table, err)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package meta
+
+import (
+	"context"
+	"fmt"
+	"github.com/wentaojin/transferdb/common"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CSV 文件元数据表，记录每个 csv 文件行数、大小以及 checksum，用于生成 manifest
+// full_sync_meta 表任务成功后记录会被清理，故单独记录
+type CSVFileMeta struct {
+	ID           uint   `gorm:"primary_key;autoIncrement;comment:'自增编号'" json:"id"`
+	DBTypeS      string `gorm:"type:varchar(30);index:idx_dbtype_st_map,unique;index:idx_schema_mode;comment:'源数据库类型'" json:"db_type_s"`
+	DBTypeT      string `gorm:"type:varchar(30);index:idx_dbtype_st_map,unique;index:idx_schema_mode;comment:'目标数据库类型'" json:"db_type_t"`
+	SchemaNameS  string `gorm:"type:varchar(100);not null;index:idx_schema_mode;comment:'源端 schema'" json:"schema_name_s"`
+	TableNameS   string `gorm:"type:varchar(100);not null;comment:'源端表名'" json:"table_name_s"`
+	SchemaNameT  string `gorm:"type:varchar(100);not null;comment:'目标端 schema'" json:"schema_name_t"`
+	TableNameT   string `gorm:"type:varchar(100);not null;comment:'目标端表名'" json:"table_name_t"`
+	TaskMode     string `gorm:"type:varchar(30);not null;index:idx_schema_mode;comment:'任务模式'" json:"task_mode"`
+	GlobalScnS   uint64 `gorm:"comment:'源端全局 SCN'" json:"global_scn_s"`
+	ChunkDetailS string `gorm:"type:varchar(300);not null;comment:'表 chunk 切分信息'" json:"chunk_detail_s"`
+	ColumnNameS  string `gorm:"type:text;comment:'csv 文件字段信息'" json:"column_name_s"`
+	CSVFile      string `gorm:"type:varchar(300);not null;index:idx_dbtype_st_map,unique;comment:'csv 文件名'" json:"csv_file"`
+	RowCounts    uint64 `gorm:"comment:'csv 文件数据行数（不包含表头）'" json:"row_counts"`
+	FileSize     uint64 `gorm:"comment:'csv 文件大小 bytes'" json:"file_size"`
+	FileChecksum string `gorm:"type:varchar(64);comment:'csv 文件 sha256 checksum'" json:"file_checksum"`
+	*BaseModel
+}
+
+func NewCSVFileMetaModel(m *Meta) *CSVFileMeta {
+	return &CSVFileMeta{BaseModel: &BaseModel{
+		Meta: m}}
+}
+
+func (rw *CSVFileMeta) ParseSchemaTable() (string, error) {
+	stmt := &gorm.Statement{DB: rw.GormDB}
+	err := stmt.Parse(rw)
+	if err != nil {
+		return "", fmt.Errorf("parse struct [CSVFileMeta] get table_name failed: %v", err)
+	}
+	return stmt.Schema.Table, nil
+}
+
+// CreateOrUpdateCSVFileMeta chunk 重新导出会覆盖 csv 文件，对应记录同步覆盖
+func (rw *CSVFileMeta) CreateOrUpdateCSVFileMeta(ctx context.Context, createS *CSVFileMeta) error {
+	table, err := rw.ParseSchemaTable()
+	if err != nil {
+		return err
+	}
+	if err = rw.DB(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{
+			{Name: "db_type_s"},
+			{Name: "db_type_t"},
+			{Name: "csv_file"},
+		},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"schema_name_s", "table_name_s", "schema_name_t", "table_name_t", "task_mode",
+			"global_scn_s", "chunk_detail_s", "column_name_s", "row_counts", "file_size", "file_checksum", "updated_at"}),
+	}).Create(createS).Error; err != nil {
+		return fmt.Errorf("create or update table [%s] record failed: %v", table, err)
+	}
+	return nil
+}
+
+func (rw *CSVFileMeta) DetailCSVFileMetaBySchema(ctx context.Context, detailS *CSVFileMeta) ([]CSVFileMeta, error) {
+	var fileMetas []CSVFileMeta
+	table, err := rw.ParseSchemaTable()
+	if err != nil {
+		return fileMetas, err
+	}
+	if err = rw.DB(ctx).Where("db_type_s = ? AND db_type_t = ? AND schema_name_s = ? AND task_mode = ?",
+		common.StringUPPER(detailS.DBTypeS),
+		common.StringUPPER(detailS.DBTypeT),
+		common.StringUPPER(detailS.SchemaNameS),
+		detailS.TaskMode).
+		Order("table_name_s ASC, id ASC").
+		Find(&fileMetas).Error; err != nil {
+		return fileMetas, fmt.Errorf("detail table [%s] record by schema failed: %v", table, err)
+	}
+	return fileMetas, nil
+}
+
+func (rw *CSVFileMeta) DeleteCSVFileMetaBySchemaTaskMode(ctx context.Context, deleteS *CSVFileMeta) error {
+	table, err := rw.ParseSchemaTable()
+	if err != nil {
+		return err
+	}
+	err = rw.DB(ctx).Where("db_type_s = ? AND db_type_t = ? AND schema_name_s = ? AND task_mode = ?",
+		common.StringUPPER(deleteS.DBTypeS),
+		common.StringUPPER(deleteS.DBTypeT),
+		common.StringUPPER(deleteS.SchemaNameS),
+		deleteS.TaskMode).Delete(&CSVFileMeta{}).Error
+	if err != nil {
+		return fmt.Errorf("delete table [%s] reocrd failed: %v", table, err)
+	}
+	return nil
+}
+
+func (rw *CSVFileMeta) DeleteCSVFileMetaBySchemaTable(ctx context.Context, deleteS *CSVFileMeta) error {
+	table, err := rw.ParseSchemaTable()
+	if err != nil {
+		return err
+	}
+	err = rw.DB(ctx).Where("db_type_s = ? AND db_type_t = ? AND schema_name_s = ? AND table_name_s = ? AND task_mode = ?",
+		common.StringUPPER(deleteS.DBTypeS),
+		common.StringUPPER(deleteS.DBTypeT),
+		common.StringUPPER(deleteS.SchemaNameS),
+		common.StringUPPER(deleteS.TableNameS),
+		deleteS.TaskMode).Delete(&CSVFileMeta{}).Error
+	if err != nil {
+		return fmt.Errorf("delete table [%s] reocrd failed: %v", table, err)
+	}
+	return nil
+}
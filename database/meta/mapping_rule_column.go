@@ -0,0 +1,85 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package meta
+
+import (
+	"context"
+	"fmt"
+	"github.com/wentaojin/transferdb/common"
+	"gorm.io/gorm"
+)
+
+/*
+	数据迁移以及校验字段映射规则表
+*/
+// 适用于 reverse、full、csv、all 以及 compare，同一字段结构、全量、增量以及校验保持一致
+// rule_type: EXCLUDE/RENAME/EXPR，column_name_t 为目标端字段名（RENAME 必填，EXPR 可选），column_expr 为源端 SQL 表达式（EXPR 必填）
+type ColumnMappingRule struct {
+	ID          uint   `gorm:"primary_key;autoIncrement;comment:'自增编号'" json:"id"`
+	DBTypeS     string `gorm:"type:varchar(30);index:idx_dbtype_st_map,unique;comment:'源数据库类型'" json:"db_type_s"`
+	DBTypeT     string `gorm:"type:varchar(30);index:idx_dbtype_st_map,unique;comment:'目标数据库类型'" json:"db_type_t"`
+	SchemaNameS string `gorm:"type:varchar(100);not null;index:idx_dbtype_st_map,unique;comment:'源端库 schema'" json:"schema_name_s"`
+	TableNameS  string `gorm:"type:varchar(100);not null;index:idx_dbtype_st_map,unique;comment:'源端表名'" json:"table_name_s"`
+	ColumnNameS string `gorm:"type:varchar(200);not null;index:idx_dbtype_st_map,unique;comment:'源端表字段列名'" json:"column_name_s"`
+	RuleType    string `gorm:"type:varchar(30);not null;comment:'映射规则类型'" json:"rule_type"`
+	ColumnNameT string `gorm:"type:varchar(200);comment:'目标端表字段列名'" json:"column_name_t"`
+	ColumnExpr  string `gorm:"type:varchar(1000);comment:'源端字段 SQL 表达式'" json:"column_expr"`
+	*BaseModel
+}
+
+func NewColumnMappingRuleModel(m *Meta) *ColumnMappingRule {
+	return &ColumnMappingRule{BaseModel: &BaseModel{
+		Meta: m,
+	}}
+}
+
+func (rw *ColumnMappingRule) ParseSchemaTable() (string, error) {
+	stmt := &gorm.Statement{DB: rw.GormDB}
+	err := stmt.Parse(rw)
+	if err != nil {
+		return "", fmt.Errorf("parse struct [ColumnMappingRule] get table_name failed: %v", err)
+	}
+	return stmt.Schema.Table, nil
+}
+
+func (rw *ColumnMappingRule) CreateColumnMappingRule(ctx context.Context, createS *ColumnMappingRule) error {
+	table, err := rw.ParseSchemaTable()
+	if err != nil {
+		return err
+	}
+	if err = rw.DB(ctx).Create(createS).Error; err != nil {
+		return fmt.Errorf("create table [%s] record failed: %v", table, err)
+	}
+	return nil
+}
+
+func (rw *ColumnMappingRule) DetailColumnMappingRuleBySchema(ctx context.Context, detailS *ColumnMappingRule) ([]ColumnMappingRule, error) {
+	var mappingRules []ColumnMappingRule
+
+	table, err := rw.ParseSchemaTable()
+	if err != nil {
+		return mappingRules, err
+	}
+
+	if err = rw.DB(ctx).Where("UPPER(db_type_s) = ? AND UPPER(db_type_t) = ? AND UPPER(schema_name_s) = ?",
+		common.StringUPPER(detailS.DBTypeS),
+		common.StringUPPER(detailS.DBTypeT),
+		common.StringUPPER(detailS.SchemaNameS)).Find(&mappingRules).Error; err != nil {
+		return mappingRules, fmt.Errorf("detail table [%s] record failed: %v", table, err)
+	}
+
+	return mappingRules, nil
+}
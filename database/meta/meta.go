@@ -19,22 +19,32 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	mysqldriver "github.com/go-sql-driver/mysql"
 	"github.com/wentaojin/transferdb/config"
 	"github.com/wentaojin/transferdb/logger"
 	"go.uber.org/zap"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 	"gorm.io/gorm/schema"
+	"sync"
 )
 
+// 元数据库 TLS 配置注册名，连接参数 tls=${name}
+const metaTLSConfigName = "transferdb-meta"
+
 type Meta struct {
 	GormDB *gorm.DB
 }
 
 func NewMetaDBEngine(ctx context.Context, mysqlCfg config.MetaConfig, slowThreshold int) (*Meta, error) {
+	tlsParams, err := metaTLSParams(mysqlCfg)
+	if err != nil {
+		return nil, err
+	}
+
 	// 创建元数据库
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/?charset=utf8mb4&parseTime=True&loc=Local",
-		mysqlCfg.Username, mysqlCfg.Password, mysqlCfg.Host, mysqlCfg.Port)
+	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/?charset=utf8mb4&parseTime=True&loc=Local%s",
+		mysqlCfg.Username, mysqlCfg.Password, mysqlCfg.Host, mysqlCfg.Port, tlsParams)
 
 	mysqlDB, err := sql.Open("mysql", dsn)
 	if err != nil {
@@ -53,8 +63,8 @@ func NewMetaDBEngine(ctx context.Context, mysqlCfg config.MetaConfig, slowThresh
 
 	// 初始化 MetaDB
 	// 初始化 gorm 日志记录器
-	dsn = fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-		mysqlCfg.Username, mysqlCfg.Password, mysqlCfg.Host, mysqlCfg.Port, mysqlCfg.MetaSchema)
+	dsn = fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=Local%s",
+		mysqlCfg.Username, mysqlCfg.Password, mysqlCfg.Host, mysqlCfg.Port, mysqlCfg.MetaSchema, tlsParams)
 	l := logger.NewGormLogger(zap.L(), slowThreshold)
 	l.SetAsDefault()
 	gormDB, err := gorm.Open(mysql.New(mysql.Config{
@@ -77,6 +87,41 @@ func NewMetaDBEngine(ctx context.Context, mysqlCfg config.MetaConfig, slowThresh
 	return &Meta{GormDB: gormDB}, nil
 }
 
+// NewMetaDBConn 元数据库普通连接，不创建元数据库以及元数据表，适用于 precheck 只读探测，调用方负责关闭连接
+func NewMetaDBConn(ctx context.Context, mysqlCfg config.MetaConfig) (*sql.DB, error) {
+	tlsParams, err := metaTLSParams(mysqlCfg)
+	if err != nil {
+		return nil, err
+	}
+	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/?charset=utf8mb4&parseTime=True&loc=Local%s",
+		mysqlCfg.Username, mysqlCfg.Password, mysqlCfg.Host, mysqlCfg.Port, tlsParams)
+
+	mysqlDB, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("error on open general database connection [%v]: %v", mysqlCfg.MetaSchema, err)
+	}
+	if err = mysqlDB.PingContext(ctx); err != nil {
+		_ = mysqlDB.Close()
+		return nil, fmt.Errorf("error on ping meta database connection: %v", err)
+	}
+	return mysqlDB, nil
+}
+
+// metaTLSParams 元数据库 TLS 连接参数
+func metaTLSParams(mysqlCfg config.MetaConfig) (string, error) {
+	if !mysqlCfg.TLS.IsEnable() {
+		return "", nil
+	}
+	tlsCfg, err := mysqlCfg.TLS.Build(mysqlCfg.Host)
+	if err != nil {
+		return "", fmt.Errorf("error on meta database tls config: %v", err)
+	}
+	if err = mysqldriver.RegisterTLSConfig(metaTLSConfigName, tlsCfg); err != nil {
+		return "", fmt.Errorf("error on register meta database tls config: %v", err)
+	}
+	return fmt.Sprintf("&tls=%s", metaTLSConfigName), nil
+}
+
 func WrapGormDB(gormDB *gorm.DB) *Meta {
 	return &Meta{GormDB: gormDB}
 }
@@ -98,7 +143,39 @@ func (m *Meta) DB(ctx context.Context) *gorm.DB {
 }
 
 func (m *Meta) MigrateTables() (err error) {
-	return m.migrateStream(
+	return m.migrateStream(metaTables()...)
+}
+
+// CheckTables 检查元数据表是否存在，返回不存在的元数据表
+func (m *Meta) CheckTables() ([]string, error) {
+	var notExistTables []string
+	for _, model := range metaTables() {
+		stmt := &gorm.Statement{DB: m.GormDB}
+		if err := stmt.Parse(model); err != nil {
+			return notExistTables, fmt.Errorf("error on parse meta table model: %v", err)
+		}
+		if !m.GormDB.Migrator().HasTable(stmt.Schema.Table) {
+			notExistTables = append(notExistTables, stmt.Schema.Table)
+		}
+	}
+	return notExistTables, nil
+}
+
+// MetaTableNames 元数据表名，不依赖元数据库连接
+func MetaTableNames() ([]string, error) {
+	var tableNames []string
+	for _, model := range metaTables() {
+		s, err := schema.Parse(model, &sync.Map{}, schema.NamingStrategy{SingularTable: true})
+		if err != nil {
+			return tableNames, fmt.Errorf("error on parse meta table model: %v", err)
+		}
+		tableNames = append(tableNames, s.Table)
+	}
+	return tableNames, nil
+}
+
+func metaTables() []interface{} {
+	return []interface{}{
 		new(ColumnDatatypeRule),
 		new(TableDatatypeRule),
 		new(SchemaDatatypeRule),
@@ -113,7 +190,13 @@ func (m *Meta) MigrateTables() (err error) {
 		new(BuildinDatatypeRule),
 		new(TableNameRule),
 		new(ChunkErrorDetail),
-	)
+		new(CSVFileMeta),
+		new(ColumnTransformRule),
+		new(ColumnMappingRule),
+		new(RowErrorDetail),
+		new(BuildinAssessCost),
+		new(AssessRunDetail),
+	}
 }
 
 func (m *Meta) InitDefaultValue(ctx context.Context) error {
@@ -133,6 +216,14 @@ func (m *Meta) InitDefaultValue(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	err = NewBuildinObjectCompatibleModel(m).InitM2OBuildinObjectCompatible(ctx)
+	if err != nil {
+		return err
+	}
+	err = NewBuildinObjectCompatibleModel(m).InitT2OBuildinObjectCompatible(ctx)
+	if err != nil {
+		return err
+	}
 	err = NewBuildinDatatypeRuleModel(m).InitO2MBuildinDatatypeRule(ctx)
 	if err != nil {
 		return err
@@ -149,6 +240,14 @@ func (m *Meta) InitDefaultValue(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	err = NewBuildinAssessCostModel(m).InitO2MTBuildinAssessCost(ctx)
+	if err != nil {
+		return err
+	}
+	err = NewBuildinAssessCostModel(m).InitMT2OBuildinAssessCost(ctx)
+	if err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -0,0 +1,119 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package meta
+
+import (
+	"context"
+	"fmt"
+	"github.com/wentaojin/transferdb/common"
+	"gorm.io/gorm"
+)
+
+// 数据隔离表，记录全量写入失败的单行数据（源端 ROWID、字段值以及错误），其余数据正常写入
+type RowErrorDetail struct {
+	ID            uint   `gorm:"primary_key;autoIncrement;comment:'自增编号'" json:"id"`
+	DBTypeS       string `gorm:"type:varchar(30);index:idx_dbtype_st_map;comment:'源数据库类型'" json:"db_type_s"`
+	DBTypeT       string `gorm:"type:varchar(30);index:idx_dbtype_st_map;comment:'目标数据库类型'" json:"db_type_t"`
+	SchemaNameS   string `gorm:"type:varchar(100);not null;index:idx_dbtype_st_map;comment:'源端 schema'" json:"schema_name_s"`
+	TableNameS    string `gorm:"type:varchar(100);not null;index:idx_dbtype_st_map;comment:'源端表名'" json:"table_name_s"`
+	SchemaNameT   string `gorm:"type:varchar(100);not null;comment:'目标端 schema'" json:"schema_name_t"`
+	TableNameT    string `gorm:"type:varchar(100);not null;comment:'目标端表名'" json:"table_name_t"`
+	TaskMode      string `gorm:"type:varchar(30);not null;index:idx_dbtype_st_map;comment:'任务模式'" json:"task_mode"`
+	ChunkDetailS  string `gorm:"type:varchar(300);not null;index:idx_dbtype_st_map;comment:'表 chunk 切分信息'" json:"chunk_detail_s"`
+	RowidS        string `gorm:"type:varchar(100);comment:'源端数据 ROWID'" json:"rowid_s"`
+	ColumnDetailT string `gorm:"type:longtext;not null;comment:'目标端字段信息'" json:"column_detail_t"`
+	RowDetailT    string `gorm:"type:longtext;not null;comment:'目标端写入数据'" json:"row_detail_t"`
+	ErrorDetail   string `gorm:"type:longtext;not null;comment:'错误详情'" json:"error_detail"`
+	*BaseModel
+}
+
+func NewRowErrorDetailModel(m *Meta) *RowErrorDetail {
+	return &RowErrorDetail{BaseModel: &BaseModel{
+		Meta: m,
+	}}
+}
+
+func (rw *RowErrorDetail) ParseSchemaTable() (string, error) {
+	stmt := &gorm.Statement{DB: rw.GormDB}
+	err := stmt.Parse(rw)
+	if err != nil {
+		return "", fmt.Errorf("parse struct [RowErrorDetail] get table_name failed: %v", err)
+	}
+	return stmt.Schema.Table, nil
+}
+
+func (rw *RowErrorDetail) CreateRowErrorDetail(ctx context.Context, createS *RowErrorDetail) error {
+	table, err := rw.ParseSchemaTable()
+	if err != nil {
+		return err
+	}
+	if err = rw.DB(ctx).Create(createS).Error; err != nil {
+		return fmt.Errorf("create table [%s] record failed: %v", table, err)
+	}
+	return nil
+}
+
+func (rw *RowErrorDetail) CountsRowErrorDetailBySchemaTable(ctx context.Context, countS *RowErrorDetail) (int64, error) {
+	var totals int64
+	table, err := rw.ParseSchemaTable()
+	if err != nil {
+		return totals, err
+	}
+	if err = rw.DB(ctx).Model(&RowErrorDetail{}).Where("db_type_s = ? AND db_type_t = ? AND schema_name_s = ? AND table_name_s = ? AND task_mode = ?",
+		common.StringUPPER(countS.DBTypeS),
+		common.StringUPPER(countS.DBTypeT),
+		common.StringUPPER(countS.SchemaNameS),
+		common.StringUPPER(countS.TableNameS),
+		countS.TaskMode).Count(&totals).Error; err != nil {
+		return totals, fmt.Errorf("get table [%s] counts failed: %v", table, err)
+	}
+	return totals, nil
+}
+
+func (rw *RowErrorDetail) DeleteRowErrorDetailBySchemaTaskMode(ctx context.Context, deleteS *RowErrorDetail) error {
+	table, err := rw.ParseSchemaTable()
+	if err != nil {
+		return err
+	}
+	err = rw.DB(ctx).Where("db_type_s = ? AND db_type_t = ? AND schema_name_s = ? AND task_mode = ?",
+		common.StringUPPER(deleteS.DBTypeS),
+		common.StringUPPER(deleteS.DBTypeT),
+		common.StringUPPER(deleteS.SchemaNameS),
+		deleteS.TaskMode).Delete(&RowErrorDetail{}).Error
+	if err != nil {
+		return fmt.Errorf("delete table [%s] reocrd failed: %v", table, err)
+	}
+	return nil
+}
+
+// DeleteRowErrorDetailByChunk chunk 重新写入前清理历史隔离记录，避免重复记录，返回清理记录数
+func (rw *RowErrorDetail) DeleteRowErrorDetailByChunk(ctx context.Context, deleteS *RowErrorDetail) (int64, error) {
+	table, err := rw.ParseSchemaTable()
+	if err != nil {
+		return 0, err
+	}
+	res := rw.DB(ctx).Where("db_type_s = ? AND db_type_t = ? AND schema_name_s = ? AND table_name_s = ? AND task_mode = ? AND chunk_detail_s = ?",
+		common.StringUPPER(deleteS.DBTypeS),
+		common.StringUPPER(deleteS.DBTypeT),
+		common.StringUPPER(deleteS.SchemaNameS),
+		common.StringUPPER(deleteS.TableNameS),
+		deleteS.TaskMode,
+		deleteS.ChunkDetailS).Delete(&RowErrorDetail{})
+	if res.Error != nil {
+		return 0, fmt.Errorf("delete table [%s] reocrd failed: %v", table, res.Error)
+	}
+	return res.RowsAffected, nil
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/wentaojin/transferdb/common"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
+	"strings"
 )
 
 type Transaction struct {
@@ -357,3 +358,136 @@ func (rw *Transaction) UpdateIncrSyncMetaSCNByArchivedLog(ctx context.Context,
 	}
 	return nil
 }
+
+// ResetFullSyncMetaChunkAndUpdateWaitSyncMeta 失败 chunk 重置为 WAITING（ChunkDetailS 为空代表表所有失败 chunk），清理对应 chunk 错误以及隔离记录，表状态重置 RUNNING 用于断点续传
+func (rw *Transaction) ResetFullSyncMetaChunkAndUpdateWaitSyncMeta(ctx context.Context, resetS *FullSyncMeta, updateS *WaitSyncMeta) error {
+	txn := rw.DB(ctx).Begin()
+
+	fullTxn := txn.Model(&FullSyncMeta{}).
+		Where("db_type_s = ? AND db_type_t = ? AND schema_name_s = ? AND table_name_s = ? AND task_mode = ? AND task_status = ?",
+			common.StringUPPER(resetS.DBTypeS),
+			common.StringUPPER(resetS.DBTypeT),
+			common.StringUPPER(resetS.SchemaNameS),
+			common.StringUPPER(resetS.TableNameS),
+			resetS.TaskMode,
+			common.TaskStatusFailed)
+	chunkTxn := txn.Where("db_type_s = ? AND db_type_t = ? AND schema_name_s = ? AND table_name_s = ? AND task_mode = ?",
+		common.StringUPPER(resetS.DBTypeS),
+		common.StringUPPER(resetS.DBTypeT),
+		common.StringUPPER(resetS.SchemaNameS),
+		common.StringUPPER(resetS.TableNameS),
+		resetS.TaskMode)
+	rowTxn := txn.Where("db_type_s = ? AND db_type_t = ? AND schema_name_s = ? AND table_name_s = ? AND task_mode = ?",
+		common.StringUPPER(resetS.DBTypeS),
+		common.StringUPPER(resetS.DBTypeT),
+		common.StringUPPER(resetS.SchemaNameS),
+		common.StringUPPER(resetS.TableNameS),
+		resetS.TaskMode)
+	if !strings.EqualFold(resetS.ChunkDetailS, "") {
+		fullTxn = fullTxn.Where("chunk_detail_s = ?", resetS.ChunkDetailS)
+		chunkTxn = chunkTxn.Where("chunk_detail_s = ?", resetS.ChunkDetailS)
+		rowTxn = rowTxn.Where("chunk_detail_s = ?", resetS.ChunkDetailS)
+	}
+
+	if err := fullTxn.Updates(map[string]interface{}{
+		"TaskStatus": common.TaskStatusWaiting,
+	}).Error; err != nil {
+		txn.Rollback()
+		return fmt.Errorf("update table [full_sync_meta] record by transaction failed: %v", err)
+	}
+	if err := chunkTxn.Delete(&ChunkErrorDetail{}).Error; err != nil {
+		txn.Rollback()
+		return fmt.Errorf("delete table [chunk_error_detail] record by transaction failed: %v", err)
+	}
+	if err := rowTxn.Delete(&RowErrorDetail{}).Error; err != nil {
+		txn.Rollback()
+		return fmt.Errorf("delete table [row_error_detail] record by transaction failed: %v", err)
+	}
+	if err := txn.Model(&WaitSyncMeta{}).
+		Where("db_type_s = ? AND db_type_t = ? AND schema_name_s = ? AND table_name_s = ? AND task_mode = ?",
+			common.StringUPPER(updateS.DBTypeS),
+			common.StringUPPER(updateS.DBTypeT),
+			common.StringUPPER(updateS.SchemaNameS),
+			common.StringUPPER(updateS.TableNameS),
+			updateS.TaskMode).
+		Updates(map[string]interface{}{
+			"TaskStatus": common.TaskStatusRunning,
+		}).Error; err != nil {
+		txn.Rollback()
+		return fmt.Errorf("update table [wait_sync_meta] record by transaction failed: %v", err)
+	}
+	return txn.Commit().Error
+}
+
+// DeleteTableFullSyncMetaAndResetWaitSyncMeta 清理表 chunk、chunk 错误以及隔离记录，表状态重置 WAITING 重新切分 chunk 全量重跑
+func (rw *Transaction) DeleteTableFullSyncMetaAndResetWaitSyncMeta(ctx context.Context, deleteS *FullSyncMeta, updateS *WaitSyncMeta) error {
+	txn := rw.DB(ctx).Begin()
+	if err := deleteTableFullSyncMetaAndErrorDetail(txn, deleteS); err != nil {
+		txn.Rollback()
+		return err
+	}
+	if err := txn.Model(&WaitSyncMeta{}).
+		Where("db_type_s = ? AND db_type_t = ? AND schema_name_s = ? AND table_name_s = ? AND task_mode = ?",
+			common.StringUPPER(updateS.DBTypeS),
+			common.StringUPPER(updateS.DBTypeT),
+			common.StringUPPER(updateS.SchemaNameS),
+			common.StringUPPER(updateS.TableNameS),
+			updateS.TaskMode).
+		Updates(map[string]interface{}{
+			"TaskStatus":       common.TaskStatusWaiting,
+			"GlobalScnS":       common.TaskTableDefaultSourceGlobalSCN,
+			"ChunkTotalNums":   common.TaskTableDefaultSplitChunkNums,
+			"ChunkSuccessNums": 0,
+			"ChunkFailedNums":  0,
+		}).Error; err != nil {
+		txn.Rollback()
+		return fmt.Errorf("update table [wait_sync_meta] record by transaction failed: %v", err)
+	}
+	return txn.Commit().Error
+}
+
+// DeleteTableFullSyncMetaAndMarkWaitSyncMeta 人工确认表数据已处理完成，清理表 chunk、chunk 错误以及隔离记录，表状态标记 SUCCESS
+func (rw *Transaction) DeleteTableFullSyncMetaAndMarkWaitSyncMeta(ctx context.Context, deleteS *FullSyncMeta, updateS *WaitSyncMeta) error {
+	txn := rw.DB(ctx).Begin()
+	if err := deleteTableFullSyncMetaAndErrorDetail(txn, deleteS); err != nil {
+		txn.Rollback()
+		return err
+	}
+	if err := txn.Model(&WaitSyncMeta{}).
+		Where("db_type_s = ? AND db_type_t = ? AND schema_name_s = ? AND table_name_s = ? AND task_mode = ?",
+			common.StringUPPER(updateS.DBTypeS),
+			common.StringUPPER(updateS.DBTypeT),
+			common.StringUPPER(updateS.SchemaNameS),
+			common.StringUPPER(updateS.TableNameS),
+			updateS.TaskMode).
+		Updates(map[string]interface{}{
+			"TaskStatus":       common.TaskStatusSuccess,
+			"ChunkSuccessNums": gorm.Expr("chunk_total_nums"),
+			"ChunkFailedNums":  0,
+		}).Error; err != nil {
+		txn.Rollback()
+		return fmt.Errorf("update table [wait_sync_meta] record by transaction failed: %v", err)
+	}
+	return txn.Commit().Error
+}
+
+func deleteTableFullSyncMetaAndErrorDetail(txn *gorm.DB, deleteS *FullSyncMeta) error {
+	where := "db_type_s = ? AND db_type_t = ? AND schema_name_s = ? AND table_name_s = ? AND task_mode = ?"
+	args := []interface{}{
+		common.StringUPPER(deleteS.DBTypeS),
+		common.StringUPPER(deleteS.DBTypeT),
+		common.StringUPPER(deleteS.SchemaNameS),
+		common.StringUPPER(deleteS.TableNameS),
+		deleteS.TaskMode,
+	}
+	if err := txn.Where(where, args...).Delete(&FullSyncMeta{}).Error; err != nil {
+		return fmt.Errorf("delete table [full_sync_meta] record by transaction failed: %v", err)
+	}
+	if err := txn.Where(where, args...).Delete(&ChunkErrorDetail{}).Error; err != nil {
+		return fmt.Errorf("delete table [chunk_error_detail] record by transaction failed: %v", err)
+	}
+	if err := txn.Where(where, args...).Delete(&RowErrorDetail{}).Error; err != nil {
+		return fmt.Errorf("delete table [row_error_detail] record by transaction failed: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package meta
+
+import (
+	"context"
+	"fmt"
+	"github.com/wentaojin/transferdb/common"
+	"gorm.io/gorm"
+)
+
+/*
+	数据迁移字段转换（脱敏）规则表
+*/
+// 适用于 full、csv 以及 all 增量，同一字段全量与增量转换结果保持一致
+// rule_type: HASH/MASK/FIXED/FPE/NULLIFY/EXPR，rule_value 为对应规则参数，max_length 大于 0 代表转换结果按字符截断
+type ColumnTransformRule struct {
+	ID          uint   `gorm:"primary_key;autoIncrement;comment:'自增编号'" json:"id"`
+	DBTypeS     string `gorm:"type:varchar(30);index:idx_dbtype_st_map,unique;comment:'源数据库类型'" json:"db_type_s"`
+	DBTypeT     string `gorm:"type:varchar(30);index:idx_dbtype_st_map,unique;comment:'目标数据库类型'" json:"db_type_t"`
+	SchemaNameS string `gorm:"type:varchar(100);not null;index:idx_dbtype_st_map,unique;comment:'源端库 schema'" json:"schema_name_s"`
+	TableNameS  string `gorm:"type:varchar(100);not null;index:idx_dbtype_st_map,unique;comment:'源端表名'" json:"table_name_s"`
+	ColumnNameS string `gorm:"type:varchar(200);not null;index:idx_dbtype_st_map,unique;comment:'源端表字段列名'" json:"column_name_s"`
+	RuleType    string `gorm:"type:varchar(30);not null;comment:'转换规则类型'" json:"rule_type"`
+	RuleValue   string `gorm:"type:varchar(1000);comment:'转换规则参数'" json:"rule_value"`
+	MaxLength   int    `gorm:"comment:'转换结果最大字符长度'" json:"max_length"`
+	*BaseModel
+}
+
+func NewColumnTransformRuleModel(m *Meta) *ColumnTransformRule {
+	return &ColumnTransformRule{BaseModel: &BaseModel{
+		Meta: m,
+	}}
+}
+
+func (rw *ColumnTransformRule) ParseSchemaTable() (string, error) {
+	stmt := &gorm.Statement{DB: rw.GormDB}
+	err := stmt.Parse(rw)
+	if err != nil {
+		return "", fmt.Errorf("parse struct [ColumnTransformRule] get table_name failed: %v", err)
+	}
+	return stmt.Schema.Table, nil
+}
+
+func (rw *ColumnTransformRule) CreateColumnTransformRule(ctx context.Context, createS *ColumnTransformRule) error {
+	table, err := rw.ParseSchemaTable()
+	if err != nil {
+		return err
+	}
+	if err = rw.DB(ctx).Create(createS).Error; err != nil {
+		return fmt.Errorf("create table [%s] record failed: %v", table, err)
+	}
+	return nil
+}
+
+func (rw *ColumnTransformRule) DetailColumnTransformRuleBySchema(ctx context.Context, detailS *ColumnTransformRule) ([]ColumnTransformRule, error) {
+	var transformRules []ColumnTransformRule
+
+	table, err := rw.ParseSchemaTable()
+	if err != nil {
+		return transformRules, err
+	}
+
+	if err = rw.DB(ctx).Where("UPPER(db_type_s) = ? AND UPPER(db_type_t) = ? AND UPPER(schema_name_s) = ?",
+		common.StringUPPER(detailS.DBTypeS),
+		common.StringUPPER(detailS.DBTypeT),
+		common.StringUPPER(detailS.SchemaNameS)).Find(&transformRules).Error; err != nil {
+		return transformRules, fmt.Errorf("detail table [%s] record failed: %v", table, err)
+	}
+
+	return transformRules, nil
+}
@@ -0,0 +1,326 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package mysql
+
+import (
+	"fmt"
+	"github.com/wentaojin/transferdb/common"
+	"strings"
+)
+
+// MySQL/TiDB 系统库，评估时排除
+const mysqlSystemSchema = `'mysql','information_schema','performance_schema','sys','metrics_schema'`
+
+func (m *MySQL) GetMySQLSchema(schemaName string) ([]string, error) {
+	var (
+		querySQL string
+		schemas  []string
+	)
+	if schemaName == "" {
+		querySQL = fmt.Sprintf(`SELECT SCHEMA_NAME FROM information_schema.SCHEMATA WHERE SCHEMA_NAME NOT IN (%s)`, mysqlSystemSchema)
+	} else {
+		querySQL = fmt.Sprintf(`SELECT SCHEMA_NAME FROM information_schema.SCHEMATA WHERE SCHEMA_NAME = '%s'`, schemaName)
+	}
+	_, res, err := Query(m.Ctx, m.MySQLDB, querySQL)
+	if err != nil {
+		return schemas, err
+	}
+	for _, r := range res {
+		schemas = append(schemas, r["SCHEMA_NAME"])
+	}
+	return schemas, nil
+}
+
+func (m *MySQL) GetMySQLDBOverview() (map[string]string, error) {
+	_, res, err := Query(m.Ctx, m.MySQLDB, `SELECT
+	@@hostname AS HOST_NAME,
+	@@port AS PORT,
+	@@version AS VERSION,
+	@@version_comment AS VERSION_COMMENT,
+	@@version_compile_os AS VERSION_COMPILE_OS,
+	@@version_compile_machine AS VERSION_COMPILE_MACHINE,
+	@@server_id AS SERVER_ID,
+	@@character_set_server AS CHARACTER_SET,
+	@@collation_server AS COLLATION`)
+	if err != nil {
+		return nil, err
+	}
+	return res[0], nil
+}
+
+// GetMySQLDataTotal 数据库数据以及索引总大小 GB，不包含系统库
+func (m *MySQL) GetMySQLDataTotal() (string, error) {
+	_, res, err := Query(m.Ctx, m.MySQLDB, fmt.Sprintf(`SELECT IFNULL(ROUND(SUM(DATA_LENGTH + INDEX_LENGTH) / 1024 / 1024 / 1024, 2), 0) AS DATA_SIZE
+FROM information_schema.TABLES WHERE TABLE_SCHEMA NOT IN (%s)`, mysqlSystemSchema))
+	if err != nil {
+		return "", err
+	}
+	return res[0]["DATA_SIZE"], nil
+}
+
+func (m *MySQL) GetMySQLActiveSessionCounts() (map[string]string, error) {
+	_, res, err := Query(m.Ctx, m.MySQLDB, `SELECT @@server_id AS SERVER_ID, NOW() AS SAMPLE_TIME, COUNT(1) AS COUNTS
+FROM information_schema.PROCESSLIST WHERE COMMAND <> 'Sleep'`)
+	if err != nil {
+		return nil, err
+	}
+	return res[0], nil
+}
+
+func (m *MySQL) GetMySQLSchemaTableTypeCounts(schemaName []string) ([]map[string]string, error) {
+	_, res, err := Query(m.Ctx, m.MySQLDB, fmt.Sprintf(`SELECT
+	TABLE_SCHEMA AS SCHEMA_NAME,
+	UPPER(IFNULL(ENGINE, 'UNKNOWN')) AS TABLE_TYPE,
+	IFNULL(ROUND(SUM(DATA_LENGTH + INDEX_LENGTH) / 1024 / 1024 / 1024, 2), 0) AS OBJECT_SIZE,
+	COUNT(1) AS COUNTS
+FROM information_schema.TABLES
+WHERE TABLE_TYPE = 'BASE TABLE' AND TABLE_SCHEMA IN (%s)
+GROUP BY TABLE_SCHEMA, UPPER(IFNULL(ENGINE, 'UNKNOWN'))`, strings.Join(schemaName, ",")))
+	if err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+func (m *MySQL) GetMySQLSchemaColumnTypeCounts(schemaName []string) ([]map[string]string, error) {
+	_, res, err := Query(m.Ctx, m.MySQLDB, fmt.Sprintf(`SELECT
+	C.TABLE_SCHEMA AS OWNER,
+	UPPER(C.DATA_TYPE) AS DATA_TYPE,
+	COUNT(1) AS COUNT,
+	IFNULL(MAX(IFNULL(C.CHARACTER_MAXIMUM_LENGTH, C.NUMERIC_PRECISION)), 0) AS MAX_DATA_LENGTH
+FROM information_schema.COLUMNS C, information_schema.TABLES T
+WHERE C.TABLE_SCHEMA = T.TABLE_SCHEMA AND C.TABLE_NAME = T.TABLE_NAME
+	AND T.TABLE_TYPE = 'BASE TABLE' AND C.TABLE_SCHEMA IN (%s)
+GROUP BY C.TABLE_SCHEMA, UPPER(C.DATA_TYPE)`, strings.Join(schemaName, ",")))
+	if err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+func (m *MySQL) GetMySQLSchemaConstraintTypeCounts(schemaName []string) ([]map[string]string, error) {
+	_, res, err := Query(m.Ctx, m.MySQLDB, fmt.Sprintf(`SELECT TABLE_SCHEMA AS OWNER, CONSTRAINT_TYPE, COUNT(1) AS COUNTS
+FROM information_schema.TABLE_CONSTRAINTS
+WHERE TABLE_SCHEMA IN (%s)
+GROUP BY TABLE_SCHEMA, CONSTRAINT_TYPE`, strings.Join(schemaName, ",")))
+	if err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+// GetMySQLSchemaIndexTypeCounts 主键以及唯一约束同时统计在约束类型
+func (m *MySQL) GetMySQLSchemaIndexTypeCounts(schemaName []string) ([]map[string]string, error) {
+	_, res, err := Query(m.Ctx, m.MySQLDB, fmt.Sprintf(`SELECT TABLE_SCHEMA AS OWNER, INDEX_TYPE, COUNT(DISTINCT TABLE_NAME, INDEX_NAME) AS COUNTS
+FROM information_schema.STATISTICS
+WHERE TABLE_SCHEMA IN (%s) AND INDEX_NAME <> 'PRIMARY'
+GROUP BY TABLE_SCHEMA, INDEX_TYPE`, strings.Join(schemaName, ",")))
+	if err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+// GetMySQLSchemaColumnDataDefaultCounts MySQL 8.0 表达式默认值 EXTRA 包含 DEFAULT_GENERATED
+func (m *MySQL) GetMySQLSchemaColumnDataDefaultCounts(schemaName []string) ([]map[string]string, error) {
+	_, res, err := Query(m.Ctx, m.MySQLDB, fmt.Sprintf(`SELECT
+	TABLE_SCHEMA AS OWNER,
+	COLUMN_DEFAULT AS DATA_DEFAULT,
+	IF(UPPER(EXTRA) LIKE '%%DEFAULT_GENERATED%%', 'YES', 'NO') AS IS_EXPRESSION,
+	COUNT(1) AS COUNTS
+FROM information_schema.COLUMNS
+WHERE TABLE_SCHEMA IN (%s) AND COLUMN_DEFAULT IS NOT NULL
+GROUP BY TABLE_SCHEMA, COLUMN_DEFAULT, IF(UPPER(EXTRA) LIKE '%%DEFAULT_GENERATED%%', 'YES', 'NO')`, strings.Join(schemaName, ",")))
+	if err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+func (m *MySQL) GetMySQLSchemaViewTypeCounts(schemaName []string) ([]map[string]string, error) {
+	_, res, err := Query(m.Ctx, m.MySQLDB, fmt.Sprintf(`SELECT TABLE_SCHEMA AS OWNER, '%s' AS VIEW_TYPE, SECURITY_TYPE AS VIEW_TYPE_OWNER, COUNT(1) AS COUNTS
+FROM information_schema.VIEWS
+WHERE TABLE_SCHEMA IN (%s)
+GROUP BY TABLE_SCHEMA, SECURITY_TYPE`, common.BuildInMySQLViewTypeView, strings.Join(schemaName, ",")))
+	if err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+func (m *MySQL) GetMySQLSchemaObjectTypeCounts(schemaName []string) ([]map[string]string, error) {
+	_, res, err := Query(m.Ctx, m.MySQLDB, fmt.Sprintf(`SELECT OWNER, OBJECT_TYPE, COUNT(1) AS COUNTS
+FROM (
+	SELECT ROUTINE_SCHEMA AS OWNER, ROUTINE_TYPE AS OBJECT_TYPE FROM information_schema.ROUTINES WHERE ROUTINE_SCHEMA IN (%[1]s)
+	UNION ALL
+	SELECT TRIGGER_SCHEMA AS OWNER, '%[2]s' AS OBJECT_TYPE FROM information_schema.TRIGGERS WHERE TRIGGER_SCHEMA IN (%[1]s)
+	UNION ALL
+	SELECT EVENT_SCHEMA AS OWNER, '%[3]s' AS OBJECT_TYPE FROM information_schema.EVENTS WHERE EVENT_SCHEMA IN (%[1]s)
+) T
+GROUP BY OWNER, OBJECT_TYPE`, strings.Join(schemaName, ","), common.BuildInMySQLCodeTypeTrigger, common.BuildInMySQLCodeTypeEvent))
+	if err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+func (m *MySQL) GetMySQLSchemaPartitionTypeCounts(schemaName []string) ([]map[string]string, error) {
+	_, res, err := Query(m.Ctx, m.MySQLDB, fmt.Sprintf(`SELECT TABLE_SCHEMA AS OWNER, PARTITION_METHOD AS PARTITIONING_TYPE, COUNT(DISTINCT TABLE_NAME) AS COUNTS
+FROM information_schema.PARTITIONS
+WHERE TABLE_SCHEMA IN (%s) AND PARTITION_METHOD IS NOT NULL
+GROUP BY TABLE_SCHEMA, PARTITION_METHOD`, strings.Join(schemaName, ",")))
+	if err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+func (m *MySQL) GetMySQLSchemaSubPartitionTypeCounts(schemaName []string) ([]map[string]string, error) {
+	_, res, err := Query(m.Ctx, m.MySQLDB, fmt.Sprintf(`SELECT TABLE_SCHEMA AS OWNER, SUBPARTITION_METHOD AS SUBPARTITIONING_TYPE, COUNT(DISTINCT TABLE_NAME) AS COUNTS
+FROM information_schema.PARTITIONS
+WHERE TABLE_SCHEMA IN (%s) AND SUBPARTITION_METHOD IS NOT NULL
+GROUP BY TABLE_SCHEMA, SUBPARTITION_METHOD`, strings.Join(schemaName, ",")))
+	if err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+func (m *MySQL) GetMySQLSchemaColumnCharsetCounts(schemaName []string) ([]map[string]string, error) {
+	_, res, err := Query(m.Ctx, m.MySQLDB, fmt.Sprintf(`SELECT
+	TABLE_SCHEMA AS OWNER,
+	UPPER(CHARACTER_SET_NAME) AS CHARACTER_SET,
+	UPPER(COLLATION_NAME) AS COLLATION,
+	COUNT(1) AS COUNTS
+FROM information_schema.COLUMNS
+WHERE TABLE_SCHEMA IN (%s) AND CHARACTER_SET_NAME IS NOT NULL
+GROUP BY TABLE_SCHEMA, UPPER(CHARACTER_SET_NAME), UPPER(COLLATION_NAME)`, strings.Join(schemaName, ",")))
+	if err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+// GetMySQLSchemaFeatureCounts 每个 schema 一行，字段名为特性名，值为字段数
+func (m *MySQL) GetMySQLSchemaFeatureCounts(schemaName []string) ([]map[string]string, error) {
+	_, res, err := Query(m.Ctx, m.MySQLDB, fmt.Sprintf("SELECT\n"+
+		"\tC.TABLE_SCHEMA AS OWNER,\n"+
+		"\tSUM(CASE WHEN UPPER(C.DATA_TYPE) = 'ENUM' THEN 1 ELSE 0 END) AS `%s`,\n"+
+		"\tSUM(CASE WHEN UPPER(C.DATA_TYPE) = 'SET' THEN 1 ELSE 0 END) AS `%s`,\n"+
+		"\tSUM(CASE WHEN UPPER(C.COLUMN_TYPE) LIKE '%%UNSIGNED%%' THEN 1 ELSE 0 END) AS `%s`,\n"+
+		"\tSUM(CASE WHEN UPPER(C.EXTRA) LIKE '%%AUTO_INCREMENT%%' THEN 1 ELSE 0 END) AS `%s`,\n"+
+		"\tSUM(CASE WHEN UPPER(C.EXTRA) LIKE '%%VIRTUAL GENERATED%%' THEN 1 ELSE 0 END) AS `%s`,\n"+
+		"\tSUM(CASE WHEN UPPER(C.EXTRA) LIKE '%%STORED GENERATED%%' THEN 1 ELSE 0 END) AS `%s`,\n"+
+		"\tSUM(CASE WHEN UPPER(C.EXTRA) LIKE '%%ON UPDATE%%' THEN 1 ELSE 0 END) AS `%s`\n"+
+		"FROM information_schema.COLUMNS C, information_schema.TABLES T\n"+
+		"WHERE C.TABLE_SCHEMA = T.TABLE_SCHEMA AND C.TABLE_NAME = T.TABLE_NAME\n"+
+		"\tAND T.TABLE_TYPE = 'BASE TABLE' AND C.TABLE_SCHEMA IN (%s)\n"+
+		"GROUP BY C.TABLE_SCHEMA",
+		common.BuildInMySQLFeatureEnum,
+		common.BuildInMySQLFeatureSet,
+		common.BuildInMySQLFeatureUnsigned,
+		common.BuildInMySQLFeatureAutoIncrement,
+		common.BuildInMySQLFeatureVirtualGenerated,
+		common.BuildInMySQLFeatureStoredGenerated,
+		common.BuildInMySQLFeatureOnUpdate,
+		strings.Join(schemaName, ",")))
+	if err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+// GetMySQLSchemaIdentifierCheck 表、视图、字段、索引名长度（bytes）超过 maxLength 或者属于保留字
+func (m *MySQL) GetMySQLSchemaIdentifierCheck(schemaName []string, maxLength int, reservedWords []string) ([]map[string]string, error) {
+	var words []string
+	for _, w := range reservedWords {
+		words = append(words, fmt.Sprintf("'%s'", common.StringUPPER(w)))
+	}
+	_, res, err := Query(m.Ctx, m.MySQLDB, fmt.Sprintf(`SELECT OWNER, OBJECT_TYPE, TABLE_NAME, OBJECT_NAME, LENGTH(OBJECT_NAME) AS LENGTH
+FROM (
+	SELECT TABLE_SCHEMA AS OWNER, IF(TABLE_TYPE = 'VIEW', 'VIEW', 'TABLE') AS OBJECT_TYPE, TABLE_NAME, TABLE_NAME AS OBJECT_NAME FROM information_schema.TABLES WHERE TABLE_SCHEMA IN (%[1]s)
+	UNION ALL
+	SELECT TABLE_SCHEMA AS OWNER, 'COLUMN' AS OBJECT_TYPE, TABLE_NAME, COLUMN_NAME AS OBJECT_NAME FROM information_schema.COLUMNS WHERE TABLE_SCHEMA IN (%[1]s)
+	UNION ALL
+	SELECT DISTINCT TABLE_SCHEMA AS OWNER, 'INDEX' AS OBJECT_TYPE, TABLE_NAME, INDEX_NAME AS OBJECT_NAME FROM information_schema.STATISTICS WHERE TABLE_SCHEMA IN (%[1]s) AND INDEX_NAME <> 'PRIMARY'
+) T
+WHERE LENGTH(OBJECT_NAME) > %[2]d OR UPPER(OBJECT_NAME) IN (%[3]s)
+ORDER BY OWNER, TABLE_NAME, OBJECT_TYPE, OBJECT_NAME`, strings.Join(schemaName, ","), maxLength, strings.Join(words, ",")))
+	if err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+func (m *MySQL) GetMySQLSchemaTableSizeData(schemaName []string) ([]map[string]string, error) {
+	_, res, err := Query(m.Ctx, m.MySQLDB, fmt.Sprintf(`SELECT
+	TABLE_SCHEMA AS OWNER,
+	IFNULL(ROUND(SUM(DATA_LENGTH) / 1024 / 1024 / 1024, 2), 0) AS TABLE_SIZE,
+	IFNULL(ROUND(SUM(INDEX_LENGTH) / 1024 / 1024 / 1024, 2), 0) AS INDEX_SIZE,
+	IFNULL(SUM(TABLE_ROWS), 0) AS ROWCOUNT
+FROM information_schema.TABLES
+WHERE TABLE_TYPE = 'BASE TABLE' AND TABLE_SCHEMA IN (%s)
+GROUP BY TABLE_SCHEMA`, strings.Join(schemaName, ",")))
+	if err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+func (m *MySQL) GetMySQLSchemaTableRowsTOP(schemaName []string) ([]map[string]string, error) {
+	_, res, err := Query(m.Ctx, m.MySQLDB, fmt.Sprintf(`SELECT
+	TABLE_SCHEMA AS OWNER,
+	TABLE_NAME,
+	UPPER(IFNULL(ENGINE, 'UNKNOWN')) AS TABLE_TYPE,
+	IFNULL(ROUND((DATA_LENGTH + INDEX_LENGTH) / 1024 / 1024 / 1024, 2), 0) AS TABLE_SIZE
+FROM information_schema.TABLES
+WHERE TABLE_TYPE = 'BASE TABLE' AND TABLE_SCHEMA IN (%s)
+ORDER BY DATA_LENGTH + INDEX_LENGTH DESC
+LIMIT 10`, strings.Join(schemaName, ",")))
+	if err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+// GetMySQLSchemaCodeObject 存储过程、函数、触发器以及事件代码行数，未授权查看定义时行数为 1
+func (m *MySQL) GetMySQLSchemaCodeObject(schemaName []string) ([]map[string]string, error) {
+	_, res, err := Query(m.Ctx, m.MySQLDB, fmt.Sprintf("SELECT OWNER, OBJECT_NAME, OBJECT_TYPE, LENGTH(DEFINITION) - LENGTH(REPLACE(DEFINITION, CHAR(10), '')) + 1 AS `LINES`\n"+
+		"FROM (\n"+
+		"\tSELECT ROUTINE_SCHEMA AS OWNER, ROUTINE_NAME AS OBJECT_NAME, ROUTINE_TYPE AS OBJECT_TYPE, IFNULL(ROUTINE_DEFINITION, '') AS DEFINITION FROM information_schema.ROUTINES WHERE ROUTINE_SCHEMA IN (%[1]s)\n"+
+		"\tUNION ALL\n"+
+		"\tSELECT TRIGGER_SCHEMA AS OWNER, TRIGGER_NAME AS OBJECT_NAME, '%[2]s' AS OBJECT_TYPE, IFNULL(ACTION_STATEMENT, '') AS DEFINITION FROM information_schema.TRIGGERS WHERE TRIGGER_SCHEMA IN (%[1]s)\n"+
+		"\tUNION ALL\n"+
+		"\tSELECT EVENT_SCHEMA AS OWNER, EVENT_NAME AS OBJECT_NAME, '%[3]s' AS OBJECT_TYPE, IFNULL(EVENT_DEFINITION, '') AS DEFINITION FROM information_schema.EVENTS WHERE EVENT_SCHEMA IN (%[1]s)\n"+
+		") T\n"+
+		"ORDER BY OWNER, OBJECT_TYPE, OBJECT_NAME", strings.Join(schemaName, ","), common.BuildInMySQLCodeTypeTrigger, common.BuildInMySQLCodeTypeEvent))
+	if err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+func (m *MySQL) GetMySQLSchemaTableAvgRowLengthTOP(schemaName []string) ([]map[string]string, error) {
+	_, res, err := Query(m.Ctx, m.MySQLDB, fmt.Sprintf(`SELECT TABLE_SCHEMA AS OWNER, TABLE_NAME, IFNULL(AVG_ROW_LENGTH, 0) AS AVG_ROW_LEN
+FROM information_schema.TABLES
+WHERE TABLE_TYPE = 'BASE TABLE' AND TABLE_SCHEMA IN (%s)
+ORDER BY AVG_ROW_LENGTH DESC
+LIMIT 10`, strings.Join(schemaName, ",")))
+	if err != nil {
+		return res, err
+	}
+	return res, nil
+}
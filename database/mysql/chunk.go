@@ -0,0 +1,260 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package mysql
+
+import (
+	"fmt"
+	"github.com/wentaojin/transferdb/common"
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TiDB 非聚簇表隐藏行 ID 字段
+const TiDBRowIDColumn = "_tidb_rowid"
+
+// TiDB Region 边界 Record Key，例如 t_75_r_1000
+var tidbRegionRecordKeyRegexp = regexp.MustCompile(`^t_\d+_r_(-?\d+)$`)
+
+// GetMySQLTableChunkColumn 获取表范围切分字段
+// 1、指定 chunkColumn，校验字段数据类型
+// 2、未指定，优先主键，其次唯一索引，选择单列整型数据类型字段
+func (m *MySQL) GetMySQLTableChunkColumn(schemaName, tableName, chunkColumn string) (string, error) {
+	var querySQL string
+	if !strings.EqualFold(chunkColumn, "") {
+		querySQL = fmt.Sprintf(`SELECT COLUMN_NAME, DATA_TYPE
+  FROM information_schema.COLUMNS
+ WHERE TABLE_SCHEMA = '%s'
+   AND TABLE_NAME = '%s'
+   AND UPPER(COLUMN_NAME) = UPPER('%s')`, schemaName, tableName, chunkColumn)
+	} else {
+		querySQL = fmt.Sprintf(`SELECT s.COLUMN_NAME, c.DATA_TYPE
+  FROM information_schema.STATISTICS s
+  JOIN information_schema.COLUMNS c
+    ON s.TABLE_SCHEMA = c.TABLE_SCHEMA
+   AND s.TABLE_NAME = c.TABLE_NAME
+   AND s.COLUMN_NAME = c.COLUMN_NAME
+ WHERE s.TABLE_SCHEMA = '%s'
+   AND s.TABLE_NAME = '%s'
+   AND s.NON_UNIQUE = 0
+   AND (SELECT COUNT(1) FROM information_schema.STATISTICS x WHERE x.TABLE_SCHEMA = s.TABLE_SCHEMA AND x.TABLE_NAME = s.TABLE_NAME AND x.INDEX_NAME = s.INDEX_NAME) = 1
+ ORDER BY IF(s.INDEX_NAME = 'PRIMARY', 0, 1), c.IS_NULLABLE, s.INDEX_NAME`, schemaName, tableName)
+	}
+
+	_, res, err := Query(m.Ctx, m.MySQLDB, querySQL)
+	if err != nil {
+		return "", err
+	}
+	for _, r := range res {
+		if IsMySQLChunkColumnDataType(r["DATA_TYPE"]) {
+			return r["COLUMN_NAME"], nil
+		}
+	}
+	if !strings.EqualFold(chunkColumn, "") {
+		return "", fmt.Errorf("mysql table [%s.%s] chunk column [%s] isn't exist or data type isn't support, support data type [TINYINT SMALLINT MEDIUMINT INT BIGINT]",
+			schemaName, tableName, chunkColumn)
+	}
+	return "", nil
+}
+
+// GetMySQLTableChunksByRange 基于整型字段最小值、最大值等距切分 chunk，chunk 左闭右开且首尾开区间
+// 字段值分布不均匀只影响 chunk 均匀度不影响数据完整性
+func (m *MySQL) GetMySQLTableChunksByRange(schemaName, tableName, columnName string, tableRows, chunkSize int) ([]map[string]string, error) {
+	var res []map[string]string
+	if chunkSize <= 0 || tableRows <= chunkSize {
+		return res, nil
+	}
+	buckets := int64((tableRows + chunkSize - 1) / chunkSize)
+
+	column := common.StringsBuilder("`", columnName, "`")
+	_, boundaryRes, err := Query(m.Ctx, m.MySQLDB, common.StringsBuilder(`SELECT IFNULL(MIN(`, column, `),'') MIN_VALUE, IFNULL(MAX(`, column, `),'') MAX_VALUE FROM `,
+		"`", schemaName, "`.`", tableName, "`"))
+	if err != nil {
+		return res, err
+	}
+	if len(boundaryRes) == 0 || strings.EqualFold(boundaryRes[0]["MIN_VALUE"], "") {
+		return res, nil
+	}
+
+	// 无符号 BIGINT 超出 int64 范围，统一 big.Int 计算
+	minValue, ok := new(big.Int).SetString(boundaryRes[0]["MIN_VALUE"], 10)
+	if !ok {
+		return res, fmt.Errorf("mysql table [%s.%s] chunk column [%s] min value [%s] strconv failed", schemaName, tableName, columnName, boundaryRes[0]["MIN_VALUE"])
+	}
+	maxValue, ok := new(big.Int).SetString(boundaryRes[0]["MAX_VALUE"], 10)
+	if !ok {
+		return res, fmt.Errorf("mysql table [%s.%s] chunk column [%s] max value [%s] strconv failed", schemaName, tableName, columnName, boundaryRes[0]["MAX_VALUE"])
+	}
+
+	return genMySQLRangeChunks(column, minValue, maxValue, buckets), nil
+}
+
+// genMySQLRangeChunks 最小值、最大值等距切分 chunk，最小值等于最大值（统计信息过期或者唯一索引仅单个非 NULL 值）无切分边界返回空，调用方回退全表扫
+// 唯一索引允许 NULL 值，仅存在范围 chunk 时补充 IS NULL chunk，避免表只剩 IS NULL chunk 遗漏非 NULL 数据
+func genMySQLRangeChunks(column string, minValue, maxValue *big.Int, buckets int64) []map[string]string {
+	var res []map[string]string
+	if buckets <= 0 {
+		return res
+	}
+	step := new(big.Int).Sub(maxValue, minValue)
+	step.Add(step, big.NewInt(buckets))
+	step.Div(step, big.NewInt(buckets))
+	if step.Sign() <= 0 {
+		return res
+	}
+
+	var boundaries []string
+	b := new(big.Int).Add(minValue, step)
+	for b.Cmp(maxValue) <= 0 {
+		boundaries = append(boundaries, b.String())
+		b.Add(b, step)
+	}
+	if len(boundaries) == 0 {
+		return res
+	}
+
+	res = genMySQLChunkRanges(column, boundaries)
+	res = append(res, map[string]string{
+		"CMD": common.StringsBuilder(column, ` IS NULL`),
+	})
+	return res
+}
+
+// IsMySQLChunkColumnDataType 范围切分支持整型数据类型
+func IsMySQLChunkColumnDataType(dataType string) bool {
+	switch common.StringUPPER(dataType) {
+	case "TINYINT", "SMALLINT", "MEDIUMINT", "INT", "INTEGER", "BIGINT":
+		return true
+	default:
+		return false
+	}
+}
+
+// GetTiDBTableHandleColumn 获取 TiDB 表 Region Record Key 对应字段
+// 1、聚簇整型主键表，返回主键字段
+// 2、非聚簇表，返回 _tidb_rowid
+// 3、聚簇非整型主键表（Common Handle），Record Key 非整型，返回空
+func (m *MySQL) GetTiDBTableHandleColumn(schemaName, tableName string) (string, error) {
+	_, res, err := Query(m.Ctx, m.MySQLDB, fmt.Sprintf(`SELECT IFNULL(TIDB_PK_TYPE,'NONCLUSTERED') TIDB_PK_TYPE FROM information_schema.TABLES WHERE TABLE_SCHEMA = '%s' AND TABLE_NAME = '%s'`, schemaName, tableName))
+	if err != nil {
+		return "", err
+	}
+	if len(res) == 0 {
+		return "", fmt.Errorf("tidb table [%s.%s] isn't exist", schemaName, tableName)
+	}
+	if !strings.EqualFold(res[0]["TIDB_PK_TYPE"], "CLUSTERED") {
+		return TiDBRowIDColumn, nil
+	}
+
+	pkRes, err := m.GetMySQLTablePrimaryKey(schemaName, tableName)
+	if err != nil {
+		return "", err
+	}
+	if len(pkRes) != 1 || strings.Contains(pkRes[0]["COLUMN_LIST"], ",") {
+		return "", nil
+	}
+	columnName := strings.Trim(strings.TrimSpace(pkRes[0]["COLUMN_LIST"]), "`")
+	_, colRes, err := Query(m.Ctx, m.MySQLDB, fmt.Sprintf(`SELECT DATA_TYPE AS DATA_TYPE FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = '%s' AND TABLE_NAME = '%s' AND COLUMN_NAME = '%s'`, schemaName, tableName, columnName))
+	if err != nil {
+		return "", err
+	}
+	if len(colRes) == 0 {
+		return "", fmt.Errorf("tidb table [%s.%s] primary key column [%s] isn't exist", schemaName, tableName, columnName)
+	}
+	if !IsMySQLChunkColumnDataType(colRes[0]["DATA_TYPE"]) {
+		return "", nil
+	}
+	return columnName, nil
+}
+
+// GetTiDBTableChunksByRegion 基于 TiDB 表 Region 边界切分 chunk，chunk 左闭右开且首尾开区间
+func (m *MySQL) GetTiDBTableChunksByRegion(schemaName, tableName, handleColumn string) ([]map[string]string, error) {
+	var res []map[string]string
+	_, regionRes, err := Query(m.Ctx, m.MySQLDB, common.StringsBuilder("SHOW TABLE `", schemaName, "`.`", tableName, "` REGIONS"))
+	if err != nil {
+		return res, err
+	}
+
+	var handles []int64
+	for _, r := range regionRes {
+		matches := tidbRegionRecordKeyRegexp.FindStringSubmatch(r["START_KEY"])
+		if len(matches) != 2 {
+			continue
+		}
+		h, err := strconv.ParseInt(matches[1], 10, 64)
+		if err != nil {
+			return res, fmt.Errorf("tidb table [%s.%s] region start key [%s] strconv failed: %v", schemaName, tableName, r["START_KEY"], err)
+		}
+		handles = append(handles, h)
+	}
+	if len(handles) == 0 {
+		return res, nil
+	}
+	sort.Slice(handles, func(i, j int) bool { return handles[i] < handles[j] })
+
+	var boundaries []string
+	for i, h := range handles {
+		if i > 0 && handles[i-1] == h {
+			continue
+		}
+		boundaries = append(boundaries, strconv.FormatInt(h, 10))
+	}
+
+	column := handleColumn
+	if !strings.EqualFold(handleColumn, TiDBRowIDColumn) {
+		column = common.StringsBuilder("`", handleColumn, "`")
+	}
+	return genMySQLChunkRanges(column, boundaries), nil
+}
+
+// GetTiDBCurrentTSO 获取 TiDB 当前 TSO，用于一致性读
+func (m *MySQL) GetTiDBCurrentTSO() (uint64, error) {
+	_, res, err := Query(m.Ctx, m.MySQLDB, `SHOW MASTER STATUS`)
+	if err != nil {
+		return 0, err
+	}
+	if len(res) == 0 {
+		return 0, fmt.Errorf("tidb show master status return empty")
+	}
+	tso, err := strconv.ParseUint(res[0]["Position"], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("tidb current tso [%s] strconv failed: %v", res[0]["Position"], err)
+	}
+	return tso, nil
+}
+
+func genMySQLChunkRanges(column string, boundaries []string) []map[string]string {
+	var res []map[string]string
+	for i, b := range boundaries {
+		if i == 0 {
+			res = append(res, map[string]string{
+				"CMD": common.StringsBuilder(column, ` < `, b),
+			})
+		}
+		if i == len(boundaries)-1 {
+			res = append(res, map[string]string{
+				"CMD": common.StringsBuilder(column, ` >= `, b),
+			})
+		} else {
+			res = append(res, map[string]string{
+				"CMD": common.StringsBuilder(column, ` >= `, b, ` AND `, column, ` < `, boundaries[i+1]),
+			})
+		}
+	}
+	return res
+}
@@ -0,0 +1,96 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package mysql
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func TestGenMySQLRangeChunks(t *testing.T) {
+	cases := []struct {
+		name     string
+		min      string
+		max      string
+		buckets  int64
+		expected []string
+	}{
+		{
+			name:     "min equal max",
+			min:      "5",
+			max:      "5",
+			buckets:  4,
+			expected: nil,
+		},
+		{
+			name:     "zero bucket",
+			min:      "1",
+			max:      "100",
+			buckets:  0,
+			expected: nil,
+		},
+		{
+			name:    "adjacent values",
+			min:     "1",
+			max:     "2",
+			buckets: 10,
+			expected: []string{
+				"`id` < 2",
+				"`id` >= 2",
+				"`id` IS NULL",
+			},
+		},
+		{
+			name:    "even split",
+			min:     "1",
+			max:     "100",
+			buckets: 4,
+			expected: []string{
+				"`id` < 26",
+				"`id` >= 26 AND `id` < 51",
+				"`id` >= 51 AND `id` < 76",
+				"`id` >= 76",
+				"`id` IS NULL",
+			},
+		},
+		{
+			name:    "unsigned bigint",
+			min:     "18446744073709551613",
+			max:     "18446744073709551615",
+			buckets: 2,
+			expected: []string{
+				"`id` < 18446744073709551615",
+				"`id` >= 18446744073709551615",
+				"`id` IS NULL",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			minValue, _ := new(big.Int).SetString(c.min, 10)
+			maxValue, _ := new(big.Int).SetString(c.max, 10)
+			var got []string
+			for _, r := range genMySQLRangeChunks("`id`", minValue, maxValue, c.buckets) {
+				got = append(got, r["CMD"])
+			}
+			if !reflect.DeepEqual(got, c.expected) {
+				t.Fatalf("range chunks %v, want %v", got, c.expected)
+			}
+		})
+	}
+}
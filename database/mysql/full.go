@@ -16,7 +16,13 @@ limitations under the License.
 package mysql
 
 import (
+	"database/sql"
 	"fmt"
+	"github.com/wentaojin/transferdb/common"
+	"github.com/wentaojin/transferdb/throttle"
+	"github.com/wentaojin/transferdb/transform"
+	"strconv"
+	"strings"
 )
 
 func (m *MySQL) TruncateMySQLTable(targetSchema string, targetTable string) error {
@@ -34,3 +40,173 @@ func (m *MySQL) WriteMySQLTable(sql string) error {
 	}
 	return nil
 }
+
+// GetMySQLActualSchemaName 获取源端 schema 实际名称，配置 schema 统一大写，大小写敏感（lower_case_table_names=0）需基于实际名称访问
+func (m *MySQL) GetMySQLActualSchemaName(schemaName string) (string, error) {
+	_, res, err := Query(m.Ctx, m.MySQLDB, fmt.Sprintf(`SELECT SCHEMA_NAME AS SCHEMA_NAME FROM information_schema.SCHEMATA WHERE UPPER(SCHEMA_NAME) = UPPER('%s')`, schemaName))
+	if err != nil {
+		return "", err
+	}
+	switch len(res) {
+	case 0:
+		return "", fmt.Errorf("mysql schema [%s] isn't exist", schemaName)
+	case 1:
+		return res[0]["SCHEMA_NAME"], nil
+	default:
+		for _, r := range res {
+			if r["SCHEMA_NAME"] == schemaName {
+				return r["SCHEMA_NAME"], nil
+			}
+		}
+		return "", fmt.Errorf("mysql schema [%s] matches multiple case-sensitive schemas [%d], please config actual schema name", schemaName, len(res))
+	}
+}
+
+// GetMySQLTableRowsByStatistics 基于统计信息获取表数据行数
+func (m *MySQL) GetMySQLTableRowsByStatistics(schemaName, tableName string) (int, error) {
+	_, res, err := Query(m.Ctx, m.MySQLDB, fmt.Sprintf(`SELECT IFNULL(TABLE_ROWS,0) TABLE_ROWS FROM information_schema.TABLES WHERE TABLE_SCHEMA = '%s' AND TABLE_NAME = '%s'`, schemaName, tableName))
+	if err != nil {
+		return 0, err
+	}
+	if len(res) == 0 {
+		return 0, fmt.Errorf("mysql table [%s.%s] isn't exist", schemaName, tableName)
+	}
+	rows, err := strconv.Atoi(res[0]["TABLE_ROWS"])
+	if err != nil {
+		return 0, fmt.Errorf("mysql table [%s.%s] table_rows [%s] strconv failed: %v", schemaName, tableName, res[0]["TABLE_ROWS"], err)
+	}
+	return rows, nil
+}
+
+// GetMySQLTableRowsData 表数据按行读取，字段值按查询字段顺序输出，按行数或者批次字节数写入通道
+// 注意 Oracle 空字符串与 NULL 归于一类，NULL 统一输出空字符串
+// 非二进制字段按源端字符集转换为目标端字符集，二进制字段原样输出
+func (m *MySQL) GetMySQLTableRowsData(querySQL string, insertBatchSize, insertBatchBytes int, sourceDBCharset, targetDBCharset string, limiter *throttle.TableLimiter, transformer *transform.TableTransformer, dataChan chan [][]string) error {
+	var (
+		err        error
+		batchBytes int
+	)
+
+	rows, err := m.MySQLDB.QueryContext(m.Ctx, querySQL)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	colTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return err
+	}
+	var (
+		columnNames   []string
+		databaseTypes []string
+	)
+	for _, ct := range colTypes {
+		columnNames = append(columnNames, ct.Name())
+		databaseTypes = append(databaseTypes, ct.DatabaseTypeName())
+	}
+
+	columns := len(columnNames)
+	rawResult := make([]sql.RawBytes, columns)
+	dest := make([]interface{}, columns)
+	for i := range rawResult {
+		dest[i] = &rawResult[i]
+	}
+
+	// 临时数据存放
+	var rowsTMP [][]string
+
+	for rows.Next() {
+		if err = rows.Scan(dest...); err != nil {
+			return err
+		}
+
+		rowValues := make([]string, columns)
+		for i, raw := range rawResult {
+			// 源端抽取字节数，用于限速
+			batchBytes += len(raw)
+
+			if IsMySQLBinaryType(databaseTypes[i]) && !transformer.IsTransform(columnNames[i]) {
+				rowValues[i] = string(raw)
+				continue
+			}
+
+			var value string
+			if raw != nil {
+				convertUtf8Raw, err := common.CharsetConvert(raw, sourceDBCharset, common.CharsetUTF8MB4)
+				if err != nil {
+					return fmt.Errorf("column [%s] charset convert failed, %v", columnNames[i], err)
+				}
+				value = string(convertUtf8Raw)
+			}
+
+			// 字段转换（脱敏）规则，转换结果统一按字符处理
+			value, isNull := transformer.Transform(columnNames[i], value, raw == nil)
+			if isNull || strings.EqualFold(value, "") {
+				continue
+			}
+
+			convertTargetRaw, err := common.CharsetConvert([]byte(value), common.CharsetUTF8MB4, targetDBCharset)
+			if err != nil {
+				return fmt.Errorf("column [%s] charset convert failed, %v", columnNames[i], err)
+			}
+			rowValues[i] = string(convertTargetRaw)
+		}
+
+		rowsTMP = append(rowsTMP, rowValues)
+
+		// batch 批次，按行数或者批次字节数（LOB 大字段）
+		if len(rowsTMP) == insertBatchSize || (insertBatchBytes > 0 && batchBytes >= insertBatchBytes) {
+			// 源端限速，等待期间暂停游标 fetch
+			if err = limiter.Wait(m.Ctx, len(rowsTMP), batchBytes); err != nil {
+				return err
+			}
+			batchBytes = 0
+
+			dataChan <- rowsTMP
+
+			// 数组清空
+			rowsTMP = make([][]string, 0)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	// 非 batch 批次
+	if len(rowsTMP) > 0 {
+		if err = limiter.Wait(m.Ctx, len(rowsTMP), batchBytes); err != nil {
+			return err
+		}
+		dataChan <- rowsTMP
+	}
+	return nil
+}
+
+// IsMySQLBinaryType 二进制以及 BIT 字段，不做字符集转换
+func IsMySQLBinaryType(databaseType string) bool {
+	switch common.StringUPPER(databaseType) {
+	case "BIT", "BINARY", "VARBINARY", "TINYBLOB", "BLOB", "MEDIUMBLOB", "LONGBLOB":
+		return true
+	default:
+		return false
+	}
+}
+
+// GetMySQLSessionLoad 源端负载采样，用于自适应限速
+// 返回活跃用户会话数（不包含当前连接用户会话），MySQL/TiDB 不提供会话等待时间，统一返回 0
+func (m *MySQL) GetMySQLSessionLoad() (int, float64, error) {
+	_, res, err := Query(m.Ctx, m.MySQLDB, `SELECT COUNT(1) ACTIVE_SESSIONS FROM information_schema.PROCESSLIST WHERE COMMAND <> 'Sleep' AND ID <> CONNECTION_ID()`)
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(res) == 0 {
+		return 0, 0, nil
+	}
+	activeSessions, err := strconv.Atoi(res[0]["ACTIVE_SESSIONS"])
+	if err != nil {
+		return 0, 0, fmt.Errorf("mysql active sessions [%s] strconv failed: %v", res[0]["ACTIVE_SESSIONS"], err)
+	}
+	return activeSessions, 0, nil
+}
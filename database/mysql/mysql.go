@@ -19,12 +19,15 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	_ "github.com/go-sql-driver/mysql"
+	mysqldriver "github.com/go-sql-driver/mysql"
 	"github.com/wentaojin/transferdb/common"
 	"github.com/wentaojin/transferdb/config"
 	"strings"
 )
 
+// MySQL/TiDB TLS 配置注册名，连接参数 tls=${name}
+const mysqlTLSConfigName = "transferdb-mysql"
+
 type MySQL struct {
 	Ctx     context.Context
 	MySQLDB *sql.DB
@@ -34,6 +37,16 @@ func NewMySQLDBEngine(ctx context.Context, mysqlCfg config.MySQLConfig) (*MySQL,
 	if !strings.EqualFold(mysqlCfg.Charset, "") {
 		mysqlCfg.ConnectParams = fmt.Sprintf("charset=%s&%s", strings.ToLower(mysqlCfg.Charset), mysqlCfg.ConnectParams)
 	}
+	if mysqlCfg.TLS.IsEnable() {
+		tlsCfg, err := mysqlCfg.TLS.Build(mysqlCfg.Host)
+		if err != nil {
+			return nil, fmt.Errorf("error on mysql tls config: %v", err)
+		}
+		if err = mysqldriver.RegisterTLSConfig(mysqlTLSConfigName, tlsCfg); err != nil {
+			return nil, fmt.Errorf("error on register mysql tls config: %v", err)
+		}
+		mysqlCfg.ConnectParams = fmt.Sprintf("tls=%s&%s", mysqlTLSConfigName, mysqlCfg.ConnectParams)
+	}
 	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/?%s",
 		mysqlCfg.Username, mysqlCfg.Password, mysqlCfg.Host, mysqlCfg.Port, mysqlCfg.ConnectParams)
 
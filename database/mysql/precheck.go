@@ -0,0 +1,38 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package mysql
+
+// 服务端字符集以及当前会话 sql_mode（包含 connect-params 会话参数）
+func (m *MySQL) GetMySQLCharsetAndSQLMode() (string, string, error) {
+	_, res, err := Query(m.Ctx, m.MySQLDB, `SELECT @@character_set_server AS CHARSET, @@SESSION.sql_mode AS SQL_MODE`)
+	if err != nil {
+		return "", "", err
+	}
+	return res[0]["CHARSET"], res[0]["SQL_MODE"], nil
+}
+
+// 当前用户授权语句
+func (m *MySQL) GetMySQLCurrentUserGrants() ([]string, error) {
+	var grants []string
+	cols, res, err := Query(m.Ctx, m.MySQLDB, `SHOW GRANTS`)
+	if err != nil {
+		return grants, err
+	}
+	for _, r := range res {
+		grants = append(grants, r[cols[0]])
+	}
+	return grants, nil
+}
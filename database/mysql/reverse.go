@@ -143,6 +143,37 @@ func (m *MySQL) GetMySQLNormalTable(schemaName string) ([]string, error) {
 	return tables, nil
 }
 
+// GetMySQLSchemaTableAttribute 表对象属性，用于表选择对象类型、大小以及行数过滤，MySQL/TiDB 不存在临时表以及索引组织表，行数基于统计信息 TABLE_ROWS
+func (m *MySQL) GetMySQLSchemaTableAttribute(schemaName string) ([]map[string]string, error) {
+	_, res, err := Query(m.Ctx, m.MySQLDB, fmt.Sprintf(`SELECT t.TABLE_NAME,
+	IF(LOWER(IFNULL(t.CREATE_OPTIONS, '')) LIKE '%%partitioned%%', 'Y', 'N') AS IS_PARTITIONED,
+	'N' AS IS_TEMPORARY,
+	'N' AS IS_IOT,
+	IF(l.TABLE_NAME IS NULL, 'N', 'Y') AS HAS_LOB,
+	IF(c.TABLE_NAME IS NULL, 'N', 'Y') AS HAS_PK,
+	IFNULL(t.TABLE_ROWS, 0) AS TABLE_ROWS,
+	ROUND(IFNULL(t.DATA_LENGTH, 0) / 1024 / 1024, 2) AS TABLE_SIZE
+FROM INFORMATION_SCHEMA.TABLES t
+LEFT JOIN (
+	SELECT DISTINCT TABLE_SCHEMA, TABLE_NAME
+	FROM INFORMATION_SCHEMA.COLUMNS
+	WHERE UPPER(TABLE_SCHEMA) = '%[1]s'
+	AND LOWER(DATA_TYPE) IN ('tinyblob', 'blob', 'mediumblob', 'longblob', 'tinytext', 'text', 'mediumtext', 'longtext', 'json')
+) l ON t.TABLE_SCHEMA = l.TABLE_SCHEMA AND t.TABLE_NAME = l.TABLE_NAME
+LEFT JOIN (
+	SELECT TABLE_SCHEMA, TABLE_NAME
+	FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS
+	WHERE UPPER(TABLE_SCHEMA) = '%[1]s'
+	AND CONSTRAINT_TYPE = 'PRIMARY KEY'
+) c ON t.TABLE_SCHEMA = c.TABLE_SCHEMA AND t.TABLE_NAME = c.TABLE_NAME
+WHERE UPPER(t.TABLE_SCHEMA) = '%[1]s'
+AND t.TABLE_TYPE = 'BASE TABLE'`, strings.ToUpper(schemaName)))
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
 func (m *MySQL) GetMySQLPartitionTable(schemaName string) ([]string, error) {
 	_, res, err := Query(m.Ctx, m.MySQLDB, fmt.Sprintf(`SELECT DISTINCT TABLE_NAME FROM INFORMATION_SCHEMA.PARTITIONS WHERE UPPER(TABLE_SCHEMA) = '%s' AND PARTITION_NAME IS NOT NULL`, strings.ToUpper(schemaName)))
 	if err != nil {
@@ -16,6 +16,7 @@ limitations under the License.
 package oracle
 
 import (
+	"database/sql"
 	"fmt"
 	"strings"
 )
@@ -313,6 +314,107 @@ func (o *Oracle) GetOracleSchemaCodeObject(schemaName []string) ([]map[string]st
 	return res, nil
 }
 
+// GetOracleSchemaCodeSource 按对象逐个拼接 DBA_SOURCE 源码回调处理，避免全部源码一次性读入内存
+func (o *Oracle) GetOracleSchemaCodeSource(schemaName []string, fn func(owner, name, objType, source string) error) error {
+	querySQL := fmt.Sprintf(`SELECT OWNER,NAME,TYPE,TEXT FROM DBA_SOURCE WHERE OWNER IN (%s) ORDER BY OWNER,NAME,TYPE,LINE`, strings.Join(schemaName, ","))
+
+	rows, err := o.OracleDB.QueryContext(o.Ctx, querySQL)
+	if err != nil {
+		return fmt.Errorf("general sql [%v] query failed: [%v]", querySQL, err.Error())
+	}
+	defer rows.Close()
+
+	var (
+		owner, name, objType string
+		source               strings.Builder
+	)
+	for rows.Next() {
+		var (
+			ow, na, ty string
+			text       sql.NullString
+		)
+		if err = rows.Scan(&ow, &na, &ty, &text); err != nil {
+			return fmt.Errorf("general sql [%v] query rows.Scan failed: [%v]", querySQL, err.Error())
+		}
+		if ow != owner || na != name || ty != objType {
+			if source.Len() > 0 {
+				if err = fn(owner, name, objType, source.String()); err != nil {
+					return err
+				}
+			}
+			owner, name, objType = ow, na, ty
+			source.Reset()
+		}
+		source.WriteString(text.String)
+	}
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("general sql [%v] query rows.Next failed: [%v]", querySQL, err.Error())
+	}
+	if source.Len() > 0 {
+		return fn(owner, name, objType, source.String())
+	}
+	return nil
+}
+
+// GetOracleSchemaTopSQLFromSQLArea 共享池 V$SQLAREA 按执行耗时获取 TOP SQL（DML 以及查询）
+func (o *Oracle) GetOracleSchemaTopSQLFromSQLArea(schemaName []string, topN int) ([]map[string]string, error) {
+	querySQL := fmt.Sprintf(`SELECT * FROM (
+SELECT
+	SQL_ID,
+	PARSING_SCHEMA_NAME,
+	EXECUTIONS,
+	ELAPSED_TIME,
+	SQL_FULLTEXT
+FROM
+	V$SQLAREA
+WHERE
+	PARSING_SCHEMA_NAME IN (%s)
+	AND COMMAND_TYPE IN (2,3,6,7,189)
+ORDER BY ELAPSED_TIME DESC) WHERE ROWNUM <= %d`, strings.Join(schemaName, ","), topN)
+
+	_, res, err := Query(o.Ctx, o.OracleDB, querySQL)
+	if err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+// GetOracleSchemaTopSQLFromAWR AWR 历史快照 DBA_HIST_SQLSTAT/DBA_HIST_SQLTEXT 按执行耗时获取 TOP SQL，需 Diagnostics Pack 授权
+func (o *Oracle) GetOracleSchemaTopSQLFromAWR(schemaName []string, topN int) ([]map[string]string, error) {
+	querySQL := fmt.Sprintf(`SELECT * FROM (
+SELECT
+	X.SQL_ID,
+	X.PARSING_SCHEMA_NAME,
+	X.EXECUTIONS,
+	X.ELAPSED_TIME,
+	T.SQL_TEXT SQL_FULLTEXT
+FROM
+	(
+	SELECT
+		DBID,
+		SQL_ID,
+		MAX(PARSING_SCHEMA_NAME) PARSING_SCHEMA_NAME,
+		SUM(EXECUTIONS_DELTA) EXECUTIONS,
+		SUM(ELAPSED_TIME_DELTA) ELAPSED_TIME
+	FROM
+		DBA_HIST_SQLSTAT
+	WHERE
+		PARSING_SCHEMA_NAME IN (%s)
+	GROUP BY DBID,SQL_ID) X,
+	DBA_HIST_SQLTEXT T
+WHERE
+	X.DBID = T.DBID
+	AND X.SQL_ID = T.SQL_ID
+	AND T.COMMAND_TYPE IN (2,3,6,7,189)
+ORDER BY X.ELAPSED_TIME DESC) WHERE ROWNUM <= %d`, strings.Join(schemaName, ","), topN)
+
+	_, res, err := Query(o.Ctx, o.OracleDB, querySQL)
+	if err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
 func (o *Oracle) GetOracleSchemaPartitionObjectType(schemaName []string) ([]map[string]string, error) {
 	querySQL := fmt.Sprintf(`SELECT DISTINCT
 	OWNER,
@@ -0,0 +1,184 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package oracle
+
+import (
+	"fmt"
+	"github.com/wentaojin/transferdb/common"
+	"strconv"
+	"strings"
+)
+
+// NTILE 切分每个分桶最少采样行数，用于计算 SAMPLE 采样比例
+const ChunkNTILESampleRowsPerBucket = 1000
+
+// GetOracleTableChunkColumn 获取表 NTILE 切分字段以及数据类型
+// 1、指定 chunkColumn，校验字段数据类型
+// 2、未指定，优先主键，其次唯一索引，选择单列 NUMBER/DATE/TIMESTAMP 数据类型字段
+func (o *Oracle) GetOracleTableChunkColumn(schemaName, tableName, chunkColumn string) (string, string, error) {
+	var querySQL string
+	if !strings.EqualFold(chunkColumn, "") {
+		querySQL = fmt.Sprintf(`SELECT t.COLUMN_NAME, t.DATA_TYPE
+  FROM dba_tab_columns t
+ WHERE t.owner = '%s'
+   AND t.table_name = '%s'
+   AND t.column_name = '%s'`,
+			common.StringUPPER(schemaName),
+			common.StringUPPER(tableName),
+			common.StringUPPER(chunkColumn))
+	} else {
+		querySQL = fmt.Sprintf(`SELECT ic.COLUMN_NAME, t.DATA_TYPE
+  FROM dba_indexes i
+  JOIN dba_ind_columns ic
+    ON i.owner = ic.index_owner
+   AND i.index_name = ic.index_name
+  JOIN dba_tab_columns t
+    ON ic.table_owner = t.owner
+   AND ic.table_name = t.table_name
+   AND ic.column_name = t.column_name
+  LEFT JOIN dba_constraints c
+    ON i.table_owner = c.owner
+   AND i.table_name = c.table_name
+   AND i.index_name = c.index_name
+   AND c.constraint_type = 'P'
+ WHERE i.table_owner = '%s'
+   AND i.table_name = '%s'
+   AND i.uniqueness = 'UNIQUE'
+   AND (SELECT COUNT(1) FROM dba_ind_columns x WHERE x.index_owner = i.owner AND x.index_name = i.index_name) = 1
+ ORDER BY DECODE(c.constraint_type, 'P', 0, 1), t.nullable, i.index_name`,
+			common.StringUPPER(schemaName),
+			common.StringUPPER(tableName))
+	}
+
+	_, res, err := Query(o.Ctx, o.OracleDB, querySQL)
+	if err != nil {
+		return "", "", err
+	}
+	for _, r := range res {
+		if IsOracleChunkColumnDataType(r["DATA_TYPE"]) {
+			return r["COLUMN_NAME"], common.StringUPPER(r["DATA_TYPE"]), nil
+		}
+	}
+	if !strings.EqualFold(chunkColumn, "") {
+		return "", "", fmt.Errorf("oracle table [%s.%s] chunk column [%s] isn't exist or data type isn't support, support data type [NUMBER DATE TIMESTAMP]",
+			schemaName, tableName, chunkColumn)
+	}
+	return "", "", fmt.Errorf("oracle table [%s.%s] isn't exist single column primary key or unique index with data type [NUMBER DATE TIMESTAMP], please configure chunk-column or chunk-strategy = 'rowid'",
+		schemaName, tableName)
+}
+
+// GetOracleTableChunksByNTILE 基于字段 NTILE 分桶获取 chunk 边界切分，不依赖 DBMS_PARALLEL_EXECUTE
+// 表数据量大于采样行数时基于 SAMPLE 采样计算边界，chunk 左闭右开且首尾开区间，边界只影响 chunk 均匀度不影响数据完整性
+func (o *Oracle) GetOracleTableChunksByNTILE(schemaName, tableName, columnName, dataType string, tableRows, chunkSize int) ([]map[string]string, error) {
+	var res []map[string]string
+	if chunkSize <= 0 || tableRows <= chunkSize {
+		return res, nil
+	}
+	buckets := (tableRows + chunkSize - 1) / chunkSize
+
+	var sampleSQL string
+	sampleRows := buckets * ChunkNTILESampleRowsPerBucket
+	if sampleRows < tableRows {
+		samplePercent := float64(sampleRows) * 100 / float64(tableRows)
+		if samplePercent < 0.000001 {
+			samplePercent = 0.000001
+		}
+		sampleSQL = common.StringsBuilder(` SAMPLE (`, strconv.FormatFloat(samplePercent, 'f', 6, 64), `)`)
+	}
+
+	var boundaryCol string
+	switch {
+	case strings.EqualFold(dataType, "DATE"):
+		boundaryCol = common.StringsBuilder(`TO_CHAR(MIN(`, columnName, `), 'YYYY-MM-DD HH24:MI:SS')`)
+	case strings.HasPrefix(common.StringUPPER(dataType), "TIMESTAMP"):
+		boundaryCol = common.StringsBuilder(`TO_CHAR(MIN(`, columnName, `), 'YYYY-MM-DD HH24:MI:SS.FF9')`)
+	default:
+		// 固定小数点符号，避免受 NLS_NUMERIC_CHARACTERS 影响
+		boundaryCol = common.StringsBuilder(`TO_CHAR(MIN(`, columnName, `), 'TM9', 'NLS_NUMERIC_CHARACTERS=''.,''')`)
+	}
+
+	querySQL := common.StringsBuilder(`SELECT `, boundaryCol, ` BOUNDARY FROM (SELECT `, columnName,
+		`, NTILE(`, strconv.Itoa(buckets), `) OVER (ORDER BY `, columnName, `) NT FROM `,
+		common.StringUPPER(schemaName), `.`, common.StringUPPER(tableName), sampleSQL,
+		` WHERE `, columnName, ` IS NOT NULL) GROUP BY NT ORDER BY NT`)
+
+	_, boundaryRes, err := Query(o.Ctx, o.OracleDB, querySQL)
+	if err != nil {
+		return res, err
+	}
+
+	// 第一个分桶下边界为最小值，首个 chunk 采用开区间，忽略
+	var boundaries []string
+	for i, b := range boundaryRes {
+		if i == 0 || strings.EqualFold(b["BOUNDARY"], "") {
+			continue
+		}
+		if len(boundaries) > 0 && boundaries[len(boundaries)-1] == b["BOUNDARY"] {
+			continue
+		}
+		boundaries = append(boundaries, b["BOUNDARY"])
+	}
+	if len(boundaries) == 0 {
+		return res, nil
+	}
+
+	for i, b := range boundaries {
+		if i == 0 {
+			res = append(res, map[string]string{
+				"CMD": common.StringsBuilder(columnName, ` < `, oracleChunkColumnLiteral(dataType, b)),
+			})
+		}
+		if i == len(boundaries)-1 {
+			res = append(res, map[string]string{
+				"CMD": common.StringsBuilder(columnName, ` >= `, oracleChunkColumnLiteral(dataType, b)),
+			})
+		} else {
+			res = append(res, map[string]string{
+				"CMD": common.StringsBuilder(columnName, ` >= `, oracleChunkColumnLiteral(dataType, b),
+					` AND `, columnName, ` < `, oracleChunkColumnLiteral(dataType, boundaries[i+1])),
+			})
+		}
+	}
+	// 唯一索引允许 NULL 值
+	res = append(res, map[string]string{
+		"CMD": common.StringsBuilder(columnName, ` IS NULL`),
+	})
+	return res, nil
+}
+
+// IsOracleChunkColumnDataType NTILE 切分支持 NUMBER/FLOAT/DATE/TIMESTAMP（不包含时区）数据类型
+func IsOracleChunkColumnDataType(dataType string) bool {
+	dataType = common.StringUPPER(dataType)
+	switch {
+	case dataType == "NUMBER" || dataType == "FLOAT" || dataType == "DATE":
+		return true
+	case strings.HasPrefix(dataType, "TIMESTAMP") && !strings.Contains(dataType, "TIME ZONE"):
+		return true
+	default:
+		return false
+	}
+}
+
+func oracleChunkColumnLiteral(dataType, value string) string {
+	switch {
+	case strings.EqualFold(dataType, "DATE"):
+		return common.StringsBuilder(`TO_DATE('`, value, `', 'YYYY-MM-DD HH24:MI:SS')`)
+	case strings.HasPrefix(common.StringUPPER(dataType), "TIMESTAMP"):
+		return common.StringsBuilder(`TO_TIMESTAMP('`, value, `', 'YYYY-MM-DD HH24:MI:SS.FF9')`)
+	default:
+		return value
+	}
+}
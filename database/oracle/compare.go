@@ -272,3 +272,44 @@ func (o *Oracle) GetOracleDataRowStrings(querySQL string) ([]string, *strset.Set
 
 	return cols, stringSet, crc32SUM, err
 }
+
+// GetOracleDataRowValues 查询数据行字段值，字段值统一字符串返回，NULL 以及空字符串统一空字符串（ORACLE 空字符串即 NULL）
+func (o *Oracle) GetOracleDataRowValues(querySQL string) ([]string, [][]string, error) {
+	var (
+		cols []string
+		res  [][]string
+	)
+	rows, err := o.OracleDB.QueryContext(o.Ctx, querySQL)
+	if err != nil {
+		return cols, res, fmt.Errorf("general sql [%v] query failed: [%v]", querySQL, err.Error())
+	}
+	defer rows.Close()
+
+	cols, err = rows.Columns()
+	if err != nil {
+		return cols, res, fmt.Errorf("general sql [%v] query rows.Columns failed: [%v]", querySQL, err.Error())
+	}
+
+	rawResult := make([][]byte, len(cols))
+	scans := make([]interface{}, len(cols))
+	for i := range rawResult {
+		scans[i] = &rawResult[i]
+	}
+
+	for rows.Next() {
+		err = rows.Scan(scans...)
+		if err != nil {
+			return cols, res, fmt.Errorf("general sql [%v] query rows.Scan failed: [%v]", querySQL, err.Error())
+		}
+		row := make([]string, len(cols))
+		for i, raw := range rawResult {
+			row[i] = string(raw)
+		}
+		res = append(res, row)
+	}
+
+	if err = rows.Err(); err != nil {
+		return cols, res, fmt.Errorf("general sql [%v] query rows.Next failed: [%v]", querySQL, err.Error())
+	}
+	return cols, res, nil
+}
@@ -18,9 +18,13 @@ package oracle
 import (
 	"context"
 	"fmt"
+	"github.com/godror/godror"
 	"github.com/shopspring/decimal"
 	"github.com/wentaojin/transferdb/common"
 	"github.com/wentaojin/transferdb/config"
+	"github.com/wentaojin/transferdb/throttle"
+	"github.com/wentaojin/transferdb/transform"
+	"strconv"
 )
 
 func (o *Oracle) GetOracleCurrentSnapshotSCN() (uint64, error) {
@@ -119,17 +123,35 @@ func (o *Oracle) GetOracleTableRowsColumnCSV(querySQL string) ([]string, error)
 	return columns, nil
 }
 
-func (o *Oracle) GetOracleTableRowsDataCSV(querySQL, sourceDBCharset, targetDBCharset string, cfg *config.Config, dataChan chan []map[string]string) error {
+func (o *Oracle) GetOracleTableRowsDataCSV(querySQL, sourceDBCharset, targetDBCharset string, cfg *config.Config, limiter *throttle.TableLimiter, transformer *transform.TableTransformer, dataChan chan []map[string]string) error {
 	var (
-		err         error
-		columnNames []string
-		columnTypes []string
+		err           error
+		columnNames   []string
+		columnTypes   []string
+		databaseTypes []string
+		batchBytes    int
+		batchMemory   int
 	)
 	// 临时数据存放
 	var rowsTMP []map[string]string
 	rowsMap := make(map[string]string)
 
-	rows, err := o.OracleDB.QueryContext(o.Ctx, querySQL)
+	// 字符串 delimiter 引用，二进制按 blob-encoding 编码
+	format := valueFormat{
+		quote:          cfg.CSVConfig.Delimiter,
+		escape:         cfg.CSVConfig.EscapeBackslash,
+		binaryEncoding: cfg.CSVConfig.BlobEncoding,
+	}
+	// parquet 字段值不引用不转义，NULL 字段不写入 rowsMap，写入端按 parquet null 写入
+	parquet := cfg.CSVConfig.FileFormat == common.FileFormatParquet
+	if parquet {
+		format.quote = ""
+		format.escape = false
+	}
+	maxLOBSize := cfg.AppConfig.MaxLOBSize
+
+	// LOB 字段以 godror.Lob 流式读取
+	rows, err := o.OracleDB.QueryContext(o.Ctx, querySQL, godror.LobAsReader())
 	if err != nil {
 		return err
 	}
@@ -144,14 +166,23 @@ func (o *Oracle) GetOracleTableRowsDataCSV(querySQL, sourceDBCharset, targetDBCh
 		columnNames = append(columnNames, ct.Name())
 		// 数据库字段类型 DatabaseTypeName() 映射 go 类型 ScanType()
 		columnTypes = append(columnTypes, ct.ScanType().String())
+		databaseTypes = append(databaseTypes, ct.DatabaseTypeName())
 	}
 
 	// 数据 SCAN
 	columnNums := len(columnNames)
 	rawResult := make([][]byte, columnNums)
+	lobResult := make([]interface{}, columnNums)
+	objResult := make([]interface{}, columnNums)
 	dest := make([]interface{}, columnNums)
 	for i := range rawResult {
-		dest[i] = &rawResult[i]
+		if isOracleLOBType(databaseTypes[i]) {
+			dest[i] = &lobResult[i]
+		} else if isOracleObjectType(databaseTypes[i]) {
+			dest[i] = &objResult[i]
+		} else {
+			dest[i] = &rawResult[i]
+		}
 	}
 
 	// 表行数读取
@@ -162,15 +193,76 @@ func (o *Oracle) GetOracleTableRowsDataCSV(querySQL, sourceDBCharset, targetDBCh
 		}
 
 		for i, raw := range rawResult {
+			// LOB 分片读取转换，存在字段转换规则的 LOB 读取完整值按字符处理
+			if isOracleLOBType(databaseTypes[i]) {
+				if transformer.IsTransform(columnNames[i]) {
+					raw, err = readOracleLOBAll(lobResult[i], maxLOBSize)
+					if err != nil {
+						return fmt.Errorf("column [%s] lob read failed, %v", columnNames[i], err)
+					}
+				} else {
+					value, isNull, size, err := convertOracleLOBValue(columnNames[i], lobResult[i], sourceDBCharset, targetDBCharset, format, maxLOBSize)
+					if err != nil {
+						return err
+					}
+					batchBytes += size
+					if isNull {
+						if !parquet {
+							rowsMap[columnNames[i]] = fmt.Sprintf("%v", `NULL`)
+						}
+					} else {
+						rowsMap[columnNames[i]] = value
+					}
+					continue
+				}
+			}
+
+			// 对象类型以及集合类型转换 JSON 按字符处理
+			if isOracleObjectType(databaseTypes[i]) {
+				raw, err = readOracleObjectJSON(objResult[i])
+				if err != nil {
+					return fmt.Errorf("column [%s] object to json failed, %v", columnNames[i], err)
+				}
+			}
+
+			// 源端抽取字节数，用于限速
+			batchBytes += len(raw)
+
+			// 字段转换（脱敏）规则，转换结果统一按字符处理
+			if transformer.IsTransform(columnNames[i]) {
+				value, isNull, err := transformOracleColumnValue(transformer, columnNames[i], columnTypes[i], raw, sourceDBCharset)
+				if err != nil {
+					return err
+				}
+				if isNull {
+					if !parquet {
+						rowsMap[columnNames[i]] = fmt.Sprintf("%v", `NULL`)
+					}
+					continue
+				}
+				var convertTargetRaw []byte
+				if format.escape {
+					convertTargetRaw, err = common.CharsetConvert([]byte(common.SpecialLettersUsingMySQL([]byte(value))), common.CharsetUTF8MB4, targetDBCharset)
+				} else {
+					convertTargetRaw, err = common.CharsetConvert([]byte(value), common.CharsetUTF8MB4, targetDBCharset)
+				}
+				if err != nil {
+					return fmt.Errorf("column [%s] charset convert failed, %v", columnNames[i], err)
+				}
+				rowsMap[columnNames[i]] = fmt.Sprintf("%v", common.StringsBuilder(format.quote, string(convertTargetRaw), format.quote))
+				continue
+			}
 			// 注意 Oracle/Mysql NULL VS 空字符串区别
 			// Oracle 空字符串与 NULL 归于一类，统一 NULL 处理 （is null 可以查询 NULL 以及空字符串值，空字符串查询无法查询到空字符串值）
 			// Mysql 空字符串与 NULL 非一类，NULL 是 NULL，空字符串是空字符串（is null 只查询 NULL 值，空字符串查询只查询到空字符串值）
 			// 按照 Oracle 特性来，转换同步统一转换成 NULL 即可，但需要注意业务逻辑中空字符串得写入，需要变更
 			// Oracle/Mysql 对于 'NULL' 统一字符 NULL 处理，查询出来转成 NULL,所以需要判断处理
-			if raw == nil {
-				rowsMap[columnNames[i]] = fmt.Sprintf("%v", `NULL`)
-			} else if string(raw) == "" {
-				rowsMap[columnNames[i]] = fmt.Sprintf("%v", `NULL`)
+			if raw == nil || string(raw) == "" {
+				if !parquet {
+					rowsMap[columnNames[i]] = fmt.Sprintf("%v", `NULL`)
+				}
+			} else if isOracleBinaryType(databaseTypes[i]) {
+				rowsMap[columnNames[i]] = encodeOracleBinary(raw, format)
 			} else {
 				switch columnTypes[i] {
 				case "int64":
@@ -218,7 +310,7 @@ func (o *Oracle) GetOracleTableRowsDataCSV(querySQL, sourceDBCharset, targetDBCh
 					}
 
 					// 处理字符集、特殊字符转义、字符串引用定界符
-					if cfg.CSVConfig.EscapeBackslash {
+					if format.escape {
 						convertTargetRaw, err = common.CharsetConvert([]byte(common.SpecialLettersUsingMySQL(convertUtf8Raw)), common.CharsetUTF8MB4, targetDBCharset)
 						if err != nil {
 							return fmt.Errorf("column [%s] charset convert failed, %v", columnNames[i], err)
@@ -230,10 +322,10 @@ func (o *Oracle) GetOracleTableRowsDataCSV(querySQL, sourceDBCharset, targetDBCh
 						}
 					}
 
-					if cfg.CSVConfig.Delimiter == "" {
+					if format.quote == "" {
 						rowsMap[columnNames[i]] = fmt.Sprintf("%v", string(convertTargetRaw))
 					} else {
-						rowsMap[columnNames[i]] = fmt.Sprintf("%v", common.StringsBuilder(cfg.CSVConfig.Delimiter, string(convertTargetRaw), cfg.CSVConfig.Delimiter))
+						rowsMap[columnNames[i]] = fmt.Sprintf("%v", common.StringsBuilder(format.quote, string(convertTargetRaw), format.quote))
 					}
 				}
 			}
@@ -241,12 +333,21 @@ func (o *Oracle) GetOracleTableRowsDataCSV(querySQL, sourceDBCharset, targetDBCh
 
 		// 临时数组
 		rowsTMP = append(rowsTMP, rowsMap)
+		for _, v := range rowsMap {
+			batchMemory += len(v)
+		}
 
 		// MAP 清空
 		rowsMap = make(map[string]string)
 
-		// batch 批次
-		if len(rowsTMP) == cfg.AppConfig.InsertBatchSize {
+		// batch 批次，按行数或者批次内存字节数（LOB 大字段）
+		if len(rowsTMP) == cfg.AppConfig.InsertBatchSize || (cfg.AppConfig.InsertBatchBytes > 0 && batchMemory >= cfg.AppConfig.InsertBatchBytes) {
+			// 源端限速，等待期间暂停游标 fetch
+			if err = limiter.Wait(o.Ctx, len(rowsTMP), batchBytes); err != nil {
+				return err
+			}
+			batchBytes = 0
+			batchMemory = 0
 
 			dataChan <- rowsTMP
 
@@ -261,6 +362,9 @@ func (o *Oracle) GetOracleTableRowsDataCSV(querySQL, sourceDBCharset, targetDBCh
 
 	// 非 batch 批次
 	if len(rowsTMP) > 0 {
+		if err = limiter.Wait(o.Ctx, len(rowsTMP), batchBytes); err != nil {
+			return err
+		}
 
 		dataChan <- rowsTMP
 	}
@@ -293,17 +397,28 @@ func (o *Oracle) GetOracleTableRowsColumn(querySQL string) ([]string, error) {
 	return columns, nil
 }
 
-func (o *Oracle) GetOracleTableRowsData(querySQL string, insertBatchSize int, sourceDBCharset, targetDBCharset string, dataChan chan []map[string]string) error {
+func (o *Oracle) GetOracleTableRowsData(querySQL string, insertBatchSize, insertBatchBytes int, maxLOBSize int64, sourceDBCharset, targetDBCharset string, limiter *throttle.TableLimiter, transformer *transform.TableTransformer, dataChan chan []map[string]string) error {
 	var (
-		err  error
-		cols []string
+		err         error
+		cols        []string
+		batchBytes  int
+		batchMemory int
 	)
 
 	// 临时数据存放
 	var rowsTMP []map[string]string
 	rowsMap := make(map[string]string)
 
-	rows, err := o.OracleDB.QueryContext(o.Ctx, querySQL)
+	// 字符串单引号引用并转义，二进制 0x 十六进制
+	format := valueFormat{
+		quote:          `'`,
+		escape:         true,
+		binaryPrefix:   `0x`,
+		binaryEncoding: common.BinaryEncodingHex,
+	}
+
+	// LOB 字段以 godror.Lob 流式读取
+	rows, err := o.OracleDB.QueryContext(o.Ctx, querySQL, godror.LobAsReader())
 	if err != nil {
 		return err
 	}
@@ -340,9 +455,17 @@ func (o *Oracle) GetOracleTableRowsData(querySQL string, insertBatchSize int, so
 	// 数据 Scan
 	columns := len(cols)
 	rawResult := make([][]byte, columns)
+	lobResult := make([]interface{}, columns)
+	objResult := make([]interface{}, columns)
 	dest := make([]interface{}, columns)
 	for i := range rawResult {
-		dest[i] = &rawResult[i]
+		if isOracleLOBType(databaseTypes[i]) {
+			dest[i] = &lobResult[i]
+		} else if isOracleObjectType(databaseTypes[i]) {
+			dest[i] = &objResult[i]
+		} else {
+			dest[i] = &rawResult[i]
+		}
 	}
 
 	// 表行数读取
@@ -353,6 +476,56 @@ func (o *Oracle) GetOracleTableRowsData(querySQL string, insertBatchSize int, so
 		}
 
 		for i, raw := range rawResult {
+			// LOB 分片读取转换，存在字段转换规则的 LOB 读取完整值按字符处理
+			if isOracleLOBType(databaseTypes[i]) {
+				if transformer.IsTransform(columnNames[i]) {
+					raw, err = readOracleLOBAll(lobResult[i], maxLOBSize)
+					if err != nil {
+						return fmt.Errorf("column [%s] lob read failed, %v", columnNames[i], err)
+					}
+				} else {
+					value, isNull, size, err := convertOracleLOBValue(columnNames[i], lobResult[i], sourceDBCharset, targetDBCharset, format, maxLOBSize)
+					if err != nil {
+						return err
+					}
+					batchBytes += size
+					if isNull {
+						rowsMap[cols[i]] = fmt.Sprintf("%v", `NULL`)
+					} else {
+						rowsMap[cols[i]] = value
+					}
+					continue
+				}
+			}
+
+			// 对象类型以及集合类型转换 JSON 按字符处理
+			if isOracleObjectType(databaseTypes[i]) {
+				raw, err = readOracleObjectJSON(objResult[i])
+				if err != nil {
+					return fmt.Errorf("column [%s] object to json failed, %v", columnNames[i], err)
+				}
+			}
+
+			// 源端抽取字节数，用于限速
+			batchBytes += len(raw)
+
+			// 字段转换（脱敏）规则，转换结果统一按字符处理
+			if transformer.IsTransform(columnNames[i]) {
+				value, isNull, err := transformOracleColumnValue(transformer, columnNames[i], columnTypes[i], raw, sourceDBCharset)
+				if err != nil {
+					return err
+				}
+				if isNull {
+					rowsMap[cols[i]] = fmt.Sprintf("%v", `NULL`)
+					continue
+				}
+				convertTargetRaw, err := common.CharsetConvert([]byte(common.SpecialLettersUsingMySQL([]byte(value))), common.CharsetUTF8MB4, targetDBCharset)
+				if err != nil {
+					return fmt.Errorf("column [%s] charset convert failed, %v", columnNames[i], err)
+				}
+				rowsMap[cols[i]] = fmt.Sprintf("'%v'", string(convertTargetRaw))
+				continue
+			}
 			// 注意 Oracle/Mysql NULL VS 空字符串区别
 			// Oracle 空字符串与 NULL 归于一类，统一 NULL 处理 （is null 可以查询 NULL 以及空字符串值，空字符串查询无法查询到空字符串值）
 			// Mysql 空字符串与 NULL 非一类，NULL 是 NULL，空字符串是空字符串（is null 只查询 NULL 值，空字符串查询只查询到空字符串值）
@@ -362,6 +535,8 @@ func (o *Oracle) GetOracleTableRowsData(querySQL string, insertBatchSize int, so
 				rowsMap[cols[i]] = fmt.Sprintf("%v", `NULL`)
 			} else if string(raw) == "" {
 				rowsMap[cols[i]] = fmt.Sprintf("%v", `NULL`)
+			} else if isOracleBinaryType(databaseTypes[i]) {
+				rowsMap[cols[i]] = encodeOracleBinary(raw, format)
 			} else {
 				switch columnTypes[i] {
 				case "int64":
@@ -419,12 +594,21 @@ func (o *Oracle) GetOracleTableRowsData(querySQL string, insertBatchSize int, so
 
 		// 临时数组
 		rowsTMP = append(rowsTMP, rowsMap)
+		for _, v := range rowsMap {
+			batchMemory += len(v)
+		}
 
 		// MAP 清空
 		rowsMap = make(map[string]string)
 
-		// batch 批次
-		if len(rowsTMP) == insertBatchSize {
+		// batch 批次，按行数或者批次内存字节数（LOB 大字段）
+		if len(rowsTMP) == insertBatchSize || (insertBatchBytes > 0 && batchMemory >= insertBatchBytes) {
+			// 源端限速，等待期间暂停游标 fetch
+			if err = limiter.Wait(o.Ctx, len(rowsTMP), batchBytes); err != nil {
+				return err
+			}
+			batchBytes = 0
+			batchMemory = 0
 
 			dataChan <- rowsTMP
 
@@ -439,8 +623,95 @@ func (o *Oracle) GetOracleTableRowsData(querySQL string, insertBatchSize int, so
 
 	// 非 batch 批次
 	if len(rowsTMP) > 0 {
+		if err = limiter.Wait(o.Ctx, len(rowsTMP), batchBytes); err != nil {
+			return err
+		}
 		dataChan <- rowsTMP
 	}
 
 	return nil
 }
+
+// transformOracleColumnValue 获取字段 UTF8 原始值并按转换规则转换，与增量 SQL 字面值转换保持一致
+// Oracle 空字符串与 NULL 统一按 NULL 处理
+func transformOracleColumnValue(transformer *transform.TableTransformer, columnName, columnType string, raw []byte, sourceDBCharset string) (string, bool, error) {
+	if raw == nil || string(raw) == "" {
+		value, isNull := transformer.Transform(columnName, "", true)
+		return value, isNull, nil
+	}
+
+	var value string
+	switch columnType {
+	case "godror.Number":
+		r, err := decimal.NewFromString(string(raw))
+		if err != nil {
+			return "", false, fmt.Errorf("column [%s] NewFromString strconv failed, %v", columnName, err)
+		}
+		value = r.String()
+	case "int64", "uint64", "float32", "float64", "rune":
+		value = string(raw)
+	default:
+		convertUtf8Raw, err := common.CharsetConvert(raw, sourceDBCharset, common.CharsetUTF8MB4)
+		if err != nil {
+			return "", false, fmt.Errorf("column [%s] charset convert failed, %v", columnName, err)
+		}
+		value = string(convertUtf8Raw)
+	}
+
+	value, isNull := transformer.Transform(columnName, value, false)
+	return value, isNull, nil
+}
+
+// GetOracleSessionLoad 源端负载采样，用于自适应限速
+// 返回活跃用户会话数（不包含当前连接用户会话）以及非空闲等待会话平均等待时间（毫秒）
+func (o *Oracle) GetOracleSessionLoad() (int, float64, error) {
+	_, res, err := Query(o.Ctx, o.OracleDB, `SELECT COUNT(1) ACTIVE_SESSIONS,
+       NVL(ROUND(AVG(CASE WHEN STATE = 'WAITING' AND WAIT_CLASS <> 'Idle' THEN WAIT_TIME_MICRO END) / 1000, 2), 0) WAIT_TIME
+  FROM GV$SESSION
+ WHERE TYPE = 'USER'
+   AND STATUS = 'ACTIVE'
+   AND USERNAME <> SYS_CONTEXT('USERENV', 'SESSION_USER')`)
+	if err != nil {
+		return 0, 0, err
+	}
+	activeSessions, err := strconv.Atoi(res[0]["ACTIVE_SESSIONS"])
+	if err != nil {
+		return 0, 0, fmt.Errorf("get oracle active sessions [%s] strconv.Atoi failed: %v", res[0]["ACTIVE_SESSIONS"], err)
+	}
+	waitTime, err := strconv.ParseFloat(res[0]["WAIT_TIME"], 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("get oracle session wait time [%s] strconv.ParseFloat failed: %v", res[0]["WAIT_TIME"], err)
+	}
+	return activeSessions, waitTime, nil
+}
+
+// TruncateOracleTable 目标端 Oracle 清理表数据，适用于 MySQL/TiDB -> Oracle
+func (o *Oracle) TruncateOracleTable(targetSchema string, targetTable string) error {
+	_, err := o.OracleDB.ExecContext(o.Ctx, fmt.Sprintf(`TRUNCATE TABLE "%s"."%s"`, targetSchema, targetTable))
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// BatchWriteOracleTable 目标端 Oracle 数据写入，args 为字段值数组时基于 godror 数组绑定批量写入
+func (o *Oracle) BatchWriteOracleTable(sql string, args ...interface{}) error {
+	_, err := o.OracleDB.ExecContext(o.Ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetOracleTableColumnDataType 获取目标端表字段数据类型，表名不区分大小写匹配，返回实际表名以及字段名
+func (o *Oracle) GetOracleTableColumnDataType(schemaName, tableName string) ([]map[string]string, error) {
+	_, res, err := Query(o.Ctx, o.OracleDB, fmt.Sprintf(`SELECT TABLE_NAME,COLUMN_NAME,DATA_TYPE
+  FROM DBA_TAB_COLUMNS
+ WHERE OWNER = '%s'
+   AND UPPER(TABLE_NAME) = '%s'
+ ORDER BY COLUMN_ID`, common.StringUPPER(schemaName), common.StringUPPER(tableName)))
+	if err != nil {
+		return res, err
+	}
+	return res, nil
+}
@@ -0,0 +1,200 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package oracle
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"github.com/godror/godror"
+	"github.com/wentaojin/transferdb/common"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// valueFormat 字段值输出格式
+// full/all: 字符串单引号引用并转义，二进制 0x 十六进制
+// csv: 字符串 delimiter 引用（是否转义由 escape-backslash 决定），二进制按 blob-encoding 编码
+type valueFormat struct {
+	quote          string
+	escape         bool
+	binaryPrefix   string
+	binaryEncoding string
+}
+
+// isOracleLOBType LOB 字段，查询基于 godror LobAsReader 分片流式读取，避免大字段一次性读入内存
+func isOracleLOBType(databaseType string) bool {
+	switch strings.ToUpper(databaseType) {
+	case "CLOB", "NCLOB", "BLOB", "BFILE":
+		return true
+	default:
+		return false
+	}
+}
+
+// isOracleBinaryType 二进制字段，按十六进制或者 base64 输出，不做字符集转换
+func isOracleBinaryType(databaseType string) bool {
+	switch strings.ToUpper(databaseType) {
+	case "BLOB", "BFILE", "RAW", "LONG RAW":
+		return true
+	default:
+		return false
+	}
+}
+
+// readOracleLOB LOB 按固定大小分片读取，CLOB 分片按完整 UTF-8 字符切分，返回读取字节数
+// maxSize 大于 0 时，读取字节数超过 maxSize 立即报错，避免超大 LOB 完整物化占用内存
+func readOracleLOB(lob *godror.Lob, maxSize int64, fn func(piece []byte) error) (int, error) {
+	var (
+		total int
+		tail  []byte
+	)
+	buf := make([]byte, common.LOBReadPieceSize)
+	for {
+		n, err := lob.Read(buf)
+		if n > 0 {
+			total += n
+			if maxSize > 0 && int64(total) > maxSize {
+				return total, fmt.Errorf("lob size exceeds [app] max-lob-size [%d] bytes", maxSize)
+			}
+			piece := buf[:n]
+			if lob.IsClob {
+				if len(tail) > 0 {
+					piece = append(tail, piece...)
+					tail = nil
+				}
+				// 末尾不完整字符留到下一分片
+				start := len(piece) - 1
+				for start > 0 && start > len(piece)-utf8.UTFMax && !utf8.RuneStart(piece[start]) {
+					start--
+				}
+				if !utf8.FullRune(piece[start:]) {
+					tail = append([]byte{}, piece[start:]...)
+					piece = piece[:start]
+				}
+			}
+			if len(piece) > 0 {
+				if errf := fn(piece); errf != nil {
+					return total, errf
+				}
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return total, err
+		}
+	}
+	if len(tail) > 0 {
+		if err := fn(tail); err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// readOracleLOBAll 读取完整 LOB，仅用于存在字段转换（脱敏）规则的 LOB 字段
+func readOracleLOBAll(val interface{}, maxSize int64) ([]byte, error) {
+	lob, ok := val.(*godror.Lob)
+	if !ok || lob == nil {
+		return nil, nil
+	}
+	var b []byte
+	if _, err := readOracleLOB(lob, maxSize, func(piece []byte) error {
+		b = append(b, piece...)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// convertOracleLOBValue LOB 字段值分片转换输出，返回字段值、是否 NULL 以及源端读取字节数
+// 注意 Oracle 空 LOB 与 NULL 统一按 NULL 处理，与字符字段保持一致
+func convertOracleLOBValue(columnName string, val interface{}, sourceDBCharset, targetDBCharset string, format valueFormat, maxSize int64) (string, bool, int, error) {
+	lob, ok := val.(*godror.Lob)
+	if !ok || lob == nil {
+		return "", true, 0, nil
+	}
+
+	var b strings.Builder
+	if !lob.IsClob {
+		b.WriteString(format.binaryPrefix)
+		enc := newBinaryEncoder(&b, format.binaryEncoding)
+		size, err := readOracleLOB(lob, maxSize, func(piece []byte) error {
+			_, err := enc.Write(piece)
+			return err
+		})
+		if err != nil {
+			return "", false, size, fmt.Errorf("column [%s] lob read failed, %v", columnName, err)
+		}
+		if err = enc.Close(); err != nil {
+			return "", false, size, fmt.Errorf("column [%s] lob encode failed, %v", columnName, err)
+		}
+		if size == 0 {
+			return "", true, size, nil
+		}
+		return b.String(), false, size, nil
+	}
+
+	b.WriteString(format.quote)
+	size, err := readOracleLOB(lob, maxSize, func(piece []byte) error {
+		convertUtf8Raw, err := common.CharsetConvert(piece, sourceDBCharset, common.CharsetUTF8MB4)
+		if err != nil {
+			return fmt.Errorf("column [%s] charset convert failed, %v", columnName, err)
+		}
+		if format.escape {
+			convertUtf8Raw = []byte(common.SpecialLettersUsingMySQL(convertUtf8Raw))
+		}
+		convertTargetRaw, err := common.CharsetConvert(convertUtf8Raw, common.CharsetUTF8MB4, targetDBCharset)
+		if err != nil {
+			return fmt.Errorf("column [%s] charset convert failed, %v", columnName, err)
+		}
+		b.Write(convertTargetRaw)
+		return nil
+	})
+	if err != nil {
+		return "", false, size, fmt.Errorf("column [%s] lob read failed, %v", columnName, err)
+	}
+	if size == 0 {
+		return "", true, size, nil
+	}
+	b.WriteString(format.quote)
+	return b.String(), false, size, nil
+}
+
+// encodeOracleBinary RAW/LONG RAW 字段值编码
+func encodeOracleBinary(raw []byte, format valueFormat) string {
+	if strings.EqualFold(format.binaryEncoding, common.BinaryEncodingBase64) {
+		return common.StringsBuilder(format.binaryPrefix, base64.StdEncoding.EncodeToString(raw))
+	}
+	return common.StringsBuilder(format.binaryPrefix, hex.EncodeToString(raw))
+}
+
+func newBinaryEncoder(w io.Writer, encoding string) io.WriteCloser {
+	if strings.EqualFold(encoding, common.BinaryEncodingBase64) {
+		return base64.NewEncoder(base64.StdEncoding, w)
+	}
+	return nopWriteCloser{hex.NewEncoder(w)}
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
@@ -0,0 +1,64 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package oracle
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/godror/godror"
+	"github.com/wentaojin/transferdb/common"
+)
+
+func TestConvertOracleLOBValueMaxSize(t *testing.T) {
+	format := valueFormat{
+		quote:          `'`,
+		escape:         true,
+		binaryPrefix:   `0x`,
+		binaryEncoding: common.BinaryEncodingHex,
+	}
+	cases := []struct {
+		name     string
+		lob      *godror.Lob
+		maxSize  int64
+		expected string
+		isNull   bool
+		hasErr   bool
+	}{
+		{name: "clob unlimited", lob: &godror.Lob{Reader: strings.NewReader("abc"), IsClob: true}, expected: `'abc'`},
+		{name: "clob within limit", lob: &godror.Lob{Reader: strings.NewReader("abc"), IsClob: true}, maxSize: 3, expected: `'abc'`},
+		{name: "clob over limit", lob: &godror.Lob{Reader: strings.NewReader("abcd"), IsClob: true}, maxSize: 3, hasErr: true},
+		{name: "blob within limit", lob: &godror.Lob{Reader: strings.NewReader("\x01\x02")}, maxSize: 2, expected: `0x0102`},
+		{name: "blob over limit", lob: &godror.Lob{Reader: strings.NewReader("\x01\x02\x03")}, maxSize: 2, hasErr: true},
+		{name: "empty lob", lob: &godror.Lob{Reader: strings.NewReader(""), IsClob: true}, maxSize: 2, isNull: true},
+	}
+	for _, c := range cases {
+		value, isNull, _, err := convertOracleLOBValue("C1", c.lob, common.CharsetUTF8MB4, common.CharsetUTF8MB4, format, c.maxSize)
+		if c.hasErr {
+			if err == nil {
+				t.Errorf("%s: expected error, got value %q", c.name, value)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", c.name, err)
+			continue
+		}
+		if isNull != c.isNull || value != c.expected {
+			t.Errorf("%s: expected %q (null %v), got %q (null %v)", c.name, c.expected, c.isNull, value, isNull)
+		}
+	}
+}
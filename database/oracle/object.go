@@ -0,0 +1,61 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package oracle
+
+import (
+	"bytes"
+	"github.com/godror/godror"
+	"github.com/wentaojin/transferdb/common"
+	"regexp"
+	"strings"
+)
+
+// SDO_GEOMETRY 字段查询以 WKT 文本输出
+var geometrySelectColumnRegex = regexp.MustCompile(`SDO_UTIL\.TO_WKTGEOMETRY\("([^"]+)"\)`)
+
+// isOracleObjectType 自定义对象类型、VARRAY 以及嵌套表字段，基于 godror.Object 读取
+func isOracleObjectType(databaseType string) bool {
+	return strings.EqualFold(databaseType, "OBJECT")
+}
+
+// readOracleObjectJSON 对象类型以及集合类型字段值转换 JSON，NULL 返回 nil
+func readOracleObjectJSON(val interface{}) ([]byte, error) {
+	obj, ok := val.(*godror.Object)
+	if !ok || obj == nil {
+		return nil, nil
+	}
+	defer obj.Close()
+
+	var b bytes.Buffer
+	if err := obj.ToJSON(&b); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// GenOracleGeometrySelectColumn SDO_GEOMETRY 字段查询 WKT 文本
+func GenOracleGeometrySelectColumn(columnName string) string {
+	return common.StringsBuilder(`SDO_UTIL.TO_WKTGEOMETRY("`, columnName, `") AS "`, columnName, `"`)
+}
+
+// GetOracleGeometryColumns 根据查询字段获取 SDO_GEOMETRY 字段，用于下游写入 GEOMETRY 转换
+func GetOracleGeometryColumns(columnDetailS string) map[string]struct{} {
+	geometryColumns := make(map[string]struct{})
+	for _, m := range geometrySelectColumnRegex.FindAllStringSubmatch(columnDetailS, -1) {
+		geometryColumns[m[1]] = struct{}{}
+	}
+	return geometryColumns
+}
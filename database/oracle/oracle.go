@@ -60,7 +60,7 @@ func NewOracleDBEngine(ctx context.Context, oraCfg config.OracleConfig, currentS
 		return nil, err
 	}
 
-	oraDSN.Username, oraDSN.Password = oraCfg.Username, godror.NewPassword(oraCfg.Password)
+	setOracleConnectAuth(&oraDSN, oraCfg)
 
 	if !strings.EqualFold(oraCfg.PDBName, "") {
 		oraCfg.SessionParams = append(oraCfg.SessionParams, fmt.Sprintf(`ALTER SESSION SET CONTAINER = %s`, oraCfg.PDBName))
@@ -70,8 +70,6 @@ func NewOracleDBEngine(ctx context.Context, oraCfg config.OracleConfig, currentS
 		oraCfg.SessionParams = append(oraCfg.SessionParams, fmt.Sprintf(`ALTER SESSION SET CURRENT_SCHEMA = %s`, currentSchema))
 	}
 
-	// 关闭外部认证
-	oraDSN.ExternalAuth = false
 	oraDSN.OnInitStmts = oraCfg.SessionParams
 
 	// libDir won't have any effect on Linux for linking reasons to do with Oracle's libnnz library that are proving to be intractable.
@@ -133,10 +131,8 @@ func NewOracleLogminerEngine(ctx context.Context, oraCfg config.OracleConfig) (*
 		return nil, err
 	}
 
-	oraDSN.Username, oraDSN.Password = oraCfg.Username, godror.NewPassword(oraCfg.Password)
+	setOracleConnectAuth(&oraDSN, oraCfg)
 
-	// 关闭外部认证
-	oraDSN.ExternalAuth = false
 	oraDSN.OnInitStmts = oraCfg.SessionParams
 
 	// libDir won't have any effect on Linux for linking reasons to do with Oracle's libnnz library that are proving to be intractable.
@@ -258,3 +254,58 @@ func (o *Oracle) GetOracleSchemaTable(schemaName string) ([]string, error) {
 
 	return tables, nil
 }
+
+// GetOracleSchemaTableAttribute 表对象属性，用于表选择对象类型、大小以及行数过滤，行数基于统计信息 NUM_ROWS
+func (o *Oracle) GetOracleSchemaTableAttribute(schemaName string) ([]map[string]string, error) {
+	_, res, err := Query(o.Ctx, o.OracleDB, fmt.Sprintf(`SELECT t.TABLE_NAME,
+       DECODE(t.PARTITIONED, 'YES', 'Y', 'N') AS IS_PARTITIONED,
+       DECODE(t.TEMPORARY, 'Y', 'Y', 'N') AS IS_TEMPORARY,
+       DECODE(t.IOT_TYPE, 'IOT', 'Y', 'N') AS IS_IOT,
+       DECODE(l.TABLE_NAME, NULL, 'N', 'Y') AS HAS_LOB,
+       DECODE(c.TABLE_NAME, NULL, 'N', 'Y') AS HAS_PK,
+       NVL(t.NUM_ROWS, 0) AS TABLE_ROWS,
+       ROUND(NVL(s.BYTES, 0) / 1024 / 1024, 2) AS TABLE_SIZE
+  FROM DBA_TABLES t
+  LEFT JOIN (SELECT DISTINCT OWNER, TABLE_NAME
+               FROM DBA_LOBS
+              WHERE UPPER(OWNER) = UPPER('%[1]s')) l
+    ON t.OWNER = l.OWNER
+   AND t.TABLE_NAME = l.TABLE_NAME
+  LEFT JOIN (SELECT OWNER, TABLE_NAME
+               FROM DBA_CONSTRAINTS
+              WHERE CONSTRAINT_TYPE = 'P'
+                AND UPPER(OWNER) = UPPER('%[1]s')) c
+    ON t.OWNER = c.OWNER
+   AND t.TABLE_NAME = c.TABLE_NAME
+  LEFT JOIN (SELECT OWNER, SEGMENT_NAME, SUM(BYTES) AS BYTES
+               FROM DBA_SEGMENTS
+              WHERE SEGMENT_TYPE LIKE 'TABLE%%'
+                AND UPPER(OWNER) = UPPER('%[1]s')
+              GROUP BY OWNER, SEGMENT_NAME) s
+    ON t.OWNER = s.OWNER
+   AND t.TABLE_NAME = s.SEGMENT_NAME
+ WHERE UPPER(t.OWNER) = UPPER('%[1]s')
+   AND (t.IOT_TYPE IS NUll OR t.IOT_TYPE = 'IOT')`, schemaName))
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// setOracleConnectAuth 连接串以及认证方式
+// 开启 external-auth 基于 Oracle Wallet 或者操作系统外部认证，忽略用户名密码，wallet 通过 config-dir 下 sqlnet.ora 指定
+func setOracleConnectAuth(oraDSN *dsn.ConnectionParams, oraCfg config.OracleConfig) {
+	if !strings.EqualFold(oraCfg.ConnectString, "") {
+		oraDSN.ConnectString = oraCfg.ConnectString
+	}
+	if !strings.EqualFold(oraCfg.ConfigDir, "") {
+		oraDSN.ConfigDir = oraCfg.ConfigDir
+	}
+	if oraCfg.ExternalAuth {
+		oraDSN.Username, oraDSN.Password = "", godror.NewPassword("")
+		oraDSN.ExternalAuth = true
+		return
+	}
+	oraDSN.Username, oraDSN.Password = oraCfg.Username, godror.NewPassword(oraCfg.Password)
+	oraDSN.ExternalAuth = false
+}
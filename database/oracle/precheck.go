@@ -0,0 +1,96 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package oracle
+
+import (
+	"fmt"
+	"github.com/wentaojin/transferdb/common"
+	"strings"
+)
+
+// 当前会话已生效系统权限（包含角色授予）
+func (o *Oracle) GetOracleSessionPrivileges() ([]string, error) {
+	var privs []string
+	_, res, err := Query(o.Ctx, o.OracleDB, `SELECT PRIVILEGE FROM SESSION_PRIVS`)
+	if err != nil {
+		return privs, err
+	}
+	for _, r := range res {
+		privs = append(privs, common.StringUPPER(r["PRIVILEGE"]))
+	}
+	return privs, nil
+}
+
+// 当前会话已生效角色
+func (o *Oracle) GetOracleSessionRoles() ([]string, error) {
+	var roles []string
+	_, res, err := Query(o.Ctx, o.OracleDB, `SELECT ROLE FROM SESSION_ROLES`)
+	if err != nil {
+		return roles, err
+	}
+	for _, r := range res {
+		roles = append(roles, common.StringUPPER(r["ROLE"]))
+	}
+	return roles, nil
+}
+
+// 探测视图或者表查询权限，无权限返回查询错误
+func (o *Oracle) ProbeOracleObjectSelect(objectName string) error {
+	_, _, err := Query(o.Ctx, o.OracleDB, fmt.Sprintf(`SELECT COUNT(1) AS COUNTS FROM %s WHERE ROWNUM = 1`, objectName))
+	return err
+}
+
+// SYS 系统包当前用户是否可执行（ALL_OBJECTS 仅包含当前用户具备权限的对象）
+func (o *Oracle) IsOracleSysPackageExecutable(packageName string) (bool, error) {
+	_, res, err := Query(o.Ctx, o.OracleDB, fmt.Sprintf(`SELECT COUNT(1) AS COUNTS FROM ALL_OBJECTS WHERE OWNER = 'SYS' AND OBJECT_NAME = '%s' AND OBJECT_TYPE = 'PACKAGE'`, common.StringUPPER(packageName)))
+	if err != nil {
+		return false, err
+	}
+	if strings.EqualFold(res[0]["COUNTS"], "0") {
+		return false, nil
+	}
+	return true, nil
+}
+
+// 数据库归档模式以及附加日志
+func (o *Oracle) GetOracleLogModeAndSupplementalLog() (map[string]string, error) {
+	_, res, err := Query(o.Ctx, o.OracleDB, `SELECT LOG_MODE,
+       SUPPLEMENTAL_LOG_DATA_MIN,
+       SUPPLEMENTAL_LOG_DATA_PK,
+       SUPPLEMENTAL_LOG_DATA_UI,
+       SUPPLEMENTAL_LOG_DATA_ALL
+  FROM V$DATABASE`)
+	if err != nil {
+		return nil, err
+	}
+	if len(res) == 0 {
+		return nil, fmt.Errorf("oracle log mode and supplemental log query v$database null")
+	}
+	return res[0], nil
+}
+
+// 表级别开启所有字段附加日志表
+func (o *Oracle) GetOracleSchemaTableAllColumnSupplementalLog(schemaName string) ([]string, error) {
+	var tables []string
+	_, res, err := Query(o.Ctx, o.OracleDB, fmt.Sprintf(`SELECT DISTINCT TABLE_NAME FROM DBA_LOG_GROUPS WHERE UPPER(OWNER) = UPPER('%s') AND LOG_GROUP_TYPE = 'ALL COLUMN LOGGING'`, schemaName))
+	if err != nil {
+		return tables, err
+	}
+	for _, r := range res {
+		tables = append(tables, r["TABLE_NAME"])
+	}
+	return tables, nil
+}
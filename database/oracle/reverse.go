@@ -543,6 +543,7 @@ func (o *Oracle) GetOracleSchemaTableColumn(schemaName string, tableName string,
 	    DECODE(NVL(TO_CHAR(t.DATA_SCALE), '*'), '*', '127', TO_CHAR(t.DATA_SCALE)) AS DATA_SCALE,
 		t.NULLABLE,
 	    NVL(s.DATA_DEFAULT, 'NULLSTRING') DATA_DEFAULT,
+	    NVL((SELECT ty.TYPECODE FROM DBA_TYPES ty WHERE ty.OWNER = t.DATA_TYPE_OWNER AND ty.TYPE_NAME = t.DATA_TYPE), 'NONE') DATA_TYPE_CODE,
 		DECODE(t.COLLATION, 'USING_NLS_COMP',(SELECT VALUE FROM NLS_DATABASE_PARAMETERS WHERE PARAMETER = 'NLS_COMP'), t.COLLATION) COLLATION,
 	    c.COMMENTS
 FROM
@@ -580,6 +581,7 @@ ORDER BY
 	    DECODE(NVL(TO_CHAR(t.DATA_SCALE), '*'), '*', '127', TO_CHAR(t.DATA_SCALE)) AS DATA_SCALE,
 		t.NULLABLE,
 	    NVL(s.DATA_DEFAULT, 'NULLSTRING') DATA_DEFAULT,
+	    NVL((SELECT ty.TYPECODE FROM DBA_TYPES ty WHERE ty.OWNER = t.DATA_TYPE_OWNER AND ty.TYPE_NAME = t.DATA_TYPE), 'NONE') DATA_TYPE_CODE,
 	    c.COMMENTS
 FROM
 	dba_tab_columns t,
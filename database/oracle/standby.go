@@ -0,0 +1,137 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package oracle
+
+import (
+	"context"
+	"fmt"
+	"github.com/wentaojin/transferdb/common"
+	"github.com/wentaojin/transferdb/config"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+// 创建 oracle ADG 备库数据库引擎，未开启备库返回 nil，数据抽取基于主库
+// 备库连接复用主库字符集、外部认证以及 client 配置，要求备库角色 PHYSICAL STANDBY 且以只读模式打开（Active Data Guard）
+func NewOracleStandbyEngine(ctx context.Context, oraCfg config.OracleConfig, standbyCfg config.StandbyConfig, currentSchema string) (*Oracle, error) {
+	if !standbyCfg.Enable {
+		return nil, nil
+	}
+	standbyOraCfg := config.OracleConfig{
+		Username:      oraCfg.Username,
+		Password:      oraCfg.Password,
+		Host:          standbyCfg.Host,
+		Port:          standbyCfg.Port,
+		ServiceName:   standbyCfg.ServiceName,
+		PDBName:       standbyCfg.PDBName,
+		Charset:       oraCfg.Charset,
+		LibDir:        oraCfg.LibDir,
+		ConnectParams: standbyCfg.ConnectParams,
+		SessionParams: standbyCfg.SessionParams,
+		ExternalAuth:  oraCfg.ExternalAuth,
+		ConfigDir:     oraCfg.ConfigDir,
+		ConnectString: standbyCfg.ConnectString,
+	}
+	if !strings.EqualFold(standbyCfg.Username, "") {
+		standbyOraCfg.Username, standbyOraCfg.Password = standbyCfg.Username, standbyCfg.Password
+	}
+
+	standbyDB, err := NewOracleDBEngine(ctx, standbyOraCfg, currentSchema)
+	if err != nil {
+		return nil, fmt.Errorf("oracle standby [%s:%d/%s] connect failed: %v", standbyCfg.Host, standbyCfg.Port, standbyCfg.ServiceName, err)
+	}
+
+	databaseRole, openMode, err := standbyDB.GetOracleDatabaseRole()
+	if err != nil {
+		standbyDB.OracleDB.Close()
+		return nil, err
+	}
+	if !strings.EqualFold(databaseRole, common.StandbyDatabaseRole) || !strings.HasPrefix(common.StringUPPER(openMode), common.StandbyOpenModeRead) {
+		standbyDB.OracleDB.Close()
+		return nil, fmt.Errorf("oracle standby [%s:%d/%s] database role [%s] open mode [%s] isn't active data guard, require database role [%s] and open mode [%s WITH APPLY]",
+			standbyCfg.Host, standbyCfg.Port, standbyCfg.ServiceName, databaseRole, openMode, common.StandbyDatabaseRole, common.StandbyOpenModeRead)
+	}
+	if !strings.Contains(common.StringUPPER(openMode), "APPLY") {
+		zap.L().Warn("oracle standby redo apply isn't running, apply scn wait maybe timeout",
+			zap.String("standby", fmt.Sprintf("%s:%d/%s", standbyCfg.Host, standbyCfg.Port, standbyCfg.ServiceName)),
+			zap.String("database role", databaseRole),
+			zap.String("open mode", openMode))
+	}
+	zap.L().Info("oracle standby extract enable",
+		zap.String("standby", fmt.Sprintf("%s:%d/%s", standbyCfg.Host, standbyCfg.Port, standbyCfg.ServiceName)),
+		zap.String("database role", databaseRole),
+		zap.String("open mode", openMode))
+	return standbyDB, nil
+}
+
+func (o *Oracle) GetOracleDatabaseRole() (string, string, error) {
+	_, res, err := Query(o.Ctx, o.OracleDB, `SELECT DATABASE_ROLE, OPEN_MODE FROM V$DATABASE`)
+	if err != nil {
+		return "", "", err
+	}
+	if len(res) == 0 {
+		return "", "", fmt.Errorf("oracle database role query v$database null")
+	}
+	return res[0]["DATABASE_ROLE"], res[0]["OPEN_MODE"], nil
+}
+
+// 备库 CURRENT_SCN 即备库已应用 SCN
+func (o *Oracle) GetOracleStandbyApplySCN() (uint64, error) {
+	_, res, err := Query(o.Ctx, o.OracleDB, `SELECT CURRENT_SCN FROM V$DATABASE`)
+	if err != nil {
+		return 0, err
+	}
+	if len(res) == 0 {
+		return 0, fmt.Errorf("oracle standby apply scn query v$database null")
+	}
+	applySCN, err := common.StrconvUintBitSize(res[0]["CURRENT_SCN"], 64)
+	if err != nil {
+		return applySCN, fmt.Errorf("get oracle standby apply scn %s utils.StrconvUintBitSize failed: %v", res[0]["CURRENT_SCN"], err)
+	}
+	return applySCN, nil
+}
+
+// 等待备库应用 SCN 大于等于主库 SCN，保证备库基于主库 SCN 闪回查询数据一致
+func (o *Oracle) WaitOracleStandbyApplySCN(globalSCN uint64, timeout, interval time.Duration) error {
+	startTime := time.Now()
+	for {
+		applySCN, err := o.GetOracleStandbyApplySCN()
+		if err != nil {
+			return err
+		}
+		if applySCN >= globalSCN {
+			zap.L().Info("oracle standby apply scn catch up",
+				zap.Uint64("global scn", globalSCN),
+				zap.Uint64("apply scn", applySCN),
+				zap.String("cost", time.Now().Sub(startTime).String()))
+			return nil
+		}
+		if time.Now().Sub(startTime) >= timeout {
+			return fmt.Errorf("oracle standby apply scn [%d] less than global scn [%d], wait timeout [%s], please check standby redo apply lag", applySCN, globalSCN, timeout.String())
+		}
+		zap.L().Warn("oracle standby apply scn less than global scn, waiting",
+			zap.Uint64("global scn", globalSCN),
+			zap.Uint64("apply scn", applySCN),
+			zap.String("interval", interval.String()))
+
+		select {
+		case <-o.Ctx.Done():
+			return o.Ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
@@ -17,8 +17,10 @@ package filter
 
 // 表过滤接口
 type Filter interface {
-	// MatchTable 检查表是否匹配
+	// MatchTable 检查表是否匹配，schema.table 规则仅匹配表名部分
 	MatchTable(table string) bool
+	// MatchSchemaTable 检查 schema 下表是否匹配，规则未指定 schema 代表匹配任意 schema
+	MatchSchemaTable(schema, table string) bool
 }
 
 // tableFilter Filter 接口具体实现
@@ -46,3 +48,13 @@ func (f tableFilter) MatchTable(table string) bool {
 	}
 	return false
 }
+
+// MatchSchemaTable 检查应用 tableFilter `f` 是否匹配 schema 下表
+func (f tableFilter) MatchSchemaTable(schema, table string) bool {
+	for _, rule := range f {
+		if rule.schema.matchString(schema) && rule.table.matchString(table) {
+			return true
+		}
+	}
+	return false
+}
@@ -25,7 +25,8 @@ import (
 // 过滤匹配成功是接受(positive)
 // 过滤匹配不成功是拒绝(negative)
 type tableRule struct {
-	table matcher
+	schema matcher
+	table  matcher
 }
 
 // matcher 表规则过滤接口
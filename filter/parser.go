@@ -25,19 +25,68 @@ type tableRulesParser struct {
 	rules []tableRule
 }
 
+// parse 规则支持 table 以及 schema.table 两种格式，未指定 schema 匹配任意 schema
+// schema、table 各部分支持通配符（* ? [a-z]）以及 /regexp/ 正则（忽略大小写），名称包含 . 需使用 \. 转义
 func (p *tableRulesParser) parse(pat string) error {
-	var tm matcher
-	tm, err := p.parsePattern(pat)
+	pat = strings.TrimSpace(pat)
+	if pat == "" {
+		return fmt.Errorf("table filter rule cannot be empty")
+	}
+
+	schemaPat, tablePat, err := splitSchemaTable(pat)
 	if err != nil {
-		return err
+		return fmt.Errorf("table filter rule [%s] parse failed: %v", pat, err)
+	}
+
+	var sm matcher = trueMatcher{}
+	if schemaPat != "" {
+		sm, err = p.parsePart(schemaPat)
+		if err != nil {
+			return fmt.Errorf("table filter rule [%s] parse failed: %v", pat, err)
+		}
+	}
+	tm, err := p.parsePart(tablePat)
+	if err != nil {
+		return fmt.Errorf("table filter rule [%s] parse failed: %v", pat, err)
 	}
 
 	p.rules = append(p.rules, tableRule{
-		table: tm,
+		schema: sm,
+		table:  tm,
 	})
 	return nil
 }
 
+// parsePart 解析 schema 或者 table 部分规则，/regexp/ 为正则，否则为通配符
+func (p *tableRulesParser) parsePart(part string) (matcher, error) {
+	if len(part) >= 2 && part[0] == '/' && part[len(part)-1] == '/' {
+		return newRegexpMatcher("(?i)" + part[1:len(part)-1])
+	}
+	return p.parsePattern(part)
+}
+
+// splitSchemaTable 按第一个非转义、非正则内的 . 切分 schema 与 table 部分，无 . 代表仅 table 部分
+func splitSchemaTable(line string) (string, string, error) {
+	inRegexp := false
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case c == '\\':
+			i++
+		case c == '/' && (inRegexp || i == 0 || line[i-1] == '.'):
+			inRegexp = !inRegexp
+		case c == '.' && !inRegexp:
+			if i == 0 || i == len(line)-1 {
+				return "", "", fmt.Errorf("schema or table part cannot be empty")
+			}
+			return line[:i], line[i+1:], nil
+		}
+	}
+	if inRegexp {
+		return "", "", fmt.Errorf("regexp part missing the terminating '/'")
+	}
+	return "", line, nil
+}
+
 var (
 	wildcardRangeRegexp = regexp.MustCompile(`^\[!?(?:\\[^0-9a-zA-Z]|[^\\\]])+\]`)
 )
@@ -0,0 +1,394 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package mapping
+
+import (
+	"context"
+	"fmt"
+	"github.com/wentaojin/transferdb/common"
+	"github.com/wentaojin/transferdb/database/meta"
+	"go.uber.org/zap"
+	"sort"
+	"strings"
+)
+
+// Mapper schema 字段映射规则，规则来源于元数据表 [column_mapping_rule]
+// 字段排除、重命名以及表达式计算，reverse、full、csv、all 以及 compare 保持一致
+type Mapper struct {
+	tables map[string]*TableMapper
+}
+
+// TableMapper 表级别字段映射规则，nil 代表表不存在映射规则
+type TableMapper struct {
+	// 源端数据库类型，MySQL/TiDB 约束条件字符串支持反斜杠转义
+	dbTypeS string
+	columns map[string]*columnRule
+}
+
+type columnRule struct {
+	ruleType    string
+	columnNameT string
+	columnExpr  string
+}
+
+func NewMapper(ctx context.Context, metaDB *meta.Meta, dbTypeS, dbTypeT, schemaNameS string) (*Mapper, error) {
+	rules, err := meta.NewColumnMappingRuleModel(metaDB).DetailColumnMappingRuleBySchema(ctx, &meta.ColumnMappingRule{
+		DBTypeS:     dbTypeS,
+		DBTypeT:     dbTypeT,
+		SchemaNameS: schemaNameS,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Mapper{tables: make(map[string]*TableMapper)}
+	for _, r := range rules {
+		rule, err := newColumnRule(r)
+		if err != nil {
+			return nil, err
+		}
+		tableName := common.StringUPPER(r.TableNameS)
+		if _, ok := m.tables[tableName]; !ok {
+			m.tables[tableName] = &TableMapper{dbTypeS: common.StringUPPER(dbTypeS), columns: make(map[string]*columnRule)}
+		}
+		m.tables[tableName].columns[trimColumnName(r.ColumnNameS)] = rule
+	}
+
+	if len(rules) > 0 {
+		zap.L().Info("column mapping rule load finished",
+			zap.String("schema", schemaNameS),
+			zap.Int("tables", len(m.tables)),
+			zap.Int("rules", len(rules)))
+	}
+	return m, nil
+}
+
+// Table 获取表字段映射规则，表不存在映射规则返回 nil
+func (m *Mapper) Table(tableName string) *TableMapper {
+	if m == nil {
+		return nil
+	}
+	if tm, ok := m.tables[common.StringUPPER(tableName)]; ok {
+		return tm
+	}
+	return nil
+}
+
+// ExprTables 存在 EXPR 规则的表，增量日志仅包含源端字段值，无法计算表达式
+func (m *Mapper) ExprTables() []string {
+	var tables []string
+	if m == nil {
+		return tables
+	}
+	for tableName, tm := range m.tables {
+		for _, rule := range tm.columns {
+			if strings.EqualFold(rule.ruleType, common.MappingRuleExpr) {
+				tables = append(tables, tableName)
+				break
+			}
+		}
+	}
+	sort.Strings(tables)
+	return tables
+}
+
+// IsMapping 字段是否存在映射规则
+func (tm *TableMapper) IsMapping(columnName string) bool {
+	if tm == nil {
+		return false
+	}
+	_, ok := tm.columns[trimColumnName(columnName)]
+	return ok
+}
+
+// IsExclude 字段是否排除不迁移
+func (tm *TableMapper) IsExclude(columnName string) bool {
+	if tm == nil {
+		return false
+	}
+	if rule, ok := tm.columns[trimColumnName(columnName)]; ok {
+		return strings.EqualFold(rule.ruleType, common.MappingRuleExclude)
+	}
+	return false
+}
+
+// ColumnNameT 目标端字段名，保持原字段名反引号或者双引号包裹格式，不存在重命名返回原字段名
+func (tm *TableMapper) ColumnNameT(columnName string) string {
+	if tm == nil {
+		return columnName
+	}
+	rule, ok := tm.columns[trimColumnName(columnName)]
+	if !ok || rule.columnNameT == "" {
+		return columnName
+	}
+	switch {
+	case strings.HasPrefix(columnName, "`") && strings.HasSuffix(columnName, "`"):
+		return common.StringsBuilder("`", rule.columnNameT, "`")
+	case strings.HasPrefix(columnName, `"`) && strings.HasSuffix(columnName, `"`):
+		return common.StringsBuilder(`"`, rule.columnNameT, `"`)
+	default:
+		return rule.columnNameT
+	}
+}
+
+// ColumnExpr 字段源端 SQL 表达式，不存在 EXPR 规则返回 false
+func (tm *TableMapper) ColumnExpr(columnName string) (string, bool) {
+	if tm == nil {
+		return "", false
+	}
+	if rule, ok := tm.columns[trimColumnName(columnName)]; ok && strings.EqualFold(rule.ruleType, common.MappingRuleExpr) {
+		return rule.columnExpr, true
+	}
+	return "", false
+}
+
+// FilterColumns 过滤排除字段
+func (tm *TableMapper) FilterColumns(columnNames []string) []string {
+	if tm == nil {
+		return columnNames
+	}
+	var columns []string
+	for _, c := range columnNames {
+		if !tm.IsExclude(c) {
+			columns = append(columns, c)
+		}
+	}
+	return columns
+}
+
+// ColumnNamesT 字段名转换为目标端字段名
+func (tm *TableMapper) ColumnNamesT(columnNames []string) []string {
+	if tm == nil {
+		return columnNames
+	}
+	var columns []string
+	for _, c := range columnNames {
+		columns = append(columns, tm.ColumnNameT(c))
+	}
+	return columns
+}
+
+// ChangeColumnList 逗号分隔字段列表（约束、索引）转换为目标端字段名，引用排除字段返回 false，对应约束、索引不迁移
+func (tm *TableMapper) ChangeColumnList(columnList string) (string, bool) {
+	if tm == nil {
+		return columnList, true
+	}
+	var columns []string
+	for _, c := range strings.Split(columnList, ",") {
+		if tm.IsExclude(c) {
+			return columnList, false
+		}
+		columns = append(columns, tm.ColumnNameT(c))
+	}
+	return strings.Join(columns, ","), true
+}
+
+// ChangeCondition 约束条件（CHECK 约束）字段转换为目标端字段名，引用排除字段返回 false，对应约束不迁移
+// 条件按词法切分，仅替换标识符（裸字段名、双引号以及反引号包裹字段名），字符串常量保持不变
+func (tm *TableMapper) ChangeCondition(condition string) (string, bool) {
+	if tm == nil {
+		return condition, true
+	}
+	backslashEscape := strings.EqualFold(tm.dbTypeS, common.DatabaseTypeMySQL) || strings.EqualFold(tm.dbTypeS, common.DatabaseTypeTiDB)
+
+	var b strings.Builder
+	for i := 0; i < len(condition); {
+		c := condition[i]
+		switch {
+		case c == '\'':
+			// 字符串常量，'' 以及 MySQL/TiDB 反斜杠转义
+			j := i + 1
+			for j < len(condition) {
+				if backslashEscape && condition[j] == '\\' && j+1 < len(condition) {
+					j += 2
+					continue
+				}
+				if condition[j] == '\'' {
+					if j+1 < len(condition) && condition[j+1] == '\'' {
+						j += 2
+						continue
+					}
+					j++
+					break
+				}
+				j++
+			}
+			b.WriteString(condition[i:j])
+			i = j
+		case c == '"' || c == '`':
+			// 双引号以及反引号包裹标识符
+			j := strings.IndexByte(condition[i+1:], c)
+			if j < 0 {
+				b.WriteString(condition[i:])
+				i = len(condition)
+				continue
+			}
+			ident := condition[i : i+j+2]
+			if tm.IsExclude(ident) {
+				return condition, false
+			}
+			b.WriteString(tm.ColumnNameT(ident))
+			i += j + 2
+		case isIdentStart(c):
+			j := i + 1
+			for j < len(condition) && isIdentChar(condition[j]) {
+				j++
+			}
+			ident := condition[i:j]
+			if tm.IsExclude(ident) {
+				return condition, false
+			}
+			b.WriteString(tm.ColumnNameT(ident))
+			i = j
+		case c >= '0' && c <= '9':
+			// 数值常量，避免 1E10 等指数部分误识别为标识符
+			j := i + 1
+			for j < len(condition) && isIdentChar(condition[j]) {
+				j++
+			}
+			b.WriteString(condition[i:j])
+			i = j
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return b.String(), true
+}
+
+// FilterColumnINFO 表结构字段信息过滤排除字段，COLUMN_NAME 保持源端字段名，用于字段类型、默认值规则匹配
+func (tm *TableMapper) FilterColumnINFO(columnINFO []map[string]string) []map[string]string {
+	if tm == nil {
+		return columnINFO
+	}
+	var columns []map[string]string
+	for _, c := range columnINFO {
+		if !tm.IsExclude(c["COLUMN_NAME"]) {
+			columns = append(columns, c)
+		}
+	}
+	return columns
+}
+
+// ChangeKeyINFO 主键、唯一约束以及索引字段列表 COLUMN_LIST 转换，引用排除字段的约束、索引不迁移
+func (tm *TableMapper) ChangeKeyINFO(keyINFO []map[string]string) []map[string]string {
+	if tm == nil {
+		return keyINFO
+	}
+	var keys []map[string]string
+	for _, k := range keyINFO {
+		columnList, ok := tm.ChangeColumnList(k["COLUMN_LIST"])
+		if !ok {
+			zap.L().Warn("column mapping rule exclude column, skip key or index",
+				zap.String("key info", fmt.Sprintf("%v", k)))
+			continue
+		}
+		k["COLUMN_LIST"] = columnList
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// ChangeCheckKeyINFO 检查约束条件 SEARCH_CONDITION 转换，引用排除字段的检查约束不迁移
+func (tm *TableMapper) ChangeCheckKeyINFO(checkKeyINFO []map[string]string) []map[string]string {
+	if tm == nil {
+		return checkKeyINFO
+	}
+	var keys []map[string]string
+	for _, k := range checkKeyINFO {
+		searchCond, ok := tm.ChangeCondition(k["SEARCH_CONDITION"])
+		if !ok {
+			zap.L().Warn("column mapping rule exclude column, skip check key",
+				zap.String("key info", fmt.Sprintf("%v", k)))
+			continue
+		}
+		k["SEARCH_CONDITION"] = searchCond
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// ChangeForeignKeyINFO 外键字段列表 COLUMN_LIST 以及引用表字段列表 RCOLUMN_LIST 转换，引用排除字段的外键不迁移
+// 引用表不属于当前 schema 不存在映射规则
+func (m *Mapper) ChangeForeignKeyINFO(schemaName, tableName string, foreignKeyINFO []map[string]string) []map[string]string {
+	if m == nil {
+		return foreignKeyINFO
+	}
+	var keys []map[string]string
+	for _, k := range foreignKeyINFO {
+		columnList, ok := m.Table(tableName).ChangeColumnList(k["COLUMN_LIST"])
+		if !ok {
+			zap.L().Warn("column mapping rule exclude column, skip foreign key",
+				zap.String("key info", fmt.Sprintf("%v", k)))
+			continue
+		}
+		rColumnList := k["RCOLUMN_LIST"]
+		if strings.EqualFold(k["R_OWNER"], schemaName) {
+			rColumnList, ok = m.Table(k["RTABLE_NAME"]).ChangeColumnList(k["RCOLUMN_LIST"])
+			if !ok {
+				zap.L().Warn("column mapping rule exclude reference column, skip foreign key",
+					zap.String("key info", fmt.Sprintf("%v", k)))
+				continue
+			}
+		}
+		k["COLUMN_LIST"] = columnList
+		k["RCOLUMN_LIST"] = rColumnList
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func newColumnRule(r meta.ColumnMappingRule) (*columnRule, error) {
+	rule := &columnRule{
+		ruleType:    common.StringUPPER(strings.TrimSpace(r.RuleType)),
+		columnNameT: strings.TrimSpace(r.ColumnNameT),
+		columnExpr:  strings.TrimSpace(r.ColumnExpr),
+	}
+	switch rule.ruleType {
+	case common.MappingRuleExclude:
+		rule.columnNameT, rule.columnExpr = "", ""
+	case common.MappingRuleRename:
+		if rule.columnNameT == "" {
+			return nil, fmt.Errorf("column mapping rule [%s.%s.%s] rule_type [%s] column_name_t can't be null",
+				r.SchemaNameS, r.TableNameS, r.ColumnNameS, r.RuleType)
+		}
+		rule.columnExpr = ""
+	case common.MappingRuleExpr:
+		if rule.columnExpr == "" {
+			return nil, fmt.Errorf("column mapping rule [%s.%s.%s] rule_type [%s] column_expr can't be null",
+				r.SchemaNameS, r.TableNameS, r.ColumnNameS, r.RuleType)
+		}
+	default:
+		return nil, fmt.Errorf("column mapping rule [%s.%s.%s] rule_type [%s] isn't support, support rule_type [%s %s %s]",
+			r.SchemaNameS, r.TableNameS, r.ColumnNameS, r.RuleType,
+			common.MappingRuleExclude, common.MappingRuleRename, common.MappingRuleExpr)
+	}
+	return rule, nil
+}
+
+// trimColumnName 字段名去除空格、反引号以及双引号，统一大写匹配
+func trimColumnName(columnName string) string {
+	return common.StringUPPER(strings.Trim(strings.TrimSpace(columnName), "`\""))
+}
+
+// isIdentStart 裸标识符首字符，非 ASCII 字符按标识符处理
+func isIdentStart(c byte) bool {
+	return c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c == '_' || c >= 0x80
+}
+
+func isIdentChar(c byte) bool {
+	return isIdentStart(c) || c >= '0' && c <= '9' || c == '$' || c == '#'
+}
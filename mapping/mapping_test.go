@@ -0,0 +1,164 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package mapping
+
+import (
+	"testing"
+
+	"github.com/wentaojin/transferdb/common"
+	"github.com/wentaojin/transferdb/database/meta"
+)
+
+func newTestTableMapper(t *testing.T, dbTypeS string) *TableMapper {
+	tm := &TableMapper{dbTypeS: dbTypeS, columns: make(map[string]*columnRule)}
+	for _, r := range []meta.ColumnMappingRule{
+		{ColumnNameS: "STATUS", RuleType: common.MappingRuleRename, ColumnNameT: "STATE"},
+		{ColumnNameS: "age", RuleType: common.MappingRuleRename, ColumnNameT: "AGE_NEW"},
+		{ColumnNameS: "REMARK", RuleType: common.MappingRuleExclude},
+		{ColumnNameS: "TOTAL", RuleType: common.MappingRuleExpr, ColumnExpr: "PRICE * QTY"},
+	} {
+		rule, err := newColumnRule(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tm.columns[trimColumnName(r.ColumnNameS)] = rule
+	}
+	return tm
+}
+
+func TestColumnNameT(t *testing.T) {
+	tm := newTestTableMapper(t, common.DatabaseTypeOracle)
+
+	cases := []struct {
+		name     string
+		column   string
+		expected string
+	}{
+		{name: "bare", column: "STATUS", expected: "STATE"},
+		{name: "lower case", column: "status", expected: "STATE"},
+		{name: "double quote", column: `"STATUS"`, expected: `"STATE"`},
+		{name: "backtick", column: "`age`", expected: "`AGE_NEW`"},
+		{name: "expr keep name", column: "TOTAL", expected: "TOTAL"},
+		{name: "without rule", column: "ID", expected: "ID"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := tm.ColumnNameT(c.column); got != c.expected {
+				t.Fatalf("column name %s, want %s", got, c.expected)
+			}
+		})
+	}
+}
+
+func TestChangeColumnList(t *testing.T) {
+	tm := newTestTableMapper(t, common.DatabaseTypeOracle)
+
+	cases := []struct {
+		name       string
+		columnList string
+		expected   string
+		ok         bool
+	}{
+		{name: "rename", columnList: `"ID","STATUS"`, expected: `"ID","STATE"`, ok: true},
+		{name: "exclude", columnList: "ID,REMARK", expected: "ID,REMARK", ok: false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, ok := tm.ChangeColumnList(c.columnList)
+			if got != c.expected || ok != c.ok {
+				t.Fatalf("column list %s %v, want %s %v", got, ok, c.expected, c.ok)
+			}
+		})
+	}
+}
+
+func TestChangeCondition(t *testing.T) {
+	cases := []struct {
+		name      string
+		dbTypeS   string
+		condition string
+		expected  string
+		ok        bool
+	}{
+		{
+			name:      "bare identifier",
+			dbTypeS:   common.DatabaseTypeOracle,
+			condition: "STATUS IN (0, 1) AND age > 18",
+			expected:  "STATE IN (0, 1) AND AGE_NEW > 18",
+			ok:        true,
+		},
+		{
+			name:      "quoted identifier",
+			dbTypeS:   common.DatabaseTypeOracle,
+			condition: `"STATUS" IS NOT NULL`,
+			expected:  `"STATE" IS NOT NULL`,
+			ok:        true,
+		},
+		{
+			name:      "string literal keep",
+			dbTypeS:   common.DatabaseTypeOracle,
+			condition: "STATUS <> 'STATUS' AND STATUS <> 'it''s age'",
+			expected:  "STATE <> 'STATUS' AND STATE <> 'it''s age'",
+			ok:        true,
+		},
+		{
+			name:      "oracle backslash literal",
+			dbTypeS:   common.DatabaseTypeOracle,
+			condition: `STATUS <> 'C:\' AND age > 0`,
+			expected:  `STATE <> 'C:\' AND AGE_NEW > 0`,
+			ok:        true,
+		},
+		{
+			name:      "mysql backslash escape",
+			dbTypeS:   common.DatabaseTypeMySQL,
+			condition: "(`status` <> _utf8mb4'\\'status\\'')",
+			expected:  "(`STATE` <> _utf8mb4'\\'status\\'')",
+			ok:        true,
+		},
+		{
+			name:      "identifier prefix",
+			dbTypeS:   common.DatabaseTypeOracle,
+			condition: "STATUS_CODE > 0 AND AGE$ > 0 AND 1E5 > 0",
+			expected:  "STATUS_CODE > 0 AND AGE$ > 0 AND 1E5 > 0",
+			ok:        true,
+		},
+		{
+			name:      "exclude column",
+			dbTypeS:   common.DatabaseTypeOracle,
+			condition: "REMARK IS NOT NULL",
+			expected:  "REMARK IS NOT NULL",
+			ok:        false,
+		},
+		{
+			name:      "exclude column in literal",
+			dbTypeS:   common.DatabaseTypeOracle,
+			condition: "STATUS <> 'REMARK'",
+			expected:  "STATE <> 'REMARK'",
+			ok:        true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, ok := newTestTableMapper(t, c.dbTypeS).ChangeCondition(c.condition)
+			if got != c.expected || ok != c.ok {
+				t.Fatalf("condition %s %v, want %s %v", got, ok, c.expected, c.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package m2o
+
+import (
+	"context"
+	"fmt"
+	"github.com/wentaojin/transferdb/common"
+	"github.com/wentaojin/transferdb/config"
+	"github.com/wentaojin/transferdb/database/meta"
+	"github.com/wentaojin/transferdb/database/mysql"
+	"github.com/wentaojin/transferdb/module/assess/oracle/public"
+	"go.uber.org/zap"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type Assess struct {
+	ctx    context.Context
+	cfg    *config.Config
+	metaDB *meta.Meta
+	mysql  *mysql.MySQL
+}
+
+func NewAssess(ctx context.Context, cfg *config.Config) (*Assess, error) {
+	mysqlDB, err := mysql.NewMySQLDBEngine(ctx, cfg.MySQLConfig)
+	if err != nil {
+		return nil, err
+	}
+	metaDB, err := meta.NewMetaDBEngine(ctx, cfg.MetaConfig, cfg.AppConfig.SlowlogThreshold)
+	if err != nil {
+		return nil, err
+	}
+	return &Assess{
+		ctx:    ctx,
+		cfg:    cfg,
+		metaDB: metaDB,
+		mysql:  mysqlDB,
+	}, nil
+}
+
+func (r *Assess) Assess() error {
+	startTime := time.Now()
+	// 评估运行编号基于评估开始时间
+	runID := public.NewAssessRunID(startTime)
+	zap.L().Info("assess mysql migrate oracle cost start",
+		zap.String("mysql Schema", r.cfg.SchemaConfig.SourceSchema),
+		zap.String("oracle Schema", r.cfg.SchemaConfig.TargetSchema))
+
+	var (
+		fileName    string
+		schemaArray []string
+	)
+	if r.cfg.SchemaConfig.SourceSchema == "" {
+		fileName = "report_all"
+	} else {
+		fileName = fmt.Sprintf("report_%s", r.cfg.SchemaConfig.SourceSchema)
+	}
+
+	schemas, err := r.mysql.GetMySQLSchema(r.cfg.SchemaConfig.SourceSchema)
+	if err != nil {
+		return err
+	}
+	if len(schemas) == 0 {
+		return fmt.Errorf("mysql schema [%v] not exist", r.cfg.SchemaConfig.SourceSchema)
+	}
+	for _, s := range schemas {
+		schemaArray = append(schemaArray, fmt.Sprintf("'%s'", s))
+	}
+
+	zap.L().Info("gather database schema array", zap.Strings("schema", schemaArray))
+
+	pwdDir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	// 评估
+	beginTime := time.Now()
+	report, err := GetAssessDatabaseReport(r.ctx, r.metaDB, r.mysql, schemaArray, fileName, r.cfg.MySQLConfig.Username, r.cfg.DBTypeS, r.cfg.DBTypeT)
+	if err != nil {
+		return err
+	}
+	finishedTime := time.Now()
+	zap.L().Info("assess database result finish",
+		zap.Strings("schema", schemaArray),
+		zap.String("cost", finishedTime.Sub(beginTime).String()))
+
+	report.RunID = runID
+
+	// 评估报告按格式输出
+	var reportFiles []string
+	for _, reportFormat := range r.cfg.AssessConfig.ReportFormat {
+		startReportTime := time.Now()
+		reportFile := filepath.Join(pwdDir, common.StringsBuilder(fileName, ".", common.AssessReportFormatFileSuffix[reportFormat]))
+		if err = public.GenNewReportFile(report, reportFormat, reportFile); err != nil {
+			return err
+		}
+		reportFiles = append(reportFiles, reportFile)
+		finishReportTime := time.Now()
+		zap.L().Info("gen database assess report finish",
+			zap.Strings("schema", schemaArray),
+			zap.String("format", reportFormat),
+			zap.String("cost", finishReportTime.Sub(startReportTime).String()))
+	}
+
+	// 评估结果保存，用于多次评估结果对比
+	if err = public.SaveAssessRun(r.ctx, r.metaDB, report, r.cfg.DBTypeS, r.cfg.DBTypeT, schemaArray); err != nil {
+		return err
+	}
+	zap.L().Info("save database assess run finish", zap.String("run id", report.RunID))
+
+	if r.cfg.BaselineConfig.BaseRunID != "" {
+		baselineFiles, err := public.GenAssessBaselineReport(r.ctx, r.metaDB, r.cfg.BaselineConfig.BaseRunID, report.RunID, r.cfg.AssessConfig.ReportFormat, pwdDir)
+		if err != nil {
+			return err
+		}
+		reportFiles = append(reportFiles, baselineFiles...)
+	}
+
+	endTime := time.Now()
+	zap.L().Info("assess mysql migrate oracle cost finished",
+		zap.String("cost", endTime.Sub(startTime).String()),
+		zap.Strings("output", reportFiles))
+	return nil
+}
+
+// GetAssessDatabaseReport MySQL 无 PL/SQL 以及 SQL 负载采集，对应章节为空
+func GetAssessDatabaseReport(ctx context.Context, metaDB *meta.Meta, mysql *mysql.MySQL, schemaName []string, reportName, reportUser, dbTypeS, dbTypeT string) (*public.Report, error) {
+	assessTotal := 0
+	compatibleS := 0
+	incompatibleS := 0
+	convertibleS := 0
+	inconvertibleS := 0
+
+	dbOverview, overviewS, err := GetAssessDatabaseOverviewResult(ctx, metaDB, mysql, reportName, reportUser, dbTypeS, dbTypeT)
+	if err != nil {
+		return nil, err
+	}
+	assessTotal += overviewS.AssessTotal
+	compatibleS += overviewS.Compatible
+	incompatibleS += overviewS.Incompatible
+	convertibleS += overviewS.Convertible
+	inconvertibleS += overviewS.InConvertible
+
+	dbCompatibles, compS, err := GetAssessDatabaseCompatibleResult(ctx, metaDB, mysql, schemaName, dbTypeS, dbTypeT)
+	if err != nil {
+		return nil, err
+	}
+	assessTotal += compS.AssessTotal
+	compatibleS += compS.Compatible
+	incompatibleS += compS.Incompatible
+	convertibleS += compS.Convertible
+	inconvertibleS += compS.InConvertible
+
+	dbChecks, checkS, err := GetAssessDatabaseCheckResult(schemaName, mysql)
+	if err != nil {
+		return nil, err
+	}
+	assessTotal += checkS.AssessTotal
+	compatibleS += checkS.Compatible
+	incompatibleS += checkS.Incompatible
+	convertibleS += checkS.Convertible
+	inconvertibleS += checkS.InConvertible
+
+	dbRelated, relatedS, err := GetAssessDatabaseRelatedResult(schemaName, mysql)
+	if err != nil {
+		return nil, err
+	}
+	assessTotal += relatedS.AssessTotal
+	compatibleS += relatedS.Compatible
+	incompatibleS += relatedS.Incompatible
+	convertibleS += relatedS.Convertible
+	inconvertibleS += relatedS.InConvertible
+
+	report := &public.Report{
+		ReportOverview: dbOverview,
+		ReportSummary: &public.ReportSummary{
+			AssessTotal:   assessTotal,
+			Compatible:    compatibleS,
+			Incompatible:  incompatibleS,
+			Convertible:   convertibleS,
+			InConvertible: inconvertibleS,
+		},
+		ReportCompatible: dbCompatibles,
+		ReportCheck:      dbChecks,
+		ReportRelated:    dbRelated,
+		ReportWorkload:   &public.ReportWorkload{SQLSource: common.AssessSQLSourceNone},
+	}
+
+	// 迁移工作量评估
+	dbEffort, err := public.GetAssessDatabaseEffortResult(ctx, metaDB, report, dbTypeS, dbTypeT)
+	if err != nil {
+		return nil, err
+	}
+	report.ReportEffort = dbEffort
+
+	return report, nil
+}
@@ -0,0 +1,217 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package m2o
+
+import (
+	"context"
+	"github.com/wentaojin/transferdb/common"
+	"github.com/wentaojin/transferdb/database/meta"
+	"github.com/wentaojin/transferdb/database/mysql"
+	"github.com/wentaojin/transferdb/module/assess/oracle/public"
+)
+
+/*
+MySQL Database Compatible
+*/
+func GetAssessDatabaseCompatibleResult(ctx context.Context, metaDB *meta.Meta, mysql *mysql.MySQL, schemaName []string, dbTypeS, dbTypeT string) (*public.ReportCompatible, *public.ReportSummary, error) {
+	var (
+		ListSchemaTableTypeCompatibles        []public.SchemaTableTypeCompatibles
+		ListSchemaColumnTypeCompatibles       []public.SchemaColumnTypeCompatibles
+		ListSchemaConstraintTypeCompatibles   []public.SchemaConstraintTypeCompatibles
+		ListSchemaIndexTypeCompatibles        []public.SchemaIndexTypeCompatibles
+		ListSchemaDefaultValueCompatibles     []public.SchemaDefaultValueCompatibles
+		ListSchemaViewTypeCompatibles         []public.SchemaViewTypeCompatibles
+		ListSchemaObjectTypeCompatibles       []public.SchemaObjectTypeCompatibles
+		ListSchemaPartitionTypeCompatibles    []public.SchemaPartitionTypeCompatibles
+		ListSchemaSubPartitionTypeCompatibles []public.SchemaSubPartitionTypeCompatibles
+		ListSchemaCharsetCompatibles          []public.SchemaCharsetCompatibles
+		ListSchemaFeatureCompatibles          []public.SchemaFeatureCompatibles
+	)
+
+	// 获取自定义兼容性内容
+	compatibles, err := meta.NewBuildinObjectCompatibleModel(metaDB).BatchQueryObjAssessCompatible(ctx, &meta.BuildinObjectCompatible{
+		DBTypeS: dbTypeS,
+		DBTypeT: dbTypeT,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	objAssessCompsMap := make(map[string]meta.BuildinObjectCompatible)
+	for _, c := range compatibles {
+		objAssessCompsMap[common.StringUPPER(c.ObjectNameS)] = c
+	}
+
+	// 获取自定义数据类型
+	buildDatatypeRules, err := meta.NewBuildinDatatypeRuleModel(metaDB).BatchQueryBuildinDatatype(ctx, &meta.BuildinDatatypeRule{
+		DBTypeS: dbTypeS,
+		DBTypeT: dbTypeT,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	buildDatatypeMap := make(map[string]meta.BuildinDatatypeRule)
+	for _, d := range buildDatatypeRules {
+		buildDatatypeMap[common.StringUPPER(d.DatatypeNameS)] = d
+	}
+
+	// 获取自定义默认值内容
+	defaultValues, err := meta.NewBuildinGlobalDefaultvalModel(metaDB).DetailGlobalDefaultVal(ctx, &meta.BuildinGlobalDefaultval{
+		DBTypeS: dbTypeS,
+		DBTypeT: dbTypeT})
+	if err != nil {
+		return nil, nil, err
+	}
+	defaultValuesMap := make(map[string]meta.BuildinGlobalDefaultval)
+	for _, d := range defaultValues {
+		defaultValuesMap[common.StringUPPER(d.DefaultValueS)] = d
+	}
+
+	assessTotal := 0
+	compatibleS := 0
+	incompatibleS := 0
+	convertibleS := 0
+	inconvertibleS := 0
+
+	ListSchemaTableTypeCompatibles, tableSummary, err := AssessMySQLSchemaTableTypeCompatible(schemaName, mysql, objAssessCompsMap)
+	if err != nil {
+		return nil, nil, err
+	}
+	assessTotal += tableSummary.AssessTotal
+	compatibleS += tableSummary.Compatible
+	incompatibleS += tableSummary.Incompatible
+	convertibleS += tableSummary.Convertible
+	inconvertibleS += tableSummary.InConvertible
+
+	ListSchemaColumnTypeCompatibles, columnSummary, err := AssessMySQLSchemaColumnTypeCompatible(schemaName, mysql, buildDatatypeMap)
+	if err != nil {
+		return nil, nil, err
+	}
+	assessTotal += columnSummary.AssessTotal
+	compatibleS += columnSummary.Compatible
+	incompatibleS += columnSummary.Incompatible
+	convertibleS += columnSummary.Convertible
+	inconvertibleS += columnSummary.InConvertible
+
+	ListSchemaConstraintTypeCompatibles, constraintSummary, err := AssessMySQLSchemaConstraintTypeCompatible(schemaName, mysql, objAssessCompsMap)
+	if err != nil {
+		return nil, nil, err
+	}
+	assessTotal += constraintSummary.AssessTotal
+	compatibleS += constraintSummary.Compatible
+	incompatibleS += constraintSummary.Incompatible
+	convertibleS += constraintSummary.Convertible
+	inconvertibleS += constraintSummary.InConvertible
+
+	ListSchemaIndexTypeCompatibles, indexSummary, err := AssessMySQLSchemaIndexTypeCompatible(schemaName, mysql, objAssessCompsMap)
+	if err != nil {
+		return nil, nil, err
+	}
+	assessTotal += indexSummary.AssessTotal
+	compatibleS += indexSummary.Compatible
+	incompatibleS += indexSummary.Incompatible
+	convertibleS += indexSummary.Convertible
+	inconvertibleS += indexSummary.InConvertible
+
+	ListSchemaDefaultValueCompatibles, defaultValSummary, err := AssessMySQLSchemaDefaultValue(schemaName, mysql, defaultValuesMap)
+	if err != nil {
+		return nil, nil, err
+	}
+	assessTotal += defaultValSummary.AssessTotal
+	compatibleS += defaultValSummary.Compatible
+	incompatibleS += defaultValSummary.Incompatible
+	convertibleS += defaultValSummary.Convertible
+	inconvertibleS += defaultValSummary.InConvertible
+
+	ListSchemaViewTypeCompatibles, viewSummary, err := AssessMySQLSchemaViewTypeCompatible(schemaName, mysql, objAssessCompsMap)
+	if err != nil {
+		return nil, nil, err
+	}
+	assessTotal += viewSummary.AssessTotal
+	compatibleS += viewSummary.Compatible
+	incompatibleS += viewSummary.Incompatible
+	convertibleS += viewSummary.Convertible
+	inconvertibleS += viewSummary.InConvertible
+
+	ListSchemaObjectTypeCompatibles, codeSummary, err := AssessMySQLSchemaObjectTypeCompatible(schemaName, mysql, objAssessCompsMap)
+	if err != nil {
+		return nil, nil, err
+	}
+	assessTotal += codeSummary.AssessTotal
+	compatibleS += codeSummary.Compatible
+	incompatibleS += codeSummary.Incompatible
+	convertibleS += codeSummary.Convertible
+	inconvertibleS += codeSummary.InConvertible
+
+	ListSchemaPartitionTypeCompatibles, partitionSummary, err := AssessMySQLSchemaPartitionTypeCompatible(schemaName, mysql, objAssessCompsMap)
+	if err != nil {
+		return nil, nil, err
+	}
+	assessTotal += partitionSummary.AssessTotal
+	compatibleS += partitionSummary.Compatible
+	incompatibleS += partitionSummary.Incompatible
+	convertibleS += partitionSummary.Convertible
+	inconvertibleS += partitionSummary.InConvertible
+
+	ListSchemaSubPartitionTypeCompatibles, subPartitionSummary, err := AssessMySQLSchemaSubPartitionTypeCompatible(schemaName, mysql, objAssessCompsMap)
+	if err != nil {
+		return nil, nil, err
+	}
+	assessTotal += subPartitionSummary.AssessTotal
+	compatibleS += subPartitionSummary.Compatible
+	incompatibleS += subPartitionSummary.Incompatible
+	convertibleS += subPartitionSummary.Convertible
+	inconvertibleS += subPartitionSummary.InConvertible
+
+	ListSchemaCharsetCompatibles, charsetSummary, err := AssessMySQLSchemaCharsetCompatible(schemaName, mysql, common.StringsBuilder(common.StringUPPER(dbTypeS), "2", common.StringUPPER(dbTypeT)), objAssessCompsMap)
+	if err != nil {
+		return nil, nil, err
+	}
+	assessTotal += charsetSummary.AssessTotal
+	compatibleS += charsetSummary.Compatible
+	incompatibleS += charsetSummary.Incompatible
+	convertibleS += charsetSummary.Convertible
+	inconvertibleS += charsetSummary.InConvertible
+
+	ListSchemaFeatureCompatibles, featureSummary, err := AssessMySQLSchemaFeatureCompatible(schemaName, mysql, objAssessCompsMap)
+	if err != nil {
+		return nil, nil, err
+	}
+	assessTotal += featureSummary.AssessTotal
+	compatibleS += featureSummary.Compatible
+	incompatibleS += featureSummary.Incompatible
+	convertibleS += featureSummary.Convertible
+	inconvertibleS += featureSummary.InConvertible
+
+	return &public.ReportCompatible{
+		ListSchemaTableTypeCompatibles:        ListSchemaTableTypeCompatibles,
+		ListSchemaColumnTypeCompatibles:       ListSchemaColumnTypeCompatibles,
+		ListSchemaConstraintTypeCompatibles:   ListSchemaConstraintTypeCompatibles,
+		ListSchemaIndexTypeCompatibles:        ListSchemaIndexTypeCompatibles,
+		ListSchemaDefaultValueCompatibles:     ListSchemaDefaultValueCompatibles,
+		ListSchemaViewTypeCompatibles:         ListSchemaViewTypeCompatibles,
+		ListSchemaObjectTypeCompatibles:       ListSchemaObjectTypeCompatibles,
+		ListSchemaPartitionTypeCompatibles:    ListSchemaPartitionTypeCompatibles,
+		ListSchemaSubPartitionTypeCompatibles: ListSchemaSubPartitionTypeCompatibles,
+		ListSchemaCharsetCompatibles:          ListSchemaCharsetCompatibles,
+		ListSchemaFeatureCompatibles:          ListSchemaFeatureCompatibles,
+	}, &public.ReportSummary{
+		AssessTotal:   assessTotal,
+		Compatible:    compatibleS,
+		Incompatible:  incompatibleS,
+		Convertible:   convertibleS,
+		InConvertible: inconvertibleS,
+	}, nil
+}
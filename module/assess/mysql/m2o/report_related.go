@@ -0,0 +1,104 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package m2o
+
+import (
+	"github.com/wentaojin/transferdb/database/mysql"
+	"github.com/wentaojin/transferdb/module/assess/oracle/public"
+)
+
+/*
+MySQL Database Related
+*/
+func GetAssessDatabaseRelatedResult(schemaName []string, mysql *mysql.MySQL) (*public.ReportRelated, *public.ReportSummary, error) {
+	var (
+		ListSchemaActiveSession        []public.SchemaActiveSession
+		ListSchemaTableSizeData        []public.SchemaTableSizeData
+		ListSchemaTableRowsTOP         []public.SchemaTableRowsTOP
+		ListSchemaCodeObject           []public.SchemaCodeObject
+		ListSchemaTableAvgRowLengthTOP []public.SchemaTableAvgRowLengthTOP
+	)
+
+	assessTotal := 0
+	compatibleS := 0
+	incompatibleS := 0
+	convertibleS := 0
+	inconvertibleS := 0
+
+	ListSchemaActiveSession, sessionSummary, err := AssessMySQLMaxActiveSessionCount(mysql)
+	if err != nil {
+		return nil, nil, err
+	}
+	assessTotal += sessionSummary.AssessTotal
+	compatibleS += sessionSummary.Compatible
+	incompatibleS += sessionSummary.Incompatible
+	convertibleS += sessionSummary.Convertible
+	inconvertibleS += sessionSummary.InConvertible
+
+	ListSchemaTableSizeData, overviewSummary, err := AssessMySQLSchemaOverview(schemaName, mysql)
+	if err != nil {
+		return nil, nil, err
+	}
+	assessTotal += overviewSummary.AssessTotal
+	compatibleS += overviewSummary.Compatible
+	incompatibleS += overviewSummary.Incompatible
+	convertibleS += overviewSummary.Convertible
+	inconvertibleS += overviewSummary.InConvertible
+
+	ListSchemaTableRowsTOP, tableSummary, err := AssessMySQLSchemaTableRowsTOP(schemaName, mysql)
+	if err != nil {
+		return nil, nil, err
+	}
+	assessTotal += tableSummary.AssessTotal
+	compatibleS += tableSummary.Compatible
+	incompatibleS += tableSummary.Incompatible
+	convertibleS += tableSummary.Convertible
+	inconvertibleS += tableSummary.InConvertible
+
+	ListSchemaCodeObject, codeSummary, err := AssessMySQLSchemaCodeOverview(schemaName, mysql)
+	if err != nil {
+		return nil, nil, err
+	}
+	assessTotal += codeSummary.AssessTotal
+	compatibleS += codeSummary.Compatible
+	incompatibleS += codeSummary.Incompatible
+	convertibleS += codeSummary.Convertible
+	inconvertibleS += codeSummary.InConvertible
+
+	ListSchemaTableAvgRowLengthTOP, tableTSummary, err := AssessMySQLSchemaTableAvgRowLengthTOP(schemaName, mysql)
+	if err != nil {
+		return nil, nil, err
+	}
+	assessTotal += tableTSummary.AssessTotal
+	compatibleS += tableTSummary.Compatible
+	incompatibleS += tableTSummary.Incompatible
+	convertibleS += tableTSummary.Convertible
+	inconvertibleS += tableTSummary.InConvertible
+
+	return &public.ReportRelated{
+		ListSchemaActiveSession:        ListSchemaActiveSession,
+		ListSchemaTableSizeData:        ListSchemaTableSizeData,
+		ListSchemaTableRowsTOP:         ListSchemaTableRowsTOP,
+		ListSchemaCodeObject:           ListSchemaCodeObject,
+		ListSchemaTableAvgRowLengthTOP: ListSchemaTableAvgRowLengthTOP,
+	}, &public.ReportSummary{
+		AssessTotal:   assessTotal,
+		Compatible:    compatibleS,
+		Incompatible:  incompatibleS,
+		Convertible:   convertibleS,
+		InConvertible: inconvertibleS,
+	}, nil
+}
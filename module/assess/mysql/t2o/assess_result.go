@@ -0,0 +1,737 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package t2o
+
+import (
+	"fmt"
+	"github.com/wentaojin/transferdb/common"
+	"github.com/wentaojin/transferdb/database/meta"
+	"github.com/wentaojin/transferdb/database/mysql"
+	"github.com/wentaojin/transferdb/module/assess/oracle/public"
+	"strconv"
+	"strings"
+)
+
+// assessObjectCompatible 基于 [buildin_object_compatible] 规则判断对象兼容性并计数，未配置规则的对象不兼容且不可改造
+func assessObjectCompatible(objAssessCompsMap map[string]meta.BuildinObjectCompatible, objectName string, rs *public.ReportSummary) (string, string) {
+	isCompatible := common.AssessNoCompatible
+	isConvertible := common.AssessNoConvertible
+	if val, ok := objAssessCompsMap[common.StringUPPER(objectName)]; ok {
+		isCompatible = val.IsCompatible
+		isConvertible = val.IsConvertible
+	}
+	if strings.EqualFold(isCompatible, common.AssessYesCompatible) {
+		rs.Compatible += 1
+	} else {
+		rs.Incompatible += 1
+	}
+	if strings.EqualFold(isConvertible, common.AssessYesConvertible) {
+		rs.Convertible += 1
+	} else {
+		rs.InConvertible += 1
+	}
+	return isCompatible, isConvertible
+}
+
+/*
+MySQL Database Overview
+*/
+func AssessMySQLDBOverview(mysql *mysql.MySQL, objAssessCompsMap map[string]meta.BuildinObjectCompatible, reportName, reportUser string) (*public.ReportOverview, public.ReportSummary, error) {
+	overview, err := mysql.GetMySQLDBOverview()
+	if err != nil {
+		return nil, public.ReportSummary{}, err
+	}
+	dataSize, err := mysql.GetMySQLDataTotal()
+	if err != nil {
+		return nil, public.ReportSummary{}, err
+	}
+
+	var rs public.ReportSummary
+	assessObjectCompatible(objAssessCompsMap, overview["CHARACTER_SET"], &rs)
+
+	return &public.ReportOverview{
+			ReportName:        reportName,
+			ReportUser:        reportUser,
+			HostName:          overview["HOST_NAME"],
+			PlatformName:      fmt.Sprintf("%s/%s", overview["VERSION_COMPILE_OS"], overview["VERSION_COMPILE_MACHINE"]),
+			DBName:            overview["VERSION"],
+			GlobalDBName:      overview["VERSION_COMMENT"],
+			ClusterDB:         "",
+			ClusterDBInstance: "",
+			InstanceName:      fmt.Sprintf("%s:%s", overview["HOST_NAME"], overview["PORT"]),
+			InstanceNumber:    overview["SERVER_ID"],
+			ThreadNumber:      "",
+			BlockSize:         "",
+			TotalUsedSize:     dataSize,
+			HostCPUS:          "",
+			HostMem:           "",
+			CharacterSet:      fmt.Sprintf("%s/%s", overview["CHARACTER_SET"], overview["COLLATION"])},
+		public.ReportSummary{
+			AssessType:    common.AssessTypeDatabaseOverview,
+			AssessName:    common.AssessNameDBOverview,
+			AssessTotal:   1,
+			Compatible:    rs.Compatible,
+			Incompatible:  rs.Incompatible,
+			Convertible:   rs.Convertible,
+			InConvertible: rs.InConvertible,
+		}, nil
+}
+
+/*
+MySQL Database Compatible
+*/
+// AssessMySQLSchemaTableTypeCompatible 表类型基于存储引擎
+func AssessMySQLSchemaTableTypeCompatible(schemaName []string, mysql *mysql.MySQL, objAssessCompsMap map[string]meta.BuildinObjectCompatible) ([]public.SchemaTableTypeCompatibles, public.ReportSummary, error) {
+	res, err := mysql.GetMySQLSchemaTableTypeCounts(schemaName)
+	if err != nil {
+		return nil, public.ReportSummary{}, err
+	}
+	if len(res) == 0 {
+		return nil, public.ReportSummary{}, nil
+	}
+
+	var (
+		listData []public.SchemaTableTypeCompatibles
+		rs       public.ReportSummary
+	)
+	for _, ow := range res {
+		isCompatible, isConvertible := assessObjectCompatible(objAssessCompsMap, ow["TABLE_TYPE"], &rs)
+		listData = append(listData, public.SchemaTableTypeCompatibles{
+			Schema:        ow["SCHEMA_NAME"],
+			TableType:     ow["TABLE_TYPE"],
+			ObjectCounts:  ow["COUNTS"],
+			ObjectSize:    ow["OBJECT_SIZE"],
+			IsCompatible:  isCompatible,
+			IsConvertible: isConvertible,
+		})
+	}
+
+	return listData, public.ReportSummary{
+		AssessType:    common.AssessTypeObjectTypeCompatible,
+		AssessName:    common.AssessNameTableTypeCompatible,
+		AssessTotal:   len(listData),
+		Compatible:    rs.Compatible,
+		Incompatible:  rs.Incompatible,
+		Convertible:   rs.Convertible,
+		InConvertible: rs.InConvertible,
+	}, nil
+}
+
+func AssessMySQLSchemaColumnTypeCompatible(schemaName []string, mysql *mysql.MySQL, buildinDatatypeMap map[string]meta.BuildinDatatypeRule) ([]public.SchemaColumnTypeCompatibles, public.ReportSummary, error) {
+	columnInfo, err := mysql.GetMySQLSchemaColumnTypeCounts(schemaName)
+	if err != nil {
+		return nil, public.ReportSummary{}, err
+	}
+	if len(columnInfo) == 0 {
+		return nil, public.ReportSummary{}, nil
+	}
+
+	var (
+		listData []public.SchemaColumnTypeCompatibles
+		rs       public.ReportSummary
+	)
+	for _, ow := range columnInfo {
+		if val, ok := buildinDatatypeMap[common.StringUPPER(ow["DATA_TYPE"])]; ok {
+			listData = append(listData, public.SchemaColumnTypeCompatibles{
+				Schema:        ow["OWNER"],
+				ColumnType:    ow["DATA_TYPE"],
+				ObjectCounts:  ow["COUNT"],
+				MaxDataLength: ow["MAX_DATA_LENGTH"],
+				ColumnTypeMap: val.DatatypeNameT,
+				IsEquivalent:  common.AssessYesEquivalent,
+			})
+			rs.Compatible += 1
+			rs.Convertible += 1
+		} else {
+			listData = append(listData, public.SchemaColumnTypeCompatibles{
+				Schema:        ow["OWNER"],
+				ColumnType:    ow["DATA_TYPE"],
+				ObjectCounts:  ow["COUNT"],
+				MaxDataLength: ow["MAX_DATA_LENGTH"],
+				IsEquivalent:  common.AssessNoEquivalent,
+			})
+			rs.Incompatible += 1
+			rs.InConvertible += 1
+		}
+	}
+
+	return listData, public.ReportSummary{
+		AssessType:    common.AssessTypeObjectTypeCompatible,
+		AssessName:    common.AssessNameColumnTypeCompatible,
+		AssessTotal:   len(listData),
+		Compatible:    rs.Compatible,
+		Incompatible:  rs.Incompatible,
+		Convertible:   rs.Convertible,
+		InConvertible: rs.InConvertible,
+	}, nil
+}
+
+func AssessMySQLSchemaConstraintTypeCompatible(schemaName []string, mysql *mysql.MySQL, objAssessCompsMap map[string]meta.BuildinObjectCompatible) ([]public.SchemaConstraintTypeCompatibles, public.ReportSummary, error) {
+	res, err := mysql.GetMySQLSchemaConstraintTypeCounts(schemaName)
+	if err != nil {
+		return nil, public.ReportSummary{}, err
+	}
+	if len(res) == 0 {
+		return nil, public.ReportSummary{}, nil
+	}
+
+	var (
+		listData []public.SchemaConstraintTypeCompatibles
+		rs       public.ReportSummary
+	)
+	for _, ow := range res {
+		isCompatible, isConvertible := assessObjectCompatible(objAssessCompsMap, ow["CONSTRAINT_TYPE"], &rs)
+		listData = append(listData, public.SchemaConstraintTypeCompatibles{
+			Schema:         ow["OWNER"],
+			ConstraintType: ow["CONSTRAINT_TYPE"],
+			ObjectCounts:   ow["COUNTS"],
+			IsCompatible:   isCompatible,
+			IsConvertible:  isConvertible,
+		})
+	}
+
+	return listData, public.ReportSummary{
+		AssessType:    common.AssessTypeObjectTypeCompatible,
+		AssessName:    common.AssessNameConstraintTypeCompatible,
+		AssessTotal:   len(listData),
+		Compatible:    rs.Compatible,
+		Incompatible:  rs.Incompatible,
+		Convertible:   rs.Convertible,
+		InConvertible: rs.InConvertible,
+	}, nil
+}
+
+func AssessMySQLSchemaIndexTypeCompatible(schemaName []string, mysql *mysql.MySQL, objAssessCompsMap map[string]meta.BuildinObjectCompatible) ([]public.SchemaIndexTypeCompatibles, public.ReportSummary, error) {
+	res, err := mysql.GetMySQLSchemaIndexTypeCounts(schemaName)
+	if err != nil {
+		return nil, public.ReportSummary{}, err
+	}
+	if len(res) == 0 {
+		return nil, public.ReportSummary{}, nil
+	}
+
+	var (
+		listData []public.SchemaIndexTypeCompatibles
+		rs       public.ReportSummary
+	)
+	for _, ow := range res {
+		isCompatible, isConvertible := assessObjectCompatible(objAssessCompsMap, ow["INDEX_TYPE"], &rs)
+		listData = append(listData, public.SchemaIndexTypeCompatibles{
+			Schema:        ow["OWNER"],
+			IndexType:     ow["INDEX_TYPE"],
+			ObjectCounts:  ow["COUNTS"],
+			IsCompatible:  isCompatible,
+			IsConvertible: isConvertible,
+		})
+	}
+
+	return listData, public.ReportSummary{
+		AssessType:    common.AssessTypeObjectTypeCompatible,
+		AssessName:    common.AssessNameIndexTypeCompatible,
+		AssessTotal:   len(listData),
+		Compatible:    rs.Compatible,
+		Incompatible:  rs.Incompatible,
+		Convertible:   rs.Convertible,
+		InConvertible: rs.InConvertible,
+	}, nil
+}
+
+// AssessMySQLSchemaDefaultValue 默认值优先匹配 [buildin_global_defaultval] 规则，常量默认值兼容，表达式默认值需改造
+func AssessMySQLSchemaDefaultValue(schemaName []string, mysql *mysql.MySQL, defaultValueMap map[string]meta.BuildinGlobalDefaultval) ([]public.SchemaDefaultValueCompatibles, public.ReportSummary, error) {
+	dataDefaults, err := mysql.GetMySQLSchemaColumnDataDefaultCounts(schemaName)
+	if err != nil {
+		return nil, public.ReportSummary{}, err
+	}
+	if len(dataDefaults) == 0 {
+		return nil, public.ReportSummary{}, nil
+	}
+
+	var (
+		listData []public.SchemaDefaultValueCompatibles
+		rs       public.ReportSummary
+	)
+	for _, ow := range dataDefaults {
+		defaultVal := common.StringUPPER(ow["DATA_DEFAULT"])
+		// CURRENT_TIMESTAMP(6) 等带精度时间函数
+		if strings.HasPrefix(defaultVal, common.BuildInMySQLColumnDefaultValueCurrentTimestamp) {
+			defaultVal = common.BuildInMySQLColumnDefaultValueCurrentTimestamp
+		}
+		if val, ok := defaultValueMap[defaultVal]; ok {
+			listData = append(listData, public.SchemaDefaultValueCompatibles{
+				Schema:             ow["OWNER"],
+				ColumnDefaultValue: ow["DATA_DEFAULT"],
+				ObjectCounts:       ow["COUNTS"],
+				DefaultValueMap:    val.DefaultValueT,
+				IsCompatible:       common.AssessYesCompatible,
+				IsConvertible:      common.AssessYesConvertible,
+			})
+			rs.Compatible += 1
+			rs.Convertible += 1
+		} else if strings.EqualFold(ow["IS_EXPRESSION"], "YES") {
+			listData = append(listData, public.SchemaDefaultValueCompatibles{
+				Schema:             ow["OWNER"],
+				ColumnDefaultValue: ow["DATA_DEFAULT"],
+				ObjectCounts:       ow["COUNTS"],
+				IsCompatible:       common.AssessNoCompatible,
+				IsConvertible:      common.AssessYesConvertible,
+			})
+			rs.Incompatible += 1
+			rs.Convertible += 1
+		} else {
+			listData = append(listData, public.SchemaDefaultValueCompatibles{
+				Schema:             ow["OWNER"],
+				ColumnDefaultValue: ow["DATA_DEFAULT"],
+				ObjectCounts:       ow["COUNTS"],
+				DefaultValueMap:    ow["DATA_DEFAULT"],
+				IsCompatible:       common.AssessYesCompatible,
+				IsConvertible:      common.AssessYesConvertible,
+			})
+			rs.Compatible += 1
+			rs.Convertible += 1
+		}
+	}
+
+	return listData, public.ReportSummary{
+		AssessType:    common.AssessTypeObjectTypeCompatible,
+		AssessName:    common.AssessNameDefaultValueCompatible,
+		AssessTotal:   len(listData),
+		Compatible:    rs.Compatible,
+		Incompatible:  rs.Incompatible,
+		Convertible:   rs.Convertible,
+		InConvertible: rs.InConvertible,
+	}, nil
+}
+
+func AssessMySQLSchemaViewTypeCompatible(schemaName []string, mysql *mysql.MySQL, objAssessCompsMap map[string]meta.BuildinObjectCompatible) ([]public.SchemaViewTypeCompatibles, public.ReportSummary, error) {
+	res, err := mysql.GetMySQLSchemaViewTypeCounts(schemaName)
+	if err != nil {
+		return nil, public.ReportSummary{}, err
+	}
+	if len(res) == 0 {
+		return nil, public.ReportSummary{}, nil
+	}
+
+	var (
+		listData []public.SchemaViewTypeCompatibles
+		rs       public.ReportSummary
+	)
+	for _, ow := range res {
+		isCompatible, isConvertible := assessObjectCompatible(objAssessCompsMap, ow["VIEW_TYPE"], &rs)
+		listData = append(listData, public.SchemaViewTypeCompatibles{
+			Schema:        ow["OWNER"],
+			ViewType:      ow["VIEW_TYPE"],
+			ViewTypeOwner: ow["VIEW_TYPE_OWNER"],
+			ObjectCounts:  ow["COUNTS"],
+			IsCompatible:  isCompatible,
+			IsConvertible: isConvertible,
+		})
+	}
+
+	return listData, public.ReportSummary{
+		AssessType:    common.AssessTypeObjectTypeCompatible,
+		AssessName:    common.AssessNameViewTypeCompatible,
+		AssessTotal:   len(listData),
+		Compatible:    rs.Compatible,
+		Incompatible:  rs.Incompatible,
+		Convertible:   rs.Convertible,
+		InConvertible: rs.InConvertible,
+	}, nil
+}
+
+func AssessMySQLSchemaObjectTypeCompatible(schemaName []string, mysql *mysql.MySQL, objAssessCompsMap map[string]meta.BuildinObjectCompatible) ([]public.SchemaObjectTypeCompatibles, public.ReportSummary, error) {
+	res, err := mysql.GetMySQLSchemaObjectTypeCounts(schemaName)
+	if err != nil {
+		return nil, public.ReportSummary{}, err
+	}
+	if len(res) == 0 {
+		return nil, public.ReportSummary{}, nil
+	}
+
+	var (
+		listData []public.SchemaObjectTypeCompatibles
+		rs       public.ReportSummary
+	)
+	for _, ow := range res {
+		isCompatible, isConvertible := assessObjectCompatible(objAssessCompsMap, ow["OBJECT_TYPE"], &rs)
+		listData = append(listData, public.SchemaObjectTypeCompatibles{
+			Schema:        ow["OWNER"],
+			ObjectType:    ow["OBJECT_TYPE"],
+			ObjectCounts:  ow["COUNTS"],
+			IsCompatible:  isCompatible,
+			IsConvertible: isConvertible,
+		})
+	}
+
+	return listData, public.ReportSummary{
+		AssessType:    common.AssessTypeObjectTypeCompatible,
+		AssessName:    common.AssessNameObjectTypeCompatible,
+		AssessTotal:   len(listData),
+		Compatible:    rs.Compatible,
+		Incompatible:  rs.Incompatible,
+		Convertible:   rs.Convertible,
+		InConvertible: rs.InConvertible,
+	}, nil
+}
+
+func AssessMySQLSchemaPartitionTypeCompatible(schemaName []string, mysql *mysql.MySQL, objAssessCompsMap map[string]meta.BuildinObjectCompatible) ([]public.SchemaPartitionTypeCompatibles, public.ReportSummary, error) {
+	res, err := mysql.GetMySQLSchemaPartitionTypeCounts(schemaName)
+	if err != nil {
+		return nil, public.ReportSummary{}, err
+	}
+	if len(res) == 0 {
+		return nil, public.ReportSummary{}, nil
+	}
+
+	var (
+		listData []public.SchemaPartitionTypeCompatibles
+		rs       public.ReportSummary
+	)
+	for _, ow := range res {
+		isCompatible, isConvertible := assessObjectCompatible(objAssessCompsMap, ow["PARTITIONING_TYPE"], &rs)
+		listData = append(listData, public.SchemaPartitionTypeCompatibles{
+			Schema:        ow["OWNER"],
+			PartitionType: ow["PARTITIONING_TYPE"],
+			ObjectCounts:  ow["COUNTS"],
+			IsCompatible:  isCompatible,
+			IsConvertible: isConvertible,
+		})
+	}
+
+	return listData, public.ReportSummary{
+		AssessType:    common.AssessTypeObjectTypeCompatible,
+		AssessName:    common.AssessNamePartitionTypeCompatible,
+		AssessTotal:   len(listData),
+		Compatible:    rs.Compatible,
+		Incompatible:  rs.Incompatible,
+		Convertible:   rs.Convertible,
+		InConvertible: rs.InConvertible,
+	}, nil
+}
+
+func AssessMySQLSchemaSubPartitionTypeCompatible(schemaName []string, mysql *mysql.MySQL, objAssessCompsMap map[string]meta.BuildinObjectCompatible) ([]public.SchemaSubPartitionTypeCompatibles, public.ReportSummary, error) {
+	res, err := mysql.GetMySQLSchemaSubPartitionTypeCounts(schemaName)
+	if err != nil {
+		return nil, public.ReportSummary{}, err
+	}
+	if len(res) == 0 {
+		return nil, public.ReportSummary{}, nil
+	}
+
+	var (
+		listData []public.SchemaSubPartitionTypeCompatibles
+		rs       public.ReportSummary
+	)
+	for _, ow := range res {
+		isCompatible, isConvertible := assessObjectCompatible(objAssessCompsMap, ow["SUBPARTITIONING_TYPE"], &rs)
+		listData = append(listData, public.SchemaSubPartitionTypeCompatibles{
+			Schema:           ow["OWNER"],
+			SubPartitionType: ow["SUBPARTITIONING_TYPE"],
+			ObjectCounts:     ow["COUNTS"],
+			IsCompatible:     isCompatible,
+			IsConvertible:    isConvertible,
+		})
+	}
+
+	return listData, public.ReportSummary{
+		AssessType:    common.AssessTypeObjectTypeCompatible,
+		AssessName:    common.AssessNameSubPartitionTypeCompatible,
+		AssessTotal:   len(listData),
+		Compatible:    rs.Compatible,
+		Incompatible:  rs.Incompatible,
+		Convertible:   rs.Convertible,
+		InConvertible: rs.InConvertible,
+	}, nil
+}
+
+// AssessMySQLSchemaCharsetCompatible 字段字符集以及排序规则映射基于表结构迁移映射规则，字符集兼容性基于 [buildin_object_compatible] 规则
+func AssessMySQLSchemaCharsetCompatible(schemaName []string, mysql *mysql.MySQL, taskType string, objAssessCompsMap map[string]meta.BuildinObjectCompatible) ([]public.SchemaCharsetCompatibles, public.ReportSummary, error) {
+	charsets, err := mysql.GetMySQLSchemaColumnCharsetCounts(schemaName)
+	if err != nil {
+		return nil, public.ReportSummary{}, err
+	}
+	if len(charsets) == 0 {
+		return nil, public.ReportSummary{}, nil
+	}
+
+	var (
+		listData []public.SchemaCharsetCompatibles
+		rs       public.ReportSummary
+	)
+	for _, ow := range charsets {
+		charset := common.StringUPPER(ow["CHARACTER_SET"])
+		charsetMap := common.MigrateTableStructureDatabaseCharsetMap[taskType][charset]
+		collationMap := common.MigrateTableStructureDatabaseCollationMap[taskType][common.StringUPPER(ow["COLLATION"])][charsetMap]
+
+		var isCompatible, isConvertible string
+		if charsetMap == "" || collationMap == "" {
+			isCompatible = common.AssessNoCompatible
+			isConvertible = common.AssessNoConvertible
+			if val, ok := objAssessCompsMap[charset]; ok {
+				isConvertible = val.IsConvertible
+			}
+			rs.Incompatible += 1
+			if strings.EqualFold(isConvertible, common.AssessYesConvertible) {
+				rs.Convertible += 1
+			} else {
+				rs.InConvertible += 1
+			}
+		} else {
+			isCompatible, isConvertible = assessObjectCompatible(objAssessCompsMap, charset, &rs)
+		}
+
+		listData = append(listData, public.SchemaCharsetCompatibles{
+			Schema:          ow["OWNER"],
+			CharacterSet:    ow["CHARACTER_SET"],
+			Collation:       ow["COLLATION"],
+			ObjectCounts:    ow["COUNTS"],
+			CharacterSetMap: charsetMap,
+			CollationMap:    collationMap,
+			IsCompatible:    isCompatible,
+			IsConvertible:   isConvertible,
+		})
+	}
+
+	return listData, public.ReportSummary{
+		AssessType:    common.AssessTypeObjectTypeCompatible,
+		AssessName:    common.AssessNameCharsetCompatible,
+		AssessTotal:   len(listData),
+		Compatible:    rs.Compatible,
+		Incompatible:  rs.Incompatible,
+		Convertible:   rs.Convertible,
+		InConvertible: rs.InConvertible,
+	}, nil
+}
+
+// AssessMySQLSchemaFeatureCompatible ENUM/SET、UNSIGNED、AUTO_INCREMENT、生成列等特性按字段数统计
+func AssessMySQLSchemaFeatureCompatible(schemaName []string, mysql *mysql.MySQL, objAssessCompsMap map[string]meta.BuildinObjectCompatible) ([]public.SchemaFeatureCompatibles, public.ReportSummary, error) {
+	features, err := mysql.GetMySQLSchemaFeatureCounts(schemaName)
+	if err != nil {
+		return nil, public.ReportSummary{}, err
+	}
+	if len(features) == 0 {
+		return nil, public.ReportSummary{}, nil
+	}
+
+	var (
+		listData []public.SchemaFeatureCompatibles
+		rs       public.ReportSummary
+	)
+	for _, ow := range features {
+		for _, f := range common.BuildInMySQLFeatures {
+			counts, err := strconv.Atoi(ow[f])
+			if err != nil || counts == 0 {
+				continue
+			}
+			isCompatible, isConvertible := assessObjectCompatible(objAssessCompsMap, f, &rs)
+			listData = append(listData, public.SchemaFeatureCompatibles{
+				Schema:        ow["OWNER"],
+				Feature:       f,
+				ObjectCounts:  ow[f],
+				IsCompatible:  isCompatible,
+				IsConvertible: isConvertible,
+			})
+		}
+	}
+
+	return listData, public.ReportSummary{
+		AssessType:    common.AssessTypeObjectTypeCompatible,
+		AssessName:    common.AssessNameFeatureCompatible,
+		AssessTotal:   len(listData),
+		Compatible:    rs.Compatible,
+		Incompatible:  rs.Incompatible,
+		Convertible:   rs.Convertible,
+		InConvertible: rs.InConvertible,
+	}, nil
+}
+
+/*
+MySQL Database Check
+*/
+// AssessMySQLIdentifierCheck 对象名长度超过 Oracle 标识符长度限制或者与 Oracle 保留字冲突，需重命名或者使用双引号
+func AssessMySQLIdentifierCheck(schemaName []string, mysql *mysql.MySQL) ([]public.SchemaIdentifierCheck, public.ReportSummary, error) {
+	identifiers, err := mysql.GetMySQLSchemaIdentifierCheck(schemaName, common.AssessOracleIdentifierMaxLength, common.AssessOracleReservedWords)
+	if err != nil {
+		return nil, public.ReportSummary{}, err
+	}
+	if len(identifiers) == 0 {
+		return nil, public.ReportSummary{}, nil
+	}
+
+	reservedWords := make(map[string]struct{})
+	for _, w := range common.AssessOracleReservedWords {
+		reservedWords[w] = struct{}{}
+	}
+
+	var (
+		listData []public.SchemaIdentifierCheck
+		rs       public.ReportSummary
+	)
+	for _, ow := range identifiers {
+		var checkItems []string
+		if length, err := strconv.Atoi(ow["LENGTH"]); err == nil && length > common.AssessOracleIdentifierMaxLength {
+			checkItems = append(checkItems, common.AssessIdentifierCheckLengthOver)
+		}
+		if _, ok := reservedWords[common.StringUPPER(ow["OBJECT_NAME"])]; ok {
+			checkItems = append(checkItems, common.AssessIdentifierCheckReservedWords)
+		}
+		listData = append(listData, public.SchemaIdentifierCheck{
+			Schema:     ow["OWNER"],
+			ObjectType: ow["OBJECT_TYPE"],
+			TableName:  ow["TABLE_NAME"],
+			ObjectName: ow["OBJECT_NAME"],
+			Length:     ow["LENGTH"],
+			CheckItem:  strings.Join(checkItems, ","),
+		})
+	}
+
+	return listData, public.ReportSummary{
+		AssessType:    common.AssessTypeObjectTypeCheck,
+		AssessName:    common.AssessNameIdentifierCheck,
+		AssessTotal:   len(listData),
+		Compatible:    rs.Compatible,
+		Incompatible:  rs.Incompatible,
+		Convertible:   rs.Convertible,
+		InConvertible: rs.InConvertible,
+	}, nil
+}
+
+/*
+MySQL Database Related
+*/
+func AssessMySQLMaxActiveSessionCount(mysql *mysql.MySQL) ([]public.SchemaActiveSession, public.ReportSummary, error) {
+	session, err := mysql.GetMySQLActiveSessionCounts()
+	if err != nil {
+		return nil, public.ReportSummary{}, err
+	}
+
+	listData := []public.SchemaActiveSession{{
+		Rownum:        "1",
+		DBID:          session["SERVER_ID"],
+		SampleTime:    session["SAMPLE_TIME"],
+		SessionCounts: session["COUNTS"],
+	}}
+
+	return listData, public.ReportSummary{
+		AssessType:  common.AssessTypeObjectTypeRelated,
+		AssessName:  common.AssessNameSchemaActiveSessionRelated,
+		AssessTotal: len(listData),
+	}, nil
+}
+
+func AssessMySQLSchemaOverview(schemaName []string, mysql *mysql.MySQL) ([]public.SchemaTableSizeData, public.ReportSummary, error) {
+	overview, err := mysql.GetMySQLSchemaTableSizeData(schemaName)
+	if err != nil {
+		return nil, public.ReportSummary{}, err
+	}
+	if len(overview) == 0 {
+		return nil, public.ReportSummary{}, nil
+	}
+
+	var listData []public.SchemaTableSizeData
+	for _, ow := range overview {
+		listData = append(listData, public.SchemaTableSizeData{
+			Schema:        ow["OWNER"],
+			TableSize:     ow["TABLE_SIZE"],
+			IndexSize:     ow["INDEX_SIZE"],
+			LobTableSize:  "0",
+			LobIndexSize:  "0",
+			AllTablesRows: ow["ROWCOUNT"],
+		})
+	}
+
+	return listData, public.ReportSummary{
+		AssessType:  common.AssessTypeObjectTypeRelated,
+		AssessName:  common.AssessNameSchemaDataSizeRelated,
+		AssessTotal: len(listData),
+	}, nil
+}
+
+func AssessMySQLSchemaTableRowsTOP(schemaName []string, mysql *mysql.MySQL) ([]public.SchemaTableRowsTOP, public.ReportSummary, error) {
+	overview, err := mysql.GetMySQLSchemaTableRowsTOP(schemaName)
+	if err != nil {
+		return nil, public.ReportSummary{}, err
+	}
+	if len(overview) == 0 {
+		return nil, public.ReportSummary{}, nil
+	}
+
+	var listData []public.SchemaTableRowsTOP
+	for _, ow := range overview {
+		listData = append(listData, public.SchemaTableRowsTOP{
+			Schema:    ow["OWNER"],
+			TableName: ow["TABLE_NAME"],
+			TableType: ow["TABLE_TYPE"],
+			TableSize: ow["TABLE_SIZE"],
+		})
+	}
+
+	return listData, public.ReportSummary{
+		AssessType:  common.AssessTypeObjectTypeRelated,
+		AssessName:  common.AssessNameSchemaTableRowsTopRelated,
+		AssessTotal: len(listData),
+	}, nil
+}
+
+func AssessMySQLSchemaCodeOverview(schemaName []string, mysql *mysql.MySQL) ([]public.SchemaCodeObject, public.ReportSummary, error) {
+	overview, err := mysql.GetMySQLSchemaCodeObject(schemaName)
+	if err != nil {
+		return nil, public.ReportSummary{}, err
+	}
+	if len(overview) == 0 {
+		return nil, public.ReportSummary{}, nil
+	}
+
+	var listData []public.SchemaCodeObject
+	for _, ow := range overview {
+		listData = append(listData, public.SchemaCodeObject{
+			Schema:     ow["OWNER"],
+			ObjectName: ow["OBJECT_NAME"],
+			ObjectType: ow["OBJECT_TYPE"],
+			Lines:      ow["LINES"],
+		})
+	}
+
+	return listData, public.ReportSummary{
+		AssessType:  common.AssessTypeObjectTypeRelated,
+		AssessName:  common.AssessNameSchemaCodeObjectRelated,
+		AssessTotal: len(listData),
+	}, nil
+}
+
+func AssessMySQLSchemaTableAvgRowLengthTOP(schemaName []string, mysql *mysql.MySQL) ([]public.SchemaTableAvgRowLengthTOP, public.ReportSummary, error) {
+	overview, err := mysql.GetMySQLSchemaTableAvgRowLengthTOP(schemaName)
+	if err != nil {
+		return nil, public.ReportSummary{}, err
+	}
+	if len(overview) == 0 {
+		return nil, public.ReportSummary{}, nil
+	}
+
+	var listData []public.SchemaTableAvgRowLengthTOP
+	for _, ow := range overview {
+		listData = append(listData, public.SchemaTableAvgRowLengthTOP{
+			Schema:       ow["OWNER"],
+			TableName:    ow["TABLE_NAME"],
+			AvgRowLength: ow["AVG_ROW_LEN"],
+		})
+	}
+
+	return listData, public.ReportSummary{
+		AssessType:  common.AssessTypeObjectTypeRelated,
+		AssessName:  common.AssessNameSchemaTableAvgRowLengthTopRelated,
+		AssessTotal: len(listData),
+	}, nil
+}
@@ -0,0 +1,41 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package t2o
+
+import (
+	"github.com/wentaojin/transferdb/database/mysql"
+	"github.com/wentaojin/transferdb/module/assess/oracle/public"
+)
+
+/*
+MySQL Database Check
+*/
+func GetAssessDatabaseCheckResult(schemaName []string, mysql *mysql.MySQL) (*public.ReportCheck, *public.ReportSummary, error) {
+	ListSchemaIdentifierCheck, identifierSummary, err := AssessMySQLIdentifierCheck(schemaName, mysql)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &public.ReportCheck{
+		ListSchemaIdentifierCheck: ListSchemaIdentifierCheck,
+	}, &public.ReportSummary{
+		AssessTotal:   identifierSummary.AssessTotal,
+		Compatible:    identifierSummary.Compatible,
+		Incompatible:  identifierSummary.Incompatible,
+		Convertible:   identifierSummary.Convertible,
+		InConvertible: identifierSummary.InConvertible,
+	}, nil
+}
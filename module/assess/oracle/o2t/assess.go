@@ -56,6 +56,8 @@ func NewAssess(ctx context.Context, cfg *config.Config) (*Assess, error) {
 
 func (r *Assess) Assess() error {
 	startTime := time.Now()
+	// 评估运行编号基于评估开始时间
+	runID := public.NewAssessRunID(startTime)
 	zap.L().Info("assess oracle migrate mysql cost start",
 		zap.String("oracle Schema", r.cfg.SchemaConfig.SourceSchema),
 		zap.String("mysql Schema", r.cfg.SchemaConfig.TargetSchema))
@@ -116,10 +118,10 @@ func (r *Assess) Assess() error {
 			'OWBSYS'
 		)`
 
-		fileName = "report_all.html"
+		fileName = "report_all"
 	} else {
 		usernameSQL = fmt.Sprintf(`select username from dba_users where username = '%s'`, strings.ToUpper(r.cfg.SchemaConfig.SourceSchema))
-		fileName = fmt.Sprintf("report_%s.html", r.cfg.OracleConfig.ServiceName)
+		fileName = fmt.Sprintf("report_%s", r.cfg.OracleConfig.ServiceName)
 	}
 	_, usernameMapArray, err := oracle.Query(r.ctx, r.oracle.OracleDB, usernameSQL)
 	if err != nil {
@@ -141,15 +143,9 @@ func (r *Assess) Assess() error {
 		return err
 	}
 
-	file, err := os.OpenFile(filepath.Join(pwdDir, fileName), os.O_WRONLY|os.O_CREATE|os.O_APPEND|os.O_TRUNC, 0666)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
 	// 评估
 	beginTime := time.Now()
-	report, err := GetAssessDatabaseReport(r.ctx, r.metaDB, r.oracle, usernameArray, fileName, common.StringUPPER(r.cfg.OracleConfig.Username), r.cfg.DBTypeS, r.cfg.DBTypeT)
+	report, err := GetAssessDatabaseReport(r.ctx, r.metaDB, r.oracle, usernameArray, fileName, common.StringUPPER(r.cfg.OracleConfig.Username), r.cfg.DBTypeS, r.cfg.DBTypeT, r.cfg.AssessConfig)
 	if err != nil {
 		return err
 	}
@@ -158,23 +154,46 @@ func (r *Assess) Assess() error {
 		zap.Strings("schema", usernameArray),
 		zap.String("cost", finishedTime.Sub(beginTime).String()))
 
-	startHTMLTime := time.Now()
-	if err = public.GenNewHTMLReport(report, file); err != nil {
+	report.RunID = runID
+
+	// 评估报告按格式输出
+	var reportFiles []string
+	for _, reportFormat := range r.cfg.AssessConfig.ReportFormat {
+		startReportTime := time.Now()
+		reportFile := filepath.Join(pwdDir, common.StringsBuilder(fileName, ".", common.AssessReportFormatFileSuffix[reportFormat]))
+		if err = public.GenNewReportFile(report, reportFormat, reportFile); err != nil {
+			return err
+		}
+		reportFiles = append(reportFiles, reportFile)
+		finishReportTime := time.Now()
+		zap.L().Info("gen database assess report finish",
+			zap.Strings("schema", usernameArray),
+			zap.String("format", reportFormat),
+			zap.String("cost", finishReportTime.Sub(startReportTime).String()))
+	}
+
+	// 评估结果保存，用于多次评估结果对比
+	if err = public.SaveAssessRun(r.ctx, r.metaDB, report, r.cfg.DBTypeS, r.cfg.DBTypeT, usernameArray); err != nil {
 		return err
 	}
-	finishHTMLTime := time.Now()
-	zap.L().Info("get database result from db finish",
-		zap.Strings("schema", usernameArray),
-		zap.String("cost", finishHTMLTime.Sub(startHTMLTime).String()))
+	zap.L().Info("save database assess run finish", zap.String("run id", report.RunID))
+
+	if r.cfg.BaselineConfig.BaseRunID != "" {
+		baselineFiles, err := public.GenAssessBaselineReport(r.ctx, r.metaDB, r.cfg.BaselineConfig.BaseRunID, report.RunID, r.cfg.AssessConfig.ReportFormat, pwdDir)
+		if err != nil {
+			return err
+		}
+		reportFiles = append(reportFiles, baselineFiles...)
+	}
 
 	endTime := time.Now()
 	zap.L().Info("assess oracle migrate mysql cost finished",
 		zap.String("cost", endTime.Sub(startTime).String()),
-		zap.String("output", filepath.Join(pwdDir, fileName)))
+		zap.Strings("output", reportFiles))
 	return nil
 }
 
-func GetAssessDatabaseReport(ctx context.Context, metaDB *meta.Meta, oracle *oracle.Oracle, schemaName []string, reportName, reportUser, dbTypeS, dbTypeT string) (*public.Report, error) {
+func GetAssessDatabaseReport(ctx context.Context, metaDB *meta.Meta, oracle *oracle.Oracle, schemaName []string, reportName, reportUser, dbTypeS, dbTypeT string, assessCfg config.AssessConfig) (*public.Report, error) {
 	assessTotal := 0
 	compatibleS := 0
 	incompatibleS := 0
@@ -221,7 +240,18 @@ func GetAssessDatabaseReport(ctx context.Context, metaDB *meta.Meta, oracle *ora
 	convertibleS += relatedS.Convertible
 	inconvertibleS += relatedS.InConvertible
 
-	return &public.Report{
+	// SQL 负载兼容性评估
+	dbWorkload, workloadS, err := public.GetAssessDatabaseWorkloadResult(oracle, schemaName, assessCfg.SQLSource, assessCfg.SQLTopN)
+	if err != nil {
+		return nil, err
+	}
+	assessTotal += workloadS.AssessTotal
+	compatibleS += workloadS.Compatible
+	incompatibleS += workloadS.Incompatible
+	convertibleS += workloadS.Convertible
+	inconvertibleS += workloadS.InConvertible
+
+	report := &public.Report{
 		ReportOverview: dbOverview,
 		ReportSummary: &public.ReportSummary{
 			AssessTotal:   assessTotal,
@@ -233,5 +263,15 @@ func GetAssessDatabaseReport(ctx context.Context, metaDB *meta.Meta, oracle *ora
 		ReportCompatible: dbCompatibles,
 		ReportCheck:      dbChecks,
 		ReportRelated:    dbRelated,
-	}, nil
+		ReportWorkload:   dbWorkload,
+	}
+
+	// 迁移工作量评估
+	dbEffort, err := public.GetAssessDatabaseEffortResult(ctx, metaDB, report, dbTypeS, dbTypeT)
+	if err != nil {
+		return nil, err
+	}
+	report.ReportEffort = dbEffort
+
+	return report, nil
 }
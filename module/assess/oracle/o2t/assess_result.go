@@ -21,6 +21,8 @@ import (
 	"github.com/wentaojin/transferdb/database/meta"
 	"github.com/wentaojin/transferdb/database/oracle"
 	"github.com/wentaojin/transferdb/module/assess/oracle/public"
+	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -1287,6 +1289,69 @@ func AssessOracleSchemaCodeOverview(schemaName []string, oracle *oracle.Oracle)
 	}, nil
 }
 
+func AssessOracleSchemaCodeInventory(schemaName []string, oracle *oracle.Oracle) ([]public.SchemaCodeInventory, public.ReportSummary, error) {
+	var (
+		listData  []public.SchemaCodeInventory
+		listCodes []public.CodeAnalysis
+	)
+
+	err := oracle.GetOracleSchemaCodeSource(schemaName, func(owner, name, objType, source string) error {
+		ca := public.AnalyzeOracleCode(source)
+		listCodes = append(listCodes, ca)
+
+		autonomous := "NO"
+		if ca.Autonomous {
+			autonomous = "YES"
+		}
+		listData = append(listData, public.SchemaCodeInventory{
+			Schema:                owner,
+			ObjectName:            name,
+			ObjectType:            objType,
+			Lines:                 strconv.Itoa(ca.Lines),
+			Complexity:            strconv.Itoa(ca.Complexity),
+			AutonomousTransaction: autonomous,
+			DynamicSQL:            strconv.Itoa(ca.DynamicSQL),
+			Cursors:               strconv.Itoa(ca.Cursors),
+			DbmsPackages:          strings.Join(ca.DbmsPackages, ","),
+			DatabaseLinks:         strings.Join(ca.DatabaseLinks, ","),
+			OracleBuiltins:        strings.Join(ca.OracleBuiltins, ","),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, public.ReportSummary{}, err
+	}
+
+	if len(listData) == 0 {
+		return nil, public.ReportSummary{}, nil
+	}
+
+	// 按圈复杂度倒序，优先关注复杂度高的对象
+	sort.SliceStable(listData, func(i, j int) bool {
+		ci, _ := strconv.Atoi(listData[i].Complexity)
+		cj, _ := strconv.Atoi(listData[j].Complexity)
+		return ci > cj
+	})
+
+	// 自治事务、数据库链接无法迁移至 MySQL/TiDB 存储过程，需应用改写
+	assessInComp := 0
+	for _, ca := range listCodes {
+		if ca.Autonomous || len(ca.DatabaseLinks) > 0 {
+			assessInComp++
+		}
+	}
+
+	return listData, public.ReportSummary{
+		AssessType:    common.AssessTypeObjectTypeRelated,
+		AssessName:    common.AssessNameSchemaCodeInventoryRelated,
+		AssessTotal:   len(listData),
+		Compatible:    len(listData) - assessInComp,
+		Incompatible:  assessInComp,
+		Convertible:   0,
+		InConvertible: 0,
+	}, nil
+}
+
 func AssessOracleSchemaSynonymOverview(schemaName []string, oracle *oracle.Oracle) ([]public.SchemaSynonymObject, public.ReportSummary, error) {
 	overview, err := oracle.GetOracleSchemaCodeObject(schemaName)
 	if err != nil {
@@ -29,6 +29,7 @@ func GetAssessDatabaseRelatedResult(schemaName []string, oracle *oracle.Oracle)
 		ListSchemaTableSizeData          []public.SchemaTableSizeData
 		ListSchemaTableRowsTOP           []public.SchemaTableRowsTOP
 		ListSchemaCodeObject             []public.SchemaCodeObject
+		ListSchemaCodeInventory          []public.SchemaCodeInventory
 		ListSchemaSynonymObject          []public.SchemaSynonymObject
 		ListSchemaMaterializedViewObject []public.SchemaMaterializedViewObject
 		ListSchemaTableAvgRowLengthTOP   []public.SchemaTableAvgRowLengthTOP
@@ -81,6 +82,16 @@ func GetAssessDatabaseRelatedResult(schemaName []string, oracle *oracle.Oracle)
 	convertibleS += objSummary.Convertible
 	inconvertibleS += objSummary.InConvertible
 
+	ListSchemaCodeInventory, inventorySummary, err := AssessOracleSchemaCodeInventory(schemaName, oracle)
+	if err != nil {
+		return nil, nil, err
+	}
+	assessTotal += inventorySummary.AssessTotal
+	compatibleS += inventorySummary.Compatible
+	incompatibleS += inventorySummary.Incompatible
+	convertibleS += inventorySummary.Convertible
+	inconvertibleS += inventorySummary.InConvertible
+
 	ListSchemaSynonymObject, seqSummary, err := AssessOracleSchemaSynonymOverview(schemaName, oracle)
 	if err != nil {
 		return nil, nil, err
@@ -126,6 +137,7 @@ func GetAssessDatabaseRelatedResult(schemaName []string, oracle *oracle.Oracle)
 			ListSchemaTableSizeData:          ListSchemaTableSizeData,
 			ListSchemaTableRowsTOP:           ListSchemaTableRowsTOP,
 			ListSchemaCodeObject:             ListSchemaCodeObject,
+			ListSchemaCodeInventory:          ListSchemaCodeInventory,
 			ListSchemaSynonymObject:          ListSchemaSynonymObject,
 			ListSchemaMaterializedViewObject: ListSchemaMaterializedViewObject,
 			ListSchemaTableAvgRowLengthTOP:   ListSchemaTableAvgRowLengthTOP,
@@ -0,0 +1,349 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package public
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/wentaojin/transferdb/common"
+	"github.com/wentaojin/transferdb/database/meta"
+	"go.uber.org/zap"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// NewAssessRunID 评估运行编号，格式 ${评估开始时间 yyyymmddhhmiss}-${6 位随机十六进制}，随机后缀避免同一秒内多次评估编号冲突
+func NewAssessRunID(startTime time.Time) string {
+	suffix := make([]byte, 3)
+	if _, err := rand.Read(suffix); err != nil {
+		// 随机数获取失败，退化为纳秒后缀
+		return fmt.Sprintf("%s-%06x", startTime.Format("20060102150405"), startTime.Nanosecond()&0xffffff)
+	}
+	return fmt.Sprintf("%s-%s", startTime.Format("20060102150405"), hex.EncodeToString(suffix))
+}
+
+// SaveAssessRun 保存评估 JSON 报告至元数据库，用于后续评估结果对比
+func SaveAssessRun(ctx context.Context, metaDB *meta.Meta, report *Report, dbTypeS, dbTypeT string, schemaName []string) error {
+	jsonByte, err := json.Marshal(NewReportJSON(report))
+	if err != nil {
+		return fmt.Errorf("assess run [%s] report json marshal failed: %v", report.RunID, err)
+	}
+	return meta.NewAssessRunDetailModel(metaDB).CreateAssessRunDetail(ctx, &meta.AssessRunDetail{
+		RunID:         report.RunID,
+		DBTypeS:       common.StringUPPER(dbTypeS),
+		DBTypeT:       common.StringUPPER(dbTypeT),
+		SchemaNameS:   strings.Join(schemaName, ","),
+		ReportVersion: ReportSchemaVersion,
+		ReportDetail:  string(jsonByte),
+	})
+}
+
+// GetAssessDatabaseBaselineResult 对比两次评估结果
+// 1、兼容性以及检查项：基线不兼容、对比兼容（或者对象已不存在）为已兼容，反之为新增不兼容
+// 2、兼容性以及相关信息：同一对象对象数、大小、行数等值变化
+func GetAssessDatabaseBaselineResult(ctx context.Context, metaDB *meta.Meta, baseRunID, targetRunID string) (*ReportBaseline, error) {
+	baseRun, baseReport, err := getAssessRun(ctx, metaDB, baseRunID)
+	if err != nil {
+		return nil, err
+	}
+	targetRun, targetReport, err := getAssessRun(ctx, metaDB, targetRunID)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(baseRun.DBTypeS, targetRun.DBTypeS) {
+		return nil, fmt.Errorf("assess run [%s] source db type [%s] and run [%s] source db type [%s] isn't match", baseRunID, baseRun.DBTypeS, targetRunID, targetRun.DBTypeS)
+	}
+	if !strings.EqualFold(baseRun.DBTypeT, targetRun.DBTypeT) {
+		return nil, fmt.Errorf("assess run [%s] target db type [%s] and run [%s] target db type [%s] isn't match", baseRunID, baseRun.DBTypeT, targetRunID, targetRun.DBTypeT)
+	}
+	if !strings.EqualFold(baseRun.SchemaNameS, targetRun.SchemaNameS) {
+		return nil, fmt.Errorf("assess run [%s] source schema [%s] and run [%s] source schema [%s] isn't match", baseRunID, baseRun.SchemaNameS, targetRunID, targetRun.SchemaNameS)
+	}
+	if baseRun.ReportVersion != targetRun.ReportVersion {
+		zap.L().Warn("assess run report version isn't match, sections only exist in one of them will be skipped",
+			zap.String("base run", baseRunID),
+			zap.String("base version", baseRun.ReportVersion),
+			zap.String("target run", targetRunID),
+			zap.String("target version", targetRun.ReportVersion))
+	}
+
+	bl := &ReportBaseline{
+		BaseRunID:           baseRunID,
+		BaseRunTime:         baseRun.CreatedAt.Format("2006-01-02 15:04:05"),
+		BaseReportVersion:   baseRun.ReportVersion,
+		TargetRunID:         targetRunID,
+		TargetRunTime:       targetRun.CreatedAt.Format("2006-01-02 15:04:05"),
+		TargetReportVersion: targetRun.ReportVersion,
+	}
+	if baseReport.Summary != nil {
+		bl.BaseIncompatible = baseReport.Summary.Incompatible
+	}
+	if targetReport.Summary != nil {
+		bl.TargetIncompatible = targetReport.Summary.Incompatible
+	}
+	if baseReport.Effort != nil {
+		bl.BaseTotalEffort = baseReport.Effort.TotalEffort
+	}
+	if targetReport.Effort != nil {
+		bl.TargetTotalEffort = targetReport.Effort.TotalEffort
+	}
+
+	diffAssessSection(bl, "compatible", baseReport.Compatible, targetReport.Compatible, false, true)
+	diffAssessSection(bl, "check", baseReport.Check, targetReport.Check, true, false)
+	diffAssessSection(bl, "related", baseReport.Related, targetReport.Related, false, true)
+	diffAssessSection(bl, "workload", baseReport.Workload, targetReport.Workload, false, false)
+
+	return bl, nil
+}
+
+func getAssessRun(ctx context.Context, metaDB *meta.Meta, runID string) (meta.AssessRunDetail, *ReportJSON, error) {
+	runs, err := meta.NewAssessRunDetailModel(metaDB).DetailAssessRunDetailByRunID(ctx, &meta.AssessRunDetail{RunID: runID})
+	if err != nil {
+		return meta.AssessRunDetail{}, nil, err
+	}
+	if len(runs) == 0 {
+		return meta.AssessRunDetail{}, nil, fmt.Errorf("assess run [%s] isn't exist in meta database", runID)
+	}
+	var report ReportJSON
+	if err = json.Unmarshal([]byte(runs[0].ReportDetail), &report); err != nil {
+		return runs[0], nil, fmt.Errorf("assess run [%s] report json unmarshal failed: %v", runID, err)
+	}
+	return runs[0], &report, nil
+}
+
+// baselineRow 评估列表单行数据，schema 以及对象名、对象类型等字段作为对象标识
+type baselineRow struct {
+	schema    string
+	objectKey string
+	issue     bool
+	items     []string
+	values    map[string]string
+}
+
+// diffAssessSection 结构体内每个列表字段按对象标识对比，allIssue 代表列表每行均为不兼容项（检查项）
+func diffAssessSection(bl *ReportBaseline, section string, base, target interface{}, allIssue, changed bool) {
+	bv := reflect.Indirect(reflect.ValueOf(base))
+	tv := reflect.Indirect(reflect.ValueOf(target))
+	// 章节仅存在于其中一次评估结果（评估报告版本不一致或者未开启评估），不参与对比
+	if !bv.IsValid() || !tv.IsValid() {
+		return
+	}
+	for i := 0; i < bv.NumField(); i++ {
+		field := bv.Type().Field(i)
+		if field.Type.Kind() != reflect.Slice || field.Type.Elem().Kind() != reflect.Struct {
+			continue
+		}
+		name := common.StringsBuilder(section, ".", strings.TrimPrefix(field.Tag.Get("json"), "list_"))
+
+		baseKeys, baseRows, ok := newBaselineRows(bv.Field(i), allIssue)
+		if !ok {
+			continue
+		}
+		targetKeys, targetRows, _ := newBaselineRows(tv.Field(i), allIssue)
+
+		for _, k := range targetKeys {
+			t := targetRows[k]
+			if b, exist := baseRows[k]; t.issue && !(exist && b.issue) {
+				bl.ListNewIncompatible = append(bl.ListNewIncompatible, BaselineObject{
+					Section:   name,
+					Schema:    t.schema,
+					ObjectKey: t.objectKey,
+					Detail:    t.detail(),
+				})
+			}
+		}
+		for _, k := range baseKeys {
+			b := baseRows[k]
+			if t, exist := targetRows[k]; b.issue && !(exist && t.issue) {
+				bl.ListBecameCompatible = append(bl.ListBecameCompatible, BaselineObject{
+					Section:   name,
+					Schema:    b.schema,
+					ObjectKey: b.objectKey,
+					Detail:    b.detail(),
+				})
+			}
+		}
+
+		if !changed {
+			continue
+		}
+		for _, k := range targetKeys {
+			t := targetRows[k]
+			b, exist := baseRows[k]
+			if !exist {
+				continue
+			}
+			for _, item := range t.items {
+				if strings.HasPrefix(item, "is_") || b.values[item] == t.values[item] {
+					continue
+				}
+				bl.ListChangedObjectValue = append(bl.ListChangedObjectValue, BaselineChange{
+					Section:     name,
+					Schema:      t.schema,
+					ObjectKey:   t.objectKey,
+					Item:        item,
+					BaseValue:   b.values[item],
+					TargetValue: t.values[item],
+				})
+			}
+		}
+	}
+}
+
+// newBaselineRows 列表行按对象标识去重，返回对象标识顺序、对象标识行数据，列表不存在对象标识字段返回 false
+func newBaselineRows(rows reflect.Value, allIssue bool) ([]string, map[string]baselineRow, bool) {
+	var keys []string
+	res := make(map[string]baselineRow)
+
+	elem := rows.Type().Elem()
+	hasKey := false
+	for i := 0; i < elem.NumField(); i++ {
+		if isBaselineKeyItem(elem.Field(i).Tag.Get("json")) {
+			hasKey = true
+		}
+	}
+	if !hasKey {
+		return keys, res, false
+	}
+
+	for r := 0; r < rows.Len(); r++ {
+		row := rows.Index(r)
+		br := baselineRow{values: make(map[string]string)}
+		var objectKeys []string
+		for i := 0; i < row.NumField(); i++ {
+			item := elem.Field(i).Tag.Get("json")
+			val := fmt.Sprintf("%v", row.Field(i).Interface())
+			switch {
+			case item == "schema":
+				br.schema = val
+			case isBaselineKeyItem(item):
+				objectKeys = append(objectKeys, common.StringsBuilder(item, "=", val))
+			default:
+				br.items = append(br.items, item)
+				br.values[item] = val
+			}
+		}
+		br.objectKey = strings.Join(objectKeys, ",")
+		br.issue = allIssue ||
+			(br.values["is_compatible"] == common.AssessNoCompatible && br.values["is_convertible"] != common.AssessYesConvertible) ||
+			br.values["is_equivalent"] == common.AssessNoEquivalent
+
+		key := common.StringsBuilder(br.schema, "|", br.objectKey)
+		if _, ok := res[key]; !ok {
+			keys = append(keys, key)
+		}
+		res[key] = br
+	}
+	return keys, res, true
+}
+
+func isBaselineKeyItem(item string) bool {
+	return item == "schema" || item == "sql_id" || item == "column_default_value" || item == "view_type_owner" || item == "table_owner" ||
+		strings.HasSuffix(item, "_name") || strings.HasSuffix(item, "_type")
+}
+
+func (br baselineRow) detail() string {
+	var details []string
+	for _, item := range br.items {
+		details = append(details, common.StringsBuilder(item, "=", br.values[item]))
+	}
+	return strings.Join(details, ",")
+}
+
+// GenNewBaselineReport 根据报告格式输出评估对比报告
+func GenNewBaselineReport(bl *ReportBaseline, reportFormat string, file *os.File) error {
+	switch common.StringUPPER(reportFormat) {
+	case common.AssessReportFormatHTML:
+		tf, err := template.ParseFS(fs, "template/*.html")
+		if err != nil {
+			return fmt.Errorf("template parse FS failed: %v", err)
+		}
+		for _, name := range []string{"report_header", "report_body", "report_baseline", "report_footer"} {
+			var data interface{}
+			if name == "report_baseline" {
+				data = bl
+			}
+			if err = tf.ExecuteTemplate(file, name, data); err != nil {
+				return fmt.Errorf("template FS Execute [%s] template HTML failed: %v", name, err)
+			}
+		}
+		return nil
+	case common.AssessReportFormatJSON:
+		jsonByte, err := json.MarshalIndent(bl, "", "  ")
+		if err != nil {
+			return fmt.Errorf("assess baseline report json marshal failed: %v", err)
+		}
+		if _, err = file.Write(append(jsonByte, '\n')); err != nil {
+			return fmt.Errorf("assess baseline report json write failed: %v", err)
+		}
+		return nil
+	case common.AssessReportFormatMarkdown:
+		w := bufio.NewWriter(file)
+		w.WriteString("# TRANSFERDB ASSESS BASELINE REPORT\n\n")
+		writeMarkdownObject(w, bl)
+		writeMarkdownSections(w, bl)
+		if err := w.Flush(); err != nil {
+			return fmt.Errorf("assess baseline report markdown write failed: %v", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("assess report format [%s] isn't support", reportFormat)
+	}
+}
+
+func GenNewBaselineReportFile(bl *ReportBaseline, reportFormat, fileName string) error {
+	file, err := os.OpenFile(fileName, os.O_WRONLY|os.O_CREATE|os.O_APPEND|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return GenNewBaselineReport(bl, reportFormat, file)
+}
+
+// GenAssessBaselineReport 对比两次评估结果并按格式输出对比报告，文件命名格式：report_baseline_${base_run_id}_${target_run_id}
+func GenAssessBaselineReport(ctx context.Context, metaDB *meta.Meta, baseRunID, targetRunID string, reportFormats []string, outputDir string) ([]string, error) {
+	bl, err := GetAssessDatabaseBaselineResult(ctx, metaDB, baseRunID, targetRunID)
+	if err != nil {
+		return nil, err
+	}
+
+	var reportFiles []string
+	for _, reportFormat := range reportFormats {
+		reportFile := filepath.Join(outputDir, common.StringsBuilder("report_baseline_", baseRunID, "_", targetRunID, ".", common.AssessReportFormatFileSuffix[reportFormat]))
+		if err = GenNewBaselineReportFile(bl, reportFormat, reportFile); err != nil {
+			return reportFiles, err
+		}
+		reportFiles = append(reportFiles, reportFile)
+	}
+
+	zap.L().Info("gen database assess baseline report finish",
+		zap.String("base run", baseRunID),
+		zap.String("target run", targetRunID),
+		zap.Int("became compatible", len(bl.ListBecameCompatible)),
+		zap.Int("new incompatible", len(bl.ListNewIncompatible)),
+		zap.Int("changed object value", len(bl.ListChangedObjectValue)),
+		zap.Strings("output", reportFiles))
+	return reportFiles, nil
+}
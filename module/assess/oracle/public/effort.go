@@ -0,0 +1,166 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package public
+
+import (
+	"context"
+	"fmt"
+	"github.com/wentaojin/transferdb/common"
+	"github.com/wentaojin/transferdb/database/meta"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// effortModel 工作量评估模型 cost_type -> cost_name -> weight
+type effortModel map[string]map[string]float64
+
+// weight 优先匹配对象名权重，未配置时不兼容对象使用默认权重，兼容对象不计工作量
+func (m effortModel) weight(costType, costName string, incompatible bool) float64 {
+	if w, ok := m[costType][common.StringUPPER(costName)]; ok {
+		return w
+	}
+	if incompatible {
+		return m[costType][common.AssessCostNameDefault]
+	}
+	return 0
+}
+
+// difficulty 工作量不超过对应等级上限即为该等级，超过最高等级上限为 EXTREME
+func (m effortModel) difficulty(effort float64) string {
+	for _, level := range common.AssessDifficultyLevels {
+		if limit, ok := m[common.AssessCostTypeDifficulty][level]; ok && effort <= limit {
+			return level
+		}
+	}
+	return common.AssessDifficultyExtreme
+}
+
+// GetAssessDatabaseEffortResult 基于评估结果以及元数据库 [buildin_assess_cost] 模型计算每个 schema 工作量以及迁移难度
+func GetAssessDatabaseEffortResult(ctx context.Context, metaDB *meta.Meta, report *Report, dbTypeS, dbTypeT string) (*ReportEffort, error) {
+	costs, err := meta.NewBuildinAssessCostModel(metaDB).BatchQueryBuildinAssessCost(ctx, &meta.BuildinAssessCost{
+		DBTypeS: dbTypeS,
+		DBTypeT: dbTypeT,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(costs) == 0 {
+		return nil, fmt.Errorf("meta table [buildin_assess_cost] db_type_s [%s] db_type_t [%s] cost model isn't exist, please run prepare mode", dbTypeS, dbTypeT)
+	}
+
+	model := make(effortModel)
+	for _, c := range costs {
+		costType := common.StringUPPER(c.CostType)
+		if _, ok := model[costType]; !ok {
+			model[costType] = make(map[string]float64)
+		}
+		model[costType][common.StringUPPER(c.CostName)] = c.CostWeight
+	}
+
+	schemaEfforts := make(map[string]*SchemaEffort)
+	getSchema := func(schema string) *SchemaEffort {
+		if _, ok := schemaEfforts[schema]; !ok {
+			schemaEfforts[schema] = &SchemaEffort{Schema: schema}
+		}
+		return schemaEfforts[schema]
+	}
+	isIncompatible := func(isCompatible string) bool {
+		return strings.EqualFold(isCompatible, common.AssessNoCompatible)
+	}
+
+	if rc := report.ReportCompatible; rc != nil {
+		for _, r := range rc.ListSchemaTableTypeCompatibles {
+			getSchema(r.Schema).ObjectTypeEffort += effortCounts(r.ObjectCounts) * model.weight(common.AssessCostTypeObjectType, r.TableType, isIncompatible(r.IsCompatible))
+		}
+		for _, r := range rc.ListSchemaConstraintTypeCompatibles {
+			getSchema(r.Schema).ObjectTypeEffort += effortCounts(r.ObjectCounts) * model.weight(common.AssessCostTypeObjectType, r.ConstraintType, isIncompatible(r.IsCompatible))
+		}
+		for _, r := range rc.ListSchemaIndexTypeCompatibles {
+			getSchema(r.Schema).ObjectTypeEffort += effortCounts(r.ObjectCounts) * model.weight(common.AssessCostTypeObjectType, r.IndexType, isIncompatible(r.IsCompatible))
+		}
+		for _, r := range rc.ListSchemaViewTypeCompatibles {
+			getSchema(r.Schema).ObjectTypeEffort += effortCounts(r.ObjectCounts) * model.weight(common.AssessCostTypeObjectType, r.ViewType, isIncompatible(r.IsCompatible))
+		}
+		for _, r := range rc.ListSchemaObjectTypeCompatibles {
+			getSchema(r.Schema).ObjectTypeEffort += effortCounts(r.ObjectCounts) * model.weight(common.AssessCostTypeObjectType, r.ObjectType, isIncompatible(r.IsCompatible))
+		}
+		for _, r := range rc.ListSchemaTemporaryTableTypeCompatibles {
+			getSchema(r.Schema).ObjectTypeEffort += effortCounts(r.ObjectCounts) * model.weight(common.AssessCostTypeObjectType, r.TemporaryTableType, isIncompatible(r.IsCompatible))
+		}
+		for _, r := range rc.ListSchemaFeatureCompatibles {
+			getSchema(r.Schema).ObjectTypeEffort += effortCounts(r.ObjectCounts) * model.weight(common.AssessCostTypeObjectType, r.Feature, isIncompatible(r.IsCompatible))
+		}
+		for _, r := range rc.ListSchemaColumnTypeCompatibles {
+			getSchema(r.Schema).DatatypeEffort += effortCounts(r.ObjectCounts) * model.weight(common.AssessCostTypeDatatype, r.ColumnType, strings.EqualFold(r.IsEquivalent, common.AssessNoEquivalent))
+		}
+		for _, r := range rc.ListSchemaPartitionTypeCompatibles {
+			getSchema(r.Schema).PartitionTypeEffort += effortCounts(r.ObjectCounts) * model.weight(common.AssessCostTypePartitionType, r.PartitionType, isIncompatible(r.IsCompatible))
+		}
+		for _, r := range rc.ListSchemaSubPartitionTypeCompatibles {
+			getSchema(r.Schema).SubPartitionTypeEffort += effortCounts(r.ObjectCounts) * model.weight(common.AssessCostTypeSubPartitionType, r.SubPartitionType, isIncompatible(r.IsCompatible))
+		}
+	}
+
+	// PL/SQL 代码对象全部需要改写，按代码行数计算
+	if rr := report.ReportRelated; rr != nil {
+		for _, r := range rr.ListSchemaCodeObject {
+			getSchema(r.Schema).CodeLineEffort += effortCounts(r.Lines) * model.weight(common.AssessCostTypeCodeLine, r.ObjectType, true)
+		}
+	}
+
+	var (
+		schemas     []string
+		totalEffort float64
+	)
+	for s := range schemaEfforts {
+		schemas = append(schemas, s)
+	}
+	sort.Strings(schemas)
+
+	effort := &ReportEffort{}
+	for _, s := range schemas {
+		se := schemaEfforts[s]
+		se.ObjectTypeEffort = effortRound(se.ObjectTypeEffort)
+		se.DatatypeEffort = effortRound(se.DatatypeEffort)
+		se.CodeLineEffort = effortRound(se.CodeLineEffort)
+		se.PartitionTypeEffort = effortRound(se.PartitionTypeEffort)
+		se.SubPartitionTypeEffort = effortRound(se.SubPartitionTypeEffort)
+		se.TotalEffort = effortRound(se.ObjectTypeEffort + se.DatatypeEffort + se.CodeLineEffort + se.PartitionTypeEffort + se.SubPartitionTypeEffort)
+		se.Difficulty = model.difficulty(se.TotalEffort)
+
+		totalEffort += se.TotalEffort
+		effort.ListSchemaEffort = append(effort.ListSchemaEffort, *se)
+	}
+	effort.TotalEffort = effortRound(totalEffort)
+	effort.Difficulty = model.difficulty(effort.TotalEffort)
+
+	return effort, nil
+}
+
+func effortCounts(counts string) float64 {
+	c, err := strconv.ParseFloat(strings.TrimSpace(counts), 64)
+	if err != nil {
+		return 0
+	}
+	return c
+}
+
+// effortRound 保留两位小数
+func effortRound(effort float64) float64 {
+	return math.Round(effort*100) / 100
+}
@@ -0,0 +1,201 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package public
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	plsqlIfRegex           = regexp.MustCompile(`\bIF\b`)
+	plsqlEndIfRegex        = regexp.MustCompile(`\bEND\s+IF\b`)
+	plsqlElsifRegex        = regexp.MustCompile(`\bELSIF\b`)
+	plsqlWhenRegex         = regexp.MustCompile(`\bWHEN\b`)
+	plsqlLoopRegex         = regexp.MustCompile(`\bLOOP\b`)
+	plsqlEndLoopRegex      = regexp.MustCompile(`\bEND\s+LOOP\b`)
+	plsqlDbmsRegex         = regexp.MustCompile(`\b(DBMS_[A-Z0-9_$#]+)`)
+	plsqlUtlRegex          = regexp.MustCompile(`\b(UTL_[A-Z0-9_$#]+)`)
+	plsqlAutonomousRegex   = regexp.MustCompile(`\bPRAGMA\s+AUTONOMOUS_TRANSACTION\b`)
+	plsqlDynamicSQLRegex   = regexp.MustCompile(`\bEXECUTE\s+IMMEDIATE\b|\bDBMS_SQL\s*\.\s*PARSE\b|\bOPEN\s+[A-Z0-9_$#.]+\s+FOR\s+(''|[A-Z0-9_$#.]+\s*(;|\bUSING\b))`)
+	plsqlCursorRegex       = regexp.MustCompile(`\bCURSOR\s+[A-Z0-9_$#]+\s*(\(|\bIS\b|\bRETURN\b)|\bSYS_REFCURSOR\b|\bREF\s+CURSOR\b`)
+	plsqlDatabaseLinkRegex = regexp.MustCompile(`[A-Z0-9_$#"]\s*@\s*"?([A-Z][A-Z0-9_$#.]*)`)
+)
+
+// plsqlOracleBuiltins Oracle 特有内置函数以及语法，MySQL/TiDB 不支持或者语义不同需改写
+var plsqlOracleBuiltins = []struct {
+	name  string
+	regex *regexp.Regexp
+}{
+	{"DECODE", regexp.MustCompile(`\bDECODE\s*\(`)},
+	{"NVL", regexp.MustCompile(`\bNVL\s*\(`)},
+	{"NVL2", regexp.MustCompile(`\bNVL2\s*\(`)},
+	{"TO_CHAR", regexp.MustCompile(`\bTO_CHAR\s*\(`)},
+	{"TO_DATE", regexp.MustCompile(`\bTO_DATE\s*\(`)},
+	{"TO_NUMBER", regexp.MustCompile(`\bTO_NUMBER\s*\(`)},
+	{"TRUNC", regexp.MustCompile(`\bTRUNC\s*\(`)},
+	{"ADD_MONTHS", regexp.MustCompile(`\bADD_MONTHS\s*\(`)},
+	{"MONTHS_BETWEEN", regexp.MustCompile(`\bMONTHS_BETWEEN\s*\(`)},
+	{"LISTAGG", regexp.MustCompile(`\bLISTAGG\s*\(`)},
+	{"SYS_GUID", regexp.MustCompile(`\bSYS_GUID\s*\(`)},
+	{"SYSTIMESTAMP", regexp.MustCompile(`\bSYSTIMESTAMP\b`)},
+	{"ROWNUM", regexp.MustCompile(`\bROWNUM\b`)},
+	{"ROWID", regexp.MustCompile(`\bROWID\b`)},
+	{"CONNECT BY", regexp.MustCompile(`\bCONNECT\s+BY\b`)},
+	{"MERGE", regexp.MustCompile(`\bMERGE\s+INTO\b`)},
+	{"BULK COLLECT", regexp.MustCompile(`\bBULK\s+COLLECT\b`)},
+	{"FORALL", regexp.MustCompile(`\bFORALL\b`)},
+	{"PIPE ROW", regexp.MustCompile(`\bPIPE\s+ROW\b`)},
+	{"NEXTVAL", regexp.MustCompile(`\.\s*NEXTVAL\b`)},
+	{"CURRVAL", regexp.MustCompile(`\.\s*CURRVAL\b`)},
+	{"RAISE_APPLICATION_ERROR", regexp.MustCompile(`\bRAISE_APPLICATION_ERROR\s*\(`)},
+	{"OUTER JOIN (+)", regexp.MustCompile(`\(\s*\+\s*\)`)},
+}
+
+// CodeAnalysis PL/SQL 对象源码分析结果
+type CodeAnalysis struct {
+	Lines          int
+	Complexity     int
+	Autonomous     bool
+	DynamicSQL     int
+	Cursors        int
+	DbmsPackages   []string
+	DatabaseLinks  []string
+	OracleBuiltins []string
+}
+
+// AnalyzeOracleCode 分析 PL/SQL 源码，注释以及字符串常量不参与分析
+// 代码行数不包含空行以及注释行
+// 圈复杂度 = 1 + IF + ELSIF + WHEN（CASE/EXCEPTION）+ LOOP（FOR/WHILE/LOOP）
+func AnalyzeOracleCode(source string) CodeAnalysis {
+	code := strings.ToUpper(stripOracleCode(source))
+
+	var ca CodeAnalysis
+	for _, line := range strings.Split(code, "\n") {
+		if strings.TrimSpace(line) != "" {
+			ca.Lines++
+		}
+	}
+
+	ca.Complexity = 1 +
+		len(plsqlIfRegex.FindAllStringIndex(code, -1)) - len(plsqlEndIfRegex.FindAllStringIndex(code, -1)) +
+		len(plsqlElsifRegex.FindAllStringIndex(code, -1)) +
+		len(plsqlWhenRegex.FindAllStringIndex(code, -1)) +
+		len(plsqlLoopRegex.FindAllStringIndex(code, -1)) - len(plsqlEndLoopRegex.FindAllStringIndex(code, -1))
+
+	ca.Autonomous = plsqlAutonomousRegex.MatchString(code)
+	ca.DynamicSQL = len(plsqlDynamicSQLRegex.FindAllStringIndex(code, -1))
+	ca.Cursors = len(plsqlCursorRegex.FindAllStringIndex(code, -1))
+	ca.DbmsPackages = distinctSubmatch(plsqlDbmsRegex, code)
+	ca.DatabaseLinks = distinctSubmatch(plsqlDatabaseLinkRegex, code)
+
+	for _, b := range plsqlOracleBuiltins {
+		if b.regex.MatchString(code) {
+			ca.OracleBuiltins = append(ca.OracleBuiltins, b.name)
+		}
+	}
+	ca.OracleBuiltins = append(ca.OracleBuiltins, distinctSubmatch(plsqlUtlRegex, code)...)
+
+	return ca
+}
+
+// stripOracleCode 去除注释以及字符串常量内容（保留空字符串 ”），保留换行用于统计代码行数
+func stripOracleCode(source string) string {
+	var (
+		b strings.Builder
+		i int
+	)
+	n := len(source)
+	for i < n {
+		c := source[i]
+		switch {
+		case c == '-' && i+1 < n && source[i+1] == '-':
+			for i < n && source[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < n && source[i+1] == '*':
+			i += 2
+			for i < n && !(source[i] == '*' && i+1 < n && source[i+1] == '/') {
+				if source[i] == '\n' {
+					b.WriteByte('\n')
+				}
+				i++
+			}
+			i += 2
+		case (c == 'q' || c == 'Q') && i+2 < n && source[i+1] == '\'' && (i == 0 || !isOracleIdentifierChar(source[i-1])):
+			// q'[...]' 自定义引用符字符串
+			closing := source[i+2]
+			switch closing {
+			case '[':
+				closing = ']'
+			case '{':
+				closing = '}'
+			case '(':
+				closing = ')'
+			case '<':
+				closing = '>'
+			}
+			i += 3
+			for i < n && !(source[i] == closing && i+1 < n && source[i+1] == '\'') {
+				if source[i] == '\n' {
+					b.WriteByte('\n')
+				}
+				i++
+			}
+			i += 2
+			b.WriteString(`''`)
+		case c == '\'':
+			i++
+			for i < n {
+				if source[i] == '\'' {
+					if i+1 < n && source[i+1] == '\'' {
+						i += 2
+						continue
+					}
+					break
+				}
+				if source[i] == '\n' {
+					b.WriteByte('\n')
+				}
+				i++
+			}
+			i++
+			b.WriteString(`''`)
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return b.String()
+}
+
+func isOracleIdentifierChar(c byte) bool {
+	return c == '_' || c == '$' || c == '#' || (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func distinctSubmatch(regex *regexp.Regexp, code string) []string {
+	set := make(map[string]struct{})
+	for _, m := range regex.FindAllStringSubmatch(code, -1) {
+		set[m[1]] = struct{}{}
+	}
+	var res []string
+	for k := range set {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+	return res
+}
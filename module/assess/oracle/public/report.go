@@ -26,11 +26,14 @@ import (
 var fs embed.FS
 
 type Report struct {
+	RunID string
 	*ReportOverview
 	*ReportSummary
+	*ReportEffort
 	*ReportCompatible
 	*ReportCheck
 	*ReportRelated
+	*ReportWorkload
 }
 
 func GenNewHTMLReport(report *Report, file *os.File) error {
@@ -55,6 +58,10 @@ func GenNewHTMLReport(report *Report, file *os.File) error {
 		return fmt.Errorf("template FS Execute [report_summary] template HTML failed: %v", err)
 	}
 
+	if err = tf.ExecuteTemplate(file, "report_effort", report.ReportEffort); err != nil {
+		return fmt.Errorf("template FS Execute [report_effort] template HTML failed: %v", err)
+	}
+
 	if err = tf.ExecuteTemplate(file, "report_detail", nil); err != nil {
 		return fmt.Errorf("template FS Execute [report_detail] template HTML failed: %v", err)
 	}
@@ -71,6 +78,10 @@ func GenNewHTMLReport(report *Report, file *os.File) error {
 		return fmt.Errorf("template FS Execute [report_related] template HTML failed: %v", err)
 	}
 
+	if err = tf.ExecuteTemplate(file, "report_workload", report.ReportWorkload); err != nil {
+		return fmt.Errorf("template FS Execute [report_workload] template HTML failed: %v", err)
+	}
+
 	if err = tf.ExecuteTemplate(file, "report_footer", nil); err != nil {
 		return fmt.Errorf("template FS Execute [report_footer] template HTML failed: %v", err)
 	}
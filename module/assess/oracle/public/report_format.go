@@ -0,0 +1,198 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package public
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"github.com/wentaojin/transferdb/common"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// 评估报告 JSON schema 版本，字段新增、删除或者语义变更需升级版本
+const ReportSchemaVersion = "1.5"
+
+// ReportJSON 评估报告 JSON 结构，字段名以及层级保持稳定，便于工具解析以及多次评估结果对比
+type ReportJSON struct {
+	SchemaVersion string            `json:"schema_version"`
+	RunID         string            `json:"run_id"`
+	Overview      *ReportOverview   `json:"overview"`
+	Summary       *ReportSummary    `json:"summary"`
+	Effort        *ReportEffort     `json:"effort"`
+	Compatible    *ReportCompatible `json:"compatible"`
+	Check         *ReportCheck      `json:"check"`
+	Related       *ReportRelated    `json:"related"`
+	Workload      *ReportWorkload   `json:"workload"`
+}
+
+func NewReportJSON(report *Report) *ReportJSON {
+	return &ReportJSON{
+		SchemaVersion: ReportSchemaVersion,
+		RunID:         report.RunID,
+		Overview:      report.ReportOverview,
+		Summary:       report.ReportSummary,
+		Effort:        report.ReportEffort,
+		Compatible:    report.ReportCompatible,
+		Check:         report.ReportCheck,
+		Related:       report.ReportRelated,
+		Workload:      report.ReportWorkload,
+	}
+}
+
+// GenNewReport 根据报告格式输出评估报告
+func GenNewReport(report *Report, reportFormat string, file *os.File) error {
+	switch common.StringUPPER(reportFormat) {
+	case common.AssessReportFormatHTML:
+		return GenNewHTMLReport(report, file)
+	case common.AssessReportFormatJSON:
+		return GenNewJSONReport(report, file)
+	case common.AssessReportFormatMarkdown:
+		return GenNewMarkdownReport(report, file)
+	default:
+		return fmt.Errorf("assess report format [%s] isn't support", reportFormat)
+	}
+}
+
+func GenNewJSONReport(report *Report, file *os.File) error {
+	jsonByte, err := json.MarshalIndent(NewReportJSON(report), "", "  ")
+	if err != nil {
+		return fmt.Errorf("assess report json marshal failed: %v", err)
+	}
+	if _, err = file.Write(append(jsonByte, '\n')); err != nil {
+		return fmt.Errorf("assess report json write failed: %v", err)
+	}
+	return nil
+}
+
+// GenNewMarkdownReport 章节与 HTML 报告保持一致，表头基于 json tag
+func GenNewMarkdownReport(report *Report, file *os.File) error {
+	w := bufio.NewWriter(file)
+
+	w.WriteString("# TRANSFERDB ASSESS REPORT\n\n")
+	w.WriteString(fmt.Sprintf("schema_version: %s\n\n", ReportSchemaVersion))
+	w.WriteString(fmt.Sprintf("run_id: %s\n\n", report.RunID))
+
+	w.WriteString("## REPORT OVERVIEW\n\n")
+	writeMarkdownObject(w, report.ReportOverview)
+
+	w.WriteString("## REPORT SUMMARY\n\n")
+	writeMarkdownObject(w, report.ReportSummary)
+
+	w.WriteString("## REPORT EFFORT\n\n")
+	writeMarkdownObject(w, report.ReportEffort)
+	writeMarkdownSections(w, report.ReportEffort)
+
+	w.WriteString("## REPORT COMPATIBLE\n\n")
+	writeMarkdownSections(w, report.ReportCompatible)
+
+	w.WriteString("## REPORT CHECK\n\n")
+	writeMarkdownSections(w, report.ReportCheck)
+
+	w.WriteString("## REPORT RELATED\n\n")
+	writeMarkdownSections(w, report.ReportRelated)
+
+	w.WriteString("## REPORT SQL WORKLOAD\n\n")
+	writeMarkdownObject(w, report.ReportWorkload)
+	writeMarkdownSections(w, report.ReportWorkload)
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("assess report markdown write failed: %v", err)
+	}
+	return nil
+}
+
+// writeMarkdownObject 单个结构体按 key/value 两列输出，列表字段由 writeMarkdownSections 输出
+func writeMarkdownObject(w *bufio.Writer, obj interface{}) {
+	v := reflect.Indirect(reflect.ValueOf(obj))
+	if !v.IsValid() {
+		w.WriteString("NULL\n\n")
+		return
+	}
+	w.WriteString("| ITEM | VALUE |\n| --- | --- |\n")
+	for i := 0; i < v.NumField(); i++ {
+		if v.Type().Field(i).Type.Kind() == reflect.Slice {
+			continue
+		}
+		w.WriteString(fmt.Sprintf("| %s | %s |\n", markdownHeader(v.Type().Field(i)), markdownEscape(fmt.Sprintf("%v", v.Field(i).Interface()))))
+	}
+	w.WriteString("\n")
+}
+
+// writeMarkdownSections 结构体内每个列表字段输出一个章节表格
+func writeMarkdownSections(w *bufio.Writer, obj interface{}) {
+	v := reflect.Indirect(reflect.ValueOf(obj))
+	if !v.IsValid() {
+		w.WriteString("NULL\n\n")
+		return
+	}
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if field.Type.Kind() != reflect.Slice {
+			continue
+		}
+		w.WriteString(fmt.Sprintf("### %s\n\n", strings.TrimPrefix(field.Tag.Get("json"), "list_")))
+		writeMarkdownTable(w, field.Type.Elem(), v.Field(i))
+	}
+}
+
+func writeMarkdownTable(w *bufio.Writer, elem reflect.Type, rows reflect.Value) {
+	var (
+		headers []string
+		aligns  []string
+	)
+	for i := 0; i < elem.NumField(); i++ {
+		headers = append(headers, markdownHeader(elem.Field(i)))
+		aligns = append(aligns, "---")
+	}
+	w.WriteString(fmt.Sprintf("| %s |\n| %s |\n", strings.Join(headers, " | "), strings.Join(aligns, " | ")))
+
+	for r := 0; r < rows.Len(); r++ {
+		row := rows.Index(r)
+		var values []string
+		for i := 0; i < row.NumField(); i++ {
+			values = append(values, markdownEscape(fmt.Sprintf("%v", row.Field(i).Interface())))
+		}
+		w.WriteString(fmt.Sprintf("| %s |\n", strings.Join(values, " | ")))
+	}
+	w.WriteString("\n")
+}
+
+func markdownHeader(field reflect.StructField) string {
+	name := field.Tag.Get("json")
+	if name == "" {
+		name = field.Name
+	}
+	return common.StringUPPER(strings.ReplaceAll(name, "_", " "))
+}
+
+func markdownEscape(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(strings.ReplaceAll(s, "\r\n", "<br>"), "\n", "<br>")
+}
+
+// GenNewReportFile 评估报告写入文件，文件存在则覆盖
+func GenNewReportFile(report *Report, reportFormat, fileName string) error {
+	file, err := os.OpenFile(fileName, os.O_WRONLY|os.O_CREATE|os.O_APPEND|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return GenNewReport(report, reportFormat, file)
+}
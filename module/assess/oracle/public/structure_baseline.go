@@ -0,0 +1,66 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package public
+
+import "encoding/json"
+
+// 两次评估结果对比，base 为基线评估，target 为对比评估
+type ReportBaseline struct {
+	BaseRunID              string           `json:"base_run_id"`
+	BaseRunTime            string           `json:"base_run_time"`
+	BaseReportVersion      string           `json:"base_report_version"`
+	TargetRunID            string           `json:"target_run_id"`
+	TargetRunTime          string           `json:"target_run_time"`
+	TargetReportVersion    string           `json:"target_report_version"`
+	BaseIncompatible       int              `json:"base_incompatible"`
+	TargetIncompatible     int              `json:"target_incompatible"`
+	BaseTotalEffort        float64          `json:"base_total_effort"`
+	TargetTotalEffort      float64          `json:"target_total_effort"`
+	ListBecameCompatible   []BaselineObject `json:"list_became_compatible"`
+	ListNewIncompatible    []BaselineObject `json:"list_new_incompatible"`
+	ListChangedObjectValue []BaselineChange `json:"list_changed_object_value"`
+}
+
+func (rb *ReportBaseline) String() string {
+	jsonStr, _ := json.Marshal(rb)
+	return string(jsonStr)
+}
+
+type BaselineObject struct {
+	Section   string `json:"section"`
+	Schema    string `json:"schema"`
+	ObjectKey string `json:"object_key"`
+	Detail    string `json:"detail"`
+}
+
+func (ro *BaselineObject) String() string {
+	jsonStr, _ := json.Marshal(ro)
+	return string(jsonStr)
+}
+
+type BaselineChange struct {
+	Section     string `json:"section"`
+	Schema      string `json:"schema"`
+	ObjectKey   string `json:"object_key"`
+	Item        string `json:"item"`
+	BaseValue   string `json:"base_value"`
+	TargetValue string `json:"target_value"`
+}
+
+func (ro *BaselineChange) String() string {
+	jsonStr, _ := json.Marshal(ro)
+	return string(jsonStr)
+}
@@ -29,6 +29,7 @@ type ReportCheck struct {
 	ListSchemaTableIndexNameLengthCheck  []SchemaTableIndexNameLengthCheck  `json:"list_schema_table_index_name_length_check"`
 	ListSchemaViewNameLengthCheck        []SchemaViewNameLengthCheck        `json:"list_schema_view_name_length_check"`
 	ListSchemaSequenceNameLengthCheck    []SchemaSequenceNameLengthCheck    `json:"list_schema_sequence_name_length_check"`
+	ListSchemaIdentifierCheck            []SchemaIdentifierCheck            `json:"list_schema_identifier_check"`
 }
 
 func (rc *ReportCheck) String() string {
@@ -162,3 +163,18 @@ func (ro *SchemaSequenceNameLengthCheck) String() string {
 	jsonStr, _ := json.Marshal(ro)
 	return string(jsonStr)
 }
+
+// SchemaIdentifierCheck 对象名长度超过 Oracle 标识符长度限制或者与 Oracle 保留字冲突，适用于 MySQL/TiDB 源端
+type SchemaIdentifierCheck struct {
+	Schema     string `json:"schema"`
+	ObjectType string `json:"object_type"`
+	TableName  string `json:"table_name"`
+	ObjectName string `json:"object_name"`
+	Length     string `json:"length"`
+	CheckItem  string `json:"check_item"`
+}
+
+func (ro *SchemaIdentifierCheck) String() string {
+	jsonStr, _ := json.Marshal(ro)
+	return string(jsonStr)
+}
@@ -28,6 +28,8 @@ type ReportCompatible struct {
 	ListSchemaPartitionTypeCompatibles      []SchemaPartitionTypeCompatibles      `json:"list_schema_partition_type_compatibles"`
 	ListSchemaSubPartitionTypeCompatibles   []SchemaSubPartitionTypeCompatibles   `json:"list_schema_sub_partition_type_compatibles"`
 	ListSchemaTemporaryTableTypeCompatibles []SchemaTemporaryTableTypeCompatibles `json:"list_schema_temporary_table_type_compatibles"`
+	ListSchemaCharsetCompatibles            []SchemaCharsetCompatibles            `json:"list_schema_charset_compatibles"`
+	ListSchemaFeatureCompatibles            []SchemaFeatureCompatibles            `json:"list_schema_feature_compatibles"`
 }
 
 func (sc *ReportCompatible) String() string {
@@ -168,3 +170,34 @@ func (sc *SchemaTemporaryTableTypeCompatibles) String() string {
 	jsonStr, _ := json.Marshal(sc)
 	return string(jsonStr)
 }
+
+// SchemaCharsetCompatibles 字段字符集以及排序规则映射，适用于 MySQL/TiDB 源端
+type SchemaCharsetCompatibles struct {
+	Schema          string `json:"schema"`
+	CharacterSet    string `json:"character_set"`
+	Collation       string `json:"collation"`
+	ObjectCounts    string `json:"object_counts"`
+	CharacterSetMap string `json:"character_set_map"`
+	CollationMap    string `json:"collation_map"`
+	IsCompatible    string `json:"is_compatible"`
+	IsConvertible   string `json:"is_convertible"`
+}
+
+func (sc *SchemaCharsetCompatibles) String() string {
+	jsonStr, _ := json.Marshal(sc)
+	return string(jsonStr)
+}
+
+// SchemaFeatureCompatibles 目标端无等价实现的源端特性，适用于 MySQL/TiDB 源端
+type SchemaFeatureCompatibles struct {
+	Schema        string `json:"schema"`
+	Feature       string `json:"feature"`
+	ObjectCounts  string `json:"object_counts"`
+	IsCompatible  string `json:"is_compatible"`
+	IsConvertible string `json:"is_convertible"`
+}
+
+func (sc *SchemaFeatureCompatibles) String() string {
+	jsonStr, _ := json.Marshal(sc)
+	return string(jsonStr)
+}
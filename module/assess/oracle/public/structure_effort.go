@@ -0,0 +1,46 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package public
+
+import "encoding/json"
+
+// 迁移工作量评估，单位：人天
+type ReportEffort struct {
+	TotalEffort      float64        `json:"total_effort"`
+	Difficulty       string         `json:"difficulty"`
+	ListSchemaEffort []SchemaEffort `json:"list_schema_effort"`
+}
+
+func (re *ReportEffort) String() string {
+	jsonStr, _ := json.Marshal(re)
+	return string(jsonStr)
+}
+
+type SchemaEffort struct {
+	Schema                 string  `json:"schema"`
+	ObjectTypeEffort       float64 `json:"object_type_effort"`
+	DatatypeEffort         float64 `json:"datatype_effort"`
+	CodeLineEffort         float64 `json:"code_line_effort"`
+	PartitionTypeEffort    float64 `json:"partition_type_effort"`
+	SubPartitionTypeEffort float64 `json:"sub_partition_type_effort"`
+	TotalEffort            float64 `json:"total_effort"`
+	Difficulty             string  `json:"difficulty"`
+}
+
+func (ro *SchemaEffort) String() string {
+	jsonStr, _ := json.Marshal(ro)
+	return string(jsonStr)
+}
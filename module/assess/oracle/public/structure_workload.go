@@ -0,0 +1,51 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package public
+
+import "encoding/json"
+
+// SQL 负载兼容性评估，耗时单位：毫秒
+type ReportWorkload struct {
+	SQLSource                   string           `json:"sql_source"`
+	TotalSQL                    int              `json:"total_sql"`
+	IncompatibleSQL             int              `json:"incompatible_sql"`
+	IncompatibleExecutionsRatio string           `json:"incompatible_executions_ratio"`
+	IncompatibleElapsedRatio    string           `json:"incompatible_elapsed_ratio"`
+	ListSchemaSQLWorkload       []SchemaWorkload `json:"list_schema_sql_workload"`
+}
+
+func (rw *ReportWorkload) String() string {
+	jsonStr, _ := json.Marshal(rw)
+	return string(jsonStr)
+}
+
+type SchemaWorkload struct {
+	Schema         string `json:"schema"`
+	SQLID          string `json:"sql_id"`
+	Executions     string `json:"executions"`
+	ElapsedTime    string `json:"elapsed_time"`
+	ElapsedRatio   string `json:"elapsed_ratio"`
+	IsCompatible   string `json:"is_compatible"`
+	IsConvertible  string `json:"is_convertible"`
+	TranslateRules string `json:"translate_rules"`
+	Reason         string `json:"reason"`
+	SQLText        string `json:"sql_text"`
+}
+
+func (ro *SchemaWorkload) String() string {
+	jsonStr, _ := json.Marshal(ro)
+	return string(jsonStr)
+}
@@ -22,6 +22,7 @@ type ReportRelated struct {
 	ListSchemaTableSizeData          []SchemaTableSizeData          `json:"list_schema_table_size_data"`
 	ListSchemaTableRowsTOP           []SchemaTableRowsTOP           `json:"list_schema_table_rows_top"`
 	ListSchemaCodeObject             []SchemaCodeObject             `json:"list_schema_code_object"`
+	ListSchemaCodeInventory          []SchemaCodeInventory          `json:"list_schema_code_inventory"`
 	ListSchemaSynonymObject          []SchemaSynonymObject          `json:"list_schema_synonym_object"`
 	ListSchemaMaterializedViewObject []SchemaMaterializedViewObject `json:"list_schema_materialized_view_object"`
 	ListSchemaTableAvgRowLengthTOP   []SchemaTableAvgRowLengthTOP   `json:"list_schema_table_avg_row_length_top"`
@@ -85,6 +86,25 @@ func (ro *SchemaCodeObject) String() string {
 	return string(jsonStr)
 }
 
+type SchemaCodeInventory struct {
+	Schema                string `json:"schema"`
+	ObjectName            string `json:"object_name"`
+	ObjectType            string `json:"object_type"`
+	Lines                 string `json:"lines"`
+	Complexity            string `json:"complexity"`
+	AutonomousTransaction string `json:"autonomous_transaction"`
+	DynamicSQL            string `json:"dynamic_sql"`
+	Cursors               string `json:"cursors"`
+	DbmsPackages          string `json:"dbms_packages"`
+	DatabaseLinks         string `json:"database_links"`
+	OracleBuiltins        string `json:"oracle_builtins"`
+}
+
+func (ro *SchemaCodeInventory) String() string {
+	jsonStr, _ := json.Marshal(ro)
+	return string(jsonStr)
+}
+
 type SchemaSynonymObject struct {
 	Schema      string `json:"schema"`
 	SynonymName string `json:"synonym_name"`
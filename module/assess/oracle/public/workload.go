@@ -0,0 +1,250 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package public
+
+import (
+	"fmt"
+	"github.com/wentaojin/transferdb/common"
+	"github.com/wentaojin/transferdb/database/oracle"
+	migrate "github.com/wentaojin/transferdb/module/migrate/sql/oracle/public"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// sqlTranslateRule Oracle 特有语法改写为 MySQL/TiDB 语法，仅作用于字符串常量之外的 SQL 文本
+type sqlTranslateRule struct {
+	name    string
+	regex   *regexp.Regexp
+	replace string
+}
+
+var (
+	sqlQuotedIdentRegex = regexp.MustCompile(`"([^"]+)"`)
+	sqlBindVarRegex     = regexp.MustCompile(`:([A-Za-z_][A-Za-z0-9_$#]*|[0-9]+)`)
+
+	sqlTranslateRules = []sqlTranslateRule{
+		{"NVL", regexp.MustCompile(`(?i)\bNVL\s*\(`), "IFNULL("},
+		{"SYSDATE", regexp.MustCompile(`(?i)\bSYSDATE\b(\s*[^\s(]|\s*$)`), "SYSDATE()$1"},
+		{"SYSTIMESTAMP", regexp.MustCompile(`(?i)\bSYSTIMESTAMP\b`), "CURRENT_TIMESTAMP(6)"},
+		{"NEXTVAL", regexp.MustCompile(`(?i)\b((?:[A-Za-z0-9_$#` + "`" + `]+\.)?[A-Za-z0-9_$#` + "`" + `]+)\s*\.\s*NEXTVAL\b`), "NEXTVAL($1)"},
+		{"CURRVAL", regexp.MustCompile(`(?i)\b((?:[A-Za-z0-9_$#` + "`" + `]+\.)?[A-Za-z0-9_$#` + "`" + `]+)\s*\.\s*CURRVAL\b`), "LASTVAL($1)"},
+		{"MINUS", regexp.MustCompile(`(?i)\bMINUS\b`), "EXCEPT"},
+	}
+)
+
+// GetAssessDatabaseWorkloadResult 采样 TOP SQL，改写 Oracle 特有语法后基于 TiDB parser 解析评估兼容性
+// 执行次数、执行耗时用于加权评估不兼容 SQL 对业务负载的影响
+func GetAssessDatabaseWorkloadResult(oracle *oracle.Oracle, schemaName []string, sqlSource string, topN int) (*ReportWorkload, ReportSummary, error) {
+	var (
+		res []map[string]string
+		err error
+	)
+	switch sqlSource {
+	case common.AssessSQLSourceNone:
+		return nil, ReportSummary{}, nil
+	case common.AssessSQLSourceAWR:
+		res, err = oracle.GetOracleSchemaTopSQLFromAWR(schemaName, topN)
+	default:
+		res, err = oracle.GetOracleSchemaTopSQLFromSQLArea(schemaName, topN)
+	}
+	if err != nil {
+		return nil, ReportSummary{}, err
+	}
+
+	var (
+		totalExecutions, totalElapsed               float64
+		incompatibleExecutions, incompatibleElapsed float64
+		assessComp, assessInComp                    int
+		assessConvert, assessInConvert              int
+		listData                                    []SchemaWorkload
+	)
+	for _, r := range res {
+		totalExecutions += workloadFloat(r["EXECUTIONS"])
+		totalElapsed += workloadFloat(r["ELAPSED_TIME"])
+	}
+
+	for _, r := range res {
+		executions := workloadFloat(r["EXECUTIONS"])
+		elapsed := workloadFloat(r["ELAPSED_TIME"])
+
+		translated, rules := TranslateOracleSQL(r["SQL_FULLTEXT"])
+		reason := AssessTiDBSQLCompatible(translated)
+
+		w := SchemaWorkload{
+			Schema:     r["PARSING_SCHEMA_NAME"],
+			SQLID:      r["SQL_ID"],
+			Executions: strconv.FormatFloat(executions, 'f', 0, 64),
+			// 微秒转毫秒
+			ElapsedTime:    strconv.FormatFloat(elapsed/1000, 'f', 2, 64),
+			ElapsedRatio:   workloadRatio(elapsed, totalElapsed),
+			TranslateRules: strings.Join(rules, ","),
+			Reason:         reason,
+			SQLText:        workloadSQLText(r["SQL_FULLTEXT"]),
+		}
+		switch {
+		case reason == "" && len(rules) == 0:
+			w.IsCompatible = common.AssessYesCompatible
+			w.IsConvertible = common.AssessNoConvertible
+			assessComp++
+		case reason == "":
+			w.IsCompatible = common.AssessNoCompatible
+			w.IsConvertible = common.AssessYesConvertible
+			assessConvert++
+		default:
+			w.IsCompatible = common.AssessNoCompatible
+			w.IsConvertible = common.AssessNoConvertible
+			assessInComp++
+			assessInConvert++
+			incompatibleExecutions += executions
+			incompatibleElapsed += elapsed
+		}
+		listData = append(listData, w)
+	}
+
+	return &ReportWorkload{
+		SQLSource:                   sqlSource,
+		TotalSQL:                    len(listData),
+		IncompatibleSQL:             assessInComp,
+		IncompatibleExecutionsRatio: workloadRatio(incompatibleExecutions, totalExecutions),
+		IncompatibleElapsedRatio:    workloadRatio(incompatibleElapsed, totalElapsed),
+		ListSchemaSQLWorkload:       listData,
+	}, ReportSummary{
+		AssessType:    common.AssessTypeSQLWorkload,
+		AssessName:    common.AssessNameSQLWorkloadCompatible,
+		AssessTotal:   len(listData),
+		Compatible:    assessComp,
+		Incompatible:  assessInComp,
+		Convertible:   assessConvert,
+		InConvertible: assessInConvert,
+	}, nil
+}
+
+// TranslateOracleSQL Oracle SQL 改写，返回改写后 SQL 以及命中的改写规则
+// 1、双引号标识符转反引号，绑定变量转 ?，不计入改写规则
+// 2、NVL、SYSDATE、SYSTIMESTAMP、序列 NEXTVAL/CURRVAL、MINUS 改写
+func TranslateOracleSQL(sqlText string) (string, []string) {
+	sqlText = strings.TrimRight(strings.TrimSpace(sqlText), ";")
+
+	hits := make(map[string]struct{})
+	var b strings.Builder
+	for i, seg := range splitOracleSQLLiteral(sqlText) {
+		// 奇数段为字符串常量
+		if i%2 == 1 {
+			b.WriteString(seg)
+			continue
+		}
+		seg = sqlQuotedIdentRegex.ReplaceAllString(seg, "`$1`")
+		seg = sqlBindVarRegex.ReplaceAllString(seg, "?")
+		for _, rule := range sqlTranslateRules {
+			if rule.regex.MatchString(seg) {
+				hits[rule.name] = struct{}{}
+				seg = rule.regex.ReplaceAllString(seg, rule.replace)
+			}
+		}
+		b.WriteString(seg)
+	}
+
+	var rules []string
+	for _, rule := range sqlTranslateRules {
+		if _, ok := hits[rule.name]; ok {
+			rules = append(rules, rule.name)
+		}
+	}
+	return b.String(), rules
+}
+
+// AssessTiDBSQLCompatible TiDB parser 解析 SQL，解析成功后检查 Oracle 特有且无法改写的内置函数以及语法
+// 兼容返回空字符串，否则返回不兼容原因
+func AssessTiDBSQLCompatible(sqlText string) string {
+	if strings.TrimSpace(sqlText) == "" {
+		return "empty sql text"
+	}
+	if _, err := migrate.ParseSQL(sqlText); err != nil {
+		return strings.TrimSpace(fmt.Sprintf("parse failed: %v", err))
+	}
+
+	code := strings.ToUpper(stripOracleCode(sqlText))
+	var builtins []string
+	for _, b := range plsqlOracleBuiltins {
+		if b.regex.MatchString(code) {
+			builtins = append(builtins, b.name)
+		}
+	}
+	builtins = append(builtins, distinctSubmatch(plsqlDbmsRegex, code)...)
+	builtins = append(builtins, distinctSubmatch(plsqlUtlRegex, code)...)
+	if len(builtins) > 0 {
+		return fmt.Sprintf("oracle builtin unsupported: %s", strings.Join(builtins, ","))
+	}
+	return ""
+}
+
+// splitOracleSQLLiteral 按单引号字符串常量切分 SQL，偶数段为 SQL 文本，奇数段为字符串常量（含引号）
+func splitOracleSQLLiteral(sqlText string) []string {
+	var (
+		segs  []string
+		start int
+	)
+	inLiteral := false
+	for i := 0; i < len(sqlText); i++ {
+		if sqlText[i] != '\'' {
+			continue
+		}
+		if inLiteral {
+			if i+1 < len(sqlText) && sqlText[i+1] == '\'' {
+				i++
+				continue
+			}
+			segs = append(segs, sqlText[start:i+1])
+			start = i + 1
+			inLiteral = false
+		} else {
+			segs = append(segs, sqlText[start:i])
+			start = i
+			inLiteral = true
+		}
+	}
+	segs = append(segs, sqlText[start:])
+	return segs
+}
+
+func workloadFloat(s string) float64 {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0
+	}
+	return f
+}
+
+func workloadRatio(part, total float64) string {
+	if total == 0 {
+		return "0.00%"
+	}
+	return strconv.FormatFloat(part/total*100, 'f', 2, 64) + "%"
+}
+
+func workloadSQLText(sqlText string) string {
+	if len(sqlText) <= common.AssessSQLTextMaxLength {
+		return sqlText
+	}
+	// 按完整 UTF-8 字符截断
+	end := common.AssessSQLTextMaxLength
+	for end > 0 && !utf8.RuneStart(sqlText[end]) {
+		end--
+	}
+	return common.StringsBuilder(sqlText[:end], "...")
+}
@@ -17,19 +17,22 @@ package check
 
 import (
 	"bufio"
-	"os"
+	"context"
+	"github.com/wentaojin/transferdb/config"
+	"github.com/wentaojin/transferdb/storage"
+	"io"
 	"sync"
 )
 
 type File struct {
-	CFile   *os.File
+	CFile   io.WriteCloser
 	CWriter *bufio.Writer
 	Mutex   *sync.Mutex
 }
 
-func NewWriter(checkFile string) (*File, error) {
+func NewWriter(ctx context.Context, storageCfg config.StorageConfig, checkFile string) (*File, error) {
 	f := &File{}
-	err := f.initOutFile(checkFile)
+	err := f.initOutFile(ctx, storageCfg, checkFile)
 	if err != nil {
 		return nil, err
 	}
@@ -44,8 +47,9 @@ func (f *File) CWriteFile(s string) (nn int, err error) {
 	return f.CWriter.WriteString(s)
 }
 
-func (f *File) initOutFile(checkFile string) error {
-	outCheckFile, err := os.OpenFile(checkFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND|os.O_TRUNC, 0666)
+func (f *File) initOutFile(ctx context.Context, storageCfg config.StorageConfig, checkFile string) error {
+	// 支持本地目录以及 S3 兼容对象存储
+	outCheckFile, err := storage.NewWriter(ctx, storageCfg, checkFile)
 	if err != nil {
 		return err
 	}
@@ -57,6 +61,7 @@ func (f *File) Close() error {
 	if f.CFile != nil {
 		err := f.CWriter.Flush()
 		if err != nil {
+			_ = storage.Abort(f.CFile, err)
 			return err
 		}
 		err = f.CFile.Close()
@@ -24,10 +24,10 @@ import (
 	"github.com/wentaojin/transferdb/database/mysql"
 	"github.com/wentaojin/transferdb/database/oracle"
 	"github.com/wentaojin/transferdb/module/check"
-	"github.com/wentaojin/transferdb/module/check/mysql/public"
+	"github.com/wentaojin/transferdb/selector"
+	"github.com/wentaojin/transferdb/storage"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
-	"path/filepath"
 	"strings"
 	"time"
 )
@@ -68,7 +68,7 @@ func (r *Check) Check() error {
 		zap.String("mysqlSchema", r.cfg.SchemaConfig.SourceSchema),
 		zap.String("oracleSchema", r.cfg.SchemaConfig.TargetSchema))
 
-	tablesByCfg, _, err := public.FilterCFGTable(r.cfg, r.mysql)
+	tablesByCfg, _, err := selector.SelectMySQLTableAndView(r.cfg, r.mysql)
 	if err != nil {
 		return err
 	}
@@ -281,15 +281,10 @@ func (r *Check) Check() error {
 	tasks := GenCheckTaskTable(r.cfg.SchemaConfig.SourceSchema, r.cfg.SchemaConfig.TargetSchema, oracleDBCharacterSet, nlsSort, nlsComp, oracleTableCollation, oracleSchemaCollation, oracleDBCollation,
 		r.oracle, r.mysql, sourceTableNameRuleMap, waitSyncMetas)
 
-	err = common.PathExist(r.cfg.CheckConfig.CheckSQLDir)
-	if err != nil {
-		return err
-	}
-
-	checkFile := filepath.Join(r.cfg.CheckConfig.CheckSQLDir, fmt.Sprintf("check_%s.sql", r.cfg.SchemaConfig.SourceSchema))
+	checkFile := storage.JoinPath(r.cfg.CheckConfig.CheckSQLDir, fmt.Sprintf("check_%s.sql", r.cfg.SchemaConfig.SourceSchema))
 
 	// file writer
-	f, err := check.NewWriter(checkFile)
+	f, err := check.NewWriter(r.ctx, r.cfg.StorageConfig, checkFile)
 	if err != nil {
 		return err
 	}
@@ -395,7 +390,7 @@ func (r *Check) Check() error {
 		return err
 	}
 
-	zap.L().Info("check", zap.String("output", filepath.Join(r.cfg.CheckConfig.CheckSQLDir, fmt.Sprintf("check_%s.sql", r.cfg.SchemaConfig.SourceSchema))))
+	zap.L().Info("check", zap.String("output", storage.JoinPath(r.cfg.CheckConfig.CheckSQLDir, fmt.Sprintf("check_%s.sql", r.cfg.SchemaConfig.SourceSchema))))
 	if len(failedTotals) == 0 {
 		zap.L().Info("check table mysql to oracle finished",
 			zap.Int("table totals", len(waitSyncMetas)),
@@ -166,9 +166,10 @@ func GetOracleTableColumn(schemaName, tableName string, oracle *oracle.Oracle, s
 		}
 
 		column := public.Column{
-			DataType:   strings.ToUpper(rowCol["DATA_TYPE"]),
-			CharLength: strings.ToUpper(rowCol["CHAR_LENGTH"]),
-			CharUsed:   strings.ToUpper(rowCol["CHAR_USED"]),
+			DataType:     strings.ToUpper(rowCol["DATA_TYPE"]),
+			DataTypeCode: strings.ToUpper(rowCol["DATA_TYPE_CODE"]),
+			CharLength:   strings.ToUpper(rowCol["CHAR_LENGTH"]),
+			CharUsed:     strings.ToUpper(rowCol["CHAR_USED"]),
 			ColumnInfo: public.ColumnInfo{
 				DataLength:        strings.ToUpper(rowCol["DATA_LENGTH"]),
 				DataPrecision:     strings.ToUpper(rowCol["DATA_PRECISION"]),
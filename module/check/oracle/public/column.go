@@ -37,6 +37,7 @@ type Table struct {
 
 type Column struct {
 	DataType                string
+	DataTypeCode            string // 自定义数据类型 DBA_TYPES.TYPECODE（OBJECT、COLLECTION），内置数据类型 NONE
 	CharLength              string
 	CharUsed                string
 	CharacterSet            string
@@ -179,6 +179,7 @@ func ChangeTableColumnType(ctx context.Context, metaDB *meta.Meta, dbTypeS, dbTy
 	}
 	originColumnType, buildInColumnType, err := reverseO2M.OracleTableColumnMapMySQLRule(sourceSchema, sourceTableName, reverseO2M.Column{
 		DataType:                columnINFO.DataType,
+		DataTypeCode:            columnINFO.DataTypeCode,
 		CharLength:              columnINFO.CharLength,
 		CharUsed:                columnINFO.CharUsed,
 		CharacterSet:            columnINFO.CharacterSet,
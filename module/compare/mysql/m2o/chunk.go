@@ -0,0 +1,242 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package m2o
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/wentaojin/transferdb/common"
+	"github.com/wentaojin/transferdb/config"
+	"github.com/wentaojin/transferdb/database/meta"
+	"github.com/wentaojin/transferdb/database/mysql"
+	"github.com/wentaojin/transferdb/database/oracle"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+// Chunk 数据对比
+type Chunk struct {
+	Ctx              context.Context `json:"-"`
+	ChunkID          int             `json:"chunk_id"`
+	SourceGlobalSCN  uint64          `json:"source_global_scn"`
+	SourceTable      string          `json:"source_table"`
+	TargetTable      string          `json:"target_table"`
+	IsPartition      string          `json:"is_partition"`
+	SourceColumnInfo string          `json:"source_column_info"`
+	TargetColumnInfo string          `json:"target_column_info"`
+	WhereColumn      string          `json:"where_column"`
+	WhereRange       string          `json:"where_range"` // chunk split need
+	Cfg              *config.Config  `json:"-"`
+	Oracle           *oracle.Oracle  `json:"-"`
+	MySQL            *mysql.MySQL    `json:"-"`
+	MetaDB           *meta.Meta      `json:"-"`
+}
+
+func NewChunk(ctx context.Context, cfg *config.Config, oracle *oracle.Oracle, mysql *mysql.MySQL, metaDB *meta.Meta,
+	chunkID int, sourceGlobalSCN uint64, sourceTable, targetTable string, isPartition string, sourceColumnInfo, targetColumnInfo string,
+	whereColumn string) *Chunk {
+	return &Chunk{
+		Ctx:              ctx,
+		ChunkID:          chunkID,
+		SourceGlobalSCN:  sourceGlobalSCN,
+		SourceTable:      sourceTable,
+		TargetTable:      targetTable,
+		IsPartition:      isPartition,
+		SourceColumnInfo: sourceColumnInfo,
+		TargetColumnInfo: targetColumnInfo,
+		WhereColumn:      whereColumn,
+		Oracle:           oracle,
+		MySQL:            mysql,
+		MetaDB:           metaDB,
+		Cfg:              cfg,
+	}
+}
+
+func (c *Chunk) CustomTableConfig() (customColumn string, customRange string, err error) {
+	// 获取配置文件自定义配置
+	for _, tableCfg := range c.Cfg.SchemaConfig.CompareConfig {
+		if strings.EqualFold(c.SourceTable, tableCfg.SourceTable) {
+			// 同张表如果同时存在 indexFields 以及 Range，那么 Range 优先级 > indexFields
+			// range 条件同时用于上下游查询，需上下游语法通用
+			if tableCfg.Range != "" {
+				customRange = tableCfg.Range
+				return customColumn, customRange, nil
+			}
+			// indexFields 需要是整型数据类型字段
+			if tableCfg.IndexFields != "" {
+				customColumn, err = c.MySQL.GetMySQLTableChunkColumn(c.Cfg.SchemaConfig.SourceSchema, c.SourceTable, tableCfg.IndexFields)
+				if err != nil {
+					zap.L().Warn("compare table config index filed isn't integer data type",
+						zap.String("table", tableCfg.SourceTable),
+						zap.String("index filed", tableCfg.IndexFields),
+						zap.String("range", tableCfg.Range))
+					return customColumn, customRange, fmt.Errorf("config file index-filed isn't integer type, error: %v", err)
+				}
+				return customColumn, customRange, nil
+			}
+			return customColumn, customRange, nil
+		}
+	}
+	return customColumn, customRange, nil
+}
+
+func (c *Chunk) Split() error {
+	startTime := time.Now()
+
+	// 配置文件参数优先级
+	// onlyCheckRows > configRange > configIndexFiled > chunk-column > DBFilter Integer Column
+	// first
+	if c.Cfg.DiffConfig.OnlyCheckRows {
+		// SELECT COUNT(1) FROM TAB WHERE 1=1
+		c.SourceColumnInfo = "COUNT(1)"
+		c.TargetColumnInfo = "COUNT(1)"
+		c.WhereColumn = ""
+		return c.createChunks([]map[string]string{{"CMD": "1 = 1"}}, startTime)
+	}
+
+	// second
+	// Range > IndexFields
+	customColumn, customRange, err := c.CustomTableConfig()
+	if err != nil {
+		return err
+	}
+
+	if !strings.EqualFold(customRange, "") {
+		// range = "age > 1 and age < 10"
+		// select xxx from tab where age > 1 and age < 10
+		c.WhereColumn = ""
+		return c.createChunks([]map[string]string{{"CMD": customRange}}, startTime)
+	}
+
+	// third
+	tableRowsByStatistics, err := c.MySQL.GetMySQLTableRowsByStatistics(c.Cfg.SchemaConfig.SourceSchema, c.SourceTable)
+	if err != nil {
+		return err
+	}
+	// 统计信息数据行数 0，直接全表扫
+	if tableRowsByStatistics == 0 {
+		zap.L().Warn("get mysql table rows",
+			zap.String("schema", c.Cfg.SchemaConfig.SourceSchema),
+			zap.String("table", c.SourceTable),
+			zap.String("where", "1 = 1"),
+			zap.Int("statistics rows", tableRowsByStatistics))
+		c.WhereColumn = ""
+		return c.createChunks([]map[string]string{{"CMD": "1 = 1"}}, startTime)
+	}
+
+	zap.L().Info("get mysql table statistics rows",
+		zap.String("schema", c.Cfg.SchemaConfig.SourceSchema),
+		zap.String("table", c.SourceTable),
+		zap.Int("rows", tableRowsByStatistics))
+
+	// forth
+	// 基于整型字段范围切分，chunk 条件同时用于上下游查询，字段优先级 index-fields > chunk-column > DB Filter integer column
+	chunkStrategy, chunkColumn := c.CustomMigrateChunkConfig()
+	switch {
+	case strings.EqualFold(chunkStrategy, "") || strings.EqualFold(chunkStrategy, common.ChunkStrategyPK):
+	default:
+		return fmt.Errorf("mysql table [%s.%s] migrate config chunk-strategy [%s] isn't support, support [pk]", c.Cfg.SchemaConfig.SourceSchema, c.SourceTable, chunkStrategy)
+	}
+
+	switch {
+	case !strings.EqualFold(customColumn, ""):
+		c.WhereColumn = customColumn
+	case !strings.EqualFold(chunkColumn, ""):
+		c.WhereColumn, err = c.MySQL.GetMySQLTableChunkColumn(c.Cfg.SchemaConfig.SourceSchema, c.SourceTable, chunkColumn)
+		if err != nil {
+			return err
+		}
+	}
+
+	var chunkRes []map[string]string
+	if !strings.EqualFold(c.WhereColumn, "") {
+		chunkRes, err = c.MySQL.GetMySQLTableChunksByRange(c.Cfg.SchemaConfig.SourceSchema, c.SourceTable, c.WhereColumn, tableRowsByStatistics, c.Cfg.DiffConfig.ChunkSize)
+		if err != nil {
+			return err
+		}
+	}
+
+	// chunk 首尾开区间，无需额外补充上下游数据边界
+	// 不存在切分字段或者无范围 chunk（最小值等于最大值），全表对比，不能仅对比 IS NULL 数据
+	if len(chunkRes) == 0 {
+		c.WhereColumn = ""
+		chunkRes = append(chunkRes, map[string]string{"CMD": "1 = 1"})
+	}
+	return c.createChunks(chunkRes, startTime)
+}
+
+func (c *Chunk) CustomMigrateChunkConfig() (string, string) {
+	for _, tableCfg := range c.Cfg.SchemaConfig.MigrateConfig {
+		if strings.EqualFold(c.SourceTable, tableCfg.SourceTable) {
+			return tableCfg.ChunkStrategy, tableCfg.ChunkColumn
+		}
+	}
+	return "", ""
+}
+
+// createChunks chunk 条件以源端 MySQL 语法记录，目标端查询时转换
+func (c *Chunk) createChunks(chunkRes []map[string]string, startTime time.Time) error {
+	var fullMetas []meta.DataCompareMeta
+	for _, r := range chunkRes {
+		fullMetas = append(fullMetas, meta.DataCompareMeta{
+			DBTypeS:       c.Cfg.DBTypeS,
+			DBTypeT:       c.Cfg.DBTypeT,
+			SchemaNameS:   c.Cfg.SchemaConfig.SourceSchema,
+			TableNameS:    c.SourceTable,
+			SchemaNameT:   common.StringUPPER(c.Cfg.SchemaConfig.TargetSchema),
+			TableNameT:    c.TargetTable,
+			ColumnDetailS: c.SourceColumnInfo,
+			ColumnDetailT: c.TargetColumnInfo,
+			WhereRange:    r["CMD"],
+			WhereColumn:   c.WhereColumn,
+			IsPartition:   c.IsPartition,
+			TaskMode:      c.Cfg.TaskMode,
+			TaskStatus:    common.TaskStatusWaiting})
+	}
+
+	// 元数据库信息 batch 写入
+	err := meta.NewCommonModel(c.MetaDB).BatchCreateDataCompareMetaAndUpdateWaitSyncMeta(c.Ctx,
+		fullMetas, c.Cfg.AppConfig.InsertBatchSize, &meta.WaitSyncMeta{
+			DBTypeS:          c.Cfg.DBTypeS,
+			DBTypeT:          c.Cfg.DBTypeT,
+			SchemaNameS:      common.StringUPPER(c.Cfg.SchemaConfig.SourceSchema),
+			TableNameS:       c.SourceTable,
+			TaskMode:         c.Cfg.TaskMode,
+			GlobalScnS:       c.SourceGlobalSCN,
+			ChunkTotalNums:   int64(len(fullMetas)),
+			ChunkSuccessNums: 0,
+			ChunkFailedNums:  0,
+			IsPartition:      c.IsPartition,
+		})
+	if err != nil {
+		return fmt.Errorf("create table [%s.%s] data_diff_meta [batch size] failed: %v", c.Cfg.SchemaConfig.SourceSchema, c.SourceTable, err)
+	}
+
+	zap.L().Info("pre split mysql and oracle table chunk finished",
+		zap.String("schema", c.Cfg.SchemaConfig.SourceSchema),
+		zap.String("table", c.SourceTable),
+		zap.String("column", c.WhereColumn),
+		zap.Int("chunks", len(fullMetas)),
+		zap.String("cost", time.Now().Sub(startTime).String()))
+	return nil
+}
+
+func (c *Chunk) String() string {
+	jsonByte, _ := json.Marshal(c)
+	return string(jsonByte)
+}
@@ -0,0 +1,635 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package m2o
+
+import (
+	"context"
+	"fmt"
+	"github.com/wentaojin/transferdb/common"
+	"github.com/wentaojin/transferdb/config"
+	"github.com/wentaojin/transferdb/database/meta"
+	"github.com/wentaojin/transferdb/database/mysql"
+	"github.com/wentaojin/transferdb/database/oracle"
+	"github.com/wentaojin/transferdb/mapping"
+	"github.com/wentaojin/transferdb/module/compare"
+	"github.com/wentaojin/transferdb/module/compare/mysql/public"
+	"github.com/wentaojin/transferdb/selector"
+	"github.com/wentaojin/transferdb/storage"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+	"strings"
+	"time"
+)
+
+type Compare struct {
+	ctx    context.Context
+	cfg    *config.Config
+	mysql  *mysql.MySQL
+	oracle *oracle.Oracle
+	metaDB *meta.Meta
+}
+
+func NewCompare(ctx context.Context, cfg *config.Config) (*Compare, error) {
+	mysqlDB, err := mysql.NewMySQLDBEngine(ctx, cfg.MySQLConfig)
+	if err != nil {
+		return nil, err
+	}
+	oracleDB, err := oracle.NewOracleDBEngine(ctx, cfg.OracleConfig, cfg.SchemaConfig.TargetSchema)
+	if err != nil {
+		return nil, err
+	}
+	metaDB, err := meta.NewMetaDBEngine(ctx, cfg.MetaConfig, cfg.AppConfig.SlowlogThreshold)
+	if err != nil {
+		return nil, err
+	}
+	return &Compare{
+		ctx:    ctx,
+		cfg:    cfg,
+		mysql:  mysqlDB,
+		oracle: oracleDB,
+		metaDB: metaDB,
+	}, nil
+}
+
+func (r *Compare) NewCompare() error {
+	startTime := time.Now()
+	zap.L().Info("diff table mysql to oracle start",
+		zap.String("schema", r.cfg.SchemaConfig.SourceSchema))
+
+	// 获取配置文件待同步表列表
+	exporters, err := selector.SelectMySQLTable(r.cfg, r.mysql)
+	if err != nil {
+		return err
+	}
+
+	// 关于全量断点恢复
+	if !r.cfg.DiffConfig.EnableCheckpoint {
+		err = meta.NewDataCompareMetaModel(r.metaDB).TruncateDataCompareMeta(r.ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, tableName := range exporters {
+			err = meta.NewWaitSyncMetaModel(r.metaDB).DeleteWaitSyncMeta(r.ctx, &meta.WaitSyncMeta{
+				DBTypeS:     r.cfg.DBTypeS,
+				DBTypeT:     r.cfg.DBTypeT,
+				SchemaNameS: r.cfg.SchemaConfig.SourceSchema,
+				TableNameS:  tableName,
+				TaskMode:    r.cfg.TaskMode,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// 清理非当前任务 SUCCESS 表元数据记录 wait_sync_meta (用于统计 SUCCESS 准备)
+	// 例如：当前任务表 A/B，之前任务表 A/C (SUCCESS)，清理元数据 C，对于表 A 任务 Skip 忽略处理，除非手工清理表 A
+	tablesByMeta, err := meta.NewWaitSyncMetaModel(r.metaDB).DetailWaitSyncMetaSuccessTables(r.ctx, &meta.WaitSyncMeta{
+		DBTypeS:     r.cfg.DBTypeS,
+		DBTypeT:     r.cfg.DBTypeT,
+		SchemaNameS: common.StringUPPER(r.cfg.SchemaConfig.SourceSchema),
+		TaskMode:    r.cfg.TaskMode,
+		TaskStatus:  common.TaskStatusSuccess,
+	})
+	if err != nil {
+		return err
+	}
+
+	clearTables := common.FilterDifferenceStringItems(tablesByMeta, exporters)
+	interTables := common.FilterIntersectionStringItems(tablesByMeta, exporters)
+	if len(clearTables) > 0 {
+		err = meta.NewWaitSyncMetaModel(r.metaDB).DeleteWaitSyncMetaSuccessTables(r.ctx, &meta.WaitSyncMeta{
+			DBTypeS:     r.cfg.DBTypeS,
+			DBTypeT:     r.cfg.DBTypeT,
+			SchemaNameS: common.StringUPPER(r.cfg.SchemaConfig.SourceSchema),
+			TaskMode:    r.cfg.TaskMode,
+			TaskStatus:  common.TaskStatusSuccess,
+		}, clearTables)
+		if err != nil {
+			return err
+		}
+	}
+	zap.L().Warn("non-task table clear",
+		zap.Strings("clear tables", clearTables),
+		zap.Strings("intersection tables", interTables),
+		zap.Int("clear totals", len(clearTables)),
+		zap.Int("intersection total", len(interTables)))
+
+	// 判断 [wait_sync_meta] 是否存在错误记录，是否可进行 COMPARE
+	errTotals, err := meta.NewWaitSyncMetaModel(r.metaDB).CountsErrWaitSyncMetaBySchema(r.ctx, &meta.WaitSyncMeta{
+		DBTypeS:     r.cfg.DBTypeS,
+		DBTypeT:     r.cfg.DBTypeT,
+		SchemaNameS: common.StringUPPER(r.cfg.SchemaConfig.SourceSchema),
+		TaskMode:    r.cfg.TaskMode,
+		TaskStatus:  common.TaskStatusFailed,
+	})
+	if err != nil {
+		return err
+	}
+	if errTotals > 0 {
+		return fmt.Errorf(`compare schema [%s] mode [%s] table task failed: meta table [wait_sync_meta] exist failed error, please: firstly check meta table [wait_sync_meta] and [data_compare_meta] log record; secondly if need resume, update meta table [wait_sync_meta] column [task_status] table status RUNNING (Need UPPER); finally rerunning`, strings.ToUpper(r.cfg.SchemaConfig.SourceSchema), r.cfg.TaskMode)
+	}
+
+	// 判断并记录待同步表列表
+	for _, tableName := range exporters {
+		waitSyncMetas, err := meta.NewWaitSyncMetaModel(r.metaDB).DetailWaitSyncMeta(r.ctx, &meta.WaitSyncMeta{
+			DBTypeS:     r.cfg.DBTypeS,
+			DBTypeT:     r.cfg.DBTypeT,
+			SchemaNameS: common.StringUPPER(r.cfg.SchemaConfig.SourceSchema),
+			TableNameS:  tableName,
+			TaskMode:    r.cfg.TaskMode,
+		})
+		if err != nil {
+			return err
+		}
+		if len(waitSyncMetas) == 0 {
+			err = meta.NewWaitSyncMetaModel(r.metaDB).CreateWaitSyncMeta(r.ctx, &meta.WaitSyncMeta{
+				DBTypeS:        r.cfg.DBTypeS,
+				DBTypeT:        r.cfg.DBTypeT,
+				SchemaNameS:    common.StringUPPER(r.cfg.SchemaConfig.SourceSchema),
+				TableNameS:     tableName,
+				TaskMode:       r.cfg.TaskMode,
+				TaskStatus:     common.TaskStatusWaiting,
+				GlobalScnS:     common.TaskTableDefaultSourceGlobalSCN,
+				ChunkTotalNums: common.TaskTableDefaultSplitChunkNums,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// 获取等待同步以及未同步完成的表列表
+	var waitSyncTables []string
+
+	waitSyncDetails, err := meta.NewWaitSyncMetaModel(r.metaDB).DetailWaitSyncMeta(r.ctx, &meta.WaitSyncMeta{
+		DBTypeS:        r.cfg.DBTypeS,
+		DBTypeT:        r.cfg.DBTypeT,
+		SchemaNameS:    common.StringUPPER(r.cfg.SchemaConfig.SourceSchema),
+		TaskMode:       r.cfg.TaskMode,
+		TaskStatus:     common.TaskStatusWaiting,
+		GlobalScnS:     common.TaskTableDefaultSourceGlobalSCN,
+		ChunkTotalNums: common.TaskTableDefaultSplitChunkNums,
+	})
+	if err != nil {
+		return err
+	}
+	for _, table := range waitSyncDetails {
+		waitSyncTables = append(waitSyncTables, table.TableNameS)
+	}
+
+	// 判断未同步完成的表列表能否断点续传
+	var (
+		partSyncTables    []string
+		panicTblFullSlice []string
+	)
+	partWaitSyncMetas, err := meta.NewWaitSyncMetaModel(r.metaDB).QueryWaitSyncMetaByPartTask(r.ctx, &meta.WaitSyncMeta{
+		DBTypeS:     r.cfg.DBTypeS,
+		DBTypeT:     r.cfg.DBTypeT,
+		SchemaNameS: common.StringUPPER(r.cfg.SchemaConfig.SourceSchema),
+		TaskMode:    r.cfg.TaskMode,
+		TaskStatus:  common.TaskStatusRunning,
+	})
+	if err != nil {
+		return err
+	}
+	for _, t := range partWaitSyncMetas {
+		// 判断 running 状态表 chunk 数是否一致，一致可断点续传
+		chunkCounts, err := meta.NewDataCompareMetaModel(r.metaDB).CountsDataCompareMetaByTaskTable(r.ctx, &meta.DataCompareMeta{
+			DBTypeS:     t.DBTypeS,
+			DBTypeT:     t.DBTypeT,
+			SchemaNameS: t.SchemaNameS,
+			TableNameS:  t.TableNameS,
+			TaskMode:    t.TaskMode,
+		})
+		if err != nil {
+			return err
+		}
+		if chunkCounts != t.ChunkTotalNums {
+			panicTblFullSlice = append(panicTblFullSlice, t.TableNameS)
+		} else {
+			partSyncTables = append(partSyncTables, t.TableNameS)
+		}
+	}
+
+	if len(panicTblFullSlice) > 0 {
+		zap.L().Error("all mysql table data compare error",
+			zap.String("schema", r.cfg.SchemaConfig.SourceSchema),
+			zap.String("cost", time.Now().Sub(startTime).String()),
+			zap.Int("part sync tables", len(partSyncTables)),
+			zap.Strings("panic tables", panicTblFullSlice))
+		return fmt.Errorf("checkpoint isn't consistent, can't be resume, please reruning [enable-checkpoint = fase]")
+	}
+
+	// 判断下游是否存在 ORACLE 表，获取源端表对应目标端实际表名
+	tableNameRuleMap, err := r.getTargetTableName(exporters)
+	if err != nil {
+		return err
+	}
+
+	// 字段映射规则，排除字段不校验，重命名字段按目标端字段名校验，EXPR 字段按源端表达式计算结果校验
+	mapper, err := mapping.NewMapper(r.ctx, r.metaDB, r.cfg.DBTypeS, r.cfg.DBTypeT, r.cfg.SchemaConfig.SourceSchema)
+	if err != nil {
+		return err
+	}
+
+	partTableTasks := NewPartCompareTableTask(r.ctx, r.cfg, partSyncTables, r.mysql, r.oracle, tableNameRuleMap, mapper)
+	waitTableTasks := NewWaitCompareTableTask(r.ctx, r.cfg, waitSyncTables, r.mysql, r.oracle, tableNameRuleMap, mapper)
+
+	// 数据对比
+	checkFile := storage.JoinPath(r.cfg.DiffConfig.FixSqlDir, fmt.Sprintf("compare_%s.sql", r.cfg.SchemaConfig.SourceSchema))
+
+	// file writer
+	f, err := compare.NewWriter(r.ctx, r.cfg.StorageConfig, checkFile)
+	if err != nil {
+		return err
+	}
+
+	// 优先存在断点的表校验
+	// partTableTask -> waitTableTasks
+	if len(partTableTasks) > 0 {
+		err = PreTableStructCheck(r.ctx, r.cfg, r.metaDB, partSyncTables)
+		if err != nil {
+			return err
+		}
+		err = r.comparePartTableTasks(f, partTableTasks)
+		if err != nil {
+			return err
+		}
+	}
+	if len(waitTableTasks) > 0 {
+		err = PreTableStructCheck(r.ctx, r.cfg, r.metaDB, waitSyncTables)
+		if err != nil {
+			return err
+		}
+		err = r.compareWaitTableTasks(f, waitTableTasks)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = f.Close()
+	if err != nil {
+		return err
+	}
+
+	// 任务详情
+	succTotals, err := meta.NewWaitSyncMetaModel(r.metaDB).DetailWaitSyncMeta(r.ctx, &meta.WaitSyncMeta{
+		DBTypeS:     r.cfg.DBTypeS,
+		DBTypeT:     r.cfg.DBTypeT,
+		SchemaNameS: common.StringUPPER(r.cfg.SchemaConfig.SourceSchema),
+		TaskMode:    r.cfg.TaskMode,
+		TaskStatus:  common.TaskStatusSuccess,
+	})
+	if err != nil {
+		return err
+	}
+	failedTotals, err := meta.NewWaitSyncMetaModel(r.metaDB).DetailWaitSyncMeta(r.ctx, &meta.WaitSyncMeta{
+		DBTypeS:     r.cfg.DBTypeS,
+		DBTypeT:     r.cfg.DBTypeT,
+		SchemaNameS: common.StringUPPER(r.cfg.SchemaConfig.SourceSchema),
+		TaskMode:    r.cfg.TaskMode,
+		TaskStatus:  common.TaskStatusFailed,
+	})
+	if err != nil {
+		return err
+	}
+
+	zap.L().Info("compare", zap.String("fix sql file output", checkFile))
+	if len(failedTotals) == 0 {
+		zap.L().Info("compare table mysql to oracle finished",
+			zap.Int("table totals", len(exporters)),
+			zap.Int("table success", len(succTotals)),
+			zap.Int("table failed", len(failedTotals)),
+			zap.String("cost", time.Now().Sub(startTime).String()))
+	} else {
+		zap.L().Warn("compare table mysql to oracle finished",
+			zap.Int("table totals", len(exporters)),
+			zap.Int("table success", len(succTotals)),
+			zap.Int("table failed", len(failedTotals)),
+			zap.String("failed tips", "failed detail, please see table [data_compare_meta]"),
+			zap.String("cost", time.Now().Sub(startTime).String()))
+	}
+	return nil
+}
+
+// getTargetTableName 获取源端表对应目标端实际表名，表名规则优先（未配置按正向迁移 Oracle -> MySQL 表名规则反向映射），默认同名（不区分大小写）
+func (r *Compare) getTargetTableName(exporters []string) (map[string]string, error) {
+	tableNameRules, err := meta.NewTableNameRuleModel(r.metaDB).DetailTableNameRule(r.ctx, &meta.TableNameRule{
+		DBTypeS:     r.cfg.DBTypeS,
+		DBTypeT:     r.cfg.DBTypeT,
+		SchemaNameS: r.cfg.SchemaConfig.SourceSchema,
+		SchemaNameT: r.cfg.SchemaConfig.TargetSchema,
+	})
+	if err != nil {
+		return nil, err
+	}
+	reverseNameRules, err := meta.NewTableNameRuleModel(r.metaDB).DetailTableNameRule(r.ctx, &meta.TableNameRule{
+		DBTypeS:     common.DatabaseTypeOracle,
+		DBTypeT:     r.cfg.DBTypeS,
+		SchemaNameS: r.cfg.SchemaConfig.TargetSchema,
+		SchemaNameT: r.cfg.SchemaConfig.SourceSchema,
+	})
+	if err != nil {
+		return nil, err
+	}
+	tableNameRuleMap := make(map[string]string)
+	for _, tr := range reverseNameRules {
+		tableNameRuleMap[common.StringUPPER(tr.TableNameT)] = common.StringUPPER(tr.TableNameS)
+	}
+	for _, tr := range tableNameRules {
+		tableNameRuleMap[common.StringUPPER(tr.TableNameS)] = common.StringUPPER(tr.TableNameT)
+	}
+
+	var diffItems []string
+	targetTableMap := make(map[string]string)
+	for _, t := range exporters {
+		targetTableName := t
+		if val, ok := tableNameRuleMap[common.StringUPPER(t)]; ok {
+			targetTableName = val
+		}
+		columns, err := r.oracle.GetOracleTableColumnDataType(r.cfg.SchemaConfig.TargetSchema, targetTableName)
+		if err != nil {
+			return nil, err
+		}
+		if len(columns) == 0 {
+			diffItems = append(diffItems, t)
+			continue
+		}
+		targetTableMap[common.StringUPPER(t)] = columns[0]["TABLE_NAME"]
+	}
+	if len(diffItems) != 0 {
+		return nil, fmt.Errorf("table [%v] target db isn't exists, please create table", diffItems)
+	}
+	return targetTableMap, nil
+}
+
+func (r *Compare) comparePartTableTasks(f *compare.File, partTableTasks []*Task) error {
+	for _, task := range partTableTasks {
+		// 获取对比记录
+		diffStartTime := time.Now()
+
+		err := meta.NewWaitSyncMetaModel(r.metaDB).UpdateWaitSyncMeta(r.ctx, &meta.WaitSyncMeta{
+			DBTypeS:     r.cfg.DBTypeS,
+			DBTypeT:     r.cfg.DBTypeT,
+			SchemaNameS: r.cfg.SchemaConfig.SourceSchema,
+			TableNameS:  task.sourceTableName,
+			TaskMode:    r.cfg.TaskMode,
+		}, map[string]interface{}{
+			"TaskStatus": common.TaskStatusRunning,
+		})
+		if err != nil {
+			return err
+		}
+
+		// 字段映射用于目标端查询条件转换以及修复语句生成
+		columnNameS, _, columnNameT, columnTypeT, err := task.columnMapping()
+		if err != nil {
+			return err
+		}
+
+		waitCompareMetas, err := meta.NewDataCompareMetaModel(r.metaDB).DetailDataCompareMeta(r.ctx, &meta.DataCompareMeta{
+			DBTypeS:     r.cfg.DBTypeS,
+			DBTypeT:     r.cfg.DBTypeT,
+			SchemaNameS: r.cfg.SchemaConfig.SourceSchema,
+			TableNameS:  task.sourceTableName,
+			TaskMode:    r.cfg.TaskMode,
+			TaskStatus:  common.TaskStatusWaiting,
+		})
+		if err != nil {
+			return err
+		}
+		failedCompareMetas, err := meta.NewDataCompareMetaModel(r.metaDB).DetailDataCompareMeta(r.ctx, &meta.DataCompareMeta{
+			DBTypeS:     r.cfg.DBTypeS,
+			DBTypeT:     r.cfg.DBTypeT,
+			SchemaNameS: r.cfg.SchemaConfig.SourceSchema,
+			TableNameS:  task.sourceTableName,
+			TaskMode:    r.cfg.TaskMode,
+			TaskStatus:  common.TaskStatusFailed,
+		})
+		if err != nil {
+			return err
+		}
+
+		waitCompareMetas = append(waitCompareMetas, failedCompareMetas...)
+
+		// 设置工作池
+		// 设置 goroutine 数
+		g1 := &errgroup.Group{}
+		g1.SetLimit(r.cfg.DiffConfig.DiffThreads)
+
+		for _, compareMeta := range waitCompareMetas {
+			newReport := NewReport(compareMeta, r.mysql, r.oracle, columnNameS, columnNameT, columnTypeT, r.cfg.DiffConfig.OnlyCheckRows)
+			g1.Go(func() error {
+				// 数据对比报告
+				report, err := public.IReport(newReport)
+				if err != nil {
+					// error skip, continue
+					if err = meta.NewDataCompareMetaModel(r.metaDB).UpdateDataCompareMeta(r.ctx, &meta.DataCompareMeta{
+						DBTypeS:     newReport.DataCompareMeta.DBTypeS,
+						DBTypeT:     newReport.DataCompareMeta.DBTypeT,
+						SchemaNameS: newReport.DataCompareMeta.SchemaNameS,
+						TableNameS:  newReport.DataCompareMeta.TableNameS,
+						TaskMode:    newReport.DataCompareMeta.TaskMode,
+						WhereRange:  newReport.DataCompareMeta.WhereRange,
+					}, map[string]interface{}{
+						"TaskStatus":  common.TaskStatusFailed,
+						"InfoDetail":  newReport.String(),
+						"ErrorDetail": err.Error(),
+					}); err != nil {
+						return err
+					}
+
+					return nil
+				}
+
+				// 数据对比是否不一致
+				if !strings.EqualFold(report, "") {
+					var errMsg error
+					errMsg = fmt.Errorf("schema table data chunk isn't euqal")
+
+					if _, err := f.CWriteString(report); err != nil {
+						errMsg = fmt.Errorf("fix sql file write failed: %v", err.Error())
+					}
+					// error skip, continue
+					if err = meta.NewDataCompareMetaModel(r.metaDB).UpdateDataCompareMeta(r.ctx, &meta.DataCompareMeta{
+						DBTypeS:     newReport.DataCompareMeta.DBTypeS,
+						DBTypeT:     newReport.DataCompareMeta.DBTypeT,
+						SchemaNameS: newReport.DataCompareMeta.SchemaNameS,
+						TableNameS:  newReport.DataCompareMeta.TableNameS,
+						TaskMode:    newReport.DataCompareMeta.TaskMode,
+						WhereRange:  newReport.DataCompareMeta.WhereRange,
+					}, map[string]interface{}{
+						"TaskStatus":  common.TaskStatusFailed,
+						"InfoDetail":  newReport.String(),
+						"ErrorDetail": errMsg.Error(),
+					}); err != nil {
+						return err
+					}
+
+					return nil
+				}
+
+				err = meta.NewDataCompareMetaModel(r.metaDB).UpdateDataCompareMeta(r.ctx, &meta.DataCompareMeta{
+					DBTypeS:     newReport.DataCompareMeta.DBTypeS,
+					DBTypeT:     newReport.DataCompareMeta.DBTypeT,
+					SchemaNameS: newReport.DataCompareMeta.SchemaNameS,
+					TableNameS:  newReport.DataCompareMeta.TableNameS,
+					TaskMode:    newReport.DataCompareMeta.TaskMode,
+					WhereRange:  newReport.DataCompareMeta.WhereRange,
+				}, map[string]interface{}{
+					"TaskStatus": common.TaskStatusSuccess,
+				})
+				if err != nil {
+					return err
+				}
+				return nil
+			})
+		}
+
+		if err = g1.Wait(); err != nil {
+			return fmt.Errorf("compare table task failed, update table [data_compare_meta] failed: %v", err)
+		}
+
+		// 清理元数据记录
+		// 更新 wait_sync_meta 记录
+		failedTotalErrs, err := meta.NewDataCompareMetaModel(r.metaDB).CountsErrorDataCompareMeta(r.ctx, &meta.DataCompareMeta{
+			DBTypeS:     r.cfg.DBTypeS,
+			DBTypeT:     r.cfg.DBTypeT,
+			SchemaNameS: r.cfg.SchemaConfig.SourceSchema,
+			TableNameS:  task.sourceTableName,
+			TaskMode:    r.cfg.TaskMode,
+			TaskStatus:  common.TaskStatusFailed,
+		})
+		if err != nil {
+			return fmt.Errorf("get meta table [data_compare_meta] counts failed, error: %v", err)
+		}
+
+		successTotalErrs, err := meta.NewDataCompareMetaModel(r.metaDB).CountsErrorDataCompareMeta(r.ctx, &meta.DataCompareMeta{
+			DBTypeS:     r.cfg.DBTypeS,
+			DBTypeT:     r.cfg.DBTypeT,
+			SchemaNameS: r.cfg.SchemaConfig.SourceSchema,
+			TableNameS:  task.sourceTableName,
+			TaskMode:    r.cfg.TaskMode,
+			TaskStatus:  common.TaskStatusSuccess,
+		})
+		if err != nil {
+			return fmt.Errorf("get meta table [data_compare_meta] counts failed, error: %v", err)
+		}
+
+		// 不存在错误，清理 data_compare_meta 记录, 更新 wait_sync_meta 记录
+		if failedTotalErrs == 0 {
+			err = meta.NewCommonModel(r.metaDB).DeleteTableDataCompareMetaAndUpdateWaitSyncMeta(r.ctx,
+				&meta.DataCompareMeta{
+					DBTypeS:     r.cfg.DBTypeS,
+					DBTypeT:     r.cfg.DBTypeT,
+					SchemaNameS: r.cfg.SchemaConfig.SourceSchema,
+					TableNameS:  task.sourceTableName,
+					TaskMode:    r.cfg.TaskMode,
+				}, &meta.WaitSyncMeta{
+					DBTypeS:          r.cfg.DBTypeS,
+					DBTypeT:          r.cfg.DBTypeT,
+					SchemaNameS:      r.cfg.SchemaConfig.SourceSchema,
+					TableNameS:       task.sourceTableName,
+					TaskMode:         r.cfg.TaskMode,
+					TaskStatus:       common.TaskStatusSuccess,
+					ChunkSuccessNums: successTotalErrs,
+					ChunkFailedNums:  0,
+				})
+			if err != nil {
+				return err
+			}
+			zap.L().Info("diff single table mysql to oracle finished",
+				zap.String("schema", r.cfg.SchemaConfig.SourceSchema),
+				zap.String("table", task.sourceTableName),
+				zap.String("cost", time.Now().Sub(diffStartTime).String()))
+			// 继续
+			continue
+		}
+
+		// 若存在错误，修改表状态，skip 清理，统一忽略，最后显示
+		err = meta.NewWaitSyncMetaModel(r.metaDB).UpdateWaitSyncMeta(r.ctx, &meta.WaitSyncMeta{
+			DBTypeS:     r.cfg.DBTypeS,
+			DBTypeT:     r.cfg.DBTypeT,
+			SchemaNameS: r.cfg.SchemaConfig.SourceSchema,
+			TableNameS:  task.sourceTableName,
+			TaskMode:    r.cfg.TaskMode,
+		}, map[string]interface{}{
+			"TaskStatus":       common.TaskStatusFailed,
+			"ChunkSuccessNums": successTotalErrs,
+			"ChunkFailedNums":  failedTotalErrs,
+		})
+		if err != nil {
+			return err
+		}
+		zap.L().Warn("update mysql [wait_sync_meta] meta",
+			zap.String("schema", r.cfg.SchemaConfig.SourceSchema),
+			zap.String("table", task.sourceTableName),
+			zap.String("mode", r.cfg.TaskMode),
+			zap.String("updated", "table check exist error, skip"),
+			zap.String("cost", time.Now().Sub(diffStartTime).String()))
+	}
+	return nil
+}
+
+func (r *Compare) compareWaitTableTasks(f *compare.File, waitTableTasks []*Task) error {
+	// MySQL 不支持一致性读，数据对比期间需停止源端写入
+	var globalSCN uint64 = common.TaskTableDefaultSourceGlobalSCN
+
+	var chunks []*Chunk
+	for cid, task := range waitTableTasks {
+		sourceColumnInfo, targetColumnInfo, err := task.AdjustDBSelectColumn()
+		if err != nil {
+			return err
+		}
+		whereColumn, err := task.FilterDBWhereColumn()
+		if err != nil {
+			return err
+		}
+		isPartition, err := task.IsPartitionTable()
+		if err != nil {
+			return err
+		}
+		chunks = append(chunks, NewChunk(r.ctx, r.cfg, r.oracle, r.mysql, r.metaDB,
+			cid, globalSCN, task.sourceTableName, task.targetTableName, isPartition, sourceColumnInfo, targetColumnInfo,
+			whereColumn))
+	}
+
+	// chunk split
+	g := &errgroup.Group{}
+	g.SetLimit(r.cfg.DiffConfig.DiffThreads)
+	for _, chunk := range chunks {
+		c := chunk
+		g.Go(func() error {
+			err := public.IChunker(c)
+			if err != nil {
+				return err
+			}
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	err := r.comparePartTableTasks(f, waitTableTasks)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,348 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package m2o
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/scylladb/go-set/strset"
+	"github.com/wentaojin/transferdb/common"
+	"github.com/wentaojin/transferdb/database/meta"
+	"github.com/wentaojin/transferdb/database/mysql"
+	"github.com/wentaojin/transferdb/database/oracle"
+	"github.com/wentaojin/transferdb/module/compare/mysql/public"
+	migrate "github.com/wentaojin/transferdb/module/migrate/sql/mysql/public"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+	"hash/crc32"
+	"strings"
+)
+
+type DBSummary struct {
+	StringSet *strset.Set
+	RowValues map[string][]string
+	Crc32Val  uint32
+}
+
+type Report struct {
+	DataCompareMeta meta.DataCompareMeta `json:"data_compare_meta"`
+	Mysql           *mysql.MySQL         `json:"-"`
+	Oracle          *oracle.Oracle       `json:"-"`
+	ColumnNameS     []string             `json:"-"`
+	ColumnNameT     []string             `json:"-"`
+	ColumnTypeT     []string             `json:"-"`
+	OnlyCheckRows   bool                 `json:"only_check_rows"`
+}
+
+func NewReport(dataCompareMeta meta.DataCompareMeta, mysql *mysql.MySQL, oracle *oracle.Oracle, columnNameS, columnNameT, columnTypeT []string, onlyCheckRows bool) *Report {
+	return &Report{
+		DataCompareMeta: dataCompareMeta,
+		Mysql:           mysql,
+		Oracle:          oracle,
+		ColumnNameS:     columnNameS,
+		ColumnNameT:     columnNameT,
+		ColumnTypeT:     columnTypeT,
+		OnlyCheckRows:   onlyCheckRows,
+	}
+}
+
+// GenDBQuery chunk 条件以源端 MySQL 语法记录，目标端 Oracle 查询基于字段映射转换
+func (r *Report) GenDBQuery() (oracleQuery string, mysqlQuery string) {
+	whereT := r.targetWhereRange()
+
+	mysqlQuery = common.StringsBuilder(
+		"SELECT ", r.DataCompareMeta.ColumnDetailS, " FROM `", r.DataCompareMeta.SchemaNameS, "`.`", r.DataCompareMeta.TableNameS, "` WHERE ", r.DataCompareMeta.WhereRange)
+	oracleQuery = common.StringsBuilder(
+		"SELECT ", r.DataCompareMeta.ColumnDetailT, ` FROM "`, r.DataCompareMeta.SchemaNameT, `"."`, r.DataCompareMeta.TableNameT, `" WHERE `, whereT)
+
+	if r.DataCompareMeta.WhereColumn != "" {
+		mysqlQuery = common.StringsBuilder(mysqlQuery, " ORDER BY `", r.DataCompareMeta.WhereColumn, "` DESC")
+		for i, c := range r.ColumnNameS {
+			if strings.EqualFold(c, r.DataCompareMeta.WhereColumn) {
+				oracleQuery = common.StringsBuilder(oracleQuery, ` ORDER BY "`, r.ColumnNameT[i], `" DESC`)
+				break
+			}
+		}
+	}
+	return
+}
+
+func (r *Report) targetWhereRange() string {
+	whereT, ok := migrate.GenOracleChunkWhere(r.DataCompareMeta.WhereRange, r.ColumnNameS, r.ColumnNameT)
+	if !ok {
+		return r.DataCompareMeta.WhereRange
+	}
+	return whereT
+}
+
+func (r *Report) CheckOracleRows(oracleQuery string) (int64, error) {
+	rows, err := r.Oracle.GetOracleTableActualRows(oracleQuery)
+	if err != nil {
+		return rows, err
+	}
+	return rows, nil
+}
+
+func (r *Report) CheckMySQLRows(mysqlQuery string) (int64, error) {
+	rows, err := r.Mysql.GetMySQLTableActualRows(mysqlQuery)
+	if err != nil {
+		return rows, err
+	}
+	return rows, nil
+}
+
+func (r *Report) ReportCheckRows() (string, error) {
+	oracleQuery, mysqlQuery := r.GenDBQuery()
+	g1 := &errgroup.Group{}
+	g2 := &errgroup.Group{}
+	oracleRowsChan := make(chan int64, 1)
+	mysqlRowsChan := make(chan int64, 1)
+
+	g1.Go(func() error {
+		rows, err := r.CheckMySQLRows(mysqlQuery)
+		if err != nil {
+			return err
+		}
+		mysqlRowsChan <- rows
+		return nil
+	})
+
+	g2.Go(func() error {
+		rows, err := r.CheckOracleRows(oracleQuery)
+		if err != nil {
+			return err
+		}
+		oracleRowsChan <- rows
+		return nil
+	})
+
+	if err := g1.Wait(); err != nil {
+		return "", err
+	}
+	if err := g2.Wait(); err != nil {
+		return "", err
+	}
+
+	mysqlRows := <-mysqlRowsChan
+	oracleRows := <-oracleRowsChan
+
+	if mysqlRows == oracleRows {
+		zap.L().Info("mysql table chunk diff equal",
+			zap.String("mysql schema", r.DataCompareMeta.SchemaNameS),
+			zap.String("oracle schema", r.DataCompareMeta.SchemaNameT),
+			zap.String("mysql table", r.DataCompareMeta.TableNameS),
+			zap.String("oracle table", r.DataCompareMeta.TableNameT),
+			zap.Int64("mysql rows count", mysqlRows),
+			zap.Int64("oracle rows count", oracleRows),
+			zap.String("mysql sql", mysqlQuery),
+			zap.String("oracle sql", oracleQuery))
+		return "", nil
+	}
+
+	zap.L().Info("mysql table chunk diff isn't equal",
+		zap.String("mysql schema", r.DataCompareMeta.SchemaNameS),
+		zap.String("oracle schema", r.DataCompareMeta.SchemaNameT),
+		zap.String("mysql table", r.DataCompareMeta.TableNameS),
+		zap.String("oracle table", r.DataCompareMeta.TableNameT),
+		zap.Int64("mysql rows count", mysqlRows),
+		zap.Int64("oracle rows count", oracleRows),
+		zap.String("mysql sql", mysqlQuery),
+		zap.String("oracle sql", oracleQuery))
+
+	sw := table.NewWriter()
+	sw.SetStyle(table.StyleLight)
+	sw.AppendHeader(table.Row{"SOURCE TABLE", "SOURCE SQL", "SOURCE COUNTS", "TARGET TABLE", "TARGET SQL", "TARGET TABLE COUNTS", "RANGE"})
+	sw.AppendRows([]table.Row{
+		{
+			common.StringsBuilder(r.DataCompareMeta.SchemaNameS, ".", r.DataCompareMeta.TableNameS),
+			mysqlQuery,
+			mysqlRows,
+			common.StringsBuilder(r.DataCompareMeta.SchemaNameT, ".", r.DataCompareMeta.TableNameT),
+			oracleQuery,
+			oracleRows,
+			r.DataCompareMeta.WhereRange,
+		},
+	})
+
+	fixSQLStr := fmt.Sprintf("/* \n\tmysql and oracle table range [%s] data rows aren't equal\n", r.DataCompareMeta.WhereRange) + sw.Render() + "\n*/\n"
+
+	return fixSQLStr, nil
+}
+
+func (r *Report) ReportCheckCRC32() (string, error) {
+	errMySQL := &errgroup.Group{}
+	errORA := &errgroup.Group{}
+	mysqlChan := make(chan DBSummary, 1)
+	oraChan := make(chan DBSummary, 1)
+
+	oracleQuery, mysqlQuery := r.GenDBQuery()
+
+	errMySQL.Go(func() error {
+		_, mysqlRows, err := r.Mysql.GetMySQLDataRowValues(mysqlQuery)
+		if err != nil {
+			return fmt.Errorf("get mysql data row values failed: %v", err)
+		}
+		summary, err := r.genDBSummary(mysqlRows)
+		if err != nil {
+			return err
+		}
+		mysqlChan <- summary
+		return nil
+	})
+
+	errORA.Go(func() error {
+		_, oraRows, err := r.Oracle.GetOracleDataRowValues(oracleQuery)
+		if err != nil {
+			return fmt.Errorf("get oracle data row values failed: %v", err)
+		}
+		summary, err := r.genDBSummary(oraRows)
+		if err != nil {
+			return err
+		}
+		oraChan <- summary
+		return nil
+	})
+
+	if err := errMySQL.Wait(); err != nil {
+		return "", err
+	}
+	if err := errORA.Wait(); err != nil {
+		return "", err
+	}
+
+	mysqlReport := <-mysqlChan
+	oraReport := <-oraChan
+
+	// 数据相同
+	if mysqlReport.Crc32Val == oraReport.Crc32Val {
+		zap.L().Info("mysql table chunk diff equal",
+			zap.String("mysql schema", r.DataCompareMeta.SchemaNameS),
+			zap.String("oracle schema", r.DataCompareMeta.SchemaNameT),
+			zap.String("mysql table", r.DataCompareMeta.TableNameS),
+			zap.String("oracle table", r.DataCompareMeta.TableNameT),
+			zap.Uint32("mysql crc32 values", mysqlReport.Crc32Val),
+			zap.Uint32("oracle crc32 values", oraReport.Crc32Val),
+			zap.String("mysql sql", mysqlQuery),
+			zap.String("oracle sql", oracleQuery))
+		return "", nil
+	}
+
+	zap.L().Info("mysql table chunk diff isn't equal",
+		zap.String("mysql schema", r.DataCompareMeta.SchemaNameS),
+		zap.String("oracle schema", r.DataCompareMeta.SchemaNameT),
+		zap.String("mysql table", r.DataCompareMeta.TableNameS),
+		zap.String("oracle table", r.DataCompareMeta.TableNameT),
+		zap.Uint32("mysql crc32 values", mysqlReport.Crc32Val),
+		zap.Uint32("oracle crc32 values", oraReport.Crc32Val),
+		zap.String("mysql sql", mysqlQuery),
+		zap.String("oracle sql", oracleQuery))
+
+	//上游存在，下游存在 Skip
+	//上游不存在，下游不存在 Skip
+	//上游存在，下游不存在 INSERT 下游
+	//上游不存在，下游存在 DELETE 下游
+	// 修复语句 Oracle 语法，时间字段基于 TO_DATE/TO_TIMESTAMP 固定格式转换
+
+	var fixSQL strings.Builder
+
+	sw := table.NewWriter()
+	sw.SetStyle(table.StyleLight)
+	sw.AppendHeader(table.Row{"DATABASE", "DATA COUNTS SQL", "CRC32"})
+	sw.AppendRows([]table.Row{
+		{"MySQL", common.StringsBuilder(
+			"SELECT COUNT(1) FROM `", r.DataCompareMeta.SchemaNameS, "`.`", r.DataCompareMeta.TableNameS, "` WHERE ", r.DataCompareMeta.WhereRange),
+			mysqlReport.Crc32Val},
+		{"ORACLE", common.StringsBuilder(
+			`SELECT COUNT(1) FROM "`, r.DataCompareMeta.SchemaNameT, `"."`, r.DataCompareMeta.TableNameT, `" WHERE `, r.targetWhereRange()),
+			oraReport.Crc32Val},
+	})
+	countSQL := sw.Render()
+
+	// 判断下游数据是否多
+	targetMore := strset.Difference(oraReport.StringSet, mysqlReport.StringSet).List()
+	if len(targetMore) > 0 {
+		fixSQL.WriteString("/*\n")
+		fixSQL.WriteString(fmt.Sprintf(" oracle table [%s.%s] chunk [%s] data rows are more \n", r.DataCompareMeta.SchemaNameT, r.DataCompareMeta.TableNameT, r.DataCompareMeta.WhereRange))
+		fixSQL.WriteString(fmt.Sprintf("%v\n", countSQL))
+		fixSQL.WriteString("*/\n")
+		deletePrefix := common.StringsBuilder(`DELETE FROM "`, r.DataCompareMeta.SchemaNameT, `"."`, r.DataCompareMeta.TableNameT, `" WHERE `)
+		for _, t := range targetMore {
+			fixSQL.WriteString(public.GenOracleFixSQL(r.ColumnTypeT, oraReport.RowValues[t], func(literals []string) string {
+				var whereCond []string
+				for i, v := range literals {
+					whereCond = append(whereCond, public.GenOracleColumnCondition(r.ColumnNameT[i], r.ColumnTypeT[i], v))
+				}
+				return common.StringsBuilder(deletePrefix, strings.Join(whereCond, " AND "))
+			}))
+		}
+	}
+
+	// 判断上游数据是否多
+	sourceMore := strset.Difference(mysqlReport.StringSet, oraReport.StringSet).List()
+	if len(sourceMore) > 0 {
+		fixSQL.WriteString("/*\n")
+		fixSQL.WriteString(fmt.Sprintf(" oracle table [%s.%s] chunk [%s] data rows are less \n", r.DataCompareMeta.SchemaNameT, r.DataCompareMeta.TableNameT, r.DataCompareMeta.WhereRange))
+		fixSQL.WriteString(fmt.Sprintf("%v\n", countSQL))
+		fixSQL.WriteString("*/\n")
+
+		var columnNames []string
+		for _, c := range r.ColumnNameT {
+			columnNames = append(columnNames, common.StringsBuilder(`"`, c, `"`))
+		}
+		insertPrefix := common.StringsBuilder(`INSERT INTO "`, r.DataCompareMeta.SchemaNameT, `"."`, r.DataCompareMeta.TableNameT, `" (`, strings.Join(columnNames, ","), ") VALUES (")
+		for _, s := range sourceMore {
+			fixSQL.WriteString(public.GenOracleFixSQL(r.ColumnTypeT, mysqlReport.RowValues[s], func(literals []string) string {
+				return common.StringsBuilder(insertPrefix, strings.Join(literals, ","), ")")
+			}))
+		}
+	}
+	return fixSQL.String(), nil
+}
+
+// genDBSummary 数据行字段值转换目标端 Oracle 字面量，字面量数据行用于比对以及生成修复语句
+func (r *Report) genDBSummary(rows [][]string) (DBSummary, error) {
+	var crc32Val uint32 = 0
+	stringSet := strset.New()
+	rowValues := make(map[string][]string)
+	for _, row := range rows {
+		if len(row) != len(r.ColumnTypeT) {
+			return DBSummary{}, fmt.Errorf("mysql schema [%s] table [%s] column counts [%d] isn't match values counts [%d]", r.DataCompareMeta.SchemaNameS, r.DataCompareMeta.TableNameS, len(r.ColumnTypeT), len(row))
+		}
+		literals := public.GenOracleRowLiterals(r.ColumnTypeT, row)
+		rowS := strings.Join(literals, ",")
+		crc32Val += crc32.ChecksumIEEE([]byte(rowS))
+		stringSet.Add(rowS)
+		rowValues[rowS] = literals
+	}
+	return DBSummary{
+		StringSet: stringSet,
+		RowValues: rowValues,
+		Crc32Val:  crc32Val,
+	}, nil
+}
+
+func (r *Report) Report() (string, error) {
+	if r.OnlyCheckRows {
+		return r.ReportCheckRows()
+	}
+	return r.ReportCheckCRC32()
+}
+
+func (r *Report) String() string {
+	jsonStr, _ := json.Marshal(r)
+	return string(jsonStr)
+}
@@ -0,0 +1,185 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package m2o
+
+import (
+	"context"
+	"fmt"
+	"github.com/wentaojin/transferdb/common"
+	"github.com/wentaojin/transferdb/config"
+	"github.com/wentaojin/transferdb/database/meta"
+	"github.com/wentaojin/transferdb/database/mysql"
+	"github.com/wentaojin/transferdb/database/oracle"
+	"github.com/wentaojin/transferdb/mapping"
+	"github.com/wentaojin/transferdb/module/check"
+	"github.com/wentaojin/transferdb/module/check/mysql/m2o"
+	"github.com/wentaojin/transferdb/module/compare/mysql/public"
+	migrate "github.com/wentaojin/transferdb/module/migrate/sql/mysql/public"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+type Task struct {
+	ctx             context.Context
+	cfg             *config.Config
+	sourceTableName string
+	targetTableName string
+	mysql           *mysql.MySQL
+	oracle          *oracle.Oracle
+	columnMapper    *mapping.TableMapper
+}
+
+func NewPartCompareTableTask(ctx context.Context, cfg *config.Config, compareTables []string, mysql *mysql.MySQL, oracle *oracle.Oracle, tableNameRule map[string]string, mapper *mapping.Mapper) []*Task {
+	var tasks []*Task
+	for _, table := range compareTables {
+		// 库名、表名规则，以目标端实际表名为准
+		var targetTableName string
+		if val, ok := tableNameRule[common.StringUPPER(table)]; ok {
+			targetTableName = val
+		} else {
+			targetTableName = common.StringUPPER(table)
+		}
+		tasks = append(tasks, &Task{
+			ctx:             ctx,
+			cfg:             cfg,
+			sourceTableName: table,
+			targetTableName: targetTableName,
+			mysql:           mysql,
+			oracle:          oracle,
+			columnMapper:    mapper.Table(table),
+		})
+	}
+	return tasks
+}
+
+func NewWaitCompareTableTask(ctx context.Context, cfg *config.Config, compareTables []string, mysql *mysql.MySQL, oracle *oracle.Oracle,
+	tableNameRule map[string]string, mapper *mapping.Mapper) []*Task {
+	return NewPartCompareTableTask(ctx, cfg, compareTables, mysql, oracle, tableNameRule, mapper)
+}
+
+func PreTableStructCheck(ctx context.Context, cfg *config.Config, metaDB *meta.Meta, exporters []string) error {
+	// 表结构检查
+	if !cfg.DiffConfig.IgnoreStructCheck {
+		startTime := time.Now()
+		cfg.SchemaConfig.SourceIncludeTable = exporters
+
+		var (
+			r   check.Reporter
+			err error
+		)
+		r, err = m2o.NewCheck(ctx, cfg)
+		if err != nil {
+			return err
+		}
+		err = r.Check()
+		if err != nil {
+			return err
+		}
+		errTotals, err := meta.NewErrorLogDetailModel(metaDB).CountsErrorLogBySchema(ctx, &meta.ErrorLogDetail{
+			DBTypeS:     cfg.DBTypeS,
+			DBTypeT:     cfg.DBTypeT,
+			SchemaNameS: common.StringUPPER(cfg.SchemaConfig.SourceSchema),
+			TaskMode:    cfg.TaskMode,
+		})
+
+		if errTotals != 0 || err != nil {
+			return fmt.Errorf("compare schema [%s] mode [%s] table structure task failed: %v, please check log, error: %v", strings.ToUpper(cfg.SchemaConfig.SourceSchema), cfg.TaskMode, errTotals, err)
+		}
+		endTime := time.Now()
+		zap.L().Info("pre check schema mysql to oracle finished",
+			zap.String("table structure check", "equal"),
+			zap.String("schema", strings.ToUpper(cfg.SchemaConfig.SourceSchema)),
+			zap.String("cost", endTime.Sub(startTime).String()))
+	}
+
+	return nil
+}
+
+// columnMapping 源端字段按字段名（不区分大小写）匹配目标端字段，字段映射规则排除字段不校验，重命名字段按目标端字段名匹配
+func (t *Task) columnMapping() ([]string, []string, []string, []string, error) {
+	var columnNameS, columnTypeS []string
+	sourceColumns, err := t.mysql.GetMySQLTableColumn(t.cfg.SchemaConfig.SourceSchema, t.sourceTableName)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	targetColumns, err := t.oracle.GetOracleTableColumnDataType(t.cfg.SchemaConfig.TargetSchema, t.targetTableName)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	sourceColumns = t.columnMapper.FilterColumnINFO(sourceColumns)
+	columnNameT, columnTypeT, err := migrate.GetOracleTableColumnMapping(sourceColumns, targetColumns, t.columnMapper)
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("mysql schema [%s] table [%s] column mapping failed: %v", t.cfg.SchemaConfig.SourceSchema, t.sourceTableName, err)
+	}
+	for _, c := range sourceColumns {
+		columnNameS = append(columnNameS, c["COLUMN_NAME"])
+		columnTypeS = append(columnTypeS, c["DATA_TYPE"])
+	}
+	return columnNameS, columnTypeS, columnNameT, columnTypeT, nil
+}
+
+// 字段查询以 MySQL 字段为主，按目标端 Oracle 字段数据类型统一格式化
+func (t *Task) AdjustDBSelectColumn() (sourceColumnInfo string, targetColumnInfo string, err error) {
+	columnNameS, columnTypeS, columnNameT, columnTypeT, err := t.columnMapping()
+	if err != nil {
+		return sourceColumnInfo, targetColumnInfo, err
+	}
+	sourceColumnInfo, targetColumnInfo = public.GenCompareSelectColumn(columnNameS, columnTypeS, columnNameT, columnTypeT, t.columnMapper)
+	return sourceColumnInfo, targetColumnInfo, nil
+}
+
+// 筛选主键/唯一索引单列整型字段，用于 chunk 范围切分
+// 如果表不存在主键/唯一键直接报错，因为可能导致数据校验不准
+// 如果表不存在单列整型主键/唯一索引字段，返回空，全表对比
+func (t *Task) FilterDBWhereColumn() (string, error) {
+	pkInfo, err := t.mysql.GetMySQLTablePrimaryKey(t.cfg.SchemaConfig.SourceSchema, t.sourceTableName)
+	if err != nil {
+		return "", err
+	}
+	ukInfo, err := t.mysql.GetMySQLTableUniqueKey(t.cfg.SchemaConfig.SourceSchema, t.sourceTableName)
+	if err != nil {
+		return "", err
+	}
+	if len(pkInfo) == 0 && len(ukInfo) == 0 {
+		return "", fmt.Errorf("mysql schema [%s] table [%s] pk/uk isn't exist, it's not support, please skip", t.cfg.SchemaConfig.SourceSchema, t.sourceTableName)
+	}
+
+	whereColumn, err := t.mysql.GetMySQLTableChunkColumn(t.cfg.SchemaConfig.SourceSchema, t.sourceTableName, "")
+	if err != nil {
+		return "", err
+	}
+	// 字段映射规则排除字段、EXPR 字段目标端数据与源端范围条件不一致，无法用于 chunk 切分，全表对比
+	if _, ok := t.columnMapper.ColumnExpr(whereColumn); ok || t.columnMapper.IsExclude(whereColumn) {
+		zap.L().Warn("mysql table chunk column is column mapping rule exclude or expr column, compare full table",
+			zap.String("schema", t.cfg.SchemaConfig.SourceSchema),
+			zap.String("table", t.sourceTableName),
+			zap.String("chunk column", whereColumn))
+		return "", nil
+	}
+	return whereColumn, nil
+}
+
+func (t *Task) IsPartitionTable() (string, error) {
+	isOK, err := t.mysql.IsMySQLPartitionTable(t.cfg.SchemaConfig.SourceSchema, t.sourceTableName)
+	if err != nil {
+		return "", err
+	}
+	if isOK {
+		return "YES", nil
+	}
+	return "NO", nil
+}
@@ -0,0 +1,254 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package public
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/wentaojin/transferdb/common"
+	"github.com/wentaojin/transferdb/mapping"
+	migrate "github.com/wentaojin/transferdb/module/migrate/sql/mysql/public"
+	"strings"
+)
+
+// GenCompareSelectColumn 源端 MySQL、目标端 Oracle 字段查询统一字符串格式化，格式化规则以目标端字段数据类型为准（与全量迁移写入规则一致）
+// 1、数值 -> 去除小数尾部 0，补充 Oracle 小数前导 0
+// 2、DATE -> yyyy-MM-dd HH24:mi:ss，TIMESTAMP -> yyyy-MM-dd HH24:mi:ss.ff6，MySQL 零值日期即 NULL，TIME 补充固定日期部分
+// 3、INTERVAL DAY TO SECOND -> 秒数
+// 4、CHAR -> 去除尾部空格（MySQL CHAR 查询自动去除尾部空格）
+// 5、二进制以及其他 -> 原值
+// 6、字段映射规则 EXPR 字段 -> 源端按表达式查询
+func GenCompareSelectColumn(columnNameS, columnTypeS, columnNameT, columnTypeT []string, columnMapper *mapping.TableMapper) (string, string) {
+	var sourceColumns, targetColumns []string
+	for i, c := range columnNameS {
+		columnS := common.StringsBuilder("`", c, "`")
+		if expr, ok := columnMapper.ColumnExpr(c); ok {
+			columnS = common.StringsBuilder("(", expr, ")")
+		}
+		columnT := common.StringsBuilder(`"`, columnNameT[i], `"`)
+		typeS := common.StringUPPER(columnTypeS[i])
+		typeT := common.StringUPPER(columnTypeT[i])
+
+		switch {
+		case migrate.IsOracleNumberType(typeT):
+			switch typeS {
+			case "TINYINT", "SMALLINT", "MEDIUMINT", "INT", "INTEGER", "BIGINT", "YEAR":
+				sourceColumns = append(sourceColumns, common.StringsBuilder("CAST(", columnS, " AS CHAR)"))
+			case "BIT":
+				sourceColumns = append(sourceColumns, common.StringsBuilder("CAST(", columnS, " + 0 AS CHAR)"))
+			case "DECIMAL", "NUMERIC":
+				sourceColumns = append(sourceColumns, common.StringsBuilder("IF(INSTR(CAST(", columnS, " AS CHAR),'.') > 0,TRIM(TRAILING '.' FROM TRIM(TRAILING '0' FROM CAST(", columnS, " AS CHAR))),CAST(", columnS, " AS CHAR))"))
+			default:
+				sourceColumns = append(sourceColumns, common.StringsBuilder("CAST(0 + CAST(", columnS, " AS CHAR) AS CHAR)"))
+			}
+			numberT := columnT
+			if strings.HasPrefix(typeT, "BINARY_") {
+				numberT = common.StringsBuilder("CAST(", columnT, " AS NUMBER)")
+			}
+			targetColumns = append(targetColumns, common.StringsBuilder(`REGEXP_REPLACE(TO_CHAR(`, numberT, `),'^(-?)\.','\10.')`))
+		case typeT == "DATE":
+			if typeS == "TIME" {
+				sourceColumns = append(sourceColumns, common.StringsBuilder("CONCAT('", migrate.OracleTimeColumnDatePrefix, "',TIME_FORMAT(", columnS, ",'%H:%i:%s'))"))
+			} else {
+				sourceColumns = append(sourceColumns, common.StringsBuilder("NULLIF(DATE_FORMAT(", columnS, ",'%Y-%m-%d %H:%i:%s'),'0000-00-00 00:00:00')"))
+			}
+			targetColumns = append(targetColumns, common.StringsBuilder("TO_CHAR(", columnT, ",'YYYY-MM-DD HH24:MI:SS')"))
+		case strings.HasPrefix(typeT, "TIMESTAMP"):
+			if typeS == "TIME" {
+				sourceColumns = append(sourceColumns, common.StringsBuilder("CONCAT('", migrate.OracleTimeColumnDatePrefix, "',TIME_FORMAT(", columnS, ",'%H:%i:%s.%f'))"))
+			} else {
+				sourceColumns = append(sourceColumns, common.StringsBuilder("NULLIF(DATE_FORMAT(", columnS, ",'%Y-%m-%d %H:%i:%s.%f'),'0000-00-00 00:00:00.000000')"))
+			}
+			targetColumns = append(targetColumns, common.StringsBuilder("TO_CHAR(", columnT, ",'YYYY-MM-DD HH24:MI:SS.FF6')"))
+		case strings.HasPrefix(typeT, "INTERVAL DAY"):
+			sourceColumns = append(sourceColumns, common.StringsBuilder("CAST(TIME_TO_SEC(", columnS, ") AS CHAR)"))
+			targetColumns = append(targetColumns, common.StringsBuilder("TO_CHAR(EXTRACT(DAY FROM ", columnT, ") * 86400 + EXTRACT(HOUR FROM ", columnT,
+				") * 3600 + EXTRACT(MINUTE FROM ", columnT, ") * 60 + TRUNC(EXTRACT(SECOND FROM ", columnT, ")))"))
+		case typeT == "CHAR" || typeT == "NCHAR":
+			sourceColumns = append(sourceColumns, columnS)
+			targetColumns = append(targetColumns, common.StringsBuilder("RTRIM(", columnT, ")"))
+		default:
+			switch typeS {
+			case "GEOMETRY", "POINT", "LINESTRING", "POLYGON", "MULTIPOINT", "MULTILINESTRING", "MULTIPOLYGON", "GEOMETRYCOLLECTION":
+				sourceColumns = append(sourceColumns, common.StringsBuilder("ST_AsText(", columnS, ")"))
+			default:
+				sourceColumns = append(sourceColumns, columnS)
+			}
+			targetColumns = append(targetColumns, columnT)
+		}
+	}
+	return strings.Join(sourceColumns, ","), strings.Join(targetColumns, ",")
+}
+
+// GenOracleRowLiterals 数据行字段值（GenCompareSelectColumn 格式化）转换目标端 Oracle 字面量，用于数据行比对以及修复语句
+func GenOracleRowLiterals(columnTypeT []string, row []string) []string {
+	literals := make([]string, len(row))
+	for i, v := range row {
+		literals[i] = GenOracleColumnLiteral(columnTypeT[i], v)
+	}
+	return literals
+}
+
+// Oracle SQL 字符串字面量最大 4000 字节（ORA-01704），十六进制字面量即 2000 字节二进制数据
+const (
+	oracleLiteralMaxBytes = 4000
+	// UTL_RAW.CONCAT 最多 12 个参数
+	oracleRawConcatMaxArgs = 12
+)
+
+// GenOracleColumnLiteral 字段值转换目标端 Oracle 字面量，时间字段基于固定格式转换，不受 NLS 参数影响
+// 字符、CLOB 以及 RAW 超过字面量上限按 4000 字节分片拼接，BLOB 超过上限由 GenOracleFixSQL 基于 PL/SQL 匿名块分片拼接
+func GenOracleColumnLiteral(columnTypeT, value string) string {
+	if strings.EqualFold(value, "") {
+		return `NULL`
+	}
+	typeT := common.StringUPPER(columnTypeT)
+	switch {
+	case migrate.IsOracleNumberType(typeT):
+		return value
+	case typeT == "DATE":
+		return common.StringsBuilder(`TO_DATE('`, value, `','YYYY-MM-DD HH24:MI:SS')`)
+	case strings.HasPrefix(typeT, "TIMESTAMP"):
+		return common.StringsBuilder(`TO_TIMESTAMP('`, value, `','YYYY-MM-DD HH24:MI:SS.FF')`)
+	case strings.HasPrefix(typeT, "INTERVAL DAY"):
+		return common.StringsBuilder(`NUMTODSINTERVAL(`, value, `,'SECOND')`)
+	case typeT == "RAW" || typeT == "LONG RAW":
+		return genOracleRawLiteral(splitOracleHexLiteral(value))
+	case typeT == "BLOB":
+		return common.StringsBuilder(`TO_BLOB(HEXTORAW('`, strings.ToUpper(hex.EncodeToString([]byte(value))), `'))`)
+	case typeT == "CLOB" || typeT == "NCLOB":
+		var pieces []string
+		for _, p := range splitOracleStringLiteral(value) {
+			pieces = append(pieces, common.StringsBuilder(`TO_CLOB('`, p, `')`))
+		}
+		return strings.Join(pieces, `||`)
+	default:
+		var pieces []string
+		for _, p := range splitOracleStringLiteral(value) {
+			pieces = append(pieces, common.StringsBuilder(`'`, p, `'`))
+		}
+		return strings.Join(pieces, `||`)
+	}
+}
+
+// GenOracleFixSQL 生成单行修复语句，genSQL 基于字段字面量生成 INSERT/DELETE 语句
+// BLOB 字段十六进制字面量超过 4000 字节时，SQL 无法拼接 BLOB，基于 PL/SQL 匿名块 DBMS_LOB.WRITEAPPEND 分片写入临时 BLOB 变量，语句中以变量替换字面量
+func GenOracleFixSQL(columnTypeT, literals []string, genSQL func(literals []string) string) string {
+	var (
+		declares, appends, frees []string
+		values                   []string
+	)
+	values = append(values, literals...)
+	for i, l := range literals {
+		if !strings.EqualFold(columnTypeT[i], "BLOB") {
+			continue
+		}
+		hexValue := strings.TrimSuffix(strings.TrimPrefix(l, `TO_BLOB(HEXTORAW('`), `'))`)
+		if len(hexValue) <= oracleLiteralMaxBytes {
+			continue
+		}
+		lobVar := fmt.Sprintf("v_lob_%d", i+1)
+		declares = append(declares, common.StringsBuilder(`  `, lobVar, ` BLOB;`))
+		appends = append(appends, common.StringsBuilder(`  DBMS_LOB.CREATETEMPORARY(`, lobVar, `, TRUE);`))
+		for _, p := range splitOracleHex(hexValue) {
+			appends = append(appends, fmt.Sprintf("  DBMS_LOB.WRITEAPPEND(%s, %d, HEXTORAW('%s'));", lobVar, len(p)/2, p))
+		}
+		frees = append(frees, common.StringsBuilder(`  DBMS_LOB.FREETEMPORARY(`, lobVar, `);`))
+		values[i] = lobVar
+	}
+
+	if len(declares) == 0 {
+		return common.StringsBuilder(genSQL(values), ";\n")
+	}
+	return common.StringsBuilder("DECLARE\n", strings.Join(declares, "\n"), "\nBEGIN\n",
+		strings.Join(appends, "\n"), "\n  ", genSQL(values), ";\n",
+		strings.Join(frees, "\n"), "\nEND;\n/\n")
+}
+
+// splitOracleStringLiteral 字符串转义单引号后按字符边界切分，单个分片不超过 Oracle 字面量上限
+func splitOracleStringLiteral(value string) []string {
+	var (
+		pieces []string
+		b      strings.Builder
+	)
+	for _, r := range value {
+		escaped := string(r)
+		if r == '\'' {
+			escaped = `''`
+		}
+		if b.Len()+len(escaped) > oracleLiteralMaxBytes {
+			pieces = append(pieces, b.String())
+			b.Reset()
+		}
+		b.WriteString(escaped)
+	}
+	if b.Len() > 0 {
+		pieces = append(pieces, b.String())
+	}
+	return pieces
+}
+
+// splitOracleHexLiteral 二进制值转换十六进制并按 Oracle 字面量上限切分
+func splitOracleHexLiteral(value string) []string {
+	return splitOracleHex(strings.ToUpper(hex.EncodeToString([]byte(value))))
+}
+
+func splitOracleHex(hexValue string) []string {
+	var pieces []string
+	for len(hexValue) > oracleLiteralMaxBytes {
+		pieces = append(pieces, hexValue[:oracleLiteralMaxBytes])
+		hexValue = hexValue[oracleLiteralMaxBytes:]
+	}
+	return append(pieces, hexValue)
+}
+
+// genOracleRawLiteral 多个分片基于 UTL_RAW.CONCAT 拼接，超过参数上限嵌套拼接
+func genOracleRawLiteral(pieces []string) string {
+	var exprs []string
+	for _, p := range pieces {
+		exprs = append(exprs, common.StringsBuilder(`HEXTORAW('`, p, `')`))
+	}
+	for len(exprs) > 1 {
+		var concats []string
+		for i := 0; i < len(exprs); i += oracleRawConcatMaxArgs {
+			end := i + oracleRawConcatMaxArgs
+			if end > len(exprs) {
+				end = len(exprs)
+			}
+			if end-i == 1 {
+				concats = append(concats, exprs[i])
+				continue
+			}
+			concats = append(concats, common.StringsBuilder(`UTL_RAW.CONCAT(`, strings.Join(exprs[i:end], `,`), `)`))
+		}
+		exprs = concats
+	}
+	return exprs[0]
+}
+
+// GenOracleColumnCondition 目标端 Oracle 字段等值条件，LOB 字段基于 DBMS_LOB.COMPARE 比较
+func GenOracleColumnCondition(columnNameT, columnTypeT, literal string) string {
+	columnT := common.StringsBuilder(`"`, columnNameT, `"`)
+	typeT := common.StringUPPER(columnTypeT)
+	switch {
+	case strings.EqualFold(literal, `NULL`):
+		return common.StringsBuilder(columnT, ` IS NULL`)
+	case typeT == "BLOB" || typeT == "CLOB" || typeT == "NCLOB":
+		return common.StringsBuilder(`DBMS_LOB.COMPARE(`, columnT, `,`, literal, `) = 0`)
+	default:
+		return common.StringsBuilder(columnT, ` = `, literal)
+	}
+}
@@ -0,0 +1,139 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package public
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// 单引号字面量，两个连续单引号转义视为同一字面量
+var oracleQuotedLiteral = regexp.MustCompile(`'(?:[^']|'')*'`)
+
+func TestGenOracleColumnLiteral(t *testing.T) {
+	cases := []struct {
+		name       string
+		columnType string
+		value      string
+		expected   string
+		// 期望分片数，0 代表仅校验 expected
+		pieces int
+	}{
+		{name: "null", columnType: "VARCHAR2", value: "", expected: "NULL"},
+		{name: "varchar2 quote", columnType: "VARCHAR2", value: "it's", expected: "'it''s'"},
+		{name: "clob short", columnType: "CLOB", value: "abc", expected: "TO_CLOB('abc')"},
+		{name: "raw short", columnType: "RAW", value: "\x01\xab", expected: "HEXTORAW('01AB')"},
+		{name: "blob short", columnType: "BLOB", value: "\x01\xab", expected: "TO_BLOB(HEXTORAW('01AB'))"},
+		{name: "clob long", columnType: "CLOB", value: strings.Repeat("a", 9000), pieces: 3},
+		{name: "clob quote on boundary", columnType: "CLOB", value: strings.Repeat("a", 3999) + "'" + strings.Repeat("b", 10), pieces: 2},
+		{name: "clob multibyte", columnType: "NCLOB", value: strings.Repeat("中", 2000), pieces: 2},
+		{name: "varchar2 extended", columnType: "VARCHAR2", value: strings.Repeat("a", 4001), pieces: 2},
+		{name: "raw long", columnType: "RAW", value: strings.Repeat("\xff", 2001), pieces: 2},
+		{name: "long raw nested concat", columnType: "LONG RAW", value: strings.Repeat("\xff", 2000*13), pieces: 13},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := GenOracleColumnLiteral(c.columnType, c.value)
+			if c.pieces == 0 {
+				if got != c.expected {
+					t.Fatalf("literal %s, want %s", got, c.expected)
+				}
+				return
+			}
+			literals := oracleQuotedLiteral.FindAllString(got, -1)
+			if len(literals) != c.pieces {
+				t.Fatalf("literal pieces %d, want %d", len(literals), c.pieces)
+			}
+			var value strings.Builder
+			for _, l := range literals {
+				// 引号内字面量不超过 Oracle 4000 字节上限
+				if len(l)-2 > oracleLiteralMaxBytes {
+					t.Fatalf("literal piece length %d exceeds %d", len(l)-2, oracleLiteralMaxBytes)
+				}
+				value.WriteString(strings.ReplaceAll(l[1:len(l)-1], "''", "'"))
+			}
+			if strings.Contains(c.columnType, "RAW") {
+				if value.Len() != len(c.value)*2 {
+					t.Fatalf("raw hex length %d, want %d", value.Len(), len(c.value)*2)
+				}
+				if strings.Count(got, "UTL_RAW.CONCAT(") == 0 {
+					t.Fatalf("raw literal %s without UTL_RAW.CONCAT", got[:64])
+				}
+				return
+			}
+			if value.String() != c.value {
+				t.Fatalf("literal pieces value isn't equal to origin value")
+			}
+		})
+	}
+}
+
+func TestGenOracleFixSQL(t *testing.T) {
+	columnTypes := []string{"NUMBER", "BLOB"}
+	insert := func(literals []string) string {
+		return `INSERT INTO "T" ("ID","B") VALUES (` + strings.Join(literals, ",") + `)`
+	}
+
+	cases := []struct {
+		name     string
+		value    string
+		contains []string
+		absent   []string
+	}{
+		{
+			name:     "short blob sql",
+			value:    "\x01",
+			contains: []string{`INSERT INTO "T" ("ID","B") VALUES (1,TO_BLOB(HEXTORAW('01')));`},
+			absent:   []string{"DECLARE"},
+		},
+		{
+			name:  "long blob plsql",
+			value: strings.Repeat("\xff", 4500),
+			contains: []string{
+				"DECLARE\n  v_lob_2 BLOB;\nBEGIN\n",
+				"DBMS_LOB.CREATETEMPORARY(v_lob_2, TRUE);",
+				"DBMS_LOB.WRITEAPPEND(v_lob_2, 2000, HEXTORAW('",
+				"DBMS_LOB.WRITEAPPEND(v_lob_2, 500, HEXTORAW('",
+				`INSERT INTO "T" ("ID","B") VALUES (1,v_lob_2);`,
+				"DBMS_LOB.FREETEMPORARY(v_lob_2);\nEND;\n/\n",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			literals := GenOracleRowLiterals(columnTypes, []string{"1", c.value})
+			got := GenOracleFixSQL(columnTypes, literals, insert)
+			for _, s := range c.contains {
+				if !strings.Contains(got, s) {
+					t.Fatalf("fix sql isn't contain %q", s)
+				}
+			}
+			for _, s := range c.absent {
+				if strings.Contains(got, s) {
+					t.Fatalf("fix sql contain %q", s)
+				}
+			}
+			for _, l := range oracleQuotedLiteral.FindAllString(got, -1) {
+				if len(l)-2 > oracleLiteralMaxBytes {
+					t.Fatalf("literal piece length %d exceeds %d", len(l)-2, oracleLiteralMaxBytes)
+				}
+			}
+		})
+	}
+}
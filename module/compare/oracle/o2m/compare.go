@@ -23,11 +23,13 @@ import (
 	"github.com/wentaojin/transferdb/database/meta"
 	"github.com/wentaojin/transferdb/database/mysql"
 	"github.com/wentaojin/transferdb/database/oracle"
+	"github.com/wentaojin/transferdb/mapping"
 	"github.com/wentaojin/transferdb/module/compare"
 	"github.com/wentaojin/transferdb/module/compare/oracle/public"
+	"github.com/wentaojin/transferdb/selector"
+	"github.com/wentaojin/transferdb/storage"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
-	"path/filepath"
 	"strings"
 	"time"
 )
@@ -36,8 +38,10 @@ type Compare struct {
 	ctx    context.Context
 	cfg    *config.Config
 	oracle *oracle.Oracle
-	mysql  *mysql.MySQL
-	metaDB *meta.Meta
+	// ADG 备库，用于数据校验查询
+	standby *oracle.Oracle
+	mysql   *mysql.MySQL
+	metaDB  *meta.Meta
 }
 
 func NewCompare(ctx context.Context, cfg *config.Config) (*Compare, error) {
@@ -45,6 +49,10 @@ func NewCompare(ctx context.Context, cfg *config.Config) (*Compare, error) {
 	if err != nil {
 		return nil, err
 	}
+	standbyDB, err := oracle.NewOracleStandbyEngine(ctx, cfg.OracleConfig, cfg.StandbyConfig, cfg.SchemaConfig.SourceSchema)
+	if err != nil {
+		return nil, err
+	}
 	mysqlDB, err := mysql.NewMySQLDBEngine(ctx, cfg.MySQLConfig)
 	if err != nil {
 		return nil, err
@@ -54,11 +62,12 @@ func NewCompare(ctx context.Context, cfg *config.Config) (*Compare, error) {
 		return nil, err
 	}
 	return &Compare{
-		ctx:    ctx,
-		cfg:    cfg,
-		oracle: oracleDB,
-		mysql:  mysqlDB,
-		metaDB: metaDB,
+		ctx:     ctx,
+		cfg:     cfg,
+		oracle:  oracleDB,
+		standby: standbyDB,
+		mysql:   mysqlDB,
+		metaDB:  metaDB,
 	}, nil
 }
 
@@ -91,7 +100,7 @@ func (r *Compare) NewCompare() error {
 	}
 
 	// 获取配置文件待同步表列表
-	exporters, err := public.FilterCFGTable(r.cfg, r.oracle)
+	exporters, err := selector.SelectOracleTable(r.cfg, r.oracle)
 	if err != nil {
 		return err
 	}
@@ -374,19 +383,20 @@ func (r *Compare) NewCompare() error {
 		}
 	}
 
-	partTableTasks := NewPartCompareTableTask(r.ctx, r.cfg, partSyncTables, r.mysql, r.oracle, tableNameRuleMap)
-	waitTableTasks := NewWaitCompareTableTask(r.ctx, r.cfg, waitSyncTables, oracleCollation, r.mysql, r.oracle, tableNameRuleMap)
-
-	// 数据对比
-	err = common.PathExist(r.cfg.DiffConfig.FixSqlDir)
+	// 字段映射规则，排除字段不校验，重命名字段按目标端字段名校验，EXPR 字段按源端表达式计算结果校验
+	mapper, err := mapping.NewMapper(r.ctx, r.metaDB, r.cfg.DBTypeS, r.cfg.DBTypeT, r.cfg.SchemaConfig.SourceSchema)
 	if err != nil {
 		return err
 	}
 
-	checkFile := filepath.Join(r.cfg.DiffConfig.FixSqlDir, fmt.Sprintf("compare_%s.sql", r.cfg.SchemaConfig.SourceSchema))
+	partTableTasks := NewPartCompareTableTask(r.ctx, r.cfg, partSyncTables, r.mysql, r.oracle, tableNameRuleMap, mapper)
+	waitTableTasks := NewWaitCompareTableTask(r.ctx, r.cfg, waitSyncTables, oracleCollation, r.mysql, r.oracle, tableNameRuleMap, mapper)
+
+	// 数据对比
+	checkFile := storage.JoinPath(r.cfg.DiffConfig.FixSqlDir, fmt.Sprintf("compare_%s.sql", r.cfg.SchemaConfig.SourceSchema))
 
 	// file writer
-	f, err := compare.NewWriter(checkFile)
+	f, err := compare.NewWriter(r.ctx, r.cfg.StorageConfig, checkFile)
 	if err != nil {
 		return err
 	}
@@ -460,6 +470,11 @@ func (r *Compare) NewCompare() error {
 }
 
 func (r *Compare) comparePartTableTasks(f *compare.File, partTableTasks []*Task) error {
+	// ADG 备库校验，等待备库应用 SCN 追上主库当前 SCN
+	if err := r.waitStandbyApply(); err != nil {
+		return err
+	}
+
 	for _, task := range partTableTasks {
 		// 获取对比记录
 		diffStartTime := time.Now()
@@ -508,7 +523,7 @@ func (r *Compare) comparePartTableTasks(f *compare.File, partTableTasks []*Task)
 		g1.SetLimit(r.cfg.DiffConfig.DiffThreads)
 
 		for _, compareMeta := range waitCompareMetas {
-			newReport := NewReport(compareMeta, r.mysql, r.oracle, r.cfg.DiffConfig.OnlyCheckRows)
+			newReport := NewReport(compareMeta, r.mysql, r.sourceReader(), r.cfg.DiffConfig.OnlyCheckRows)
 			g1.Go(func() error {
 				// 数据对比报告
 				report, err := public.IReport(newReport)
@@ -726,3 +741,24 @@ func (r *Compare) AdjustCompareConfig(sourceDBCharset string) error {
 	}
 	return nil
 }
+
+// sourceReader 数据校验源端查询数据库引擎，开启 ADG 备库基于备库查询，否则基于主库
+func (r *Compare) sourceReader() *oracle.Oracle {
+	if r.standby != nil {
+		return r.standby
+	}
+	return r.oracle
+}
+
+// waitStandbyApply ADG 备库校验，源端停写后等待备库应用 SCN 追上主库当前 SCN，备库数据与主库一致
+func (r *Compare) waitStandbyApply() error {
+	if r.standby == nil {
+		return nil
+	}
+	globalSCN, err := r.oracle.GetOracleCurrentSnapshotSCN()
+	if err != nil {
+		return err
+	}
+	return r.standby.WaitOracleStandbyApplySCN(globalSCN,
+		time.Duration(r.cfg.StandbyConfig.ApplyWaitTimeout)*time.Second, time.Duration(r.cfg.StandbyConfig.ApplyWaitInterval)*time.Second)
+}
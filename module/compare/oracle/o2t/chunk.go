@@ -252,6 +252,16 @@ func (c *Chunk) Split() error {
 		c.WhereColumn = customColumn
 	}
 
+	// 自定义迁移配置 chunk-strategy = pk，基于 NTILE 切分，不依赖 DBMS_PARALLEL_EXECUTE
+	chunkStrategy, chunkColumn := c.CustomMigrateChunkConfig()
+	switch {
+	case strings.EqualFold(chunkStrategy, common.ChunkStrategyPK):
+		return c.SplitByNTILE(startTime, customColumn, chunkColumn, tableRowsByStatistics)
+	case strings.EqualFold(chunkStrategy, "") || strings.EqualFold(chunkStrategy, common.ChunkStrategyRowID):
+	default:
+		return fmt.Errorf("oracle table [%s.%s] migrate config chunk-strategy [%s] isn't support, support [rowid pk]", c.Cfg.SchemaConfig.SourceSchema, c.SourceTable, chunkStrategy)
+	}
+
 	taskName := common.StringsBuilder(common.StringUPPER(c.Cfg.SchemaConfig.SourceSchema), `_`, c.SourceTable, `_`, `TASK`, strconv.Itoa(c.ChunkID))
 
 	if err = c.Oracle.StartOracleChunkCreateTask(taskName); err != nil {
@@ -398,6 +408,94 @@ func (c *Chunk) Split() error {
 
 }
 
+func (c *Chunk) CustomMigrateChunkConfig() (string, string) {
+	for _, tableCfg := range c.Cfg.SchemaConfig.MigrateConfig {
+		if strings.EqualFold(c.SourceTable, tableCfg.SourceTable) {
+			return tableCfg.ChunkStrategy, tableCfg.ChunkColumn
+		}
+	}
+	return "", ""
+}
+
+// SplitByNTILE 基于 NUMBER 字段 NTILE 切分，字段优先级 index-fields > chunk-column > DB Filter integer column > 主键/唯一索引
+// chunk 条件需同时用于上下游查询，只支持 NUMBER 数据类型
+func (c *Chunk) SplitByNTILE(startTime time.Time, customColumn, chunkColumn string, tableRows int) error {
+	var whereColumn string
+	switch {
+	case !strings.EqualFold(customColumn, ""):
+		whereColumn = customColumn
+	case !strings.EqualFold(chunkColumn, ""):
+		whereColumn = chunkColumn
+	default:
+		whereColumn = c.WhereColumn
+	}
+
+	columnName, dataType, err := c.Oracle.GetOracleTableChunkColumn(common.StringUPPER(c.Cfg.SchemaConfig.SourceSchema), c.SourceTable, whereColumn)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(dataType, "NUMBER") && !strings.EqualFold(dataType, "FLOAT") {
+		return fmt.Errorf("oracle table [%s.%s] chunk column [%s] data type [%s] isn't number, compare chunk-strategy pk only support number data type",
+			common.StringUPPER(c.Cfg.SchemaConfig.SourceSchema), c.SourceTable, columnName, dataType)
+	}
+
+	chunkRes, err := c.Oracle.GetOracleTableChunksByNTILE(common.StringUPPER(c.Cfg.SchemaConfig.SourceSchema), c.SourceTable, columnName, dataType, tableRows, c.Cfg.DiffConfig.ChunkSize)
+	if err != nil {
+		return err
+	}
+
+	// chunk 首尾开区间，无需额外补充上下游数据边界
+	c.WhereColumn = columnName
+	if len(chunkRes) == 0 {
+		c.WhereColumn = ""
+		chunkRes = append(chunkRes, map[string]string{"CMD": "1 = 1"})
+	}
+
+	var fullMetas []meta.DataCompareMeta
+	for _, r := range chunkRes {
+		fullMetas = append(fullMetas, meta.DataCompareMeta{
+			DBTypeS:       c.Cfg.DBTypeS,
+			DBTypeT:       c.Cfg.DBTypeT,
+			SchemaNameS:   common.StringUPPER(c.Cfg.SchemaConfig.SourceSchema),
+			TableNameS:    common.StringUPPER(c.SourceTable),
+			SchemaNameT:   common.StringUPPER(c.Cfg.SchemaConfig.TargetSchema),
+			TableNameT:    common.StringUPPER(c.TargetTable),
+			ColumnDetailS: c.SourceColumnInfo,
+			ColumnDetailT: c.TargetColumnInfo,
+			WhereRange:    r["CMD"],
+			WhereColumn:   c.WhereColumn,
+			IsPartition:   c.IsPartition,
+			TaskMode:      c.Cfg.TaskMode,
+			TaskStatus:    common.TaskStatusWaiting})
+	}
+
+	// 元数据库信息 batch 写入
+	err = meta.NewCommonModel(c.MetaDB).BatchCreateDataCompareMetaAndUpdateWaitSyncMeta(c.Ctx,
+		fullMetas, c.Cfg.AppConfig.InsertBatchSize, &meta.WaitSyncMeta{
+			DBTypeS:          c.Cfg.DBTypeS,
+			DBTypeT:          c.Cfg.DBTypeT,
+			SchemaNameS:      common.StringUPPER(c.Cfg.SchemaConfig.SourceSchema),
+			TableNameS:       common.StringUPPER(c.SourceTable),
+			TaskMode:         c.Cfg.TaskMode,
+			GlobalScnS:       c.SourceGlobalSCN,
+			ChunkTotalNums:   int64(len(fullMetas)),
+			ChunkSuccessNums: 0,
+			ChunkFailedNums:  0,
+			IsPartition:      c.IsPartition,
+		})
+	if err != nil {
+		return fmt.Errorf("create table [%s.%s] data_diff_meta [batch size] failed: %v", common.StringUPPER(c.Cfg.SchemaConfig.SourceSchema), c.SourceTable, err)
+	}
+
+	zap.L().Info("pre split oracle and mysql table chunk by ntile finished",
+		zap.String("schema", c.Cfg.SchemaConfig.SourceSchema),
+		zap.String("table", c.SourceTable),
+		zap.String("column", columnName),
+		zap.Int("chunks", len(fullMetas)),
+		zap.String("cost", time.Now().Sub(startTime).String()))
+	return nil
+}
+
 func (c *Chunk) String() string {
 	jsonByte, _ := json.Marshal(c)
 	return string(jsonByte)
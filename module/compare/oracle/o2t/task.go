@@ -23,6 +23,7 @@ import (
 	"github.com/wentaojin/transferdb/database/meta"
 	"github.com/wentaojin/transferdb/database/mysql"
 	"github.com/wentaojin/transferdb/database/oracle"
+	"github.com/wentaojin/transferdb/mapping"
 	"github.com/wentaojin/transferdb/module/check"
 	"github.com/wentaojin/transferdb/module/check/oracle/o2t"
 	"github.com/wentaojin/transferdb/module/check/oracle/public"
@@ -39,9 +40,10 @@ type Task struct {
 	oracleCollation bool
 	mysql           *mysql.MySQL
 	oracle          *oracle.Oracle
+	columnMapper    *mapping.TableMapper
 }
 
-func NewPartCompareTableTask(ctx context.Context, cfg *config.Config, compareTables []string, mysql *mysql.MySQL, oracle *oracle.Oracle, tableNameRule map[string]string) []*Task {
+func NewPartCompareTableTask(ctx context.Context, cfg *config.Config, compareTables []string, mysql *mysql.MySQL, oracle *oracle.Oracle, tableNameRule map[string]string, mapper *mapping.Mapper) []*Task {
 	var tasks []*Task
 	for _, table := range compareTables {
 		// 库名、表名规则
@@ -58,13 +60,14 @@ func NewPartCompareTableTask(ctx context.Context, cfg *config.Config, compareTab
 			targetTableName: targetTableName,
 			mysql:           mysql,
 			oracle:          oracle,
+			columnMapper:    mapper.Table(table),
 		})
 	}
 	return tasks
 }
 
 func NewWaitCompareTableTask(ctx context.Context, cfg *config.Config, compareTables []string, oracleCollation bool, mysql *mysql.MySQL, oracle *oracle.Oracle,
-	tableNameRule map[string]string) []*Task {
+	tableNameRule map[string]string, mapper *mapping.Mapper) []*Task {
 	var tasks []*Task
 	for _, table := range compareTables {
 		// 库名、表名规则
@@ -82,6 +85,7 @@ func NewWaitCompareTableTask(ctx context.Context, cfg *config.Config, compareTab
 			oracleCollation: oracleCollation,
 			mysql:           mysql,
 			oracle:          oracle,
+			columnMapper:    mapper.Table(table),
 		})
 	}
 	return tasks
@@ -131,6 +135,7 @@ func PreTableStructCheck(ctx context.Context, cfg *config.Config, metaDB *meta.M
 // 字段查询以 ORACLE 字段为主
 // Date/Timestamp 字段类型格式化
 // Interval Year/Day 数据字符 TO_CHAR 格式化
+// 字段映射规则排除字段不校验，重命名字段目标端按映射字段名查询，EXPR 字段源端按表达式查询，别名统一源端字段名
 func (t *Task) AdjustDBSelectColumn() (sourceColumnInfo string, targetColumnInfo string, err error) {
 	var (
 		sourceColumnInfos, targetColumnInfos []string
@@ -142,40 +147,48 @@ func (t *Task) AdjustDBSelectColumn() (sourceColumnInfo string, targetColumnInfo
 
 	for _, colsInfo := range columnInfo {
 		colName := colsInfo["COLUMN_NAME"]
+		if t.columnMapper.IsExclude(colName) {
+			continue
+		}
+		sourceCol := colName
+		if expr, ok := t.columnMapper.ColumnExpr(colName); ok {
+			sourceCol = common.StringsBuilder("(", expr, ")")
+		}
+		targetCol := t.columnMapper.ColumnNameT(colName)
 		switch strings.ToUpper(colsInfo["DATA_TYPE"]) {
 		// 数字
 		case "NUMBER":
-			sourceColumnInfos = append(sourceColumnInfos, common.StringsBuilder("DECODE(SUBSTR(", colName, ",1,1),'.','0' || ", colName, ",", colName, ") AS ", colName))
-			targetColumnInfos = append(targetColumnInfos, common.StringsBuilder("CAST(0 + CAST(", colName, " AS CHAR) AS CHAR) AS ", colName))
+			sourceColumnInfos = append(sourceColumnInfos, common.StringsBuilder("DECODE(SUBSTR(", sourceCol, ",1,1),'.','0' || ", sourceCol, ",", sourceCol, ") AS ", colName))
+			targetColumnInfos = append(targetColumnInfos, common.StringsBuilder("CAST(0 + CAST(", targetCol, " AS CHAR) AS CHAR) AS ", colName))
 		case "DECIMAL", "DEC", "DOUBLE PRECISION", "FLOAT", "INTEGER", "INT", "REAL", "NUMERIC", "BINARY_FLOAT", "BINARY_DOUBLE", "SMALLINT":
-			sourceColumnInfos = append(sourceColumnInfos, common.StringsBuilder("DECODE(SUBSTR(", colName, ",1,1),'.','0' || ", colName, ",", colName, ") AS ", colName))
-			targetColumnInfos = append(targetColumnInfos, common.StringsBuilder("CAST(0 + CAST(", colName, " AS CHAR) AS CHAR) AS ", colName))
+			sourceColumnInfos = append(sourceColumnInfos, common.StringsBuilder("DECODE(SUBSTR(", sourceCol, ",1,1),'.','0' || ", sourceCol, ",", sourceCol, ") AS ", colName))
+			targetColumnInfos = append(targetColumnInfos, common.StringsBuilder("CAST(0 + CAST(", targetCol, " AS CHAR) AS CHAR) AS ", colName))
 		// 字符
 		case "BFILE", "CHARACTER", "LONG", "NCHAR VARYING", "ROWID", "UROWID", "VARCHAR", "CHAR", "NCHAR", "NVARCHAR2", "NCLOB", "CLOB":
-			sourceColumnInfos = append(sourceColumnInfos, common.StringsBuilder("NVL(", colName, ",'') AS ", colName))
-			targetColumnInfos = append(targetColumnInfos, common.StringsBuilder("IFNULL(", colName, ",'') AS ", colName))
+			sourceColumnInfos = append(sourceColumnInfos, common.StringsBuilder("NVL(", sourceCol, ",'') AS ", colName))
+			targetColumnInfos = append(targetColumnInfos, common.StringsBuilder("IFNULL(", targetCol, ",'') AS ", colName))
 		case "XMLTYPE":
-			sourceColumnInfos = append(sourceColumnInfos, common.StringsBuilder("NVL(XMLSERIALIZE(CONTENT ", colName, " AS CLOB),'') AS ", colName))
-			targetColumnInfos = append(targetColumnInfos, common.StringsBuilder("IFNULL(", colName, ",'') AS ", colName))
+			sourceColumnInfos = append(sourceColumnInfos, common.StringsBuilder("NVL(XMLSERIALIZE(CONTENT ", sourceCol, " AS CLOB),'') AS ", colName))
+			targetColumnInfos = append(targetColumnInfos, common.StringsBuilder("IFNULL(", targetCol, ",'') AS ", colName))
 		// 二进制
 		case "BLOB", "LONG RAW", "RAW":
-			sourceColumnInfos = append(sourceColumnInfos, colName)
-			targetColumnInfos = append(targetColumnInfos, colName)
+			sourceColumnInfos = append(sourceColumnInfos, common.StringsBuilder(sourceCol, genColumnAlias(sourceCol, colName)))
+			targetColumnInfos = append(targetColumnInfos, common.StringsBuilder(targetCol, genColumnAlias(targetCol, colName)))
 		// 时间
 		case "DATE":
-			sourceColumnInfos = append(sourceColumnInfos, common.StringsBuilder("TO_CHAR(", colName, ",'yyyy-MM-dd HH24:mi:ss') AS ", colName))
-			targetColumnInfos = append(targetColumnInfos, common.StringsBuilder("DATE_FORMAT(", colName, ",'%Y-%m-%d %H:%i:%s') AS ", colName))
+			sourceColumnInfos = append(sourceColumnInfos, common.StringsBuilder("TO_CHAR(", sourceCol, ",'yyyy-MM-dd HH24:mi:ss') AS ", colName))
+			targetColumnInfos = append(targetColumnInfos, common.StringsBuilder("DATE_FORMAT(", targetCol, ",'%Y-%m-%d %H:%i:%s') AS ", colName))
 		// 默认其他类型
 		default:
 			if strings.Contains(colsInfo["DATA_TYPE"], "INTERVAL") {
-				sourceColumnInfos = append(sourceColumnInfos, common.StringsBuilder("TO_CHAR(", colName, ") AS ", colName))
-				targetColumnInfos = append(targetColumnInfos, colName)
+				sourceColumnInfos = append(sourceColumnInfos, common.StringsBuilder("TO_CHAR(", sourceCol, ") AS ", colName))
+				targetColumnInfos = append(targetColumnInfos, common.StringsBuilder(targetCol, genColumnAlias(targetCol, colName)))
 			} else if strings.Contains(colsInfo["DATA_TYPE"], "TIMESTAMP") {
-				sourceColumnInfos = append(sourceColumnInfos, common.StringsBuilder("TO_CHAR(", colName, ",'yyyy-MM-dd HH24:mi:ss') AS ", colName))
-				targetColumnInfos = append(targetColumnInfos, common.StringsBuilder("FROM_UNIXTIME(UNIX_TIMESTAMP(", colName, "),'%Y-%m-%d %H:%i:%s') AS ", colName))
+				sourceColumnInfos = append(sourceColumnInfos, common.StringsBuilder("TO_CHAR(", sourceCol, ",'yyyy-MM-dd HH24:mi:ss') AS ", colName))
+				targetColumnInfos = append(targetColumnInfos, common.StringsBuilder("FROM_UNIXTIME(UNIX_TIMESTAMP(", targetCol, "),'%Y-%m-%d %H:%i:%s') AS ", colName))
 			} else {
-				sourceColumnInfos = append(sourceColumnInfos, colName)
-				targetColumnInfos = append(targetColumnInfos, colName)
+				sourceColumnInfos = append(sourceColumnInfos, common.StringsBuilder(sourceCol, genColumnAlias(sourceCol, colName)))
+				targetColumnInfos = append(targetColumnInfos, common.StringsBuilder(targetCol, genColumnAlias(targetCol, colName)))
 			}
 		}
 	}
@@ -201,11 +214,11 @@ func (t *Task) FilterDBWhereColumn() (string, error) {
 		return "", err
 	}
 
-	// number 数据类型字段
+	// number 数据类型字段，字段映射规则字段上下游字段名或者数据不一致，不用于 chunk 切分
 	var integerColumns []string
 	for _, colsInfo := range columnInfo {
 		// 数字
-		if strings.EqualFold(strings.ToUpper(colsInfo["DATA_TYPE"]), "NUMBER") {
+		if strings.EqualFold(strings.ToUpper(colsInfo["DATA_TYPE"]), "NUMBER") && !t.columnMapper.IsMapping(colsInfo["COLUMN_NAME"]) {
 			integerColumns = append(integerColumns, colsInfo["COLUMN_NAME"])
 		}
 	}
@@ -352,3 +365,11 @@ func (t *Task) IsPartitionTable() (string, error) {
 	}
 	return "NO", nil
 }
+
+// genColumnAlias 字段查询存在映射规则（重命名、EXPR）时，别名统一源端字段名
+func genColumnAlias(column, columnName string) string {
+	if strings.EqualFold(column, columnName) {
+		return ""
+	}
+	return common.StringsBuilder(" AS ", columnName)
+}
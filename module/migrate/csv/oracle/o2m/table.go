@@ -16,17 +16,17 @@ limitations under the License.
 package o2m
 
 import (
-	"bufio"
 	"context"
 	"fmt"
-	"github.com/thinkeridea/go-extend/exstrings"
 	"github.com/wentaojin/transferdb/common"
 	"github.com/wentaojin/transferdb/config"
 	"github.com/wentaojin/transferdb/database/meta"
 	"github.com/wentaojin/transferdb/database/oracle"
+	"github.com/wentaojin/transferdb/module/migrate/csv/oracle/public"
+	"github.com/wentaojin/transferdb/storage"
+	"github.com/wentaojin/transferdb/throttle"
+	"github.com/wentaojin/transferdb/transform"
 	"go.uber.org/zap"
-	"os"
-	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -37,17 +37,25 @@ type Rows struct {
 	SyncMeta     meta.FullSyncMeta
 	Oracle       *oracle.Oracle
 	Cfg          *config.Config
+	Storage      storage.ExternalStorage
 	DBCharsetS   string
 	DBCharsetT   string
 	ColumnNameS  []string
+	ColumnNameT  []string
 	ReadChannel  chan []map[string]string
-	WriteChannel chan string
+	WriteChannel chan []*string
+	Limiter      *throttle.TableLimiter
+	Transformer  *transform.TableTransformer
+	// csv 文件数据行数、大小以及 sha256，ApplyData 完成后用于记录元数据表 [csv_file_meta]
+	FileRows     uint64
+	FileSize     uint64
+	FileChecksum string
 }
 
 func NewRows(ctx context.Context, syncMeta meta.FullSyncMeta,
-	oracle *oracle.Oracle, cfg *config.Config, columnNameS []string, sourceDBCharset string) *Rows {
+	oracle *oracle.Oracle, cfg *config.Config, store storage.ExternalStorage, columnNameS []string, sourceDBCharset string, limiter *throttle.TableLimiter, transformer *transform.TableTransformer, columnNameT []string) *Rows {
 
-	writeChannel := make(chan string, common.ChannelBufferSize)
+	writeChannel := make(chan []*string, common.ChannelBufferSize)
 	readChannel := make(chan []map[string]string, common.ChannelBufferSize)
 
 	return &Rows{
@@ -55,11 +63,15 @@ func NewRows(ctx context.Context, syncMeta meta.FullSyncMeta,
 		SyncMeta:     syncMeta,
 		Oracle:       oracle,
 		Cfg:          cfg,
+		Storage:      store,
 		DBCharsetS:   sourceDBCharset,
 		DBCharsetT:   common.StringUPPER(cfg.CSVConfig.Charset),
 		ColumnNameS:  columnNameS,
+		ColumnNameT:  columnNameT,
 		ReadChannel:  readChannel,
 		WriteChannel: writeChannel,
+		Limiter:      limiter,
+		Transformer:  transformer,
 	}
 }
 
@@ -77,7 +89,7 @@ func (t *Rows) ReadData() error {
 		querySQL = common.StringsBuilder(`SELECT `, t.SyncMeta.ColumnDetailS, ` FROM `, t.SyncMeta.SchemaNameS, `.`, t.SyncMeta.TableNameS, ` WHERE `, t.SyncMeta.ChunkDetailS)
 	}
 
-	err := t.Oracle.GetOracleTableRowsDataCSV(querySQL, t.DBCharsetS, t.DBCharsetT, t.Cfg, t.ReadChannel)
+	err := t.Oracle.GetOracleTableRowsDataCSV(querySQL, t.DBCharsetS, t.DBCharsetT, t.Cfg, t.Limiter, t.Transformer, t.ReadChannel)
 	if err != nil {
 		// 通道关闭
 		close(t.ReadChannel)
@@ -103,21 +115,17 @@ func (t *Rows) ProcessData() error {
 
 	for dataC := range t.ReadChannel {
 		for _, dMap := range dataC {
-			// 按字段名顺序遍历获取对应值
-			var (
-				rowsTMP []string
-			)
-			for _, column := range t.ColumnNameS {
+			// 按字段名顺序遍历获取对应值，parquet NULL 字段不存在 dMap 中，按 nil 写入
+			rowsTMP := make([]*string, len(t.ColumnNameS))
+			for i, column := range t.ColumnNameS {
 				if val, ok := dMap[column]; ok {
-					rowsTMP = append(rowsTMP, val)
+					rowsTMP[i] = &val
+				} else if !strings.EqualFold(t.Cfg.CSVConfig.FileFormat, common.FileFormatParquet) {
+					return fmt.Errorf("source schema table column counts vs data counts isn't match")
 				}
 			}
-			if len(rowsTMP) != len(t.ColumnNameS) {
-				return fmt.Errorf("source schema table column counts vs data counts isn't match")
-			} else {
-				// csv 文件行数据输入
-				t.WriteChannel <- common.StringsBuilder(exstrings.Join(rowsTMP, t.Cfg.CSVConfig.Separator), t.Cfg.CSVConfig.Terminator)
-			}
+			// 数据文件行数据输入
+			t.WriteChannel <- rowsTMP
 		}
 	}
 
@@ -129,36 +137,43 @@ func (t *Rows) ProcessData() error {
 
 func (t *Rows) ApplyData() error {
 	startTime := time.Now()
-	// 文件目录判断
-	if err := common.PathExist(
-		filepath.Join(
-			t.Cfg.CSVConfig.OutputDir,
-			strings.ToUpper(t.SyncMeta.SchemaNameS),
-			strings.ToUpper(t.SyncMeta.TableNameS))); err != nil {
+	// 文件写入，支持本地目录以及 S3 兼容对象存储
+	fileName, err := storage.RelPath(t.Cfg.CSVConfig.OutputDir, t.SyncMeta.CSVFile)
+	if err != nil {
 		return err
 	}
-
-	fileW, err := os.OpenFile(t.SyncMeta.CSVFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND|os.O_TRUNC, 0666)
+	fileW, err := t.Storage.Create(t.Ctx, fileName)
 	if err != nil {
 		return err
 	}
-	defer fileW.Close()
 
-	// 使用 bufio 来缓存写入文件，以提高效率
-	writer := bufio.NewWriterSize(fileW, 4096)
-	defer writer.Flush()
+	// 写入同时计算文件大小以及 sha256 checksum
+	checksumW := public.NewChecksumWriter(fileW)
 
-	if t.Cfg.CSVConfig.Header {
-		if _, err = writer.WriteString(common.StringsBuilder(exstrings.Join(t.ColumnNameS, t.Cfg.CSVConfig.Separator), t.Cfg.CSVConfig.Terminator)); err != nil {
-			return fmt.Errorf("failed to write headers: %v", err)
-		}
+	// 按 file-format 写入 csv 或者 parquet
+	writer, err := public.NewRowWriter(checksumW, t.Cfg, t.ColumnNameT)
+	if err != nil {
+		_ = storage.Abort(fileW, err)
+		return err
 	}
 
 	for dataC := range t.WriteChannel {
-		if _, err = writer.WriteString(dataC); err != nil {
-			return fmt.Errorf("failed to write data row to csv %w", err)
+		if err = writer.Write(dataC); err != nil {
+			_ = storage.Abort(fileW, err)
+			return fmt.Errorf("failed to write data row to [%s]: %w", t.SyncMeta.CSVFile, err)
 		}
+		t.FileRows++
+	}
+
+	// 对象存储 Close 时才完成上传，需判断 Flush 以及 Close 结果
+	if err = writer.Close(); err != nil {
+		_ = storage.Abort(fileW, err)
+		return fmt.Errorf("failed to flush data to [%s]: %v", t.SyncMeta.CSVFile, err)
+	}
+	if err = fileW.Close(); err != nil {
+		return fmt.Errorf("failed to close [%s]: %v", t.SyncMeta.CSVFile, err)
 	}
+	t.FileSize, t.FileChecksum = checksumW.Bytes, checksumW.Checksum()
 
 	endTime := time.Now()
 	zap.L().Info("target schema table chunk data applier finished",
@@ -24,10 +24,14 @@ import (
 	"github.com/wentaojin/transferdb/database/meta"
 	"github.com/wentaojin/transferdb/database/mysql"
 	"github.com/wentaojin/transferdb/database/oracle"
+	"github.com/wentaojin/transferdb/mapping"
 	"github.com/wentaojin/transferdb/module/migrate/csv/oracle/public"
+	"github.com/wentaojin/transferdb/selector"
+	"github.com/wentaojin/transferdb/storage"
+	"github.com/wentaojin/transferdb/throttle"
+	"github.com/wentaojin/transferdb/transform"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
-	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
@@ -35,11 +39,17 @@ import (
 )
 
 type CSV struct {
-	Ctx    context.Context
-	Cfg    *config.Config
-	Oracle *oracle.Oracle
-	Mysql  *mysql.MySQL
-	MetaDB *meta.Meta
+	Ctx           context.Context
+	Cfg           *config.Config
+	Oracle        *oracle.Oracle
+	OracleStandby *oracle.Oracle
+	Mysql         *mysql.MySQL
+	MetaDB        *meta.Meta
+	Throttler     *throttle.Throttler
+	Transformer   *transform.Transformer
+	Mapper        *mapping.Mapper
+	// csv 输出外部存储，任务内共用，按文件 Create 写入
+	Storage storage.ExternalStorage
 }
 
 func NewCSV(ctx context.Context, cfg *config.Config) (*CSV, error) {
@@ -47,6 +57,11 @@ func NewCSV(ctx context.Context, cfg *config.Config) (*CSV, error) {
 	if err != nil {
 		return nil, err
 	}
+	// ADG 备库，用于数据抽取
+	standbyDB, err := oracle.NewOracleStandbyEngine(ctx, cfg.OracleConfig, cfg.StandbyConfig, cfg.SchemaConfig.SourceSchema)
+	if err != nil {
+		return nil, err
+	}
 	mysqlDB, err := mysql.NewMySQLDBEngine(ctx, cfg.MySQLConfig)
 	if err != nil {
 		return nil, err
@@ -56,11 +71,12 @@ func NewCSV(ctx context.Context, cfg *config.Config) (*CSV, error) {
 		return nil, err
 	}
 	return &CSV{
-		Ctx:    ctx,
-		Cfg:    cfg,
-		Oracle: oracleDB,
-		Mysql:  mysqlDB,
-		MetaDB: metaDB,
+		Ctx:           ctx,
+		Cfg:           cfg,
+		Oracle:        oracleDB,
+		OracleStandby: standbyDB,
+		Mysql:         mysqlDB,
+		MetaDB:        metaDB,
 	}, nil
 }
 
@@ -96,10 +112,34 @@ func (r *CSV) CSV() error {
 	}
 
 	// 获取配置文件待同步表列表
-	exporters, err := public.FilterCFGTable(r.Cfg, r.Oracle)
+	exporters, err := selector.SelectOracleTable(r.Cfg, r.Oracle)
+	if err != nil {
+		return err
+	}
+
+	// 源端抽取限速，时间窗口以及自适应采样后台任务随任务结束退出
+	throttler, err := throttle.NewThrottler(r.Cfg.ThrottleConfig)
+	if err != nil {
+		return err
+	}
+	throttleCtx, throttleCancel := context.WithCancel(r.Ctx)
+	defer throttleCancel()
+	go throttler.Run(throttleCtx, r.sourceReader().GetOracleSessionLoad)
+	r.Throttler = throttler
+
+	// 字段转换（脱敏）规则
+	transformer, err := transform.NewTransformer(r.Ctx, r.MetaDB, r.Cfg.DBTypeS, r.Cfg.DBTypeT, r.Cfg.SchemaConfig.SourceSchema)
 	if err != nil {
 		return err
 	}
+	r.Transformer = transformer
+
+	// 字段映射规则
+	mapper, err := mapping.NewMapper(r.Ctx, r.MetaDB, r.Cfg.DBTypeS, r.Cfg.DBTypeT, r.Cfg.SchemaConfig.SourceSchema)
+	if err != nil {
+		return err
+	}
+	r.Mapper = mapper
 
 	// 关于全量断点恢复
 	//  - 若想断点恢复，设置 enable-checkpoint true,首次一旦运行则 batch 数不能调整，
@@ -125,6 +165,16 @@ func (r *CSV) CSV() error {
 			return err
 		}
 
+		err = meta.NewCSVFileMetaModel(r.MetaDB).DeleteCSVFileMetaBySchemaTaskMode(r.Ctx, &meta.CSVFileMeta{
+			DBTypeS:     r.Cfg.DBTypeS,
+			DBTypeT:     r.Cfg.DBTypeT,
+			SchemaNameS: r.Cfg.SchemaConfig.SourceSchema,
+			TaskMode:    r.Cfg.TaskMode,
+		})
+		if err != nil {
+			return err
+		}
+
 		for _, tableName := range exporters {
 			err = meta.NewWaitSyncMetaModel(r.MetaDB).DeleteWaitSyncMeta(r.Ctx, &meta.WaitSyncMeta{
 				DBTypeS:     r.Cfg.DBTypeS,
@@ -211,7 +261,7 @@ func (r *CSV) CSV() error {
 		return err
 	}
 	if errTotals > 0 {
-		return fmt.Errorf(`csv schema [%s] mode [%s] table task failed: meta table [wait_sync_meta] exist failed error, please: firstly check meta table [wait_sync_meta] and [full_sync_meta] log record; secondly if need resume, update meta table [wait_sync_meta] column [task_status] table status RUNNING (Need UPPER) and delete meta table [chunk_error_detail] current task all records; finally rerunning`, strings.ToUpper(r.Cfg.SchemaConfig.SourceSchema), r.Cfg.TaskMode)
+		return fmt.Errorf(`csv schema [%s] mode [%s] table task failed: meta table [wait_sync_meta] exist failed error, please: firstly check failed table by [-mode repair -repair-mode csv -repair-action list]; secondly if need resume, reset failed table by [-mode repair -repair-mode csv -repair-action reset], or restart table by [-repair-action truncate -repair-table ${table}], or mark table done by [-repair-action done -repair-table ${table}]; finally rerunning`, strings.ToUpper(r.Cfg.SchemaConfig.SourceSchema), r.Cfg.TaskMode)
 	}
 
 	// 判断并记录待同步表列表
@@ -352,6 +402,11 @@ func (r *CSV) CSV() error {
 		return err
 	}
 
+	// 生成 schema manifest 以及 checksum 文件
+	if err = public.GenCSVManifest(r.Ctx, r.Cfg, r.MetaDB, r.Storage); err != nil {
+		return err
+	}
+
 	zap.L().Info("source schema table data csv finished",
 		zap.String("schema", r.Cfg.SchemaConfig.SourceSchema),
 		zap.Int("table totals", len(exporters)),
@@ -414,12 +469,22 @@ func (r *CSV) csvPartSyncTable(csvPartTables []string, sourceDBCharset string) e
 
 			waitFullMetas = append(waitFullMetas, failedFullMetas...)
 
-			columnNameS, err := r.Oracle.GetOracleTableRowsColumnCSV(
+			// ADG 备库抽取，chunk 基于主库 SCN 闪回查询，等待备库应用追上
+			if err = r.waitStandbyApply(waitFullMetas); err != nil {
+				return err
+			}
+
+			columnNameS, err := r.sourceReader().GetOracleTableRowsColumnCSV(
 				common.StringsBuilder(`SELECT *`, ` FROM `,
 					common.StringUPPER(r.Cfg.SchemaConfig.SourceSchema), `.`, common.StringUPPER(t), ` WHERE ROWNUM = 1`))
 			if err != nil {
 				return nil
 			}
+			// 字段映射规则，排除字段不迁移，csv 文件头以及 manifest 使用目标端字段名
+			columnNameS = r.Mapper.Table(t).FilterColumns(columnNameS)
+			columnNameT := r.Mapper.Table(t).ColumnNamesT(columnNameS)
+
+			limiter := r.Throttler.Table(t, r.getCustomMigrateConfig()[common.StringUPPER(t)])
 
 			g1 := &errgroup.Group{}
 			g1.SetLimit(r.Cfg.CSVConfig.SQLThreads)
@@ -427,7 +492,8 @@ func (r *CSV) csvPartSyncTable(csvPartTables []string, sourceDBCharset string) e
 			for _, fullSyncMeta := range waitFullMetas {
 				m := fullSyncMeta
 				g1.Go(func() error {
-					err = public.IMigrate(NewRows(r.Ctx, m, r.Oracle, r.Cfg, columnNameS, common.MigrateOracleCharsetStringConvertMapping[sourceDBCharset]))
+					rows := NewRows(r.Ctx, m, r.sourceReader(), r.Cfg, r.Storage, columnNameS, common.MigrateOracleCharsetStringConvertMapping[sourceDBCharset], limiter, r.Transformer.Table(t), columnNameT)
+					err = public.IMigrate(rows)
 					if err != nil {
 						var (
 							errorSQL string
@@ -470,6 +536,26 @@ func (r *CSV) csvPartSyncTable(csvPartTables []string, sourceDBCharset string) e
 						return nil
 					}
 
+					// 记录 csv 文件元数据，用于生成 manifest
+					if errf := meta.NewCSVFileMetaModel(r.MetaDB).CreateOrUpdateCSVFileMeta(r.Ctx, &meta.CSVFileMeta{
+						DBTypeS:      m.DBTypeS,
+						DBTypeT:      m.DBTypeT,
+						SchemaNameS:  m.SchemaNameS,
+						TableNameS:   m.TableNameS,
+						SchemaNameT:  m.SchemaNameT,
+						TableNameT:   m.TableNameT,
+						TaskMode:     m.TaskMode,
+						GlobalScnS:   m.GlobalScnS,
+						ChunkDetailS: m.ChunkDetailS,
+						ColumnNameS:  strings.Join(columnNameT, ","),
+						CSVFile:      m.CSVFile,
+						RowCounts:    rows.FileRows,
+						FileSize:     rows.FileSize,
+						FileChecksum: rows.FileChecksum,
+					}); errf != nil {
+						return errf
+					}
+
 					if errf := meta.NewFullSyncMetaModel(r.MetaDB).UpdateFullSyncMetaChunk(r.Ctx, &meta.FullSyncMeta{
 						DBTypeS:      m.DBTypeS,
 						DBTypeT:      m.DBTypeT,
@@ -632,6 +718,18 @@ func (r *CSV) initWaitSyncTableChunk(csvWaitTables []string, oracleCollation boo
 		g.Go(func() error {
 			startTime := time.Now()
 
+			// 表重新切分 chunk，清理历史 csv 文件元数据记录
+			err := meta.NewCSVFileMetaModel(r.MetaDB).DeleteCSVFileMetaBySchemaTable(r.Ctx, &meta.CSVFileMeta{
+				DBTypeS:     r.Cfg.DBTypeS,
+				DBTypeT:     r.Cfg.DBTypeT,
+				SchemaNameS: r.Cfg.SchemaConfig.SourceSchema,
+				TableNameS:  t,
+				TaskMode:    r.Cfg.TaskMode,
+			})
+			if err != nil {
+				return err
+			}
+
 			// 库名、表名规则
 			var targetTableName string
 			if val, ok := tableNameRule[common.StringUPPER(t)]; ok {
@@ -642,15 +740,19 @@ func (r *CSV) initWaitSyncTableChunk(csvWaitTables []string, oracleCollation boo
 
 			// 自定义迁移配置
 			var (
-				sqlHint     string
-				wherePrefix string
-				whereRange  string
-				enableSplit bool
+				sqlHint       string
+				wherePrefix   string
+				whereRange    string
+				enableSplit   bool
+				chunkStrategy string
+				chunkColumn   string
 			)
 			if val, ok := tableMigrateRule[common.StringUPPER(t)]; ok {
 				sqlHint = val.SQLHint
 				wherePrefix = val.Range
 				enableSplit = val.EnableSplit
+				chunkStrategy = val.ChunkStrategy
+				chunkColumn = val.ChunkColumn
 			} else {
 				sqlHint = r.Cfg.FullConfig.SQLHint
 			}
@@ -697,10 +799,10 @@ func (r *CSV) initWaitSyncTableChunk(csvWaitTables []string, oracleCollation boo
 					ChunkDetailS:   whereRange,
 					TaskMode:       r.Cfg.TaskMode,
 					TaskStatus:     common.TaskStatusWaiting,
-					CSVFile: filepath.Join(r.Cfg.CSVConfig.OutputDir,
+					CSVFile: storage.JoinPath(r.Cfg.CSVConfig.OutputDir,
 						common.StringUPPER(r.Cfg.SchemaConfig.SourceSchema), common.StringUPPER(t),
 						common.StringsBuilder(common.StringUPPER(r.Cfg.SchemaConfig.TargetSchema),
-							`.`, common.StringUPPER(targetTableName), `.0.csv`)),
+							`.`, common.StringUPPER(targetTableName), `.0`, public.DataFileExt(r.Cfg.CSVConfig.FileFormat))),
 				}, &meta.WaitSyncMeta{
 					DBTypeS:          r.Cfg.DBTypeS,
 					DBTypeT:          r.Cfg.DBTypeT,
@@ -721,19 +823,38 @@ func (r *CSV) initWaitSyncTableChunk(csvWaitTables []string, oracleCollation boo
 				return nil
 			}
 
-			taskName := uuid.New().String()
+			var (
+				taskName string
+				chunkRes []map[string]string
+			)
+			switch {
+			case strings.EqualFold(chunkStrategy, "") || strings.EqualFold(chunkStrategy, common.ChunkStrategyRowID):
+				taskName = uuid.New().String()
 
-			if err = r.Oracle.StartOracleChunkCreateTask(taskName); err != nil {
-				return err
-			}
+				if err = r.Oracle.StartOracleChunkCreateTask(taskName); err != nil {
+					return err
+				}
 
-			if err = r.Oracle.StartOracleCreateChunkByRowID(taskName, common.StringUPPER(r.Cfg.SchemaConfig.SourceSchema), common.StringUPPER(t), strconv.Itoa(r.Cfg.CSVConfig.Rows)); err != nil {
-				return err
-			}
+				if err = r.Oracle.StartOracleCreateChunkByRowID(taskName, common.StringUPPER(r.Cfg.SchemaConfig.SourceSchema), common.StringUPPER(t), strconv.Itoa(r.Cfg.CSVConfig.Rows)); err != nil {
+					return err
+				}
 
-			chunkRes, err := r.Oracle.GetOracleTableChunksByRowID(taskName)
-			if err != nil {
-				return err
+				chunkRes, err = r.Oracle.GetOracleTableChunksByRowID(taskName)
+				if err != nil {
+					return err
+				}
+			case strings.EqualFold(chunkStrategy, common.ChunkStrategyPK):
+				// 基于主键或者唯一索引字段 NTILE 切分，不依赖 DBMS_PARALLEL_EXECUTE 以及 CREATE JOB 权限
+				columnName, dataType, err := r.Oracle.GetOracleTableChunkColumn(r.Cfg.SchemaConfig.SourceSchema, t, chunkColumn)
+				if err != nil {
+					return err
+				}
+				chunkRes, err = r.Oracle.GetOracleTableChunksByNTILE(r.Cfg.SchemaConfig.SourceSchema, t, columnName, dataType, tableRowsByStatistics, r.Cfg.CSVConfig.Rows)
+				if err != nil {
+					return err
+				}
+			default:
+				return fmt.Errorf("oracle table [%s.%s] migrate config chunk-strategy [%s] isn't support, support [rowid pk]", r.Cfg.SchemaConfig.SourceSchema, t, chunkStrategy)
 			}
 
 			// 判断数据是否存在
@@ -759,10 +880,10 @@ func (r *CSV) initWaitSyncTableChunk(csvWaitTables []string, oracleCollation boo
 					ChunkDetailS:   whereRange,
 					TaskMode:       r.Cfg.TaskMode,
 					TaskStatus:     common.TaskStatusWaiting,
-					CSVFile: filepath.Join(r.Cfg.CSVConfig.OutputDir,
+					CSVFile: storage.JoinPath(r.Cfg.CSVConfig.OutputDir,
 						common.StringUPPER(r.Cfg.SchemaConfig.SourceSchema), common.StringUPPER(t),
 						common.StringsBuilder(common.StringUPPER(r.Cfg.SchemaConfig.TargetSchema),
-							`.`, common.StringUPPER(targetTableName), `.0.csv`)),
+							`.`, common.StringUPPER(targetTableName), `.0`, public.DataFileExt(r.Cfg.CSVConfig.FileFormat))),
 				}, &meta.WaitSyncMeta{
 					DBTypeS:          r.Cfg.DBTypeS,
 					DBTypeT:          r.Cfg.DBTypeT,
@@ -787,10 +908,10 @@ func (r *CSV) initWaitSyncTableChunk(csvWaitTables []string, oracleCollation boo
 			var fullMetas []meta.FullSyncMeta
 			for i, res := range chunkRes {
 				var csvFile string
-				csvFile = filepath.Join(r.Cfg.CSVConfig.OutputDir,
+				csvFile = storage.JoinPath(r.Cfg.CSVConfig.OutputDir,
 					common.StringUPPER(r.Cfg.SchemaConfig.SourceSchema), common.StringUPPER(t),
 					common.StringsBuilder(common.StringUPPER(r.Cfg.SchemaConfig.TargetSchema), `.`,
-						common.StringUPPER(targetTableName), `.`, strconv.Itoa(i), `.csv`))
+						common.StringUPPER(targetTableName), `.`, strconv.Itoa(i), public.DataFileExt(r.Cfg.CSVConfig.FileFormat)))
 
 				switch {
 				case enableSplit && !strings.EqualFold(wherePrefix, ""):
@@ -837,8 +958,10 @@ func (r *CSV) initWaitSyncTableChunk(csvWaitTables []string, oracleCollation boo
 				return err
 			}
 
-			if err = r.Oracle.CloseOracleChunkTask(taskName); err != nil {
-				return err
+			if !strings.EqualFold(taskName, "") {
+				if err = r.Oracle.CloseOracleChunkTask(taskName); err != nil {
+					return err
+				}
 			}
 
 			endTime := time.Now()
@@ -899,7 +1022,16 @@ func (r *CSV) AdjustTableSelectColumn(sourceTable string, oracleCollation bool)
 
 	var columnNames []string
 
+	columnMapper := r.Mapper.Table(sourceTable)
 	for _, rowCol := range columnsINFO {
+		// 字段映射规则，排除字段不抽取，EXPR 字段按源端表达式计算并保持源端字段名别名
+		if columnMapper.IsExclude(rowCol["COLUMN_NAME"]) {
+			continue
+		}
+		if expr, ok := columnMapper.ColumnExpr(rowCol["COLUMN_NAME"]); ok {
+			columnNames = append(columnNames, common.StringsBuilder(`(`, expr, `) AS "`, rowCol["COLUMN_NAME"], `"`))
+			continue
+		}
 		switch strings.ToUpper(rowCol["DATA_TYPE"]) {
 		// 数字
 		case "NUMBER":
@@ -912,6 +1044,9 @@ func (r *CSV) AdjustTableSelectColumn(sourceTable string, oracleCollation bool)
 		// XMLTYPE
 		case "XMLTYPE":
 			columnNames = append(columnNames, fmt.Sprintf(` XMLSERIALIZE(CONTENT "%s" AS CLOB) AS "%s"`, rowCol["COLUMN_NAME"], rowCol["COLUMN_NAME"]))
+		// 空间
+		case "SDO_GEOMETRY":
+			columnNames = append(columnNames, oracle.GenOracleGeometrySelectColumn(rowCol["COLUMN_NAME"]))
 		// 二进制
 		case "BLOB", "LONG RAW", "RAW":
 			columnNames = append(columnNames, common.StringsBuilder(`"`, rowCol["COLUMN_NAME"], `"`))
@@ -951,6 +1086,12 @@ func (r *CSV) AdjustCSVConfig(sourceDBCharset string) error {
 	if r.Cfg.CSVConfig.OutputDir == "" {
 		return fmt.Errorf("csv config paramter output-dir can't be null, please configure")
 	}
+	// output-dir 支持本地目录以及 s3://bucket/prefix 对象存储路径
+	store, err := storage.New(r.Ctx, r.Cfg.CSVConfig.OutputDir, r.Cfg.StorageConfig)
+	if err != nil {
+		return fmt.Errorf("csv config paramter output-dir [%s] storage init failed: %v", r.Cfg.CSVConfig.OutputDir, err)
+	}
+	r.Storage = store
 
 	if !strings.EqualFold(r.Cfg.OracleConfig.Charset, sourceDBCharset) {
 		zap.L().Warn("oracle charset and oracle config charset",
@@ -979,3 +1120,26 @@ func (r *CSV) AdjustCSVConfig(sourceDBCharset string) error {
 
 	return nil
 }
+
+// sourceReader 数据抽取数据库引擎，开启 ADG 备库基于备库抽取，否则基于主库
+func (r *CSV) sourceReader() *oracle.Oracle {
+	if r.OracleStandby != nil {
+		return r.OracleStandby
+	}
+	return r.Oracle
+}
+
+// waitStandbyApply ADG 备库抽取，等待备库应用 SCN 追上 chunk 主库 GlobalScnS，备库闪回查询与主库一致
+func (r *CSV) waitStandbyApply(fullMetas []meta.FullSyncMeta) error {
+	if r.OracleStandby == nil {
+		return nil
+	}
+	var globalSCN uint64
+	for _, m := range fullMetas {
+		if m.GlobalScnS > globalSCN {
+			globalSCN = m.GlobalScnS
+		}
+	}
+	return r.OracleStandby.WaitOracleStandbyApplySCN(globalSCN,
+		time.Duration(r.Cfg.StandbyConfig.ApplyWaitTimeout)*time.Second, time.Duration(r.Cfg.StandbyConfig.ApplyWaitInterval)*time.Second)
+}
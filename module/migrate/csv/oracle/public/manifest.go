@@ -0,0 +1,342 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package public
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/wentaojin/transferdb/common"
+	"github.com/wentaojin/transferdb/config"
+	"github.com/wentaojin/transferdb/database/meta"
+	"github.com/wentaojin/transferdb/storage"
+	"github.com/xitongsys/parquet-go/parquet"
+	"go.uber.org/zap"
+	"hash"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	// manifest 以及 checksum 文件名，位于 ${output-dir}/${source_schema} 目录
+	ManifestFileName = "manifest.json"
+	ChecksumFileName = "checksum.sha256"
+)
+
+const (
+	// parquet 文件头尾 magic number
+	parquetMagic = "PAR1"
+	// verify 保留 parquet 文件末尾字节数上限，用于解析 footer
+	ParquetFooterMaxSize = 16 * 1024 * 1024
+)
+
+type Manifest struct {
+	DBTypeS     string          `json:"db_type_s"`
+	DBTypeT     string          `json:"db_type_t"`
+	SchemaNameS string          `json:"schema_name_s"`
+	SchemaNameT string          `json:"schema_name_t"`
+	TaskMode    string          `json:"task_mode"`
+	OutputDir   string          `json:"output_dir"`
+	CreateTime  string          `json:"create_time"`
+	Dialect     ManifestDialect `json:"dialect"`
+	Tables      []ManifestTable `json:"tables"`
+}
+
+type ManifestDialect struct {
+	FileFormat      string `json:"file_format"`
+	Header          bool   `json:"header"`
+	Separator       string `json:"separator"`
+	Delimiter       string `json:"delimiter"`
+	Terminator      string `json:"terminator"`
+	EscapeBackslash bool   `json:"escape_backslash"`
+	Charset         string `json:"charset"`
+}
+
+type ManifestTable struct {
+	TableNameS string         `json:"table_name_s"`
+	TableNameT string         `json:"table_name_t"`
+	TaskStatus string         `json:"task_status"`
+	GlobalScnS uint64         `json:"global_scn_s"`
+	Columns    []string       `json:"columns"`
+	RowCounts  uint64         `json:"row_counts"`
+	FileSize   uint64         `json:"file_size"`
+	Files      []ManifestFile `json:"files"`
+}
+
+type ManifestFile struct {
+	File      string `json:"file"`
+	ChunkS    string `json:"chunk_s"`
+	RowCounts uint64 `json:"row_counts"`
+	FileSize  uint64 `json:"file_size"`
+	SHA256    string `json:"sha256"`
+}
+
+// ChecksumWriter 写入时同步计算文件大小以及 sha256，避免写入后重新读取文件
+type ChecksumWriter struct {
+	w     io.Writer
+	hash  hash.Hash
+	Bytes uint64
+}
+
+func NewChecksumWriter(w io.Writer) *ChecksumWriter {
+	return &ChecksumWriter{
+		w:    w,
+		hash: sha256.New(),
+	}
+}
+
+func (c *ChecksumWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.hash.Write(p[:n])
+	c.Bytes += uint64(n)
+	return n, err
+}
+
+func (c *ChecksumWriter) Checksum() string {
+	return hex.EncodeToString(c.hash.Sum(nil))
+}
+
+// RecordCounter 按 csv 方言统计 terminator 分隔记录数（包含 header 行）
+// 字符串引用定界符内以及 escape-backslash 反斜杠转义的 terminator 不计数
+type RecordCounter struct {
+	terminator []byte
+	delimiter  []byte
+	escape     bool
+
+	window  []byte
+	inQuote bool
+	escaped bool
+	Records uint64
+}
+
+func NewRecordCounter(dialect ManifestDialect) *RecordCounter {
+	return &RecordCounter{
+		terminator: []byte(dialect.Terminator),
+		delimiter:  []byte(dialect.Delimiter),
+		escape:     dialect.EscapeBackslash,
+	}
+}
+
+func (c *RecordCounter) Write(p []byte) (int, error) {
+	if len(c.terminator) == 0 {
+		return len(p), nil
+	}
+	for _, b := range p {
+		if c.escaped {
+			c.escaped = false
+			c.window = c.window[:0]
+			continue
+		}
+		if c.escape && b == '\\' {
+			c.escaped = true
+			c.window = c.window[:0]
+			continue
+		}
+		c.window = append(c.window, b)
+		if len(c.delimiter) > 0 && bytes.HasSuffix(c.window, c.delimiter) {
+			c.inQuote = !c.inQuote
+			c.window = c.window[:0]
+			continue
+		}
+		if !c.inQuote && bytes.HasSuffix(c.window, c.terminator) {
+			c.Records++
+			c.window = c.window[:0]
+			continue
+		}
+		if maxLen := len(c.terminator) + len(c.delimiter); len(c.window) > maxLen {
+			c.window = append(c.window[:0], c.window[len(c.window)-maxLen:]...)
+		}
+	}
+	return len(p), nil
+}
+
+// DataRecords 数据行数，header 行不计数
+func (c *RecordCounter) DataRecords(header bool) uint64 {
+	if header && c.Records > 0 {
+		return c.Records - 1
+	}
+	return c.Records
+}
+
+// ParquetRowCounter 写入时仅保留文件末尾数据，写入完成后解析 parquet footer 获取数据行数
+type ParquetRowCounter struct {
+	tail []byte
+}
+
+func NewParquetRowCounter() *ParquetRowCounter {
+	return &ParquetRowCounter{}
+}
+
+func (c *ParquetRowCounter) Write(p []byte) (int, error) {
+	c.tail = append(c.tail, p...)
+	// 超过两倍上限才截断，避免每次写入都拷贝
+	if len(c.tail) > 2*ParquetFooterMaxSize {
+		n := copy(c.tail, c.tail[len(c.tail)-ParquetFooterMaxSize:])
+		c.tail = c.tail[:n]
+	}
+	return len(p), nil
+}
+
+// DataRecords parquet footer 记录的数据行数
+// 文件末尾格式：footer（thrift compact 编码 FileMetaData）+ 4 字节 footer 长度（小端）+ PAR1
+func (c *ParquetRowCounter) DataRecords() (uint64, error) {
+	size := len(c.tail)
+	if size < 12 || !bytes.Equal(c.tail[size-4:], []byte(parquetMagic)) {
+		return 0, fmt.Errorf("parquet file magic number isn't found")
+	}
+	footerSize := int(binary.LittleEndian.Uint32(c.tail[size-8 : size-4]))
+	if footerSize+8 > size {
+		return 0, fmt.Errorf("parquet file footer size [%d] exceeds max size [%d]", footerSize, ParquetFooterMaxSize-8)
+	}
+	footer := parquet.NewFileMetaData()
+	protocol := thrift.NewTCompactProtocolFactory().GetProtocol(thrift.NewStreamTransportR(bytes.NewReader(c.tail[size-8-footerSize : size-8])))
+	if err := footer.Read(context.Background(), protocol); err != nil {
+		return 0, fmt.Errorf("parquet file footer read failed: %v", err)
+	}
+	return uint64(footer.GetNumRows()), nil
+}
+
+// ManifestDir manifest 所在目录 ${output-dir}/${source_schema}
+func ManifestDir(cfg *config.Config) string {
+	return storage.JoinPath(cfg.CSVConfig.OutputDir, common.StringUPPER(cfg.SchemaConfig.SourceSchema))
+}
+
+// GenCSVManifest 根据元数据表 [csv_file_meta] 以及 [wait_sync_meta] 生成 schema manifest 以及 checksum 文件
+func GenCSVManifest(ctx context.Context, cfg *config.Config, metaDB *meta.Meta, store storage.ExternalStorage) error {
+	startTime := time.Now()
+
+	fileMetas, err := meta.NewCSVFileMetaModel(metaDB).DetailCSVFileMetaBySchema(ctx, &meta.CSVFileMeta{
+		DBTypeS:     cfg.DBTypeS,
+		DBTypeT:     cfg.DBTypeT,
+		SchemaNameS: cfg.SchemaConfig.SourceSchema,
+		TaskMode:    cfg.TaskMode,
+	})
+	if err != nil {
+		return err
+	}
+
+	waitSyncMetas, err := meta.NewWaitSyncMetaModel(metaDB).DetailWaitSyncMeta(ctx, &meta.WaitSyncMeta{
+		DBTypeS:     cfg.DBTypeS,
+		DBTypeT:     cfg.DBTypeT,
+		SchemaNameS: common.StringUPPER(cfg.SchemaConfig.SourceSchema),
+		TaskMode:    cfg.TaskMode,
+	})
+	if err != nil {
+		return err
+	}
+	tableStatus := make(map[string]string)
+	for _, w := range waitSyncMetas {
+		tableStatus[common.StringUPPER(w.TableNameS)] = w.TaskStatus
+	}
+
+	manifest := Manifest{
+		DBTypeS:     cfg.DBTypeS,
+		DBTypeT:     cfg.DBTypeT,
+		SchemaNameS: common.StringUPPER(cfg.SchemaConfig.SourceSchema),
+		SchemaNameT: common.StringUPPER(cfg.SchemaConfig.TargetSchema),
+		TaskMode:    cfg.TaskMode,
+		OutputDir:   cfg.CSVConfig.OutputDir,
+		CreateTime:  startTime.Format("2006-01-02 15:04:05"),
+		Dialect: ManifestDialect{
+			FileFormat:      cfg.CSVConfig.FileFormat,
+			Header:          cfg.CSVConfig.Header,
+			Separator:       cfg.CSVConfig.Separator,
+			Delimiter:       cfg.CSVConfig.Delimiter,
+			Terminator:      cfg.CSVConfig.Terminator,
+			EscapeBackslash: cfg.CSVConfig.EscapeBackslash,
+			Charset:         cfg.CSVConfig.Charset,
+		},
+	}
+
+	manifestDir := ManifestDir(cfg)
+	var checksumBuilder strings.Builder
+
+	tableIdx := make(map[string]int)
+	for _, f := range fileMetas {
+		tableName := common.StringUPPER(f.TableNameS)
+		idx, ok := tableIdx[tableName]
+		if !ok {
+			status := tableStatus[tableName]
+			if strings.EqualFold(status, "") {
+				status = common.TaskStatusWaiting
+			}
+			manifest.Tables = append(manifest.Tables, ManifestTable{
+				TableNameS: tableName,
+				TableNameT: f.TableNameT,
+				TaskStatus: status,
+				GlobalScnS: f.GlobalScnS,
+				Columns:    strings.Split(f.ColumnNameS, ","),
+			})
+			idx = len(manifest.Tables) - 1
+			tableIdx[tableName] = idx
+		}
+		manifest.Tables[idx].RowCounts += f.RowCounts
+		manifest.Tables[idx].FileSize += f.FileSize
+		manifest.Tables[idx].Files = append(manifest.Tables[idx].Files, ManifestFile{
+			File:      f.CSVFile,
+			ChunkS:    f.ChunkDetailS,
+			RowCounts: f.RowCounts,
+			FileSize:  f.FileSize,
+			SHA256:    f.FileChecksum,
+		})
+
+		// sha256sum 格式，文件路径相对于 manifest 所在目录，便于 sha256sum -c 校验
+		checksumBuilder.WriteString(common.StringsBuilder(f.FileChecksum, "  ",
+			strings.TrimPrefix(strings.TrimPrefix(f.CSVFile, manifestDir), "/"), "\n"))
+	}
+
+	manifestJSON, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("csv schema [%s] manifest json marshal failed: %v", manifest.SchemaNameS, err)
+	}
+
+	if err = writeStorageFile(ctx, store, path.Join(common.StringUPPER(cfg.SchemaConfig.SourceSchema), ManifestFileName), manifestJSON); err != nil {
+		return err
+	}
+	if err = writeStorageFile(ctx, store, path.Join(common.StringUPPER(cfg.SchemaConfig.SourceSchema), ChecksumFileName), []byte(checksumBuilder.String())); err != nil {
+		return err
+	}
+
+	zap.L().Info("csv schema manifest generate finished",
+		zap.String("schema", manifest.SchemaNameS),
+		zap.Int("tables", len(manifest.Tables)),
+		zap.Int("files", len(fileMetas)),
+		zap.String("manifest", storage.JoinPath(manifestDir, ManifestFileName)),
+		zap.String("cost", time.Now().Sub(startTime).String()))
+	return nil
+}
+
+func writeStorageFile(ctx context.Context, store storage.ExternalStorage, fileName string, data []byte) error {
+	w, err := store.Create(ctx, fileName)
+	if err != nil {
+		return err
+	}
+	if _, err = w.Write(data); err != nil {
+		_ = storage.Abort(w, err)
+		return fmt.Errorf("write file [%s] failed: %v", fileName, err)
+	}
+	if err = w.Close(); err != nil {
+		return fmt.Errorf("close file [%s] failed: %v", fileName, err)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package public
+
+import (
+	"testing"
+)
+
+func TestRecordCounter(t *testing.T) {
+	cases := []struct {
+		name     string
+		dialect  ManifestDialect
+		content  string
+		expected uint64
+	}{
+		{
+			name:     "empty file",
+			dialect:  ManifestDialect{Header: true, Separator: ",", Terminator: "\r\n"},
+			content:  "",
+			expected: 0,
+		},
+		{
+			name:     "header only",
+			dialect:  ManifestDialect{Header: true, Separator: ",", Terminator: "\r\n"},
+			content:  "ID,NAME\r\n",
+			expected: 0,
+		},
+		{
+			name:     "header and rows",
+			dialect:  ManifestDialect{Header: true, Separator: ",", Terminator: "\r\n"},
+			content:  "ID,NAME\r\n1,a\r\n2,b\r\n",
+			expected: 2,
+		},
+		{
+			name:     "without header",
+			dialect:  ManifestDialect{Separator: "|#|", Terminator: "\n"},
+			content:  "1|#|a\n2|#|b\n3|#|c\n",
+			expected: 3,
+		},
+		{
+			name:     "lf inside crlf terminator row",
+			dialect:  ManifestDialect{Separator: ",", Terminator: "\r\n"},
+			content:  "1,a\nb\r\n2,c\r\n",
+			expected: 2,
+		},
+		{
+			name:     "terminator inside delimiter",
+			dialect:  ManifestDialect{Header: true, Separator: ",", Delimiter: `"`, Terminator: "\n"},
+			content:  "ID,NAME\n1,\"a\nb\"\n2,\"c\"\n",
+			expected: 2,
+		},
+		{
+			name:     "escaped terminator and delimiter",
+			dialect:  ManifestDialect{Separator: ",", Delimiter: `"`, Terminator: "\n", EscapeBackslash: true},
+			content:  "1,\"a\\\nb\\\"\"\n2,\"c\\\\\"\n",
+			expected: 2,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			counter := NewRecordCounter(c.dialect)
+			if _, err := counter.Write([]byte(c.content)); err != nil {
+				t.Fatal(err)
+			}
+			if got := counter.DataRecords(c.dialect.Header); got != c.expected {
+				t.Fatalf("data records %d, want %d", got, c.expected)
+			}
+
+			// 逐字节写入，terminator 跨 Write 调用
+			counter = NewRecordCounter(c.dialect)
+			for i := 0; i < len(c.content); i++ {
+				if _, err := counter.Write([]byte{c.content[i]}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := counter.DataRecords(c.dialect.Header); got != c.expected {
+				t.Fatalf("byte by byte data records %d, want %d", got, c.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package public
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/wentaojin/transferdb/common"
+	"github.com/wentaojin/transferdb/config"
+	"github.com/wentaojin/transferdb/storage"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+	"io"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// IVerify 根据 manifest 重新校验 csv 文件是否存在以及文件大小、sha256 checksum、数据行数是否一致
+func IVerify(ctx context.Context, cfg *config.Config) error {
+	startTime := time.Now()
+
+	if strings.EqualFold(cfg.CSVConfig.OutputDir, "") {
+		return fmt.Errorf("csv config paramter output-dir can't be null, please configure")
+	}
+
+	// 外部存储校验任务内共用，按文件 Open 读取
+	store, err := storage.New(ctx, cfg.CSVConfig.OutputDir, cfg.StorageConfig)
+	if err != nil {
+		return err
+	}
+
+	manifestFile := storage.JoinPath(ManifestDir(cfg), ManifestFileName)
+	manifest, err := readManifest(ctx, store, path.Join(common.StringUPPER(cfg.SchemaConfig.SourceSchema), ManifestFileName))
+	if err != nil {
+		return err
+	}
+
+	threads := cfg.CSVConfig.SQLThreads
+	if threads <= 0 {
+		threads = 1
+	}
+
+	var (
+		fileTotals  int
+		failedFiles []string
+		mutex       sync.Mutex
+	)
+
+	g := &errgroup.Group{}
+	g.SetLimit(threads)
+
+	for _, table := range manifest.Tables {
+		if !strings.EqualFold(table.TaskStatus, common.TaskStatusSuccess) {
+			zap.L().Warn("csv verify table task status isn't success, files maybe incomplete",
+				zap.String("schema", manifest.SchemaNameS),
+				zap.String("table", table.TableNameS),
+				zap.String("task status", table.TaskStatus))
+		}
+		for _, file := range table.Files {
+			fileTotals++
+			f := file
+			t := table.TableNameS
+			g.Go(func() error {
+				if errMsg := verifyFile(ctx, store, cfg.CSVConfig.OutputDir, manifest.Dialect, f); !strings.EqualFold(errMsg, "") {
+					zap.L().Error("csv verify file failed",
+						zap.String("schema", manifest.SchemaNameS),
+						zap.String("table", t),
+						zap.String("file", f.File),
+						zap.String("error", errMsg))
+
+					mutex.Lock()
+					failedFiles = append(failedFiles, f.File)
+					mutex.Unlock()
+				}
+				return nil
+			})
+		}
+	}
+
+	if err = g.Wait(); err != nil {
+		return err
+	}
+
+	if len(failedFiles) > 0 {
+		return fmt.Errorf("csv verify schema [%s] manifest [%s] failed, file totals [%d], failed file totals [%d], please see log detail",
+			manifest.SchemaNameS, manifestFile, fileTotals, len(failedFiles))
+	}
+
+	zap.L().Info("csv verify finished",
+		zap.String("schema", manifest.SchemaNameS),
+		zap.String("manifest", manifestFile),
+		zap.Int("table totals", len(manifest.Tables)),
+		zap.Int("file totals", fileTotals),
+		zap.String("cost", time.Now().Sub(startTime).String()))
+	return nil
+}
+
+func readManifest(ctx context.Context, store storage.ExternalStorage, manifestFile string) (Manifest, error) {
+	var manifest Manifest
+
+	r, err := store.Open(ctx, manifestFile)
+	if err != nil {
+		return manifest, err
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return manifest, fmt.Errorf("read manifest [%s] failed: %v", manifestFile, err)
+	}
+	if err = json.Unmarshal(data, &manifest); err != nil {
+		return manifest, fmt.Errorf("manifest [%s] json unmarshal failed: %v", manifestFile, err)
+	}
+	return manifest, nil
+}
+
+// verifyFile 返回空字符串代表校验通过，否则返回不一致原因
+// 读取文件同时计算 sha256 以及按 manifest csv 方言统计数据行数
+func verifyFile(ctx context.Context, store storage.ExternalStorage, outputDir string, dialect ManifestDialect, file ManifestFile) string {
+	fileName, err := storage.RelPath(outputDir, file.File)
+	if err != nil {
+		return err.Error()
+	}
+	r, err := store.Open(ctx, fileName)
+	if err != nil {
+		return err.Error()
+	}
+	defer r.Close()
+
+	// csv 按方言统计数据行数，parquet 解析 footer 数据行数
+	var (
+		counter io.Writer
+		records func() (uint64, error)
+	)
+	if strings.EqualFold(dialect.FileFormat, common.FileFormatParquet) {
+		c := NewParquetRowCounter()
+		counter, records = c, c.DataRecords
+	} else {
+		c := NewRecordCounter(dialect)
+		counter, records = c, func() (uint64, error) {
+			return c.DataRecords(dialect.Header), nil
+		}
+	}
+	w := NewChecksumWriter(counter)
+	if _, err = io.Copy(w, r); err != nil {
+		return fmt.Sprintf("read file failed: %v", err)
+	}
+
+	if w.Bytes != file.FileSize {
+		return fmt.Sprintf("file size [%d] and manifest file size [%d] aren't equal", w.Bytes, file.FileSize)
+	}
+	if !strings.EqualFold(w.Checksum(), file.SHA256) {
+		return fmt.Sprintf("file sha256 [%s] and manifest sha256 [%s] aren't equal", w.Checksum(), file.SHA256)
+	}
+	rows, err := records()
+	if err != nil {
+		return err.Error()
+	}
+	if rows != file.RowCounts {
+		return fmt.Sprintf("file row counts [%d] and manifest row counts [%d] aren't equal", rows, file.RowCounts)
+	}
+	return ""
+}
@@ -0,0 +1,133 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package public
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/wentaojin/transferdb/common"
+	"github.com/wentaojin/transferdb/config"
+	parquetcommon "github.com/xitongsys/parquet-go/common"
+	"github.com/xitongsys/parquet-go/writer"
+	"io"
+	"strings"
+)
+
+// RowWriter 数据文件行写入，csv 按 separator/terminator 拼接，parquet 按列写入
+type RowWriter interface {
+	// Write 按目标字段顺序写入一行，nil 代表 NULL
+	Write(values []*string) error
+	// Close 刷新缓存数据，parquet 同时写入 footer，不关闭底层 io.Writer
+	Close() error
+}
+
+// NewRowWriter 根据 [csv] file-format 创建数据文件行写入
+func NewRowWriter(w io.Writer, cfg *config.Config, columnNames []string) (RowWriter, error) {
+	if strings.EqualFold(cfg.CSVConfig.FileFormat, common.FileFormatParquet) {
+		return NewParquetRowWriter(w, columnNames)
+	}
+	return NewCSVRowWriter(w, cfg, columnNames)
+}
+
+// DataFileExt 数据文件后缀
+func DataFileExt(fileFormat string) string {
+	if strings.EqualFold(fileFormat, common.FileFormatParquet) {
+		return ".parquet"
+	}
+	return ".csv"
+}
+
+type CSVRowWriter struct {
+	writer     *bufio.Writer
+	separator  string
+	terminator string
+}
+
+func NewCSVRowWriter(w io.Writer, cfg *config.Config, columnNames []string) (*CSVRowWriter, error) {
+	// 使用 bufio 来缓存写入文件，以提高效率
+	c := &CSVRowWriter{
+		writer:     bufio.NewWriterSize(w, 4096),
+		separator:  cfg.CSVConfig.Separator,
+		terminator: cfg.CSVConfig.Terminator,
+	}
+	if cfg.CSVConfig.Header {
+		if _, err := c.writer.WriteString(common.StringsBuilder(strings.Join(columnNames, c.separator), c.terminator)); err != nil {
+			return nil, fmt.Errorf("failed to write headers: %v", err)
+		}
+	}
+	return c, nil
+}
+
+func (c *CSVRowWriter) Write(values []*string) error {
+	for i, v := range values {
+		if i > 0 {
+			if _, err := c.writer.WriteString(c.separator); err != nil {
+				return err
+			}
+		}
+		val := `NULL`
+		if v != nil {
+			val = *v
+		}
+		if _, err := c.writer.WriteString(val); err != nil {
+			return err
+		}
+	}
+	_, err := c.writer.WriteString(c.terminator)
+	return err
+}
+
+func (c *CSVRowWriter) Close() error {
+	return c.writer.Flush()
+}
+
+// ParquetRowWriter parquet 字段统一按 OPTIONAL BYTE_ARRAY（UTF8）字符串写入，snappy 压缩
+type ParquetRowWriter struct {
+	writer *writer.CSVWriter
+}
+
+func NewParquetRowWriter(w io.Writer, columnNames []string) (*ParquetRowWriter, error) {
+	var (
+		metadata []string
+		inNames  = make(map[string]string)
+	)
+	for _, c := range columnNames {
+		// parquet-go schema tag 以逗号、等号分隔并去除空白，字段名不能包含分隔符以及首尾空白
+		if strings.ContainsAny(c, ",=\t") || !strings.EqualFold(strings.TrimSpace(c), c) {
+			return nil, fmt.Errorf("column [%s] contains comma, equal sign, tab or leading/trailing space, parquet file-format isn't support", c)
+		}
+		// parquet-go 字段名转换 go 变量名作为内部路径，转换后不能重复
+		inName := parquetcommon.StringToVariableName(c)
+		if col, ok := inNames[inName]; ok {
+			return nil, fmt.Errorf("column [%s] and column [%s] parquet schema name conflict, parquet file-format isn't support", col, c)
+		}
+		inNames[inName] = c
+		metadata = append(metadata, fmt.Sprintf("name=%s, type=UTF8, repetitiontype=OPTIONAL", c))
+	}
+	pw, err := writer.NewCSVWriterFromWriter(metadata, w, 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create parquet writer: %v", err)
+	}
+	return &ParquetRowWriter{writer: pw}, nil
+}
+
+func (p *ParquetRowWriter) Write(values []*string) error {
+	return p.writer.WriteString(values)
+}
+
+func (p *ParquetRowWriter) Close() error {
+	return p.writer.WriteStop()
+}
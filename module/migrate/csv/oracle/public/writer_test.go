@@ -0,0 +1,135 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package public
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/wentaojin/transferdb/common"
+	"github.com/wentaojin/transferdb/config"
+	"github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestCSVRowWriter(t *testing.T) {
+	cfg := &config.Config{CSVConfig: config.CSVConfig{
+		FileFormat: common.FileFormatCSV,
+		Header:     true,
+		Separator:  ",",
+		Terminator: "\r\n",
+	}}
+	var b bytes.Buffer
+	w, err := NewRowWriter(&b, cfg, []string{"ID", "NAME"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = w.Write([]*string{strPtr("1"), strPtr(`"a"`)}); err != nil {
+		t.Fatal(err)
+	}
+	if err = w.Write([]*string{strPtr("2"), nil}); err != nil {
+		t.Fatal(err)
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	expected := "ID,NAME\r\n1,\"a\"\r\n2,NULL\r\n"
+	if b.String() != expected {
+		t.Errorf("expected %q, got %q", expected, b.String())
+	}
+}
+
+func TestParquetRowWriter(t *testing.T) {
+	cfg := &config.Config{CSVConfig: config.CSVConfig{FileFormat: common.FileFormatParquet}}
+	rows := [][]*string{
+		{strPtr("1"), strPtr("a")},
+		{strPtr("2"), nil},
+		{strPtr("3"), strPtr("")},
+	}
+
+	var b bytes.Buffer
+	w, err := NewRowWriter(&b, cfg, []string{"ID", "NAME$1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range rows {
+		if err = w.Write(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// verify 按 footer 统计数据行数
+	counter := NewParquetRowCounter()
+	if _, err = counter.Write(b.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	records, err := counter.DataRecords()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if records != uint64(len(rows)) {
+		t.Errorf("expected %d records, got %d", len(rows), records)
+	}
+
+	pf, err := buffer.NewBufferFile(b.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pr, err := reader.NewParquetColumnReader(pf, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pr.ReadStop()
+	for i, expected := range [][]interface{}{{"1", "2", "3"}, {"a", nil, ""}} {
+		values, _, _, err := pr.ReadColumnByIndex(int64(i), int64(len(rows)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(values, expected) {
+			t.Errorf("column %d expected %v, got %v", i, expected, values)
+		}
+	}
+}
+
+func TestParquetRowWriterColumnName(t *testing.T) {
+	var b bytes.Buffer
+	if _, err := NewParquetRowWriter(&b, []string{"A$", "A36"}); err == nil {
+		t.Errorf("expected parquet schema name conflict error")
+	}
+	for _, c := range []string{"A,B", "A=B", "A\tB", " A"} {
+		if _, err := NewParquetRowWriter(&b, []string{"ID", c}); err == nil {
+			t.Errorf("column %q expected parquet schema error", c)
+		}
+	}
+}
+
+func TestParquetRowCounterInvalid(t *testing.T) {
+	counter := NewParquetRowCounter()
+	if _, err := counter.Write([]byte("ID,NAME\r\n1,a\r\n")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := counter.DataRecords(); err == nil {
+		t.Errorf("expected parquet magic number error")
+	}
+}
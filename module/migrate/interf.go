@@ -15,6 +15,8 @@ limitations under the License.
 */
 package migrate
 
+import "github.com/wentaojin/transferdb/module/migrate/plan"
+
 type Migrator interface {
 	ReadData() error
 	ProcessData() error
@@ -25,6 +27,10 @@ type Fuller interface {
 	Full() error
 }
 
+type DryRunner interface {
+	DryRun() (*plan.Plan, error)
+}
+
 type Increr interface {
 	Incr() error
 }
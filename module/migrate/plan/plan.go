@@ -0,0 +1,281 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package plan
+
+import (
+	"context"
+	"fmt"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/wentaojin/transferdb/common"
+	"github.com/wentaojin/transferdb/config"
+	"github.com/wentaojin/transferdb/database/meta"
+	"strconv"
+	"strings"
+)
+
+// 执行计划详情展示最大宽度
+const planDetailDisplayWidth = 80
+
+// Plan full/all 模式 dry-run 执行计划，基于源端统计信息、chunk 切分以及元数据只读规划，不清理以及写入目标端、元数据库
+type Plan struct {
+	Ctx              context.Context
+	MetaDB           *meta.Meta
+	TaskMode         string
+	DBTypeS          string
+	DBTypeT          string
+	SchemaNameS      string
+	SchemaNameT      string
+	ConsistentRead   string
+	EnableCheckpoint bool
+	// 全量 chunk 初始化使用的源端 SCN（Oracle）或者 TSO（TiDB），0 代表非一致性读
+	GlobalScnS uint64
+	Tables     []Table
+
+	waitMetas map[string]meta.WaitSyncMeta
+	incrMetas map[string]meta.IncrSyncMeta
+}
+
+// Table 单表执行计划
+type Table struct {
+	TableNameS string
+	TableNameT string
+	Action     string
+	// 是否清理目标表数据
+	Truncate bool
+	// 表行数以及表大小（MB），基于统计信息
+	TableRows int64
+	TableSize float64
+	// 切分策略以及 chunk 数，断点续传表以元数据记录为准
+	ChunkStrategy string
+	ChunkNums     int64
+	// 全量 chunk 或者增量同步起始 SCN
+	GlobalScnS uint64
+	Detail     string
+}
+
+// NewPlan 初始化执行计划，加载任务 wait_sync_meta 以及 all 模式 incr_sync_meta 元数据
+func NewPlan(ctx context.Context, cfg *config.Config, metaDB *meta.Meta, globalSCN uint64, consistentRead string) (*Plan, error) {
+	p := &Plan{
+		Ctx:              ctx,
+		MetaDB:           metaDB,
+		TaskMode:         cfg.TaskMode,
+		DBTypeS:          cfg.DBTypeS,
+		DBTypeT:          cfg.DBTypeT,
+		SchemaNameS:      common.StringUPPER(cfg.SchemaConfig.SourceSchema),
+		SchemaNameT:      common.StringUPPER(cfg.SchemaConfig.TargetSchema),
+		ConsistentRead:   consistentRead,
+		EnableCheckpoint: cfg.FullConfig.EnableCheckpoint,
+		GlobalScnS:       globalSCN,
+		waitMetas:        make(map[string]meta.WaitSyncMeta),
+		incrMetas:        make(map[string]meta.IncrSyncMeta),
+	}
+
+	waitSyncMetas, err := meta.NewWaitSyncMetaModel(metaDB).DetailWaitSyncMeta(ctx, &meta.WaitSyncMeta{
+		DBTypeS:     p.DBTypeS,
+		DBTypeT:     p.DBTypeT,
+		SchemaNameS: p.SchemaNameS,
+		TaskMode:    p.TaskMode,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range waitSyncMetas {
+		p.waitMetas[common.StringUPPER(m.TableNameS)] = m
+	}
+
+	if strings.EqualFold(p.TaskMode, common.TaskModeAll) {
+		incrSyncMetas, err := meta.NewIncrSyncMetaModel(metaDB).DetailIncrSyncMetaBySchema(ctx, &meta.IncrSyncMeta{
+			DBTypeS:     p.DBTypeS,
+			DBTypeT:     p.DBTypeT,
+			SchemaNameS: p.SchemaNameS,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range incrSyncMetas {
+			p.incrMetas[common.StringUPPER(m.TableNameS)] = m
+		}
+	}
+	return p, nil
+}
+
+// TableAction 基于元数据判断单表处理方式，与 full/all 模式运行逻辑一致，FULL 表 chunk 切分由调用方补充
+func (p *Plan) TableAction(tableName string) (Table, error) {
+	t := Table{
+		TableNameS: tableName,
+		Action:     common.PlanActionFull,
+	}
+
+	// all 模式增量元数据存在记录，不进行全量，全量已完成的表直接基于表 SCN 增量同步
+	if len(p.incrMetas) > 0 {
+		m, ok := p.incrMetas[common.StringUPPER(tableName)]
+		if !ok {
+			t.Action = common.PlanActionFailed
+			t.Detail = "table isn't exist in meta table [incr_sync_meta], all mode can't incrementally sync, please adjust table selection or meta table"
+			return t, nil
+		}
+		t.TableNameT = m.TableNameT
+		t.GlobalScnS = m.TableScnS
+		// 与增量运行校验一致，表全量记录必须存在且所有 chunk 成功
+		w, ok := p.waitMetas[common.StringUPPER(tableName)]
+		if !ok || !strings.EqualFold(w.TaskStatus, common.TaskStatusSuccess) || w.ChunkTotalNums != w.ChunkSuccessNums {
+			t.Action = common.PlanActionFailed
+			t.Detail = "table increment sync meta record is exist but full sync isn't finished in meta table [wait_sync_meta], all mode can't incrementally sync"
+			return t, nil
+		}
+		t.Action = common.PlanActionIncr
+		t.Detail = "incrementally sync from table scn"
+		return t, nil
+	}
+
+	// 未开启断点续传，清理元数据以及目标表数据，重新切分 chunk
+	if !p.EnableCheckpoint {
+		t.Truncate = true
+		t.Detail = "enable-checkpoint false, clear meta and truncate target table"
+		return t, nil
+	}
+
+	m, ok := p.waitMetas[common.StringUPPER(tableName)]
+	if !ok || (strings.EqualFold(m.TaskStatus, common.TaskStatusWaiting) && m.ChunkTotalNums == common.TaskTableDefaultSplitChunkNums) {
+		t.Detail = "table hasn't checkpoint, init chunk"
+		return t, nil
+	}
+
+	t.ChunkNums = m.ChunkTotalNums
+	t.GlobalScnS = m.GlobalScnS
+	switch m.TaskStatus {
+	case common.TaskStatusSuccess:
+		t.Action = common.PlanActionSkip
+		t.Detail = "full sync finished, please clear meta table [wait_sync_meta] manually if need rerun"
+	case common.TaskStatusRunning:
+		// running 状态表 chunk 数一致可断点续传
+		chunkCounts, err := meta.NewFullSyncMetaModel(p.MetaDB).CountsFullSyncMetaByTaskTable(p.Ctx, &meta.FullSyncMeta{
+			DBTypeS:     m.DBTypeS,
+			DBTypeT:     m.DBTypeT,
+			SchemaNameS: common.StringUPPER(m.SchemaNameS),
+			TableNameS:  m.TableNameS,
+			TaskMode:    m.TaskMode,
+		})
+		if err != nil {
+			return t, err
+		}
+		if chunkCounts != m.ChunkTotalNums {
+			t.Action = common.PlanActionFailed
+			t.Detail = fmt.Sprintf("checkpoint chunks [%d] and meta chunks [%d] aren't consistent, please rerun with [enable-checkpoint = false]", chunkCounts, m.ChunkTotalNums)
+			return t, nil
+		}
+		t.Action = common.PlanActionResume
+		t.Detail = fmt.Sprintf("resume checkpoint, chunk success [%d] failed [%d]", m.ChunkSuccessNums, m.ChunkFailedNums)
+	case common.TaskStatusFailed:
+		// 失败 chunk 错误均为瞬时错误，任务运行自动重置断点续传
+		errDetails, err := meta.NewChunkErrorDetailModel(p.MetaDB).DetailChunkErrorDetailBySchemaTable(p.Ctx, &meta.ChunkErrorDetail{
+			DBTypeS:     m.DBTypeS,
+			DBTypeT:     m.DBTypeT,
+			SchemaNameS: m.SchemaNameS,
+			TableNameS:  m.TableNameS,
+			TaskMode:    m.TaskMode,
+		})
+		if err != nil {
+			return t, err
+		}
+		isRetryable := len(errDetails) > 0
+		for _, e := range errDetails {
+			if !strings.EqualFold(e.ErrorClass, common.ErrorClassRetryable) {
+				isRetryable = false
+				break
+			}
+		}
+		if isRetryable {
+			t.Action = common.PlanActionResume
+			t.Detail = fmt.Sprintf("retryable failed chunks [%d] are reset automatically, resume checkpoint", len(errDetails))
+			return t, nil
+		}
+		t.Action = common.PlanActionFailed
+		t.Detail = fmt.Sprintf("failed chunks [%d], task can't run, please handle failed table by [-mode repair]", len(errDetails))
+	default:
+		t.Action = common.PlanActionSkip
+		t.Detail = fmt.Sprintf("table status [%s] chunks [%d] isn't handled by task", m.TaskStatus, m.ChunkTotalNums)
+	}
+	return t, nil
+}
+
+// AddTable 添加单表执行计划
+func (p *Plan) AddTable(t Table) {
+	p.Tables = append(p.Tables, t)
+}
+
+// Render 执行计划输出
+func (p *Plan) Render() string {
+	var (
+		tableRows    int64
+		tableSize    float64
+		chunkNums    int64
+		truncateNums int
+		actions      []string
+	)
+	actionCounts := make(map[string]int)
+
+	tw := table.NewWriter()
+	tw.SetStyle(table.StyleLight)
+	tw.SetTitle(fmt.Sprintf("DRY-RUN MODE [%s] SOURCE [%s] SCHEMA [%s] TARGET [%s] SCHEMA [%s]", p.TaskMode, p.DBTypeS, p.SchemaNameS, p.DBTypeT, p.SchemaNameT))
+	tw.AppendHeader(table.Row{"#", "SOURCE TABLE", "TARGET TABLE", "ACTION", "TRUNCATE", "TABLE ROWS", "TABLE SIZE(MB)", "CHUNK STRATEGY", "CHUNKS", "SCN", "DETAIL"})
+	tw.SetColumnConfigs([]table.ColumnConfig{{Name: "DETAIL", WidthMax: planDetailDisplayWidth}})
+	for i, t := range p.Tables {
+		tw.AppendRow(table.Row{i + 1, t.TableNameS, t.TableNameT, t.Action, displayBool(t.Truncate), t.TableRows, t.TableSize, t.ChunkStrategy, t.ChunkNums, displaySCN(t.GlobalScnS), t.Detail})
+		tableRows += t.TableRows
+		tableSize += t.TableSize
+		chunkNums += t.ChunkNums
+		if t.Truncate {
+			truncateNums++
+		}
+		actionCounts[t.Action]++
+	}
+	for _, a := range []string{common.PlanActionFull, common.PlanActionResume, common.PlanActionSkip, common.PlanActionFailed, common.PlanActionIncr} {
+		if actionCounts[a] > 0 {
+			actions = append(actions, fmt.Sprintf("%s [%d]", a, actionCounts[a]))
+		}
+	}
+	tw.AppendFooter(table.Row{"", "TOTAL", len(p.Tables), strings.Join(actions, " "), truncateNums, tableRows, fmt.Sprintf("%.2f", tableSize), "", chunkNums, "", ""})
+	tw.SetCaption("global scn [%s] consistent read [%s] enable-checkpoint [%v], table rows and size are estimated by statistics, target and meta database aren't written",
+		displaySCN(p.GlobalScnS), p.ConsistentRead, p.EnableCheckpoint)
+	return tw.Render()
+}
+
+// Counts 执行计划处理方式统计
+func (p *Plan) Counts(action string) int {
+	var counts int
+	for _, t := range p.Tables {
+		if strings.EqualFold(t.Action, action) {
+			counts++
+		}
+	}
+	return counts
+}
+
+func displayBool(b bool) string {
+	if b {
+		return "YES"
+	}
+	return "NO"
+}
+
+// displaySCN SCN 0 代表非一致性读或者未初始化
+func displaySCN(scn uint64) string {
+	if scn == common.TaskTableDefaultSourceGlobalSCN {
+		return "-"
+	}
+	return strconv.FormatUint(scn, 10)
+}
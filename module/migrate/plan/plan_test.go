@@ -0,0 +1,114 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package plan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/wentaojin/transferdb/common"
+	"github.com/wentaojin/transferdb/database/meta"
+)
+
+func TestTableAction(t *testing.T) {
+	successMeta := meta.WaitSyncMeta{TableNameS: "T1", TaskStatus: common.TaskStatusSuccess, ChunkTotalNums: 4, ChunkSuccessNums: 4}
+	cases := []struct {
+		name             string
+		enableCheckpoint bool
+		waitMetas        map[string]meta.WaitSyncMeta
+		incrMetas        map[string]meta.IncrSyncMeta
+		table            string
+		action           string
+		truncate         bool
+	}{
+		{
+			name:     "checkpoint disabled",
+			table:    "T1",
+			action:   common.PlanActionFull,
+			truncate: true,
+		},
+		{
+			name:             "no checkpoint",
+			enableCheckpoint: true,
+			table:            "T1",
+			action:           common.PlanActionFull,
+		},
+		{
+			name:             "waiting without chunk",
+			enableCheckpoint: true,
+			waitMetas: map[string]meta.WaitSyncMeta{
+				"T1": {TableNameS: "T1", TaskStatus: common.TaskStatusWaiting, ChunkTotalNums: common.TaskTableDefaultSplitChunkNums},
+			},
+			table:  "t1",
+			action: common.PlanActionFull,
+		},
+		{
+			name:             "full finished",
+			enableCheckpoint: true,
+			waitMetas:        map[string]meta.WaitSyncMeta{"T1": successMeta},
+			table:            "T1",
+			action:           common.PlanActionSkip,
+		},
+		{
+			name:      "incr after full finished",
+			waitMetas: map[string]meta.WaitSyncMeta{"T1": successMeta},
+			incrMetas: map[string]meta.IncrSyncMeta{"T1": {TableNameS: "T1", TableNameT: "T1", TableScnS: 100}},
+			table:     "T1",
+			action:    common.PlanActionIncr,
+		},
+		{
+			name: "incr with full unfinished",
+			waitMetas: map[string]meta.WaitSyncMeta{
+				"T1": {TableNameS: "T1", TaskStatus: common.TaskStatusSuccess, ChunkTotalNums: 4, ChunkSuccessNums: 3},
+			},
+			incrMetas: map[string]meta.IncrSyncMeta{"T1": {TableNameS: "T1", TableNameT: "T1", TableScnS: 100}},
+			table:     "T1",
+			action:    common.PlanActionFailed,
+		},
+		{
+			name:      "incr without full meta",
+			incrMetas: map[string]meta.IncrSyncMeta{"T1": {TableNameS: "T1", TableNameT: "T1", TableScnS: 100}},
+			table:     "T1",
+			action:    common.PlanActionFailed,
+		},
+		{
+			name:      "incr table missing",
+			waitMetas: map[string]meta.WaitSyncMeta{"T2": successMeta},
+			incrMetas: map[string]meta.IncrSyncMeta{"T2": {TableNameS: "T2", TableNameT: "T2", TableScnS: 100}},
+			table:     "T1",
+			action:    common.PlanActionFailed,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			p := &Plan{
+				Ctx:              context.Background(),
+				EnableCheckpoint: c.enableCheckpoint,
+				waitMetas:        c.waitMetas,
+				incrMetas:        c.incrMetas,
+			}
+			tbl, err := p.TableAction(c.table)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tbl.Action != c.action || tbl.Truncate != c.truncate {
+				t.Fatalf("table [%s] action [%s] truncate [%v], want action [%s] truncate [%v], detail: %s",
+					c.table, tbl.Action, tbl.Truncate, c.action, c.truncate, tbl.Detail)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package m2o
+
+import (
+	"fmt"
+	"github.com/wentaojin/transferdb/common"
+	"github.com/wentaojin/transferdb/mapping"
+	"github.com/wentaojin/transferdb/module/migrate/plan"
+	"github.com/wentaojin/transferdb/selector"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+	"strings"
+	"time"
+)
+
+// DryRun full/all 模式执行计划，基于统计信息以及 chunk 切分规划待迁移表，不清理目标表、不写入目标端以及元数据库
+func (r *Migrate) DryRun() (*plan.Plan, error) {
+	startTime := time.Now()
+
+	// 待同步表以及统计信息表行数、表大小
+	tables, _, err := selector.ListMySQLTable(r.Cfg, r.Mysql)
+	if err != nil {
+		return nil, err
+	}
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("exporter tables aren't exist, please check config [schema] table selection params, or run list mode to show the selected tables")
+	}
+
+	// 字段映射规则，影响 chunk 抽取字段
+	mapper, err := mapping.NewMapper(r.Ctx, r.MetaDB, r.Cfg.DBTypeS, r.Cfg.DBTypeT, r.Cfg.SchemaConfig.SourceSchema)
+	if err != nil {
+		return nil, err
+	}
+	r.Mapper = mapper
+
+	rule, err := r.newChunkRule()
+	if err != nil {
+		return nil, err
+	}
+	p, err := plan.NewPlan(r.Ctx, r.Cfg, r.MetaDB, rule.globalSCN, rule.isConsistentRead)
+	if err != nil {
+		return nil, err
+	}
+
+	tablePlans := make([]plan.Table, len(tables))
+	g := &errgroup.Group{}
+	g.SetLimit(r.Cfg.FullConfig.TaskThreads)
+	for i, table := range tables {
+		idx, t := i, table
+		g.Go(func() error {
+			tp, err := p.TableAction(t.TableName)
+			if err != nil {
+				return err
+			}
+			tp.TableRows = t.TableRows
+			tp.TableSize = t.TableSize
+			if strings.EqualFold(tp.TableNameT, "") {
+				// 库名、表名规则，以目标端实际表名为准
+				tp.TableNameT, _, err = r.GetTargetTableColumn(t.TableName, rule.tableNameRule)
+				if err != nil {
+					return err
+				}
+			}
+
+			if strings.EqualFold(tp.Action, common.PlanActionFull) {
+				chunk, err := r.splitTableChunk(t.TableName, rule)
+				if err != nil {
+					return err
+				}
+				tp.ChunkStrategy = chunk.ChunkStrategy
+				tp.ChunkNums = int64(len(chunk.FullMetas))
+				tp.GlobalScnS = rule.globalSCN
+				if chunk.FullScan {
+					tp.Detail = common.StringsBuilder(tp.Detail, ", full table scan by single chunk")
+				}
+			}
+			tablePlans[idx] = tp
+			return nil
+		})
+	}
+	if err = g.Wait(); err != nil {
+		return nil, err
+	}
+	for _, tp := range tablePlans {
+		p.AddTable(tp)
+	}
+
+	zap.L().Info("source schema full table data sync dry-run finished",
+		zap.String("schema", r.Cfg.SchemaConfig.SourceSchema),
+		zap.String("mode", r.Cfg.TaskMode),
+		zap.Int("table totals", len(tables)),
+		zap.Int("full tables", p.Counts(common.PlanActionFull)),
+		zap.Int("failed tables", p.Counts(common.PlanActionFailed)),
+		zap.String("cost", time.Now().Sub(startTime).String()))
+	return p, nil
+}
@@ -0,0 +1,1009 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package m2o
+
+import (
+	"context"
+	"fmt"
+	"github.com/wentaojin/transferdb/common"
+	"github.com/wentaojin/transferdb/config"
+	"github.com/wentaojin/transferdb/database/meta"
+	"github.com/wentaojin/transferdb/database/mysql"
+	"github.com/wentaojin/transferdb/database/oracle"
+	"github.com/wentaojin/transferdb/mapping"
+	"github.com/wentaojin/transferdb/module/migrate/sql/mysql/public"
+	migrate "github.com/wentaojin/transferdb/module/migrate/sql/oracle/public"
+	"github.com/wentaojin/transferdb/selector"
+	"github.com/wentaojin/transferdb/throttle"
+	"github.com/wentaojin/transferdb/transform"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+	"regexp"
+	"strings"
+	"time"
+)
+
+type Migrate struct {
+	Ctx         context.Context
+	Cfg         *config.Config
+	Mysql       *mysql.MySQL
+	Oracle      *oracle.Oracle
+	MetaDB      *meta.Meta
+	Throttler   *throttle.Throttler
+	Transformer *transform.Transformer
+	Mapper      *mapping.Mapper
+	Reader      *public.CanalJSONReader
+	// 源端 schema 实际名称，Cfg 配置 schema 统一大写
+	SchemaNameS string
+}
+
+func NewFuller(ctx context.Context, cfg *config.Config) (*Migrate, error) {
+	mysqlDB, err := mysql.NewMySQLDBEngine(ctx, cfg.MySQLConfig)
+	if err != nil {
+		return nil, err
+	}
+	oracleDB, err := oracle.NewOracleDBEngine(ctx, cfg.OracleConfig, cfg.SchemaConfig.TargetSchema)
+	if err != nil {
+		return nil, err
+	}
+	metaDB, err := meta.NewMetaDBEngine(ctx, cfg.MetaConfig, cfg.AppConfig.SlowlogThreshold)
+	if err != nil {
+		return nil, err
+	}
+	schemaNameS, err := mysqlDB.GetMySQLActualSchemaName(cfg.SchemaConfig.SourceSchema)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrate{
+		Ctx:         ctx,
+		Cfg:         cfg,
+		Mysql:       mysqlDB,
+		Oracle:      oracleDB,
+		MetaDB:      metaDB,
+		SchemaNameS: schemaNameS,
+	}, nil
+}
+
+func (r *Migrate) Full() error {
+	startTime := time.Now()
+	zap.L().Info("source schema full table data sync start",
+		zap.String("schema", r.Cfg.SchemaConfig.SourceSchema))
+
+	// 数据库字符集
+	// AMERICAN_AMERICA.AL32UTF8
+	charset, err := r.Oracle.GetOracleDBCharacterSet()
+	if err != nil {
+		return err
+	}
+	targetDBCharset := strings.Split(charset, ".")[1]
+	if !strings.EqualFold(r.Cfg.OracleConfig.Charset, targetDBCharset) {
+		zap.L().Warn("oracle charset and oracle config charset",
+			zap.String("oracle charset", targetDBCharset),
+			zap.String("oracle config charset", r.Cfg.OracleConfig.Charset))
+		return fmt.Errorf("oracle charset [%v] and oracle config charset [%v] aren't equal, please adjust oracle config charset", targetDBCharset, r.Cfg.OracleConfig.Charset)
+	}
+	if _, ok := common.MigrateOracleCharsetStringConvertMapping[common.StringUPPER(r.Cfg.OracleConfig.Charset)]; !ok {
+		return fmt.Errorf("oracle current charset [%v] isn't support, support charset [%v]", r.Cfg.OracleConfig.Charset, common.MigrateOracleCharsetStringConvertMapping)
+	}
+	if _, ok := common.MigrateMYSQLCompatibleCharsetStringConvertMapping[common.StringUPPER(r.Cfg.MySQLConfig.Charset)]; !ok {
+		return fmt.Errorf("mysql current config charset [%v] isn't support, support charset [%v]", r.Cfg.MySQLConfig.Charset, common.MigrateMYSQLCompatibleCharsetStringConvertMapping)
+	}
+
+	// MySQL 不支持一致性读，数据迁移期间需停止源端写入
+	if r.Cfg.FullConfig.ConsistentRead {
+		zap.L().Warn("mysql source isn't support consistent read, please stop source database write during migrate",
+			zap.String("schema", r.Cfg.SchemaConfig.SourceSchema))
+	}
+	// 数据隔离基于源端 ROWID 定位，MySQL 源端不支持
+	if r.Cfg.FullConfig.EnableQuarantine {
+		zap.L().Warn("mysql source isn't support quarantine, config enable-quarantine ignore",
+			zap.String("schema", r.Cfg.SchemaConfig.SourceSchema))
+	}
+
+	// 获取配置文件待同步表列表
+	exporters, err := selector.SelectMySQLTable(r.Cfg, r.Mysql)
+	if err != nil {
+		return err
+	}
+
+	// 源端抽取限速，时间窗口以及自适应采样后台任务随任务结束退出
+	throttler, err := throttle.NewThrottler(r.Cfg.ThrottleConfig)
+	if err != nil {
+		return err
+	}
+	throttleCtx, throttleCancel := context.WithCancel(r.Ctx)
+	defer throttleCancel()
+	go throttler.Run(throttleCtx, r.Mysql.GetMySQLSessionLoad)
+	r.Throttler = throttler
+
+	// 字段转换（脱敏）规则
+	transformer, err := transform.NewTransformer(r.Ctx, r.MetaDB, r.Cfg.DBTypeS, r.Cfg.DBTypeT, r.Cfg.SchemaConfig.SourceSchema)
+	if err != nil {
+		return err
+	}
+	r.Transformer = transformer
+
+	// 字段映射规则
+	mapper, err := mapping.NewMapper(r.Ctx, r.MetaDB, r.Cfg.DBTypeS, r.Cfg.DBTypeT, r.Cfg.SchemaConfig.SourceSchema)
+	if err != nil {
+		return err
+	}
+	r.Mapper = mapper
+
+	// 关于全量断点恢复
+	//  - 若想断点恢复，设置 enable-checkpoint true,首次一旦运行则 batch 数不能调整，
+	//  - 若不想断点恢复或者重新调整 batch 数，设置 enable-checkpoint false,清理元数据表 [wait_sync_meta],重新运行全量任务
+	if !r.Cfg.FullConfig.EnableCheckpoint {
+		err = meta.NewFullSyncMetaModel(r.MetaDB).DeleteFullSyncMetaBySchemaSyncMode(
+			r.Ctx, &meta.FullSyncMeta{
+				DBTypeS:     r.Cfg.DBTypeS,
+				DBTypeT:     r.Cfg.DBTypeT,
+				SchemaNameS: common.StringUPPER(r.Cfg.SchemaConfig.SourceSchema),
+				TaskMode:    common.StringUPPER(r.Cfg.TaskMode),
+			})
+		if err != nil {
+			return err
+		}
+
+		err = meta.NewChunkErrorDetailModel(r.MetaDB).DeleteChunkErrorDetailBySchemaTaskMode(r.Ctx, &meta.ChunkErrorDetail{
+			DBTypeS:     r.Cfg.DBTypeS,
+			DBTypeT:     r.Cfg.DBTypeT,
+			SchemaNameS: common.StringUPPER(r.Cfg.SchemaConfig.SourceSchema),
+			TaskMode:    r.Cfg.TaskMode,
+		})
+		if err != nil {
+			return err
+		}
+
+		tableNameRule, err := r.GetTableNameRule()
+		if err != nil {
+			return err
+		}
+
+		for _, tableName := range exporters {
+			err = meta.NewWaitSyncMetaModel(r.MetaDB).DeleteWaitSyncMeta(r.Ctx, &meta.WaitSyncMeta{
+				DBTypeS:     r.Cfg.DBTypeS,
+				DBTypeT:     r.Cfg.DBTypeT,
+				SchemaNameS: common.StringUPPER(r.Cfg.SchemaConfig.SourceSchema),
+				TableNameS:  tableName,
+				TaskMode:    r.Cfg.TaskMode,
+			})
+			if err != nil {
+				return err
+			}
+			// 清理已有表数据
+			targetTableName, _, err := r.GetTargetTableColumn(tableName, tableNameRule)
+			if err != nil {
+				return err
+			}
+			if err = r.Oracle.TruncateOracleTable(common.StringUPPER(r.Cfg.SchemaConfig.TargetSchema), targetTableName); err != nil {
+				return err
+			}
+			zap.L().Info("truncate table",
+				zap.String("schema", r.Cfg.SchemaConfig.TargetSchema),
+				zap.String("table", targetTableName),
+				zap.String("status", "success"))
+		}
+	}
+
+	// 清理非当前任务 SUCCESS 表元数据记录 wait_sync_meta (用于统计 SUCCESS 准备)
+	// 例如：当前任务表 A/B，之前任务表 A/C (SUCCESS)，清理元数据 C，对于表 A 任务 Skip 忽略处理，除非手工清理表 A
+	tablesByMeta, err := meta.NewWaitSyncMetaModel(r.MetaDB).DetailWaitSyncMetaSuccessTables(r.Ctx, &meta.WaitSyncMeta{
+		DBTypeS:     r.Cfg.DBTypeS,
+		DBTypeT:     r.Cfg.DBTypeT,
+		SchemaNameS: common.StringUPPER(r.Cfg.SchemaConfig.SourceSchema),
+		TaskMode:    r.Cfg.TaskMode,
+		TaskStatus:  common.TaskStatusSuccess,
+	})
+	if err != nil {
+		return err
+	}
+
+	clearTables := common.FilterDifferenceStringItems(tablesByMeta, exporters)
+	interTables := common.FilterIntersectionStringItems(tablesByMeta, exporters)
+	if len(clearTables) > 0 {
+		err = meta.NewWaitSyncMetaModel(r.MetaDB).DeleteWaitSyncMetaSuccessTables(r.Ctx, &meta.WaitSyncMeta{
+			DBTypeS:     r.Cfg.DBTypeS,
+			DBTypeT:     r.Cfg.DBTypeT,
+			SchemaNameS: common.StringUPPER(r.Cfg.SchemaConfig.SourceSchema),
+			TaskMode:    r.Cfg.TaskMode,
+			TaskStatus:  common.TaskStatusSuccess,
+		}, clearTables)
+		if err != nil {
+			return err
+		}
+	}
+	zap.L().Warn("non-task table clear",
+		zap.Strings("clear tables", clearTables),
+		zap.Strings("intersection tables", interTables),
+		zap.Int("clear totals", len(clearTables)),
+		zap.Int("intersection total", len(interTables)))
+
+	// 失败表 chunk 错误均为瞬时错误，自动重置断点续传
+	if err = r.ResetRetryableFailedTable(); err != nil {
+		return err
+	}
+
+	// 判断 [wait_sync_meta] 是否存在错误记录，是否可进行 FULL
+	errTotals, err := meta.NewWaitSyncMetaModel(r.MetaDB).CountsErrWaitSyncMetaBySchema(r.Ctx, &meta.WaitSyncMeta{
+		DBTypeS:     r.Cfg.DBTypeS,
+		DBTypeT:     r.Cfg.DBTypeT,
+		SchemaNameS: common.StringUPPER(r.Cfg.SchemaConfig.SourceSchema),
+		TaskMode:    r.Cfg.TaskMode,
+		TaskStatus:  common.TaskStatusFailed,
+	})
+	if err != nil {
+		return err
+	}
+	if errTotals > 0 {
+		return fmt.Errorf(`full schema [%s] mode [%s] table task failed: meta table [wait_sync_meta] exist failed error, please: firstly check failed table by [-mode repair -repair-mode full -repair-action list]; secondly if need resume, reset failed table by [-mode repair -repair-mode full -repair-action reset], or restart table by [-repair-action truncate -repair-table ${table}], or mark table done by [-repair-action done -repair-table ${table}]; finally rerunning`, strings.ToUpper(r.Cfg.SchemaConfig.SourceSchema), r.Cfg.TaskMode)
+	}
+
+	// 判断并记录待同步表列表
+	for _, tableName := range exporters {
+		waitSyncMetas, err := meta.NewWaitSyncMetaModel(r.MetaDB).DetailWaitSyncMeta(r.Ctx, &meta.WaitSyncMeta{
+			DBTypeS:     r.Cfg.DBTypeS,
+			DBTypeT:     r.Cfg.DBTypeT,
+			SchemaNameS: common.StringUPPER(r.Cfg.SchemaConfig.SourceSchema),
+			TableNameS:  tableName,
+			TaskMode:    r.Cfg.TaskMode,
+		})
+		if err != nil {
+			return err
+		}
+		if len(waitSyncMetas) == 0 {
+			err = meta.NewWaitSyncMetaModel(r.MetaDB).CreateWaitSyncMeta(r.Ctx, &meta.WaitSyncMeta{
+				DBTypeS:        r.Cfg.DBTypeS,
+				DBTypeT:        r.Cfg.DBTypeT,
+				SchemaNameS:    common.StringUPPER(r.Cfg.SchemaConfig.SourceSchema),
+				TableNameS:     tableName,
+				TaskMode:       r.Cfg.TaskMode,
+				TaskStatus:     common.TaskStatusWaiting,
+				GlobalScnS:     common.TaskTableDefaultSourceGlobalSCN,
+				ChunkTotalNums: common.TaskTableDefaultSplitChunkNums,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// 获取等待同步以及未同步完成的表列表
+	var waitSyncTables []string
+
+	waitSyncDetails, err := meta.NewWaitSyncMetaModel(r.MetaDB).DetailWaitSyncMeta(r.Ctx, &meta.WaitSyncMeta{
+		DBTypeS:        r.Cfg.DBTypeS,
+		DBTypeT:        r.Cfg.DBTypeT,
+		SchemaNameS:    common.StringUPPER(r.Cfg.SchemaConfig.SourceSchema),
+		TaskMode:       r.Cfg.TaskMode,
+		TaskStatus:     common.TaskStatusWaiting,
+		GlobalScnS:     common.TaskTableDefaultSourceGlobalSCN,
+		ChunkTotalNums: common.TaskTableDefaultSplitChunkNums,
+	})
+	if err != nil {
+		return err
+	}
+	for _, table := range waitSyncDetails {
+		waitSyncTables = append(waitSyncTables, table.TableNameS)
+	}
+
+	// 判断未同步完成的表能否断点续传
+	var (
+		partSyncTables    []string
+		panicTblFullSlice []string
+	)
+	partSyncDetails, err := meta.NewWaitSyncMetaModel(r.MetaDB).QueryWaitSyncMetaByPartTask(r.Ctx, &meta.WaitSyncMeta{
+		DBTypeS:     r.Cfg.DBTypeS,
+		DBTypeT:     r.Cfg.DBTypeT,
+		SchemaNameS: common.StringUPPER(r.Cfg.SchemaConfig.SourceSchema),
+		TaskMode:    r.Cfg.TaskMode,
+		TaskStatus:  common.TaskStatusRunning,
+	})
+	if err != nil {
+		return err
+	}
+	for _, t := range partSyncDetails {
+		// 判断 running 状态表 chunk 数是否一致，一致可断点续传
+		chunkCounts, err := meta.NewFullSyncMetaModel(r.MetaDB).CountsFullSyncMetaByTaskTable(r.Ctx, &meta.FullSyncMeta{
+			DBTypeS:     t.DBTypeS,
+			DBTypeT:     t.DBTypeT,
+			SchemaNameS: t.SchemaNameS,
+			TableNameS:  t.TableNameS,
+			TaskMode:    t.TaskMode,
+		})
+		if err != nil {
+			return err
+		}
+		if chunkCounts != t.ChunkTotalNums {
+			panicTblFullSlice = append(panicTblFullSlice, t.TableNameS)
+		} else {
+			partSyncTables = append(partSyncTables, t.TableNameS)
+		}
+	}
+
+	if len(panicTblFullSlice) > 0 {
+		endTime := time.Now()
+		zap.L().Error("all mysql table data full error",
+			zap.String("schema", r.Cfg.SchemaConfig.SourceSchema),
+			zap.String("cost", endTime.Sub(startTime).String()),
+			zap.Int("part sync tables", len(partSyncTables)),
+			zap.Strings("panic tables", panicTblFullSlice))
+		return fmt.Errorf("checkpoint isn't consistent, can't be resume, please reruning [enable-checkpoint = fase]")
+	}
+
+	// 数据迁移
+	// 优先存在断点的表，断点表 chunk 写入前清理目标端 chunk 范围数据
+	// partSyncTables -> waitSyncTables
+	if len(partSyncTables) > 0 {
+		err = r.FullPartSyncTable(partSyncTables, true)
+		if err != nil {
+			return err
+		}
+	}
+	if len(waitSyncTables) > 0 {
+		err = r.FullWaitSyncTable(waitSyncTables)
+		if err != nil {
+			return err
+		}
+	}
+
+	// 任务详情
+	succTotals, err := meta.NewWaitSyncMetaModel(r.MetaDB).DetailWaitSyncMeta(r.Ctx, &meta.WaitSyncMeta{
+		DBTypeS:     r.Cfg.DBTypeS,
+		DBTypeT:     r.Cfg.DBTypeT,
+		SchemaNameS: common.StringUPPER(r.Cfg.SchemaConfig.SourceSchema),
+		TaskMode:    r.Cfg.TaskMode,
+		TaskStatus:  common.TaskStatusSuccess,
+	})
+	if err != nil {
+		return err
+	}
+	failedTotals, err := meta.NewWaitSyncMetaModel(r.MetaDB).DetailWaitSyncMeta(r.Ctx, &meta.WaitSyncMeta{
+		DBTypeS:     r.Cfg.DBTypeS,
+		DBTypeT:     r.Cfg.DBTypeT,
+		SchemaNameS: common.StringUPPER(r.Cfg.SchemaConfig.SourceSchema),
+		TaskMode:    r.Cfg.TaskMode,
+		TaskStatus:  common.TaskStatusFailed,
+	})
+	if err != nil {
+		return err
+	}
+
+	zap.L().Info("all full table data sync finished",
+		zap.String("schema", r.Cfg.SchemaConfig.SourceSchema),
+		zap.Int("table totals", len(exporters)),
+		zap.Int("table success", len(succTotals)),
+		zap.Int("table failed", len(failedTotals)),
+		zap.String("log detail", "if exist table failed, please see meta table [wait/full_sync_meta/chunk_error_detail]"),
+		zap.String("cost", time.Now().Sub(startTime).String()))
+	return nil
+}
+
+// FullPartSyncTable 表 chunk 数据迁移，safeMode 代表 chunk 写入前清理目标端 chunk 范围数据（断点续传 chunk 可能已部分写入）
+// chunk 重试写入前同样清理目标端 chunk 范围数据，保证 chunk 重复写入幂等
+func (r *Migrate) FullPartSyncTable(fullPartTables []string, safeMode bool) error {
+	taskTime := time.Now()
+
+	// 获取报错 sql
+	re := regexp.MustCompile("sql \\[(?s).*] execute")
+
+	tableNameRule, err := r.GetTableNameRule()
+	if err != nil {
+		return err
+	}
+
+	g := &errgroup.Group{}
+	g.SetLimit(r.Cfg.FullConfig.TableThreads)
+
+	for _, table := range fullPartTables {
+		t := table
+		g.Go(func() error {
+			startTime := time.Now()
+			err := meta.NewWaitSyncMetaModel(r.MetaDB).UpdateWaitSyncMeta(r.Ctx, &meta.WaitSyncMeta{
+				DBTypeS:     r.Cfg.DBTypeS,
+				DBTypeT:     r.Cfg.DBTypeT,
+				SchemaNameS: common.StringUPPER(r.Cfg.SchemaConfig.SourceSchema),
+				TableNameS:  t,
+				TaskMode:    r.Cfg.TaskMode,
+			}, map[string]interface{}{
+				"TaskStatus": common.TaskStatusRunning,
+			})
+			if err != nil {
+				return err
+			}
+
+			waitFullMetas, err := meta.NewFullSyncMetaModel(r.MetaDB).DetailFullSyncMeta(r.Ctx, &meta.FullSyncMeta{
+				DBTypeS:     r.Cfg.DBTypeS,
+				DBTypeT:     r.Cfg.DBTypeT,
+				SchemaNameS: common.StringUPPER(r.Cfg.SchemaConfig.SourceSchema),
+				TableNameS:  t,
+				TaskMode:    r.Cfg.TaskMode,
+				TaskStatus:  common.TaskStatusWaiting,
+			})
+			if err != nil {
+				return err
+			}
+			failedFullMetas, err := meta.NewFullSyncMetaModel(r.MetaDB).DetailFullSyncMeta(r.Ctx, &meta.FullSyncMeta{
+				DBTypeS:     r.Cfg.DBTypeS,
+				DBTypeT:     r.Cfg.DBTypeT,
+				SchemaNameS: common.StringUPPER(r.Cfg.SchemaConfig.SourceSchema),
+				TableNameS:  t,
+				TaskMode:    r.Cfg.TaskMode,
+				TaskStatus:  common.TaskStatusFailed,
+			})
+			if err != nil {
+				return err
+			}
+
+			waitFullMetas = append(waitFullMetas, failedFullMetas...)
+
+			// 源端字段匹配目标端字段以及数据类型，用于数据类型转换
+			sourceColumns, err := r.Mysql.GetMySQLTableColumn(r.SchemaNameS, t)
+			if err != nil {
+				return err
+			}
+			_, targetColumns, err := r.GetTargetTableColumn(t, tableNameRule)
+			if err != nil {
+				return err
+			}
+			// 字段映射规则，排除字段不迁移
+			sourceColumns = r.Mapper.Table(t).FilterColumnINFO(sourceColumns)
+			columnNameT, columnTypeT, err := public.GetOracleTableColumnMapping(sourceColumns, targetColumns, r.Mapper.Table(t))
+			if err != nil {
+				return fmt.Errorf("mysql table [%s.%s] column mapping failed: %v", r.Cfg.SchemaConfig.SourceSchema, t, err)
+			}
+			var (
+				columnNameS []string
+				columnTypeS []string
+			)
+			for _, c := range sourceColumns {
+				columnNameS = append(columnNameS, c["COLUMN_NAME"])
+				columnTypeS = append(columnTypeS, c["DATA_TYPE"])
+			}
+
+			limiter := r.Throttler.Table(t, r.GetCustomMigrateConfig()[common.StringUPPER(t)])
+
+			g1 := &errgroup.Group{}
+			g1.SetLimit(r.Cfg.FullConfig.SQLThreads)
+			for _, fullMeta := range waitFullMetas {
+				m := fullMeta
+				g1.Go(func() error {
+					// 数据写入，瞬时错误按指数退避重试，重试以及失败 chunk 写入前清理目标端 chunk 范围数据
+					isClean := safeMode || strings.EqualFold(m.TaskStatus, common.TaskStatusFailed)
+					err := migrate.Retry(r.Ctx, r.Cfg.FullConfig.RetryTimes, time.Duration(r.Cfg.FullConfig.RetryBackoff)*time.Second, m.String(), func() error {
+						if isClean {
+							if errc := r.CleanTargetChunk(m, columnNameS, columnNameT); errc != nil {
+								return errc
+							}
+						}
+						isClean = true
+						return public.IMigrate(NewRows(r.Ctx, m, r.SchemaNameS, t, r.Mysql, r.Oracle,
+							common.MigrateMYSQLCompatibleCharsetStringConvertMapping[common.StringUPPER(r.Cfg.MySQLConfig.Charset)],
+							common.MigrateOracleCharsetStringConvertMapping[common.StringUPPER(r.Cfg.OracleConfig.Charset)],
+							r.Cfg.FullConfig.ApplyThreads, r.Cfg.AppConfig.InsertBatchSize, r.Cfg.AppConfig.InsertBatchBytes,
+							columnTypeS, columnNameT, columnTypeT, limiter, r.Transformer.Table(t)))
+					})
+
+					if err != nil {
+						var (
+							errorSQL string
+							errMsg   string
+						)
+						errMsg = err.Error()
+
+						if re.MatchString(errMsg) {
+							errorSQL = re.FindStringSubmatch(errMsg)[0]
+							errMsg = re.ReplaceAllString(errMsg, "sql execute")
+						}
+
+						// record error, skip error
+						errf := meta.NewCommonModel(r.MetaDB).UpdateFullSyncMetaChunkAndCreateChunkErrorDetail(r.Ctx, &meta.FullSyncMeta{
+							DBTypeS:      m.DBTypeS,
+							DBTypeT:      m.DBTypeT,
+							SchemaNameS:  m.SchemaNameS,
+							TableNameS:   m.TableNameS,
+							TaskMode:     m.TaskMode,
+							ChunkDetailS: m.ChunkDetailS,
+						}, map[string]interface{}{
+							"TaskStatus": common.TaskStatusFailed,
+						}, &meta.ChunkErrorDetail{
+							DBTypeS:      m.DBTypeS,
+							DBTypeT:      m.DBTypeT,
+							SchemaNameS:  m.SchemaNameS,
+							TableNameS:   m.TableNameS,
+							SchemaNameT:  m.SchemaNameT,
+							TableNameT:   m.TableNameT,
+							TaskMode:     m.TaskMode,
+							ChunkDetailS: m.ChunkDetailS,
+							InfoDetail:   m.String(),
+							ErrorSQL:     errorSQL,
+							ErrorDetail:  errMsg,
+							ErrorClass:   migrate.ClassifyError(err),
+						})
+						if errf != nil {
+							return fmt.Errorf("get mysql schema table [%v] IMigrate failed: %v", m.String(), errf)
+						}
+						return nil
+					}
+
+					if errf := meta.NewFullSyncMetaModel(r.MetaDB).UpdateFullSyncMetaChunk(r.Ctx, &meta.FullSyncMeta{
+						DBTypeS:      m.DBTypeS,
+						DBTypeT:      m.DBTypeT,
+						SchemaNameS:  m.SchemaNameS,
+						TableNameS:   m.TableNameS,
+						TaskMode:     m.TaskMode,
+						ChunkDetailS: m.ChunkDetailS,
+					}, map[string]interface{}{
+						"TaskStatus": common.TaskStatusSuccess,
+					}); errf != nil {
+						return fmt.Errorf("get mysql schema table [%v] Success failed: %v", m.String(), errf)
+					}
+					return nil
+				})
+			}
+
+			if err = g1.Wait(); err != nil {
+				return err
+			}
+
+			// 清理元数据记录
+			// 更新 wait_sync_meta 记录
+			failedChunkTotalErrs, err := meta.NewFullSyncMetaModel(r.MetaDB).CountsErrorFullSyncMeta(r.Ctx, &meta.FullSyncMeta{
+				DBTypeS:     r.Cfg.DBTypeS,
+				DBTypeT:     r.Cfg.DBTypeT,
+				SchemaNameS: common.StringUPPER(r.Cfg.SchemaConfig.SourceSchema),
+				TableNameS:  t,
+				TaskMode:    r.Cfg.TaskMode,
+				TaskStatus:  common.TaskStatusFailed,
+			})
+			if err != nil {
+				return fmt.Errorf("get meta table [full_sync_meta] counts failed, error: %v", err)
+			}
+			successChunkFullMeta, err := meta.NewFullSyncMetaModel(r.MetaDB).DetailFullSyncMeta(r.Ctx, &meta.FullSyncMeta{
+				DBTypeS:     r.Cfg.DBTypeS,
+				DBTypeT:     r.Cfg.DBTypeT,
+				SchemaNameS: common.StringUPPER(r.Cfg.SchemaConfig.SourceSchema),
+				TableNameS:  t,
+				TaskMode:    r.Cfg.TaskMode,
+				TaskStatus:  common.TaskStatusSuccess,
+			})
+			if err != nil {
+				return err
+			}
+
+			// 不存在错误，清理 full_sync_meta 记录, 更新 wait_sync_meta 记录
+			if failedChunkTotalErrs == 0 {
+				err = meta.NewCommonModel(r.MetaDB).DeleteTableFullSyncMetaAndUpdateWaitSyncMeta(r.Ctx,
+					&meta.FullSyncMeta{
+						DBTypeS:     r.Cfg.DBTypeS,
+						DBTypeT:     r.Cfg.DBTypeT,
+						SchemaNameS: common.StringUPPER(r.Cfg.SchemaConfig.SourceSchema),
+						TableNameS:  t,
+						TaskMode:    r.Cfg.TaskMode,
+					}, &meta.WaitSyncMeta{
+						DBTypeS:          r.Cfg.DBTypeS,
+						DBTypeT:          r.Cfg.DBTypeT,
+						SchemaNameS:      common.StringUPPER(r.Cfg.SchemaConfig.SourceSchema),
+						TableNameS:       t,
+						TaskMode:         r.Cfg.TaskMode,
+						TaskStatus:       common.TaskStatusSuccess,
+						ChunkSuccessNums: int64(len(successChunkFullMeta)),
+						ChunkFailedNums:  0,
+					})
+				if err != nil {
+					return err
+				}
+				zap.L().Info("full single table mysql to oracle finished",
+					zap.String("schema", r.Cfg.SchemaConfig.SourceSchema),
+					zap.String("table", t),
+					zap.String("cost", time.Now().Sub(startTime).String()))
+			} else {
+				// 若存在错误，修改表状态，skip 清理，统一忽略，最后显示
+				err = meta.NewWaitSyncMetaModel(r.MetaDB).UpdateWaitSyncMeta(r.Ctx, &meta.WaitSyncMeta{
+					DBTypeS:     r.Cfg.DBTypeS,
+					DBTypeT:     r.Cfg.DBTypeT,
+					SchemaNameS: common.StringUPPER(r.Cfg.SchemaConfig.SourceSchema),
+					TableNameS:  t,
+					TaskMode:    r.Cfg.TaskMode,
+				}, map[string]interface{}{
+					"TaskStatus":       common.TaskStatusFailed,
+					"ChunkSuccessNums": int64(len(successChunkFullMeta)),
+					"ChunkFailedNums":  failedChunkTotalErrs,
+				})
+				if err != nil {
+					return err
+				}
+				zap.L().Warn("update mysql [wait_sync_meta] meta",
+					zap.String("schema", r.Cfg.SchemaConfig.SourceSchema),
+					zap.String("table", t),
+					zap.String("mode", r.Cfg.TaskMode),
+					zap.String("updated", "table exist error, skip"),
+					zap.String("cost", time.Now().Sub(startTime).String()))
+			}
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	zap.L().Info("source schema all table data loader finished",
+		zap.String("schema", r.Cfg.SchemaConfig.SourceSchema),
+		zap.Int("table totals", len(fullPartTables)),
+		zap.String("cost", time.Now().Sub(taskTime).String()))
+	return nil
+}
+
+func (r *Migrate) FullWaitSyncTable(fullWaitTables []string) error {
+	err := r.InitWaitSyncTableChunk(fullWaitTables)
+	if err != nil {
+		return err
+	}
+	err = r.FullPartSyncTable(fullWaitTables, false)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *Migrate) InitWaitSyncTableChunk(fullWaitTables []string) error {
+	startTask := time.Now()
+	rule, err := r.newChunkRule()
+	if err != nil {
+		return err
+	}
+
+	g := &errgroup.Group{}
+	g.SetLimit(r.Cfg.FullConfig.TaskThreads)
+
+	for _, table := range fullWaitTables {
+		t := table
+		g.Go(func() error {
+			startTime := time.Now()
+			chunk, err := r.splitTableChunk(t, rule)
+			if err != nil {
+				return err
+			}
+
+			// 元数据库信息 batch 写入
+			err = meta.NewFullSyncMetaModel(r.MetaDB).BatchCreateFullSyncMeta(r.Ctx, chunk.FullMetas, r.Cfg.AppConfig.InsertBatchSize)
+			if err != nil {
+				return err
+			}
+
+			// 更新 wait_sync_meta
+			err = meta.NewWaitSyncMetaModel(r.MetaDB).UpdateWaitSyncMeta(r.Ctx, &meta.WaitSyncMeta{
+				DBTypeS:     r.Cfg.DBTypeS,
+				DBTypeT:     r.Cfg.DBTypeT,
+				SchemaNameS: common.StringUPPER(r.Cfg.SchemaConfig.SourceSchema),
+				TableNameS:  t,
+				TaskMode:    r.Cfg.TaskMode,
+			}, map[string]interface{}{
+				"TableNumRows":     uint64(chunk.TableRows),
+				"GlobalScnS":       rule.globalSCN,
+				"ConsistentRead":   rule.isConsistentRead,
+				"ChunkTotalNums":   len(chunk.FullMetas),
+				"ChunkSuccessNums": 0,
+				"ChunkFailedNums":  0,
+				"IsPartition":      chunk.IsPartition,
+			})
+			if err != nil {
+				return err
+			}
+
+			endTime := time.Now()
+			zap.L().Info("init source single table wait_sync_meta and full_sync_meta finished",
+				zap.String("schema", r.Cfg.SchemaConfig.SourceSchema),
+				zap.String("table", t),
+				zap.Int("chunks", len(chunk.FullMetas)),
+				zap.String("cost", endTime.Sub(startTime).String()))
+			return nil
+		})
+	}
+
+	if err = g.Wait(); err != nil {
+		return err
+	}
+
+	zap.L().Info("init source schema table wait_sync_meta and full_sync_meta finished",
+		zap.String("schema", r.Cfg.SchemaConfig.SourceSchema),
+		zap.String("cost", time.Now().Sub(startTask).String()))
+	return nil
+}
+
+// chunkRule 表 chunk 切分公共规则，全量元数据初始化以及 dry-run 执行计划共用
+type chunkRule struct {
+	tableNameRule    map[string]string
+	tableMigrateRule map[string]config.MigrateConfig
+	partitionTables  []string
+	globalSCN        uint64
+	isConsistentRead string
+}
+
+// tableChunk 单表 chunk 切分结果
+type tableChunk struct {
+	TableNameT    string
+	TableRows     int
+	IsPartition   string
+	ChunkStrategy string
+	// 不存在切分字段或者统计信息数据行数小于 chunk-size，单 chunk 全表扫
+	FullScan  bool
+	FullMetas []meta.FullSyncMeta
+}
+
+func (r *Migrate) newChunkRule() (*chunkRule, error) {
+	// 获取自定义库表名规则
+	tableNameRule, err := r.GetTableNameRule()
+	if err != nil {
+		return nil, err
+	}
+
+	partitionTables, err := r.Mysql.GetMySQLPartitionTable(r.SchemaNameS)
+	if err != nil {
+		return nil, err
+	}
+
+	// MySQL 不支持一致性读
+	isConsistentRead := "NO"
+
+	return &chunkRule{
+		tableNameRule: tableNameRule,
+		// 获取自定义库表迁移配置
+		tableMigrateRule: r.GetCustomMigrateConfig(),
+		partitionTables:  partitionTables,
+		globalSCN:        common.TaskTableDefaultSourceGlobalSCN,
+		isConsistentRead: isConsistentRead,
+	}, nil
+}
+
+// splitTableChunk 基于统计信息以及切分策略生成单表 full_sync_meta chunk，不写入元数据库
+func (r *Migrate) splitTableChunk(t string, rule *chunkRule) (*tableChunk, error) {
+	// 库名、表名规则，以目标端实际表名为准
+	targetTableName, _, err := r.GetTargetTableColumn(t, rule.tableNameRule)
+	if err != nil {
+		return nil, err
+	}
+
+	// 自定义迁移配置
+	var (
+		sqlHint       string
+		wherePrefix   string
+		whereRange    string
+		enableSplit   bool
+		chunkStrategy string
+		chunkColumn   string
+	)
+	if val, ok := rule.tableMigrateRule[common.StringUPPER(t)]; ok {
+		sqlHint = val.SQLHint
+		wherePrefix = val.Range
+		enableSplit = val.EnableSplit
+		chunkStrategy = val.ChunkStrategy
+		chunkColumn = val.ChunkColumn
+	} else {
+		sqlHint = r.Cfg.FullConfig.SQLHint
+	}
+
+	sourceColumns, err := r.Mysql.GetMySQLTableColumn(r.SchemaNameS, t)
+	if err != nil {
+		return nil, err
+	}
+	sourceColumnInfo := public.GenMySQLTableSelectColumn(sourceColumns, r.Mapper.Table(t))
+
+	chunk := &tableChunk{TableNameT: targetTableName}
+	if common.IsContainString(rule.partitionTables, t) {
+		chunk.IsPartition = "YES"
+	} else {
+		chunk.IsPartition = "NO"
+	}
+
+	chunk.TableRows, err = r.Mysql.GetMySQLTableRowsByStatistics(r.SchemaNameS, t)
+	if err != nil {
+		return nil, err
+	}
+
+	// 基于主键或者唯一索引整型字段范围切分，不存在切分字段或者统计信息数据行数小于 chunk-size，直接全表扫
+	var chunkRes []map[string]string
+	switch {
+	case strings.EqualFold(chunkStrategy, "") || strings.EqualFold(chunkStrategy, common.ChunkStrategyPK):
+		chunk.ChunkStrategy = common.ChunkStrategyPK
+		columnName, err := r.Mysql.GetMySQLTableChunkColumn(r.SchemaNameS, t, chunkColumn)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.EqualFold(columnName, "") {
+			chunkRes, err = r.Mysql.GetMySQLTableChunksByRange(r.SchemaNameS, t, columnName, chunk.TableRows, r.Cfg.FullConfig.ChunkSize)
+			if err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("mysql table [%s.%s] migrate config chunk-strategy [%s] isn't support, support [pk]", r.Cfg.SchemaConfig.SourceSchema, t, chunkStrategy)
+	}
+	if len(chunkRes) == 0 {
+		chunk.FullScan = true
+		chunkRes = append(chunkRes, map[string]string{"CMD": `1 = 1`})
+	}
+
+	for _, res := range chunkRes {
+		switch {
+		case enableSplit && !strings.EqualFold(wherePrefix, ""):
+			whereRange = common.StringsBuilder(res["CMD"], ` AND `, wherePrefix)
+		default:
+			whereRange = res["CMD"]
+		}
+		chunk.FullMetas = append(chunk.FullMetas, meta.FullSyncMeta{
+			DBTypeS:        r.Cfg.DBTypeS,
+			DBTypeT:        r.Cfg.DBTypeT,
+			SchemaNameS:    common.StringUPPER(r.Cfg.SchemaConfig.SourceSchema),
+			TableNameS:     t,
+			SchemaNameT:    common.StringUPPER(r.Cfg.SchemaConfig.TargetSchema),
+			TableNameT:     targetTableName,
+			GlobalScnS:     rule.globalSCN,
+			ConsistentRead: rule.isConsistentRead,
+			SQLHint:        sqlHint,
+			ColumnDetailS:  sourceColumnInfo,
+			ChunkDetailS:   whereRange,
+			TaskMode:       r.Cfg.TaskMode,
+			TaskStatus:     common.TaskStatusWaiting,
+		})
+	}
+	return chunk, nil
+}
+
+// ResetRetryableFailedTable 失败表 chunk 错误均为瞬时错误（RETRYABLE），清理 [chunk_error_detail] 记录并重置表状态 RUNNING，重新运行时断点续传失败 chunk
+func (r *Migrate) ResetRetryableFailedTable() error {
+	failedTables, err := meta.NewWaitSyncMetaModel(r.MetaDB).DetailWaitSyncMeta(r.Ctx, &meta.WaitSyncMeta{
+		DBTypeS:     r.Cfg.DBTypeS,
+		DBTypeT:     r.Cfg.DBTypeT,
+		SchemaNameS: common.StringUPPER(r.Cfg.SchemaConfig.SourceSchema),
+		TaskMode:    r.Cfg.TaskMode,
+		TaskStatus:  common.TaskStatusFailed,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, t := range failedTables {
+		errDetails, err := meta.NewChunkErrorDetailModel(r.MetaDB).DetailChunkErrorDetailBySchemaTable(r.Ctx, &meta.ChunkErrorDetail{
+			DBTypeS:     t.DBTypeS,
+			DBTypeT:     t.DBTypeT,
+			SchemaNameS: t.SchemaNameS,
+			TableNameS:  t.TableNameS,
+			TaskMode:    t.TaskMode,
+		})
+		if err != nil {
+			return err
+		}
+		if len(errDetails) == 0 {
+			continue
+		}
+
+		isRetryable := true
+		for _, e := range errDetails {
+			if !strings.EqualFold(e.ErrorClass, common.ErrorClassRetryable) {
+				isRetryable = false
+				break
+			}
+		}
+		if !isRetryable {
+			continue
+		}
+
+		err = meta.NewChunkErrorDetailModel(r.MetaDB).DeleteChunkErrorDetailBySchemaTable(r.Ctx, &meta.ChunkErrorDetail{
+			DBTypeS:     t.DBTypeS,
+			DBTypeT:     t.DBTypeT,
+			SchemaNameS: t.SchemaNameS,
+			TableNameS:  t.TableNameS,
+			TaskMode:    t.TaskMode,
+		})
+		if err != nil {
+			return err
+		}
+		err = meta.NewWaitSyncMetaModel(r.MetaDB).UpdateWaitSyncMeta(r.Ctx, &meta.WaitSyncMeta{
+			DBTypeS:     t.DBTypeS,
+			DBTypeT:     t.DBTypeT,
+			SchemaNameS: t.SchemaNameS,
+			TableNameS:  t.TableNameS,
+			TaskMode:    t.TaskMode,
+		}, map[string]interface{}{
+			"TaskStatus": common.TaskStatusRunning,
+		})
+		if err != nil {
+			return err
+		}
+		zap.L().Warn("reset failed table with retryable chunk error",
+			zap.String("schema", t.SchemaNameS),
+			zap.String("table", t.TableNameS),
+			zap.String("mode", t.TaskMode),
+			zap.Int("failed chunks", len(errDetails)))
+	}
+	return nil
+}
+
+func (r *Migrate) GetCustomMigrateConfig() map[string]config.MigrateConfig {
+	tableMigrateMap := make(map[string]config.MigrateConfig)
+	for _, t := range r.Cfg.SchemaConfig.MigrateConfig {
+		tableMigrateMap[common.StringUPPER(t.SourceTable)] = t
+	}
+	return tableMigrateMap
+}
+
+func (r *Migrate) GetTableNameRule() (map[string]string, error) {
+	// 获取表名自定义规则
+	tableNameRules, err := meta.NewTableNameRuleModel(r.MetaDB).DetailTableNameRule(r.Ctx, &meta.TableNameRule{
+		DBTypeS:     r.Cfg.DBTypeS,
+		DBTypeT:     r.Cfg.DBTypeT,
+		SchemaNameS: r.Cfg.SchemaConfig.SourceSchema,
+		SchemaNameT: r.Cfg.SchemaConfig.TargetSchema,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// 回退链路，未配置表名规则的表按正向迁移 Oracle -> MySQL 表名规则反向映射
+	reverseNameRules, err := meta.NewTableNameRuleModel(r.MetaDB).DetailTableNameRule(r.Ctx, &meta.TableNameRule{
+		DBTypeS:     common.DatabaseTypeOracle,
+		DBTypeT:     r.Cfg.DBTypeS,
+		SchemaNameS: r.Cfg.SchemaConfig.TargetSchema,
+		SchemaNameT: r.Cfg.SchemaConfig.SourceSchema,
+	})
+	if err != nil {
+		return nil, err
+	}
+	tableNameRuleMap := make(map[string]string)
+
+	for _, tr := range reverseNameRules {
+		tableNameRuleMap[common.StringUPPER(tr.TableNameT)] = common.StringUPPER(tr.TableNameS)
+	}
+	if len(tableNameRules) > 0 {
+		for _, tr := range tableNameRules {
+			tableNameRuleMap[common.StringUPPER(tr.TableNameS)] = common.StringUPPER(tr.TableNameT)
+		}
+	}
+	return tableNameRuleMap, nil
+}
+
+// CleanTargetChunk 清理目标端 chunk 范围数据，chunk 范围条件无法转换目标端条件时报错，需重新迁移表
+func (r *Migrate) CleanTargetChunk(m meta.FullSyncMeta, columnNameS, columnNameT []string) error {
+	whereT, ok := public.GenOracleChunkWhere(m.ChunkDetailS, columnNameS, columnNameT)
+	if !ok {
+		return fmt.Errorf("mysql table [%s.%s] chunk [%s] can't clean target table data before rewrite, please restart table by [-mode repair -repair-mode full -repair-action truncate -repair-table %s]",
+			m.SchemaNameS, m.TableNameS, m.ChunkDetailS, m.TableNameS)
+	}
+	deleteSQL := common.StringsBuilder(`DELETE FROM "`, m.SchemaNameT, `"."`, m.TableNameT, `" WHERE `, whereT)
+	if err := r.Oracle.BatchWriteOracleTable(deleteSQL); err != nil {
+		return fmt.Errorf("target sql [%v] execute failed: %w", deleteSQL, err)
+	}
+	return nil
+}
+
+// GetTargetTableColumn 获取源端表对应目标端实际表名以及字段，表名规则优先，默认同名（不区分大小写）
+func (r *Migrate) GetTargetTableColumn(tableName string, tableNameRule map[string]string) (string, []map[string]string, error) {
+	targetTableName := tableName
+	if val, ok := tableNameRule[common.StringUPPER(tableName)]; ok {
+		targetTableName = val
+	}
+	columns, err := r.Oracle.GetOracleTableColumnDataType(r.Cfg.SchemaConfig.TargetSchema, targetTableName)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(columns) == 0 {
+		return "", nil, fmt.Errorf("mysql table [%s.%s] target table [%s.%s] isn't exist, please reverse table structure firstly",
+			r.Cfg.SchemaConfig.SourceSchema, tableName, r.Cfg.SchemaConfig.TargetSchema, targetTableName)
+	}
+	return columns[0]["TABLE_NAME"], columns, nil
+}
@@ -0,0 +1,322 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package m2o
+
+import (
+	"context"
+	"fmt"
+	"github.com/wentaojin/transferdb/common"
+	"github.com/wentaojin/transferdb/config"
+	"github.com/wentaojin/transferdb/database/meta"
+	"github.com/wentaojin/transferdb/mapping"
+	"github.com/wentaojin/transferdb/module/migrate/sql/mysql/public"
+	"github.com/wentaojin/transferdb/selector"
+	"github.com/wentaojin/transferdb/transform"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+	"strings"
+	"time"
+)
+
+// 单次读取增量消息上限
+const incrReadMaxMessages = 20000
+
+func NewIncr(ctx context.Context, cfg *config.Config) (*Migrate, error) {
+	if strings.EqualFold(cfg.AllConfig.IncrSourceDir, "") {
+		return nil, fmt.Errorf("mysql increment sync based on canal-json message files, config [all] incr-source-dir can't be null")
+	}
+	m, err := NewFuller(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := public.NewCanalJSONReader(cfg.AllConfig.IncrSourceDir)
+	if err != nil {
+		return nil, err
+	}
+	m.Reader = reader
+	return m, nil
+}
+
+func (r *Migrate) Incr() error {
+	zap.L().Info("mysql to oracle increment sync table data start",
+		zap.String("schema", r.Cfg.SchemaConfig.SourceSchema),
+		zap.String("canal-json dir", r.Cfg.AllConfig.IncrSourceDir))
+
+	// 数据库字符集
+	// AMERICAN_AMERICA.AL32UTF8
+	charset, err := r.Oracle.GetOracleDBCharacterSet()
+	if err != nil {
+		return err
+	}
+	targetDBCharset := strings.Split(charset, ".")[1]
+	if !strings.EqualFold(r.Cfg.OracleConfig.Charset, targetDBCharset) {
+		return fmt.Errorf("oracle charset [%v] and oracle config charset [%v] aren't equal, please adjust oracle config charset", targetDBCharset, r.Cfg.OracleConfig.Charset)
+	}
+	if _, ok := common.MigrateOracleCharsetStringConvertMapping[common.StringUPPER(r.Cfg.OracleConfig.Charset)]; !ok {
+		return fmt.Errorf("oracle current charset [%v] isn't support, support charset [%v]", r.Cfg.OracleConfig.Charset, common.MigrateOracleCharsetStringConvertMapping)
+	}
+	targetDBCharset = common.MigrateOracleCharsetStringConvertMapping[common.StringUPPER(r.Cfg.OracleConfig.Charset)]
+
+	// 获取配置文件待同步表列表
+	exporters, err := selector.SelectMySQLTable(r.Cfg, r.Mysql)
+	if err != nil {
+		return err
+	}
+
+	// 字段转换（脱敏）规则，增量与全量使用相同规则
+	transformer, err := transform.NewTransformer(r.Ctx, r.MetaDB, r.Cfg.DBTypeS, r.Cfg.DBTypeT, r.Cfg.SchemaConfig.SourceSchema)
+	if err != nil {
+		return err
+	}
+	r.Transformer = transformer
+
+	// 字段映射规则，增量变更消息仅包含源端字段值，EXPR 规则无法计算，需使用字段转换规则 EXPR 替代
+	mapper, err := mapping.NewMapper(r.Ctx, r.MetaDB, r.Cfg.DBTypeS, r.Cfg.DBTypeT, r.Cfg.SchemaConfig.SourceSchema)
+	if err != nil {
+		return err
+	}
+	if exprTables := common.FilterIntersectionStringItems(mapper.ExprTables(), exporters); len(exprTables) > 0 {
+		return fmt.Errorf("mysql schema [%s] tables %v column mapping rule [%s] isn't support increment sync, please use column transform rule [%s] instead or exclude tables",
+			r.Cfg.SchemaConfig.SourceSchema, exprTables, common.MappingRuleExpr, common.TransformRuleExpr)
+	}
+	r.Mapper = mapper
+
+	// 失败表 chunk 错误均为瞬时错误，自动重置断点续传
+	if err = r.ResetRetryableFailedTable(); err != nil {
+		return err
+	}
+
+	// 判断 [wait_sync_meta] 是否存在错误记录，是否可进行 ALL
+	errTotals, err := meta.NewWaitSyncMetaModel(r.MetaDB).CountsErrWaitSyncMetaBySchema(r.Ctx, &meta.WaitSyncMeta{
+		DBTypeS:     r.Cfg.DBTypeS,
+		DBTypeT:     r.Cfg.DBTypeT,
+		SchemaNameS: common.StringUPPER(r.Cfg.SchemaConfig.SourceSchema),
+		TaskMode:    r.Cfg.TaskMode,
+		TaskStatus:  common.TaskStatusFailed,
+	})
+	if errTotals > 0 || err != nil {
+		return fmt.Errorf(`all schema [%s] mode [%s] table task failed: %v, meta table [wait_sync_meta] exist failed error, please: firstly check failed table by [-mode repair -repair-mode all -repair-action list]; secondly if need resume, reset failed table by [-mode repair -repair-mode all -repair-action reset], or restart table by [-repair-action truncate -repair-table ${table}], or mark table done by [-repair-action done -repair-table ${table}]; finally rerunning`, strings.ToUpper(r.Cfg.SchemaConfig.SourceSchema), r.Cfg.TaskMode, err)
+	}
+
+	// 全量数据导出导入，初始化全量元数据表以及导入完成初始化增量元数据表
+	var (
+		incrExistTableList, incrIsNotExistTableList []string
+	)
+	for _, tbl := range exporters {
+		counts, err := meta.NewIncrSyncMetaModel(r.MetaDB).CountsIncrSyncMetaBySchemaTable(r.Ctx, &meta.IncrSyncMeta{
+			DBTypeS:     r.Cfg.DBTypeS,
+			DBTypeT:     r.Cfg.DBTypeT,
+			SchemaNameS: common.StringUPPER(r.Cfg.SchemaConfig.SourceSchema),
+			TableNameS:  tbl,
+		})
+		if err != nil {
+			return err
+		}
+		// 表不存在或者表数异常
+		if counts == 0 || counts > 1 {
+			incrIsNotExistTableList = append(incrIsNotExistTableList, tbl)
+		}
+		if counts == 1 {
+			incrExistTableList = append(incrExistTableList, tbl)
+		}
+	}
+
+	// 如果下游数据库增量元数据表 incr_sync_meta 存在迁移表记录
+	if len(incrExistTableList) > 0 {
+		// 配置文件获取表列表等于元数据库表列表，直接增量数据同步
+		if len(incrExistTableList) == len(exporters) {
+			// 根据 wait_sync_meta 数据记录判断表全量是否完成
+			var panicTables []string
+			for _, t := range exporters {
+				waitSyncMetas, err := meta.NewWaitSyncMetaModel(r.MetaDB).DetailWaitSyncMeta(r.Ctx, &meta.WaitSyncMeta{
+					DBTypeS:     r.Cfg.DBTypeS,
+					DBTypeT:     r.Cfg.DBTypeT,
+					SchemaNameS: common.StringUPPER(r.Cfg.SchemaConfig.SourceSchema),
+					TableNameS:  t,
+					TaskMode:    r.Cfg.TaskMode,
+					TaskStatus:  common.TaskStatusSuccess,
+				})
+				if err != nil {
+					return err
+				}
+
+				// 不存在表记录或者表记录超过多行
+				if len(waitSyncMetas) == 0 || len(waitSyncMetas) > 1 {
+					panicTables = append(panicTables, t)
+				}
+
+				// 存在表记录但是迁移总数与成功总数不相等
+				if (len(waitSyncMetas) == 1) && (waitSyncMetas[0].ChunkTotalNums != waitSyncMetas[0].ChunkSuccessNums) {
+					panicTables = append(panicTables, t)
+				}
+			}
+
+			if len(panicTables) != 0 {
+				return fmt.Errorf("table list %s can't incremently sync, because table increment sync meta record is exist and full meta sync isn't finished", panicTables)
+			}
+			// 增量数据同步
+			for range time.Tick(300 * time.Millisecond) {
+				if err := r.syncTableIncrRecord(targetDBCharset); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		// 配置文件获取的表列表不等于 increment_sync_meta 表列表数，不能直接增量同步，需要手工调整
+		return fmt.Errorf("there is a migration table record for increment_sync_meta, but the configuration table list is not equal to the number of increment_sync_meta table lists, and it cannot be directly incrementally synchronized, please manually adjust to a list of meta-database tables [%v]", incrExistTableList)
+	}
+
+	// 如果下游数据库增量元数据表 incr_sync_meta 不存在任何记录，说明未进行过数据同步，则进行全量 + 增量数据同步
+	if len(incrExistTableList) == 0 && len(incrIsNotExistTableList) == len(exporters) {
+		// 全量同步
+		err = r.Full()
+		if err != nil {
+			return err
+		}
+
+		// 全量任务结束，写入增量起始位点
+		// MySQL 不支持一致性读，起始位点为 0，增量从变更消息目录起始位置重放，依赖增量写入幂等
+		tableMetas, err := meta.NewWaitSyncMetaModel(r.MetaDB).DetailWaitSyncMeta(r.Ctx, &meta.WaitSyncMeta{
+			DBTypeS:     r.Cfg.DBTypeS,
+			DBTypeT:     r.Cfg.DBTypeT,
+			SchemaNameS: common.StringUPPER(r.Cfg.SchemaConfig.SourceSchema),
+			TaskMode:    r.Cfg.TaskMode,
+			TaskStatus:  common.TaskStatusSuccess})
+		if err != nil {
+			return err
+		}
+
+		// 获取自定义库表名规则
+		tableNameRule, err := r.GetTableNameRule()
+		if err != nil {
+			return err
+		}
+
+		var incrSyncMetas []meta.IncrSyncMeta
+		if len(tableMetas) > 0 {
+			for _, table := range tableMetas {
+				targetTableName, _, err := r.GetTargetTableColumn(table.TableNameS, tableNameRule)
+				if err != nil {
+					return err
+				}
+
+				incrSyncMetas = append(incrSyncMetas, meta.IncrSyncMeta{
+					DBTypeS:     r.Cfg.DBTypeS,
+					DBTypeT:     r.Cfg.DBTypeT,
+					GlobalScnS:  table.GlobalScnS,
+					SchemaNameS: common.StringUPPER(table.SchemaNameS),
+					TableNameS:  table.TableNameS,
+					SchemaNameT: common.StringUPPER(r.Cfg.SchemaConfig.TargetSchema),
+					TableNameT:  targetTableName,
+					TableScnS:   table.GlobalScnS,
+					IsPartition: table.IsPartition,
+				})
+			}
+
+			err = meta.NewIncrSyncMetaModel(r.MetaDB).BatchCreateIncrSyncMeta(
+				r.Ctx, incrSyncMetas, r.Cfg.AppConfig.InsertBatchSize)
+			if err != nil {
+				return err
+			}
+		}
+
+		// 增量数据同步
+		for range time.Tick(300 * time.Millisecond) {
+			if err = r.syncTableIncrRecord(targetDBCharset); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("increment sync taskflow condition isn't match, can't sync")
+}
+
+func (r *Migrate) syncTableIncrRecord(targetDBCharset string) error {
+	startTime := time.Now()
+
+	// 获取增量元数据表内所需同步表信息
+	incrSyncMetas, err := meta.NewIncrSyncMetaModel(r.MetaDB).DetailIncrSyncMetaBySchema(r.Ctx, &meta.IncrSyncMeta{
+		DBTypeS:     r.Cfg.DBTypeS,
+		DBTypeT:     r.Cfg.DBTypeT,
+		SchemaNameS: r.Cfg.SchemaConfig.SourceSchema,
+	})
+	if err != nil {
+		return err
+	}
+	if len(incrSyncMetas) == 0 {
+		return fmt.Errorf("mysql increment mete table [incr_sync_meta] can't null")
+	}
+	incrSyncMetaMap := make(map[string]meta.IncrSyncMeta)
+	for _, m := range incrSyncMetas {
+		incrSyncMetaMap[common.StringUPPER(m.TableNameS)] = m
+	}
+
+	// 捕获数据
+	messages, offsets, err := r.Reader.Read(incrReadMaxMessages)
+	if err != nil {
+		return err
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+
+	// 按表级别筛选数据，跳过小于表同步位点的消息，等于位点的消息重放
+	tableMessages := make(map[string][]public.CanalJSONMessage)
+	for _, msg := range messages {
+		if !strings.EqualFold(msg.Database, r.Cfg.SchemaConfig.SourceSchema) {
+			continue
+		}
+		m, ok := incrSyncMetaMap[common.StringUPPER(msg.Table)]
+		if !ok {
+			continue
+		}
+		if msg.IsDDL {
+			zap.L().Warn("increment table ddl isn't support sync, please manual adjust target table",
+				zap.String("schema", msg.Database),
+				zap.String("table", msg.Table),
+				zap.String("ddl", msg.SQL))
+			continue
+		}
+		if msg.Position() < m.TableScnS {
+			continue
+		}
+		tableMessages[common.StringUPPER(msg.Table)] = append(tableMessages[common.StringUPPER(msg.Table)], msg)
+	}
+
+	g := &errgroup.Group{}
+	g.SetLimit(r.Cfg.AllConfig.ApplyThreads)
+	for tableName, msgs := range tableMessages {
+		m := incrSyncMetaMap[tableName]
+		tableMsgs := msgs
+		g.Go(func() error {
+			return r.applyIncrRecord(m, tableMsgs, targetDBCharset)
+		})
+	}
+	if err = g.Wait(); err != nil {
+		return err
+	}
+
+	// 消息应用完毕，更新文件读取偏移量
+	r.Reader.Commit(offsets)
+
+	zap.L().Info("increment table canal-json message applier finished",
+		zap.String("schema", r.Cfg.SchemaConfig.SourceSchema),
+		zap.Int("message counts", len(messages)),
+		zap.Int("table counts", len(tableMessages)),
+		zap.String("cost", time.Now().Sub(startTime).String()))
+	return nil
+}
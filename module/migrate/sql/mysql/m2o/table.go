@@ -0,0 +1,179 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package m2o
+
+import (
+	"context"
+	"fmt"
+	"github.com/wentaojin/transferdb/common"
+	"github.com/wentaojin/transferdb/database/meta"
+	"github.com/wentaojin/transferdb/database/mysql"
+	"github.com/wentaojin/transferdb/database/oracle"
+	"github.com/wentaojin/transferdb/module/migrate/sql/mysql/public"
+	"github.com/wentaojin/transferdb/throttle"
+	"github.com/wentaojin/transferdb/transform"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+	"strings"
+	"time"
+)
+
+type Rows struct {
+	Ctx             context.Context
+	SyncMeta        meta.FullSyncMeta
+	SchemaNameS     string
+	TableNameS      string
+	MySQL           *mysql.MySQL
+	Oracle          *oracle.Oracle
+	SourceDBCharset string
+	TargetDBCharset string
+	ApplyThreads    int
+	BatchSize       int
+	BatchBytes      int
+	ColumnTypeS     []string
+	ColumnNameT     []string
+	ColumnTypeT     []string
+	ReadChannel     chan [][]string
+	WriteChannel    chan []interface{}
+	Limiter         *throttle.TableLimiter
+	Transformer     *transform.TableTransformer
+}
+
+func NewRows(ctx context.Context, syncMeta meta.FullSyncMeta, schemaNameS, tableNameS string,
+	mysql *mysql.MySQL, oracle *oracle.Oracle, sourceDBCharset string, targetDBCharset string, applyThreads, batchSize, batchBytes int,
+	columnTypeS, columnNameT, columnTypeT []string, limiter *throttle.TableLimiter, transformer *transform.TableTransformer) *Rows {
+
+	readChannel := make(chan [][]string, common.ChannelBufferSize)
+	writeChannel := make(chan []interface{}, common.ChannelBufferSize)
+
+	return &Rows{
+		Ctx:             ctx,
+		SyncMeta:        syncMeta,
+		SchemaNameS:     schemaNameS,
+		TableNameS:      tableNameS,
+		MySQL:           mysql,
+		Oracle:          oracle,
+		SourceDBCharset: sourceDBCharset,
+		TargetDBCharset: targetDBCharset,
+		ApplyThreads:    applyThreads,
+		BatchSize:       batchSize,
+		BatchBytes:      batchBytes,
+		ColumnTypeS:     columnTypeS,
+		ColumnNameT:     columnNameT,
+		ColumnTypeT:     columnTypeT,
+		ReadChannel:     readChannel,
+		WriteChannel:    writeChannel,
+		Limiter:         limiter,
+		Transformer:     transformer,
+	}
+}
+
+func (t *Rows) ReadData() error {
+	startTime := time.Now()
+
+	var querySQL string
+	if strings.EqualFold(t.SyncMeta.SQLHint, "") {
+		querySQL = common.StringsBuilder(`SELECT `, t.SyncMeta.ColumnDetailS, " FROM `", t.SchemaNameS, "`.`", t.TableNameS, "` WHERE ", t.SyncMeta.ChunkDetailS)
+	} else {
+		querySQL = common.StringsBuilder(`SELECT `, t.SyncMeta.SQLHint, ` `, t.SyncMeta.ColumnDetailS, " FROM `", t.SchemaNameS, "`.`", t.TableNameS, "` WHERE ", t.SyncMeta.ChunkDetailS)
+	}
+
+	err := t.MySQL.GetMySQLTableRowsData(querySQL, t.BatchSize, t.BatchBytes, t.SourceDBCharset, t.TargetDBCharset, t.Limiter, t.Transformer, t.ReadChannel)
+	if err != nil {
+		// 通道关闭
+		close(t.ReadChannel)
+		return fmt.Errorf("source sql [%v] execute failed: %w", querySQL, err)
+	}
+
+	endTime := time.Now()
+	zap.L().Info("source schema table chunk rows extractor finished",
+		zap.String("schema", t.SyncMeta.SchemaNameS),
+		zap.String("table", t.SyncMeta.TableNameS),
+		zap.String("chunk", t.SyncMeta.ChunkDetailS),
+		zap.String("sql", querySQL),
+		zap.String("cost", endTime.Sub(startTime).String()))
+
+	// 通道关闭
+	close(t.ReadChannel)
+
+	return nil
+}
+
+func (t *Rows) ProcessData() error {
+	for dataC := range t.ReadChannel {
+		for _, r := range dataC {
+			if len(r) != len(t.ColumnTypeT) {
+				// 通道关闭
+				close(t.WriteChannel)
+
+				return fmt.Errorf("source schema table column counts vs data counts isn't match")
+			}
+		}
+
+		// 按字段转换数组绑定值
+		binds, err := public.GenOracleColumnBinds(t.ColumnTypeS, t.ColumnTypeT, dataC)
+		if err != nil {
+			// 通道关闭
+			close(t.WriteChannel)
+
+			return fmt.Errorf("source schema table rows strconv failed: %v", err)
+		}
+
+		// 数据输入
+		t.WriteChannel <- binds
+	}
+
+	// 通道关闭
+	close(t.WriteChannel)
+
+	return nil
+}
+
+func (t *Rows) ApplyData() error {
+	startTime := time.Now()
+
+	g := &errgroup.Group{}
+	g.SetLimit(t.ApplyThreads)
+
+	insertSQL := public.GenOracleInsertSQLStmt(
+		t.SyncMeta.SchemaNameT,
+		t.SyncMeta.TableNameT,
+		t.ColumnNameT,
+		t.ColumnTypeT)
+
+	for dataC := range t.WriteChannel {
+		binds := dataC
+		g.Go(func() error {
+			if err := t.Oracle.BatchWriteOracleTable(insertSQL, binds...); err != nil {
+				return fmt.Errorf("target sql [%v] execute failed: %w", insertSQL, err)
+			}
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	endTime := time.Now()
+	zap.L().Info("target schema table chunk data applier finished",
+		zap.String("schema", t.SyncMeta.SchemaNameT),
+		zap.String("table", t.SyncMeta.TableNameT),
+		zap.String("chunk", t.SyncMeta.ChunkDetailS),
+		zap.String("cost", endTime.Sub(startTime).String()))
+
+	return nil
+}
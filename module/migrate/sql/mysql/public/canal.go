@@ -0,0 +1,264 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package public
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	canalJSONTypeWidthRegexp = regexp.MustCompile(`\((\d+)\)`)
+	canalJSONTypeElemRegexp  = regexp.MustCompile(`'((?:[^']|'')*)'`)
+)
+
+// CanalJSONMessage TiCDC/Canal Canal-JSON 协议行变更消息
+// https://docs.pingcap.com/tidb/stable/ticdc-canal-json
+type CanalJSONMessage struct {
+	ID        int64                `json:"id"`
+	Database  string               `json:"database"`
+	Table     string               `json:"table"`
+	PKNames   []string             `json:"pkNames"`
+	IsDDL     bool                 `json:"isDdl"`
+	Type      string               `json:"type"`
+	ES        uint64               `json:"es"`
+	TS        uint64               `json:"ts"`
+	SQL       string               `json:"sql"`
+	MySQLType map[string]string    `json:"mysqlType"`
+	Data      []map[string]*string `json:"data"`
+	Old       []map[string]*string `json:"old"`
+	TiDB      *CanalJSONTiDB       `json:"_tidb"`
+}
+
+// CanalJSONTiDB TiCDC 扩展字段，commitTs 为事务提交 TSO
+type CanalJSONTiDB struct {
+	CommitTs uint64 `json:"commitTs"`
+}
+
+// Position 消息位点，TiCDC 基于事务提交 TSO，Canal 基于 binlog 执行时间（毫秒）
+// 同一位点可能对应多条消息，断点续传重放等于位点的消息，依赖增量写入幂等
+func (c *CanalJSONMessage) Position() uint64 {
+	if c.TiDB != nil && c.TiDB.CommitTs > 0 {
+		return c.TiDB.CommitTs
+	}
+	return c.ES
+}
+
+// Value 获取字段值，binlog 二进制数据按 ISO-8859-1 编码还原原始字节，BIT 还原二进制值
+// TiCDC ENUM/SET 输出下标以及位图，按字段定义还原元素值
+func (c *CanalJSONMessage) Value(row map[string]*string, columnName string) (string, bool) {
+	val, ok := row[columnName]
+	if !ok || val == nil {
+		return "", true
+	}
+
+	columnType := strings.ToLower(c.MySQLType[columnName])
+	switch {
+	case strings.HasPrefix(columnType, "bit"):
+		return canalJSONBitValue(columnType, *val), false
+	case strings.Contains(columnType, "blob") || strings.Contains(columnType, "binary"):
+		raw := make([]byte, 0, len(*val))
+		for _, r := range *val {
+			raw = append(raw, byte(r))
+		}
+		return string(raw), false
+	case c.TiDB != nil && strings.HasPrefix(columnType, "enum("):
+		return canalJSONEnumValue(c.MySQLType[columnName], *val), false
+	case c.TiDB != nil && strings.HasPrefix(columnType, "set("):
+		return canalJSONSetValue(c.MySQLType[columnName], *val), false
+	default:
+		return *val, false
+	}
+}
+
+// CanalJSONReader 读取目录内 Canal-JSON 变更文件（TiCDC storage sink 或者 Canal/Kafka 消息落盘），每行一条消息
+// 文件按路径排序读取，进程内记录文件已读取偏移量，进程重启基于元数据位点跳过已同步消息
+type CanalJSONReader struct {
+	Dir     string
+	Offsets map[string]int64
+}
+
+func NewCanalJSONReader(dir string) (*CanalJSONReader, error) {
+	fi, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("canal-json dir [%s] stat failed: %v", dir, err)
+	}
+	if !fi.IsDir() {
+		return nil, fmt.Errorf("canal-json dir [%s] isn't directory", dir)
+	}
+	return &CanalJSONReader{
+		Dir:     dir,
+		Offsets: make(map[string]int64),
+	}, nil
+}
+
+// Read 读取未读取的完整消息行，最多 maxMessages 条，未写完的行等待下次读取
+// 返回消息以及读取后文件偏移量，消息应用成功后 Commit 偏移量
+func (c *CanalJSONReader) Read(maxMessages int) ([]CanalJSONMessage, map[string]int64, error) {
+	var (
+		files    []string
+		messages []CanalJSONMessage
+	)
+	offsets := make(map[string]int64)
+
+	err := filepath.Walk(c.Dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && strings.EqualFold(filepath.Ext(path), ".json") {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return messages, offsets, fmt.Errorf("canal-json dir [%s] walk failed: %v", c.Dir, err)
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		if len(messages) >= maxMessages {
+			break
+		}
+		fi, err := os.Stat(file)
+		if err != nil {
+			return messages, offsets, fmt.Errorf("canal-json file [%s] stat failed: %v", file, err)
+		}
+		offset := c.Offsets[file]
+		if fi.Size() <= offset {
+			continue
+		}
+
+		msgs, newOffset, err := readCanalJSONFile(file, offset, maxMessages-len(messages))
+		if err != nil {
+			return messages, offsets, err
+		}
+		messages = append(messages, msgs...)
+		if newOffset > offset {
+			offsets[file] = newOffset
+		}
+	}
+	return messages, offsets, nil
+}
+
+// Commit 更新文件已读取偏移量
+func (c *CanalJSONReader) Commit(offsets map[string]int64) {
+	for file, offset := range offsets {
+		c.Offsets[file] = offset
+	}
+}
+
+func readCanalJSONFile(file string, offset int64, maxMessages int) ([]CanalJSONMessage, int64, error) {
+	var messages []CanalJSONMessage
+
+	f, err := os.Open(file)
+	if err != nil {
+		return messages, offset, fmt.Errorf("canal-json file [%s] open failed: %v", file, err)
+	}
+	defer f.Close()
+
+	if _, err = f.Seek(offset, io.SeekStart); err != nil {
+		return messages, offset, fmt.Errorf("canal-json file [%s] seek offset [%d] failed: %v", file, offset, err)
+	}
+
+	reader := bufio.NewReader(f)
+	for len(messages) < maxMessages {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			// 未写完的行
+			break
+		}
+		if err != nil {
+			return messages, offset, fmt.Errorf("canal-json file [%s] read failed: %v", file, err)
+		}
+		lineOffset := offset
+		offset += int64(len(line))
+
+		line = []byte(strings.TrimSpace(string(line)))
+		if len(line) == 0 {
+			continue
+		}
+		var msg CanalJSONMessage
+		if err = json.Unmarshal(line, &msg); err != nil {
+			return messages, lineOffset, fmt.Errorf("canal-json file [%s] offset [%d] message unmarshal failed: %v", file, lineOffset, err)
+		}
+		messages = append(messages, msg)
+	}
+	return messages, offset, nil
+}
+
+func canalJSONBitValue(columnType, value string) string {
+	if strings.EqualFold(value, "") {
+		return ""
+	}
+	n, ok := new(big.Int).SetString(value, 10)
+	if !ok {
+		return value
+	}
+	width := 1
+	if m := canalJSONTypeWidthRegexp.FindStringSubmatch(columnType); len(m) == 2 {
+		if w, err := strconv.Atoi(m[1]); err == nil {
+			width = w
+		}
+	}
+	raw := make([]byte, (width+7)/8)
+	if n.BitLen() > len(raw)*8 {
+		return string(n.Bytes())
+	}
+	return string(n.FillBytes(raw))
+}
+
+func canalJSONEnumValue(columnType, value string) string {
+	idx, err := strconv.Atoi(value)
+	if err != nil {
+		return value
+	}
+	elems := canalJSONTypeElems(columnType)
+	if idx < 1 || idx > len(elems) {
+		return ""
+	}
+	return elems[idx-1]
+}
+
+func canalJSONSetValue(columnType, value string) string {
+	bits, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return value
+	}
+	var values []string
+	for i, e := range canalJSONTypeElems(columnType) {
+		if bits&(1<<uint(i)) != 0 {
+			values = append(values, e)
+		}
+	}
+	return strings.Join(values, ",")
+}
+
+func canalJSONTypeElems(columnType string) []string {
+	var elems []string
+	for _, m := range canalJSONTypeElemRegexp.FindAllStringSubmatch(columnType, -1) {
+		elems = append(elems, strings.ReplaceAll(m[1], "''", "'"))
+	}
+	return elems
+}
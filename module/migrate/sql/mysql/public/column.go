@@ -0,0 +1,249 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package public
+
+import (
+	"fmt"
+	"github.com/godror/godror"
+	"github.com/wentaojin/transferdb/common"
+	"github.com/wentaojin/transferdb/database/mysql"
+	"github.com/wentaojin/transferdb/mapping"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// MySQL TIME 字段写入 Oracle DATE/TIMESTAMP 固定日期部分
+const OracleTimeColumnDatePrefix = "1970-01-01 "
+
+var chunkColumnRegexp = regexp.MustCompile("`([^`]+)`")
+
+// GenMySQLTableSelectColumn 源端字段查询，空间数据类型 WKT 文本输出
+// 字段映射规则排除字段不抽取，EXPR 字段按源端表达式计算并保持源端字段名别名
+func GenMySQLTableSelectColumn(columns []map[string]string, columnMapper *mapping.TableMapper) string {
+	var columnNames []string
+	for _, rowCol := range columns {
+		column := common.StringsBuilder("`", rowCol["COLUMN_NAME"], "`")
+		if columnMapper.IsExclude(rowCol["COLUMN_NAME"]) {
+			continue
+		}
+		if expr, ok := columnMapper.ColumnExpr(rowCol["COLUMN_NAME"]); ok {
+			columnNames = append(columnNames, common.StringsBuilder(`(`, expr, `) AS `, column))
+			continue
+		}
+		switch common.StringUPPER(rowCol["DATA_TYPE"]) {
+		case "GEOMETRY", "POINT", "LINESTRING", "POLYGON", "MULTIPOINT", "MULTILINESTRING", "MULTIPOLYGON", "GEOMETRYCOLLECTION":
+			columnNames = append(columnNames, common.StringsBuilder(`ST_AsText(`, column, `) AS `, column))
+		default:
+			columnNames = append(columnNames, column)
+		}
+	}
+	return strings.Join(columnNames, ",")
+}
+
+// GetOracleTableColumnMapping 源端字段按字段名（不区分大小写）匹配目标端字段，返回目标端字段名以及数据类型
+// 字段映射规则重命名字段按目标端字段名匹配，sourceColumns 需已过滤排除字段
+func GetOracleTableColumnMapping(sourceColumns, targetColumns []map[string]string, columnMapper *mapping.TableMapper) ([]string, []string, error) {
+	targetColumnMap := make(map[string]map[string]string)
+	for _, c := range targetColumns {
+		targetColumnMap[common.StringUPPER(c["COLUMN_NAME"])] = c
+	}
+
+	var (
+		columnNameT []string
+		columnTypeT []string
+	)
+	for _, c := range sourceColumns {
+		t, ok := targetColumnMap[common.StringUPPER(columnMapper.ColumnNameT(c["COLUMN_NAME"]))]
+		if !ok {
+			return nil, nil, fmt.Errorf("source column [%s] target column [%s] isn't exist in the target table", c["COLUMN_NAME"], columnMapper.ColumnNameT(c["COLUMN_NAME"]))
+		}
+		columnNameT = append(columnNameT, t["COLUMN_NAME"])
+		columnTypeT = append(columnTypeT, common.StringUPPER(t["DATA_TYPE"]))
+	}
+	return columnNameT, columnTypeT, nil
+}
+
+// GenOracleInsertSQLStmt 目标端 Oracle 数组绑定写入语句，时间字段基于固定格式转换，不受 NLS 参数影响
+func GenOracleInsertSQLStmt(schemaNameT, tableNameT string, columnNameT, columnTypeT []string) string {
+	var (
+		columnNames []string
+		bindVars    []string
+	)
+	for i, c := range columnNameT {
+		columnNames = append(columnNames, common.StringsBuilder(`"`, c, `"`))
+		bindVars = append(bindVars, genOracleBindVar(columnTypeT[i], i+1))
+	}
+	return common.StringsBuilder(`INSERT INTO "`, schemaNameT, `"."`, tableNameT, `" (`,
+		strings.Join(columnNames, ","), `) VALUES (`, strings.Join(bindVars, ","), `)`)
+}
+
+// GenOracleDeleteSQLStmt 目标端 Oracle 数组绑定按键删除语句，用于增量 UPDATE/DELETE 以及 INSERT 幂等写入
+func GenOracleDeleteSQLStmt(schemaNameT, tableNameT string, keyColumnNameT, keyColumnTypeT []string) string {
+	var conds []string
+	for i, c := range keyColumnNameT {
+		conds = append(conds, common.StringsBuilder(`"`, c, `" = `, genOracleBindVar(keyColumnTypeT[i], i+1)))
+	}
+	return common.StringsBuilder(`DELETE FROM "`, schemaNameT, `"."`, tableNameT, `" WHERE `, strings.Join(conds, ` AND `))
+}
+
+// genOracleBindVar 时间字段基于固定格式转换，不受 NLS 参数影响
+func genOracleBindVar(columnTypeT string, pos int) string {
+	bindVar := common.StringsBuilder(`:`, strconv.Itoa(pos))
+	switch {
+	case strings.EqualFold(columnTypeT, "DATE"):
+		return common.StringsBuilder(`TO_DATE(`, bindVar, `,'YYYY-MM-DD HH24:MI:SS')`)
+	case strings.HasPrefix(columnTypeT, "TIMESTAMP"):
+		return common.StringsBuilder(`TO_TIMESTAMP(`, bindVar, `,'YYYY-MM-DD HH24:MI:SS.FF')`)
+	case strings.HasPrefix(columnTypeT, "INTERVAL DAY"):
+		return common.StringsBuilder(`TO_DSINTERVAL(`, bindVar, `)`)
+	default:
+		return bindVar
+	}
+}
+
+// GenOracleColumnBinds 按字段生成数组绑定值，godror 数组元素空值即 NULL
+// 1、NUMBER/FLOAT/BINARY_FLOAT/BINARY_DOUBLE -> godror.Number，不受 NLS_NUMERIC_CHARACTERS 影响，支持无符号整型
+// 2、BLOB/RAW/LONG RAW -> []byte
+// 3、DATE/TIMESTAMP/INTERVAL DAY TO SECOND -> 固定格式字符串，零值日期转换 NULL
+// 4、其他（VARCHAR2/CHAR/CLOB/NCLOB 等）-> 字符串，JSON 按文本写入
+func GenOracleColumnBinds(columnTypeS, columnTypeT []string, rows [][]string) ([]interface{}, error) {
+	binds := make([]interface{}, len(columnTypeT))
+	for i, typeT := range columnTypeT {
+		typeS := common.StringUPPER(columnTypeS[i])
+		switch {
+		case IsOracleNumberType(typeT):
+			values := make([]godror.Number, len(rows))
+			for j, r := range rows {
+				if strings.EqualFold(typeS, "BIT") {
+					values[j] = godror.Number(bitToNumber(r[i]))
+				} else {
+					values[j] = godror.Number(r[i])
+				}
+			}
+			binds[i] = values
+		case typeT == "BLOB" || typeT == "RAW" || typeT == "LONG RAW":
+			values := make([][]byte, len(rows))
+			for j, r := range rows {
+				if !strings.EqualFold(r[i], "") {
+					values[j] = []byte(r[i])
+				}
+			}
+			binds[i] = values
+		case typeT == "DATE" || strings.HasPrefix(typeT, "TIMESTAMP"):
+			values := make([]string, len(rows))
+			for j, r := range rows {
+				values[j] = ConvertOracleDatetimeValue(typeS, typeT, r[i])
+			}
+			binds[i] = values
+		case strings.HasPrefix(typeT, "INTERVAL DAY"):
+			values := make([]string, len(rows))
+			for j, r := range rows {
+				v, err := ConvertOracleIntervalValue(r[i])
+				if err != nil {
+					return nil, err
+				}
+				values[j] = v
+			}
+			binds[i] = values
+		default:
+			values := make([]string, len(rows))
+			for j, r := range rows {
+				values[j] = r[i]
+			}
+			binds[i] = values
+		}
+	}
+	return binds, nil
+}
+
+// IsOracleNumberType 目标端数值数据类型
+func IsOracleNumberType(dataType string) bool {
+	switch common.StringUPPER(dataType) {
+	case "NUMBER", "FLOAT", "BINARY_FLOAT", "BINARY_DOUBLE", "INTEGER":
+		return true
+	default:
+		return false
+	}
+}
+
+// ConvertOracleDatetimeValue 源端时间值转换目标端 DATE/TIMESTAMP 固定格式
+// MySQL 零值日期 0000-00-00 转换 NULL，DATE 不支持小数秒截断，TIME 补充固定日期部分
+func ConvertOracleDatetimeValue(typeS, typeT, value string) string {
+	if strings.EqualFold(value, "") || strings.HasPrefix(value, "0000-00-00") {
+		return ""
+	}
+	if strings.EqualFold(typeS, "TIME") {
+		value = common.StringsBuilder(OracleTimeColumnDatePrefix, value)
+	}
+	if strings.EqualFold(typeT, "DATE") && len(value) > 19 {
+		value = value[:19]
+	}
+	return value
+}
+
+// ConvertOracleIntervalValue MySQL TIME [-]HHH:MM:SS[.ffffff] 转换 Oracle INTERVAL DAY TO SECOND [-]D HH:MM:SS[.ffffff]
+func ConvertOracleIntervalValue(value string) (string, error) {
+	if strings.EqualFold(value, "") {
+		return "", nil
+	}
+	sign := ""
+	if strings.HasPrefix(value, "-") {
+		sign = "-"
+		value = value[1:]
+	}
+	idx := strings.Index(value, ":")
+	if idx <= 0 {
+		return "", fmt.Errorf("time value [%s%s] strconv failed, format isn't support", sign, value)
+	}
+	hours, err := strconv.Atoi(value[:idx])
+	if err != nil {
+		return "", fmt.Errorf("time value [%s%s] strconv failed, %v", sign, value, err)
+	}
+	return fmt.Sprintf("%s%d %02d%s", sign, hours/24, hours%24, value[idx:]), nil
+}
+
+// GenOracleChunkWhere 源端 chunk 范围条件转换目标端条件，用于 chunk 重新写入前清理目标端数据
+// chunk 字段需存在于目标端，TiDB _tidb_rowid 切分 chunk 无法转换
+func GenOracleChunkWhere(chunkDetailS string, columnNameS, columnNameT []string) (string, bool) {
+	if strings.Contains(chunkDetailS, mysql.TiDBRowIDColumn) {
+		return "", false
+	}
+	columnMap := make(map[string]string)
+	for i, c := range columnNameS {
+		columnMap[common.StringUPPER(c)] = columnNameT[i]
+	}
+
+	isConvert := true
+	whereT := chunkColumnRegexp.ReplaceAllStringFunc(chunkDetailS, func(s string) string {
+		c, ok := columnMap[common.StringUPPER(strings.Trim(s, "`"))]
+		if !ok {
+			isConvert = false
+			return s
+		}
+		return common.StringsBuilder(`"`, c, `"`)
+	})
+	return whereT, isConvert
+}
+
+// bitToNumber BIT 字段二进制值转换十进制数值
+func bitToNumber(value string) string {
+	if strings.EqualFold(value, "") {
+		return ""
+	}
+	return new(big.Int).SetBytes([]byte(value)).String()
+}
@@ -0,0 +1,229 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package public
+
+import (
+	"fmt"
+	"github.com/wentaojin/transferdb/common"
+	"github.com/wentaojin/transferdb/database/mysql"
+	"github.com/wentaojin/transferdb/mapping"
+	"github.com/wentaojin/transferdb/transform"
+	"strings"
+)
+
+// IncrTable 增量同步表源端、目标端字段映射
+type IncrTable struct {
+	SchemaNameS string
+	TableNameS  string
+	SchemaNameT string
+	TableNameT  string
+	ColumnNameS []string
+	ColumnTypeS []string
+	ColumnNameT []string
+	ColumnTypeT []string
+	KeyIndex    []int
+}
+
+// IncrBatch 增量写入批次，批次内键不重复，先按键删除后写入，保证消息重放幂等
+type IncrBatch struct {
+	DeleteKeys [][]string
+	InsertRows [][]string
+	Position   uint64
+}
+
+// NewIncrTable 获取源端字段、键字段（主键优先，其次消息 pkNames）以及目标端字段映射
+// 字段映射规则排除字段不同步，键字段不能为排除字段
+func NewIncrTable(m *mysql.MySQL, schemaNameS, tableNameS, schemaNameT, tableNameT string, targetColumns []map[string]string, pkNames []string, columnMapper *mapping.TableMapper) (*IncrTable, error) {
+	sourceColumns, err := m.GetMySQLTableColumn(schemaNameS, tableNameS)
+	if err != nil {
+		return nil, err
+	}
+	sourceColumns = columnMapper.FilterColumnINFO(sourceColumns)
+	columnNameT, columnTypeT, err := GetOracleTableColumnMapping(sourceColumns, targetColumns, columnMapper)
+	if err != nil {
+		return nil, fmt.Errorf("mysql table [%s.%s] column mapping failed: %v", schemaNameS, tableNameS, err)
+	}
+
+	t := &IncrTable{
+		SchemaNameS: schemaNameS,
+		TableNameS:  tableNameS,
+		SchemaNameT: schemaNameT,
+		TableNameT:  tableNameT,
+		ColumnNameT: columnNameT,
+		ColumnTypeT: columnTypeT,
+	}
+	for _, c := range sourceColumns {
+		t.ColumnNameS = append(t.ColumnNameS, c["COLUMN_NAME"])
+		t.ColumnTypeS = append(t.ColumnTypeS, c["DATA_TYPE"])
+	}
+
+	keyColumns := pkNames
+	pkRes, err := m.GetMySQLTablePrimaryKey(schemaNameS, tableNameS)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkRes) > 0 {
+		keyColumns = strings.Split(pkRes[0]["COLUMN_LIST"], ",")
+	}
+	for _, k := range keyColumns {
+		idx := -1
+		for i, c := range t.ColumnNameS {
+			if strings.EqualFold(c, strings.Trim(k, "` ")) {
+				idx = i
+				break
+			}
+		}
+		if idx == -1 {
+			return nil, fmt.Errorf("mysql table [%s.%s] key column [%s] isn't exist", schemaNameS, tableNameS, k)
+		}
+		t.KeyIndex = append(t.KeyIndex, idx)
+	}
+	if len(t.KeyIndex) == 0 {
+		return nil, fmt.Errorf("mysql table [%s.%s] primary key or unique key isn't exist, increment sync isn't support", schemaNameS, tableNameS)
+	}
+	return t, nil
+}
+
+// KeyColumn 键字段源端数据类型、目标端字段名以及数据类型
+func (t *IncrTable) KeyColumn() ([]string, []string, []string) {
+	var keyTypeS, keyNameT, keyTypeT []string
+	for _, i := range t.KeyIndex {
+		keyTypeS = append(keyTypeS, t.ColumnTypeS[i])
+		keyNameT = append(keyNameT, t.ColumnNameT[i])
+		keyTypeT = append(keyTypeT, t.ColumnTypeT[i])
+	}
+	return keyTypeS, keyNameT, keyTypeT
+}
+
+// GenIncrBatches 表行变更消息按顺序转换写入批次
+// 1、INSERT -> 按新值键删除 + 写入新值
+// 2、UPDATE -> 按旧值键删除 + 按新值键删除 + 写入新值（Canal old 只包含变更字段，未变更字段取新值）
+// 3、DELETE -> 按旧值键删除
+// 批次内键重复即切分新批次，保证同一键变更顺序
+func GenIncrBatches(t *IncrTable, messages []CanalJSONMessage, targetDBCharset string, transformer *transform.TableTransformer, batchSize int) ([]IncrBatch, error) {
+	var (
+		batches []IncrBatch
+		batch   IncrBatch
+	)
+	keySet := make(map[string]struct{})
+
+	flush := func() {
+		if len(batch.DeleteKeys) > 0 || len(batch.InsertRows) > 0 {
+			batches = append(batches, batch)
+		}
+		batch = IncrBatch{}
+		keySet = make(map[string]struct{})
+	}
+
+	for _, msg := range messages {
+		op := common.StringUPPER(msg.Type)
+		if op != common.MigrateOperationInsert && op != common.MigrateOperationUpdate && op != common.MigrateOperationDelete {
+			continue
+		}
+		for i, data := range msg.Data {
+			newRow, err := t.genRow(&msg, data, nil, targetDBCharset, transformer)
+			if err != nil {
+				return nil, err
+			}
+
+			var (
+				deleteRows [][]string
+				insertRow  []string
+			)
+			switch op {
+			case common.MigrateOperationInsert:
+				deleteRows = append(deleteRows, newRow)
+				insertRow = newRow
+			case common.MigrateOperationUpdate:
+				if i < len(msg.Old) {
+					oldRow, err := t.genRow(&msg, data, msg.Old[i], targetDBCharset, transformer)
+					if err != nil {
+						return nil, err
+					}
+					deleteRows = append(deleteRows, oldRow)
+				}
+				deleteRows = append(deleteRows, newRow)
+				insertRow = newRow
+			case common.MigrateOperationDelete:
+				deleteRows = append(deleteRows, newRow)
+			}
+
+			var keys [][]string
+			for _, r := range deleteRows {
+				key := t.genKey(r)
+				keyStr := strings.Join(key, "\x00")
+				if _, ok := keySet[keyStr]; ok {
+					flush()
+				}
+				keys = append(keys, key)
+			}
+			if len(batch.DeleteKeys)+len(keys) > batchSize {
+				flush()
+			}
+			for _, key := range keys {
+				keyStr := strings.Join(key, "\x00")
+				if _, ok := keySet[keyStr]; ok {
+					continue
+				}
+				keySet[keyStr] = struct{}{}
+				batch.DeleteKeys = append(batch.DeleteKeys, key)
+			}
+			if insertRow != nil {
+				batch.InsertRows = append(batch.InsertRows, insertRow)
+			}
+			batch.Position = msg.Position()
+		}
+	}
+	flush()
+	return batches, nil
+}
+
+// genRow 按源端字段顺序获取行值，oldData 不为空时优先取旧值
+func (t *IncrTable) genRow(msg *CanalJSONMessage, data, oldData map[string]*string, targetDBCharset string, transformer *transform.TableTransformer) ([]string, error) {
+	row := make([]string, len(t.ColumnNameS))
+	for i, c := range t.ColumnNameS {
+		src := data
+		if _, ok := oldData[c]; ok {
+			src = oldData
+		}
+		value, isNull := msg.Value(src, c)
+
+		if mysql.IsMySQLBinaryType(t.ColumnTypeS[i]) && !transformer.IsTransform(c) {
+			row[i] = value
+			continue
+		}
+
+		// 字段转换（脱敏）规则，与全量使用相同规则
+		value, isNull = transformer.Transform(c, value, isNull)
+		if isNull || strings.EqualFold(value, "") {
+			continue
+		}
+		convertTargetRaw, err := common.CharsetConvert([]byte(value), common.CharsetUTF8MB4, targetDBCharset)
+		if err != nil {
+			return nil, fmt.Errorf("mysql table [%s.%s] column [%s] charset convert failed, %v", t.SchemaNameS, t.TableNameS, c, err)
+		}
+		row[i] = string(convertTargetRaw)
+	}
+	return row, nil
+}
+
+func (t *IncrTable) genKey(row []string) []string {
+	key := make([]string, 0, len(t.KeyIndex))
+	for _, i := range t.KeyIndex {
+		key = append(key, row[i])
+	}
+	return key
+}
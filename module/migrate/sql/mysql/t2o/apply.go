@@ -0,0 +1,116 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package t2o
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/wentaojin/transferdb/database/meta"
+	"github.com/wentaojin/transferdb/module/migrate/sql/mysql/public"
+	migrate "github.com/wentaojin/transferdb/module/migrate/sql/oracle/public"
+	"go.uber.org/zap"
+	"time"
+)
+
+// applyIncrRecord 应用表增量消息，批次内按键删除以及写入放一个事务内，批次提交后更新表同步位点
+// 如果同步中断，数据同步会以 table_scn_s 为准重放，也就是会进行重复消费
+func (r *Migrate) applyIncrRecord(m meta.IncrSyncMeta, messages []public.CanalJSONMessage, targetDBCharset string) error {
+	startTime := time.Now()
+
+	targetColumns, err := r.Oracle.GetOracleTableColumnDataType(m.SchemaNameT, m.TableNameT)
+	if err != nil {
+		return err
+	}
+	if len(targetColumns) == 0 {
+		return fmt.Errorf("tidb table [%s.%s] target table [%s.%s] isn't exist", m.SchemaNameS, m.TableNameS, m.SchemaNameT, m.TableNameT)
+	}
+	table, err := public.NewIncrTable(r.Mysql, r.Cfg.SchemaConfig.SourceSchema, m.TableNameS, m.SchemaNameT, targetColumns[0]["TABLE_NAME"], targetColumns, messages[0].PKNames, r.Mapper.Table(m.TableNameS))
+	if err != nil {
+		return err
+	}
+
+	batches, err := public.GenIncrBatches(table, messages, targetDBCharset, r.Transformer.Table(m.TableNameS), r.Cfg.AppConfig.InsertBatchSize)
+	if err != nil {
+		return err
+	}
+
+	keyTypeS, keyNameT, keyTypeT := table.KeyColumn()
+	deleteSQL := public.GenOracleDeleteSQLStmt(table.SchemaNameT, table.TableNameT, keyNameT, keyTypeT)
+	insertSQL := public.GenOracleInsertSQLStmt(table.SchemaNameT, table.TableNameT, table.ColumnNameT, table.ColumnTypeT)
+
+	for _, b := range batches {
+		batch := b
+		desc := fmt.Sprintf("tidb table [%s.%s] increment position [%d]", m.SchemaNameS, m.TableNameS, batch.Position)
+		err = migrate.Retry(r.Ctx, r.Cfg.FullConfig.RetryTimes, time.Duration(r.Cfg.FullConfig.RetryBackoff)*time.Second, desc, func() error {
+			return r.applyIncrBatch(table, batch, keyTypeS, keyTypeT, deleteSQL, insertSQL)
+		})
+		if err != nil {
+			return fmt.Errorf("%s apply failed: %w", desc, err)
+		}
+
+		// 数据写入完毕，更新元数据 checkpoint 表
+		err = meta.NewIncrSyncMetaModel(r.MetaDB).UpdateIncrSyncMeta(r.Ctx, &meta.IncrSyncMeta{
+			DBTypeS:     m.DBTypeS,
+			DBTypeT:     m.DBTypeT,
+			SchemaNameS: m.SchemaNameS,
+			TableNameS:  m.TableNameS,
+			GlobalScnS:  batch.Position,
+			TableScnS:   batch.Position,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	zap.L().Info("increment table record apply finished",
+		zap.String("schema", m.SchemaNameS),
+		zap.String("table", m.TableNameS),
+		zap.Int("message counts", len(messages)),
+		zap.Int("batch counts", len(batches)),
+		zap.String("cost", time.Now().Sub(startTime).String()))
+	return nil
+}
+
+func (r *Migrate) applyIncrBatch(table *public.IncrTable, batch public.IncrBatch, keyTypeS, keyTypeT []string, deleteSQL, insertSQL string) error {
+	txn, err := r.Oracle.OracleDB.BeginTx(r.Ctx, &sql.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("transaction start failed: %w", err)
+	}
+	defer txn.Rollback()
+
+	if len(batch.DeleteKeys) > 0 {
+		binds, err := public.GenOracleColumnBinds(keyTypeS, keyTypeT, batch.DeleteKeys)
+		if err != nil {
+			return err
+		}
+		if _, err = txn.ExecContext(r.Ctx, deleteSQL, binds...); err != nil {
+			return fmt.Errorf("target sql [%v] execute failed: %w", deleteSQL, err)
+		}
+	}
+	if len(batch.InsertRows) > 0 {
+		binds, err := public.GenOracleColumnBinds(table.ColumnTypeS, table.ColumnTypeT, batch.InsertRows)
+		if err != nil {
+			return err
+		}
+		if _, err = txn.ExecContext(r.Ctx, insertSQL, binds...); err != nil {
+			return fmt.Errorf("target sql [%v] execute failed: %w", insertSQL, err)
+		}
+	}
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("transaction commit failed: %w", err)
+	}
+	return nil
+}
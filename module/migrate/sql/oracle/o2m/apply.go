@@ -24,7 +24,9 @@ import (
 	"github.com/wentaojin/transferdb/config"
 	"github.com/wentaojin/transferdb/database/meta"
 	"github.com/wentaojin/transferdb/database/mysql"
+	"github.com/wentaojin/transferdb/mapping"
 	"github.com/wentaojin/transferdb/module/migrate/sql/oracle/public"
+	"github.com/wentaojin/transferdb/transform"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 	"sync"
@@ -55,7 +57,7 @@ type IncrResult struct {
 }
 
 // 应用当前日志文件中所有记录
-func applyOracleIncrRecord(metaDB *meta.Meta, mysqlDB *mysql.MySQL, cfg *config.Config, logminerMap map[string][]public.Logminer) error {
+func applyOracleIncrRecord(metaDB *meta.Meta, mysqlDB *mysql.MySQL, cfg *config.Config, transformer *transform.Transformer, mapper *mapping.Mapper, logminerMap map[string][]public.Logminer) error {
 	g := &errgroup.Group{}
 	g.SetLimit(cfg.AllConfig.ApplyThreads)
 
@@ -90,6 +92,8 @@ func applyOracleIncrRecord(metaDB *meta.Meta, mysqlDB *mysql.MySQL, cfg *config.
 						sourceTable,
 						metaDB,
 						mysql,
+						transformer.Table(sourceTable),
+						mapper.Table(sourceTable),
 						rowsResult, taskQueue); err != nil {
 						return
 					}
@@ -24,7 +24,11 @@ import (
 	"github.com/wentaojin/transferdb/database/meta"
 	"github.com/wentaojin/transferdb/database/mysql"
 	"github.com/wentaojin/transferdb/database/oracle"
+	"github.com/wentaojin/transferdb/mapping"
 	"github.com/wentaojin/transferdb/module/migrate/sql/oracle/public"
+	"github.com/wentaojin/transferdb/selector"
+	"github.com/wentaojin/transferdb/throttle"
+	"github.com/wentaojin/transferdb/transform"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 	"regexp"
@@ -34,12 +38,16 @@ import (
 )
 
 type Migrate struct {
-	Ctx         context.Context
-	Cfg         *config.Config
-	Oracle      *oracle.Oracle
-	OracleMiner *oracle.Oracle
-	Mysql       *mysql.MySQL
-	MetaDB      *meta.Meta
+	Ctx           context.Context
+	Cfg           *config.Config
+	Oracle        *oracle.Oracle
+	OracleStandby *oracle.Oracle
+	OracleMiner   *oracle.Oracle
+	Mysql         *mysql.MySQL
+	MetaDB        *meta.Meta
+	Throttler     *throttle.Throttler
+	Transformer   *transform.Transformer
+	Mapper        *mapping.Mapper
 }
 
 func NewFuller(ctx context.Context, cfg *config.Config) (*Migrate, error) {
@@ -47,6 +55,11 @@ func NewFuller(ctx context.Context, cfg *config.Config) (*Migrate, error) {
 	if err != nil {
 		return nil, err
 	}
+	// ADG 备库，用于数据抽取
+	standbyDB, err := oracle.NewOracleStandbyEngine(ctx, cfg.OracleConfig, cfg.StandbyConfig, cfg.SchemaConfig.SourceSchema)
+	if err != nil {
+		return nil, err
+	}
 	mysqlDB, err := mysql.NewMySQLDBEngine(ctx, cfg.MySQLConfig)
 	if err != nil {
 		return nil, err
@@ -56,11 +69,12 @@ func NewFuller(ctx context.Context, cfg *config.Config) (*Migrate, error) {
 		return nil, err
 	}
 	return &Migrate{
-		Ctx:    ctx,
-		Cfg:    cfg,
-		Oracle: oracleDB,
-		Mysql:  mysqlDB,
-		MetaDB: metaDB,
+		Ctx:           ctx,
+		Cfg:           cfg,
+		Oracle:        oracleDB,
+		OracleStandby: standbyDB,
+		Mysql:         mysqlDB,
+		MetaDB:        metaDB,
 	}, nil
 }
 
@@ -104,10 +118,34 @@ func (r *Migrate) Full() error {
 	}
 
 	// 获取配置文件待同步表列表
-	exporters, err := public.FilterCFGTable(r.Cfg, r.Oracle)
+	exporters, err := selector.SelectOracleTable(r.Cfg, r.Oracle)
+	if err != nil {
+		return err
+	}
+
+	// 源端抽取限速，时间窗口以及自适应采样后台任务随任务结束退出
+	throttler, err := throttle.NewThrottler(r.Cfg.ThrottleConfig)
+	if err != nil {
+		return err
+	}
+	throttleCtx, throttleCancel := context.WithCancel(r.Ctx)
+	defer throttleCancel()
+	go throttler.Run(throttleCtx, r.sourceReader().GetOracleSessionLoad)
+	r.Throttler = throttler
+
+	// 字段转换（脱敏）规则
+	transformer, err := transform.NewTransformer(r.Ctx, r.MetaDB, r.Cfg.DBTypeS, r.Cfg.DBTypeT, r.Cfg.SchemaConfig.SourceSchema)
+	if err != nil {
+		return err
+	}
+	r.Transformer = transformer
+
+	// 字段映射规则
+	mapper, err := mapping.NewMapper(r.Ctx, r.MetaDB, r.Cfg.DBTypeS, r.Cfg.DBTypeT, r.Cfg.SchemaConfig.SourceSchema)
 	if err != nil {
 		return err
 	}
+	r.Mapper = mapper
 
 	// 关于全量断点恢复
 	//  - 若想断点恢复，设置 enable-checkpoint true,首次一旦运行则 batch 数不能调整，
@@ -134,6 +172,16 @@ func (r *Migrate) Full() error {
 			return err
 		}
 
+		err = meta.NewRowErrorDetailModel(r.MetaDB).DeleteRowErrorDetailBySchemaTaskMode(r.Ctx, &meta.RowErrorDetail{
+			DBTypeS:     r.Cfg.DBTypeS,
+			DBTypeT:     r.Cfg.DBTypeT,
+			SchemaNameS: r.Cfg.SchemaConfig.SourceSchema,
+			TaskMode:    r.Cfg.TaskMode,
+		})
+		if err != nil {
+			return err
+		}
+
 		for _, tableName := range exporters {
 			err = meta.NewWaitSyncMetaModel(r.MetaDB).DeleteWaitSyncMeta(r.Ctx, &meta.WaitSyncMeta{
 				DBTypeS:     r.Cfg.DBTypeS,
@@ -216,6 +264,11 @@ func (r *Migrate) Full() error {
 		zap.Int("clear totals", len(clearTables)),
 		zap.Int("intersection total", len(interTables)))
 
+	// 失败表 chunk 错误均为瞬时错误，自动重置断点续传
+	if err = r.ResetRetryableFailedTable(); err != nil {
+		return err
+	}
+
 	// 判断 [wait_sync_meta] 是否存在错误记录，是否可进行 FULL
 	errTotals, err := meta.NewWaitSyncMetaModel(r.MetaDB).CountsErrWaitSyncMetaBySchema(r.Ctx, &meta.WaitSyncMeta{
 		DBTypeS:     r.Cfg.DBTypeS,
@@ -228,7 +281,7 @@ func (r *Migrate) Full() error {
 		return err
 	}
 	if errTotals > 0 {
-		return fmt.Errorf(`full schema [%s] mode [%s] table task failed: meta table [wait_sync_meta] exist failed error, please: firstly check meta table [wait_sync_meta] and [full_sync_meta] log record; secondly if need resume, update meta table [wait_sync_meta] column [task_status] table status RUNNING (Need UPPER) and delete meta table [chunk_error_detail] current task all records; finally rerunning`, strings.ToUpper(r.Cfg.SchemaConfig.SourceSchema), r.Cfg.TaskMode)
+		return fmt.Errorf(`full schema [%s] mode [%s] table task failed: meta table [wait_sync_meta] exist failed error, please: firstly check failed table by [-mode repair -repair-mode full -repair-action list]; secondly if need resume, reset failed table by [-mode repair -repair-mode full -repair-action reset], or restart table by [-repair-action truncate -repair-table ${table}], or mark table done by [-repair-action done -repair-table ${table}]; finally rerunning`, strings.ToUpper(r.Cfg.SchemaConfig.SourceSchema), r.Cfg.TaskMode)
 	}
 
 	// 判断并记录待同步表列表
@@ -430,22 +483,52 @@ func (r *Migrate) FullPartSyncTable(fullPartTables []string) error {
 
 			waitFullMetas = append(waitFullMetas, failedFullMetas...)
 
-			columnNameS, err := r.Oracle.GetOracleTableRowsColumn(
+			// ADG 备库抽取，chunk 基于主库 SCN 闪回查询，等待备库应用追上
+			if err = r.waitStandbyApply(waitFullMetas); err != nil {
+				return err
+			}
+
+			columnNameS, err := r.sourceReader().GetOracleTableRowsColumn(
 				common.StringsBuilder(`SELECT *`, ` FROM `,
 					common.StringUPPER(r.Cfg.SchemaConfig.SourceSchema), `.`, common.StringUPPER(t), ` WHERE ROWNUM = 1`))
 			if err != nil {
 				return nil
 			}
+			// 字段映射规则，排除字段不迁移
+			columnNameS = r.Mapper.Table(t).FilterColumns(columnNameS)
+
+			limiter := r.Throttler.Table(t, r.GetCustomMigrateConfig()[common.StringUPPER(t)])
+
+			// 数据隔离，表级别共享隔离行数预算
+			var quarantine *public.Quarantine
+			if r.Cfg.FullConfig.EnableQuarantine {
+				quarantine, err = public.NewQuarantine(r.Ctx, r.MetaDB, r.Cfg.FullConfig.MaxQuarantineRows, &meta.RowErrorDetail{
+					DBTypeS:     r.Cfg.DBTypeS,
+					DBTypeT:     r.Cfg.DBTypeT,
+					SchemaNameS: r.Cfg.SchemaConfig.SourceSchema,
+					TableNameS:  t,
+					TaskMode:    r.Cfg.TaskMode,
+				})
+				if err != nil {
+					return err
+				}
+			}
 
 			g1 := &errgroup.Group{}
 			g1.SetLimit(r.Cfg.FullConfig.SQLThreads)
 			for _, fullMeta := range waitFullMetas {
 				m := fullMeta
 				g1.Go(func() error {
-					// 数据写入
-					err = public.IMigrate(NewRows(r.Ctx, m, r.Oracle, r.Mysql,
-						common.MigrateOracleCharsetStringConvertMapping[common.StringUPPER(r.Cfg.OracleConfig.Charset)],
-						common.StringUPPER(r.Cfg.MySQLConfig.Charset), r.Cfg.FullConfig.ApplyThreads, r.Cfg.AppConfig.InsertBatchSize, true, columnNameS))
+					// 数据写入，瞬时错误按指数退避重试，safe-mode REPLACE 写入可重复执行
+					err := public.Retry(r.Ctx, r.Cfg.FullConfig.RetryTimes, time.Duration(r.Cfg.FullConfig.RetryBackoff)*time.Second, m.String(), func() error {
+						// chunk 重新写入，清理历史隔离记录
+						if errq := quarantine.ResetChunk(m); errq != nil {
+							return errq
+						}
+						return public.IMigrate(NewRows(r.Ctx, m, r.sourceReader(), r.Mysql,
+							common.MigrateOracleCharsetStringConvertMapping[common.StringUPPER(r.Cfg.OracleConfig.Charset)],
+							common.StringUPPER(r.Cfg.MySQLConfig.Charset), r.Cfg.FullConfig.ApplyThreads, r.Cfg.AppConfig.InsertBatchSize, r.Cfg.AppConfig.InsertBatchBytes, r.Cfg.AppConfig.MaxLOBSize, true, columnNameS, limiter, r.Transformer.Table(t), r.Mapper.Table(t), quarantine))
+					})
 
 					if err != nil {
 						var (
@@ -481,6 +564,7 @@ func (r *Migrate) FullPartSyncTable(fullPartTables []string) error {
 							InfoDetail:   m.String(),
 							ErrorSQL:     errorSQL,
 							ErrorDetail:  errMsg,
+							ErrorClass:   public.ClassifyError(err),
 						})
 						if errf != nil {
 							return fmt.Errorf("get oracle schema table [%v] IMigrate failed: %v", m.String(), errf)
@@ -508,6 +592,14 @@ func (r *Migrate) FullPartSyncTable(fullPartTables []string) error {
 				return err
 			}
 
+			if quarantine.Rows() > 0 {
+				zap.L().Warn("source schema table rows quarantine",
+					zap.String("schema", r.Cfg.SchemaConfig.SourceSchema),
+					zap.String("table", t),
+					zap.Int64("quarantine rows", quarantine.Rows()),
+					zap.String("detail", "please see meta table [row_error_detail]"))
+			}
+
 			// 清理元数据记录
 			// 更新 wait_sync_meta 记录
 			failedChunkTotalErrs, err := meta.NewFullSyncMetaModel(r.MetaDB).CountsErrorFullSyncMeta(r.Ctx, &meta.FullSyncMeta{
@@ -612,32 +704,11 @@ func (r *Migrate) FullWaitSyncTable(fullWaitTables []string, oracleCollation boo
 
 func (r *Migrate) InitWaitSyncTableChunk(fullWaitTables []string, oracleCollation bool) error {
 	startTask := time.Now()
-	// 获取自定义库表名规则
-	tableNameRule, err := r.GetTableNameRule()
-	if err != nil {
-		return err
-	}
-
-	// 获取自定义库表迁移配置
-	tableMigrateRule := r.GetCustomMigrateConfig()
-
 	// 全量同步前，获取 SCN 以及初始化元数据表
-	globalSCN, err := r.Oracle.GetOracleCurrentSnapshotSCN()
+	rule, err := r.newChunkRule(oracleCollation)
 	if err != nil {
 		return err
 	}
-	partitionTables, err := r.Oracle.GetOracleSchemaPartitionTable(r.Cfg.SchemaConfig.SourceSchema)
-	if err != nil {
-		return err
-	}
-
-	// 一致性读
-	var isConsistentRead string
-	if r.Cfg.FullConfig.ConsistentRead {
-		isConsistentRead = "YES"
-	} else {
-		isConsistentRead = "NO"
-	}
 
 	g := &errgroup.Group{}
 	g.SetLimit(r.Cfg.FullConfig.TaskThreads)
@@ -646,83 +717,26 @@ func (r *Migrate) InitWaitSyncTableChunk(fullWaitTables []string, oracleCollatio
 		t := table
 		g.Go(func() error {
 			startTime := time.Now()
-			// 库名、表名规则
-			var targetTableName string
-			if val, ok := tableNameRule[common.StringUPPER(t)]; ok {
-				targetTableName = val
-			} else {
-				targetTableName = common.StringUPPER(t)
-			}
-
-			// 自定义迁移配置
-			var (
-				sqlHint     string
-				wherePrefix string
-				whereRange  string
-				enableSplit bool
-			)
-			if val, ok := tableMigrateRule[common.StringUPPER(t)]; ok {
-				sqlHint = val.SQLHint
-				wherePrefix = val.Range
-				enableSplit = val.EnableSplit
-			} else {
-				sqlHint = r.Cfg.FullConfig.SQLHint
-			}
-
-			sourceColumnInfo, err := r.AdjustTableSelectColumn(t, oracleCollation)
+			chunk, err := r.splitTableChunk(t, rule)
 			if err != nil {
 				return err
 			}
 
-			var (
-				isPartition string
-			)
-			if common.IsContainString(partitionTables, common.StringUPPER(t)) {
-				isPartition = "YES"
-			} else {
-				isPartition = "NO"
-			}
-
-			tableRowsByStatistics, err := r.Oracle.GetOracleTableRowsByStatistics(r.Cfg.SchemaConfig.SourceSchema, t)
-			if err != nil {
-				return err
-			}
-			// 1、统计信息数据行数 0，直接全表扫
-			// 2、基于数据切分策略，获取指定数据迁移表的查询范围
-			if tableRowsByStatistics == 0 {
-				switch {
-				case enableSplit && !strings.EqualFold(wherePrefix, ""):
-					whereRange = common.StringsBuilder(`1 = 1 AND `, wherePrefix)
-				default:
-					whereRange = `1 = 1`
-				}
-				err = meta.NewCommonModel(r.MetaDB).CreateFullSyncMetaAndUpdateWaitSyncMeta(r.Ctx, &meta.FullSyncMeta{
-					DBTypeS:        r.Cfg.DBTypeS,
-					DBTypeT:        r.Cfg.DBTypeT,
-					SchemaNameS:    common.StringUPPER(r.Cfg.SchemaConfig.SourceSchema),
-					TableNameS:     common.StringUPPER(t),
-					SchemaNameT:    common.StringUPPER(r.Cfg.SchemaConfig.TargetSchema),
-					TableNameT:     common.StringUPPER(targetTableName),
-					GlobalScnS:     globalSCN,
-					ConsistentRead: isConsistentRead,
-					SQLHint:        sqlHint,
-					ColumnDetailS:  sourceColumnInfo,
-					ChunkDetailS:   whereRange,
-					TaskMode:       r.Cfg.TaskMode,
-					TaskStatus:     common.TaskStatusWaiting,
-				}, &meta.WaitSyncMeta{
+			// 统计信息数据行数 0 或者不存在切分结果，直接全表扫
+			if chunk.FullScan {
+				err = meta.NewCommonModel(r.MetaDB).CreateFullSyncMetaAndUpdateWaitSyncMeta(r.Ctx, &chunk.FullMetas[0], &meta.WaitSyncMeta{
 					DBTypeS:          r.Cfg.DBTypeS,
 					DBTypeT:          r.Cfg.DBTypeT,
 					SchemaNameS:      common.StringUPPER(r.Cfg.SchemaConfig.SourceSchema),
 					TableNameS:       common.StringUPPER(t),
 					TaskMode:         r.Cfg.TaskMode,
-					GlobalScnS:       globalSCN,
-					ConsistentRead:   isConsistentRead,
-					TableNumRows:     uint64(tableRowsByStatistics),
+					GlobalScnS:       rule.globalSCN,
+					ConsistentRead:   rule.isConsistentRead,
+					TableNumRows:     uint64(chunk.TableRows),
 					ChunkTotalNums:   1,
 					ChunkSuccessNums: 0,
 					ChunkFailedNums:  0,
-					IsPartition:      isPartition,
+					IsPartition:      chunk.IsPartition,
 				})
 				if err != nil {
 					return err
@@ -730,91 +744,8 @@ func (r *Migrate) InitWaitSyncTableChunk(fullWaitTables []string, oracleCollatio
 				return nil
 			}
 
-			taskName := uuid.New().String()
-
-			if err = r.Oracle.StartOracleChunkCreateTask(taskName); err != nil {
-				return err
-			}
-
-			if err = r.Oracle.StartOracleCreateChunkByRowID(taskName, common.StringUPPER(r.Cfg.SchemaConfig.SourceSchema), common.StringUPPER(t), strconv.Itoa(r.Cfg.CSVConfig.Rows)); err != nil {
-				return err
-			}
-
-			chunkRes, err := r.Oracle.GetOracleTableChunksByRowID(taskName)
-			if err != nil {
-				return err
-			}
-
-			// 判断数据是否存在
-			if len(chunkRes) == 0 {
-				switch {
-				case enableSplit && !strings.EqualFold(wherePrefix, ""):
-					whereRange = common.StringsBuilder(`1 = 1 AND `, wherePrefix)
-				default:
-					whereRange = `1 = 1`
-				}
-				err = meta.NewCommonModel(r.MetaDB).CreateFullSyncMetaAndUpdateWaitSyncMeta(r.Ctx, &meta.FullSyncMeta{
-					DBTypeS:        r.Cfg.DBTypeS,
-					DBTypeT:        r.Cfg.DBTypeT,
-					SchemaNameS:    common.StringUPPER(r.Cfg.SchemaConfig.SourceSchema),
-					TableNameS:     common.StringUPPER(t),
-					SchemaNameT:    common.StringUPPER(r.Cfg.SchemaConfig.TargetSchema),
-					TableNameT:     common.StringUPPER(targetTableName),
-					GlobalScnS:     globalSCN,
-					ConsistentRead: isConsistentRead,
-					SQLHint:        sqlHint,
-					ColumnDetailS:  sourceColumnInfo,
-					ChunkDetailS:   whereRange,
-					TaskMode:       r.Cfg.TaskMode,
-					TaskStatus:     common.TaskStatusWaiting,
-				}, &meta.WaitSyncMeta{
-					DBTypeS:          r.Cfg.DBTypeS,
-					DBTypeT:          r.Cfg.DBTypeT,
-					SchemaNameS:      common.StringUPPER(r.Cfg.SchemaConfig.SourceSchema),
-					TableNameS:       common.StringUPPER(t),
-					TaskMode:         r.Cfg.TaskMode,
-					TableNumRows:     uint64(tableRowsByStatistics),
-					GlobalScnS:       globalSCN,
-					ConsistentRead:   isConsistentRead,
-					ChunkTotalNums:   1,
-					ChunkSuccessNums: 0,
-					ChunkFailedNums:  0,
-					IsPartition:      isPartition,
-				})
-				if err != nil {
-					return err
-				}
-
-				return nil
-			}
-
-			var fullMetas []meta.FullSyncMeta
-			for _, res := range chunkRes {
-				switch {
-				case enableSplit && !strings.EqualFold(wherePrefix, ""):
-					whereRange = common.StringsBuilder(res["CMD"], ` AND `, wherePrefix)
-				default:
-					whereRange = res["CMD"]
-				}
-				fullMetas = append(fullMetas, meta.FullSyncMeta{
-					DBTypeS:        r.Cfg.DBTypeS,
-					DBTypeT:        r.Cfg.DBTypeT,
-					SchemaNameS:    common.StringUPPER(r.Cfg.SchemaConfig.SourceSchema),
-					TableNameS:     common.StringUPPER(t),
-					SchemaNameT:    common.StringUPPER(r.Cfg.SchemaConfig.TargetSchema),
-					TableNameT:     common.StringUPPER(targetTableName),
-					GlobalScnS:     globalSCN,
-					ConsistentRead: isConsistentRead,
-					SQLHint:        sqlHint,
-					ColumnDetailS:  sourceColumnInfo,
-					ChunkDetailS:   whereRange,
-					TaskMode:       r.Cfg.TaskMode,
-					TaskStatus:     common.TaskStatusWaiting,
-				})
-			}
-
 			// 元数据库信息 batch 写入
-			err = meta.NewFullSyncMetaModel(r.MetaDB).BatchCreateFullSyncMeta(r.Ctx, fullMetas, r.Cfg.AppConfig.InsertBatchSize)
+			err = meta.NewFullSyncMetaModel(r.MetaDB).BatchCreateFullSyncMeta(r.Ctx, chunk.FullMetas, r.Cfg.AppConfig.InsertBatchSize)
 			if err != nil {
 				return err
 			}
@@ -827,22 +758,18 @@ func (r *Migrate) InitWaitSyncTableChunk(fullWaitTables []string, oracleCollatio
 				TableNameS:  common.StringUPPER(t),
 				TaskMode:    r.Cfg.TaskMode,
 			}, map[string]interface{}{
-				"TableNumRows":     uint64(tableRowsByStatistics),
-				"GlobalScnS":       globalSCN,
-				"ConsistentRead":   isConsistentRead,
-				"ChunkTotalNums":   len(chunkRes),
+				"TableNumRows":     uint64(chunk.TableRows),
+				"GlobalScnS":       rule.globalSCN,
+				"ConsistentRead":   rule.isConsistentRead,
+				"ChunkTotalNums":   len(chunk.FullMetas),
 				"ChunkSuccessNums": 0,
 				"ChunkFailedNums":  0,
-				"IsPartition":      isPartition,
+				"IsPartition":      chunk.IsPartition,
 			})
 			if err != nil {
 				return err
 			}
 
-			if err = r.Oracle.CloseOracleChunkTask(taskName); err != nil {
-				return err
-			}
-
 			endTime := time.Now()
 			zap.L().Info("init source single table wait_sync_meta and full_sync_meta finished",
 				zap.String("schema", r.Cfg.SchemaConfig.SourceSchema),
@@ -862,6 +789,269 @@ func (r *Migrate) InitWaitSyncTableChunk(fullWaitTables []string, oracleCollatio
 	return nil
 }
 
+// chunkRule 表 chunk 切分公共规则，全量元数据初始化以及 dry-run 执行计划共用
+type chunkRule struct {
+	tableNameRule    map[string]string
+	tableMigrateRule map[string]config.MigrateConfig
+	partitionTables  []string
+	globalSCN        uint64
+	isConsistentRead string
+	oracleCollation  bool
+}
+
+// tableChunk 单表 chunk 切分结果
+type tableChunk struct {
+	TableNameT    string
+	TableRows     int
+	IsPartition   string
+	ChunkStrategy string
+	// 统计信息数据行数 0 或者不存在切分结果，单 chunk 全表扫
+	FullScan  bool
+	FullMetas []meta.FullSyncMeta
+}
+
+func (r *Migrate) newChunkRule(oracleCollation bool) (*chunkRule, error) {
+	// 获取自定义库表名规则
+	tableNameRule, err := r.GetTableNameRule()
+	if err != nil {
+		return nil, err
+	}
+
+	globalSCN, err := r.Oracle.GetOracleCurrentSnapshotSCN()
+	if err != nil {
+		return nil, err
+	}
+	partitionTables, err := r.Oracle.GetOracleSchemaPartitionTable(r.Cfg.SchemaConfig.SourceSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	// 一致性读
+	var isConsistentRead string
+	if r.Cfg.FullConfig.ConsistentRead {
+		isConsistentRead = "YES"
+	} else {
+		isConsistentRead = "NO"
+	}
+
+	return &chunkRule{
+		tableNameRule: tableNameRule,
+		// 获取自定义库表迁移配置
+		tableMigrateRule: r.GetCustomMigrateConfig(),
+		partitionTables:  partitionTables,
+		globalSCN:        globalSCN,
+		isConsistentRead: isConsistentRead,
+		oracleCollation:  oracleCollation,
+	}, nil
+}
+
+// getTableChunksByRowID 基于 DBMS_PARALLEL_EXECUTE ROWID 切分 chunk，任务创建后无论成功与否均删除，避免 dry-run 等异常返回遗留源端任务
+func (r *Migrate) getTableChunksByRowID(t string) (chunkRes []map[string]string, err error) {
+	taskName := uuid.New().String()
+
+	if err = r.Oracle.StartOracleChunkCreateTask(taskName); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := r.Oracle.CloseOracleChunkTask(taskName); closeErr != nil {
+			if err == nil {
+				err = closeErr
+				return
+			}
+			zap.L().Warn("oracle chunk task drop failed",
+				zap.String("schema", r.Cfg.SchemaConfig.SourceSchema),
+				zap.String("table", t),
+				zap.String("task", taskName),
+				zap.Error(closeErr))
+		}
+	}()
+
+	if err = r.Oracle.StartOracleCreateChunkByRowID(taskName, common.StringUPPER(r.Cfg.SchemaConfig.SourceSchema), common.StringUPPER(t), strconv.Itoa(r.Cfg.CSVConfig.Rows)); err != nil {
+		return nil, err
+	}
+
+	return r.Oracle.GetOracleTableChunksByRowID(taskName)
+}
+
+// splitTableChunk 基于统计信息以及切分策略生成单表 full_sync_meta chunk，不写入元数据库
+func (r *Migrate) splitTableChunk(t string, rule *chunkRule) (*tableChunk, error) {
+	// 库名、表名规则
+	var targetTableName string
+	if val, ok := rule.tableNameRule[common.StringUPPER(t)]; ok {
+		targetTableName = val
+	} else {
+		targetTableName = common.StringUPPER(t)
+	}
+
+	// 自定义迁移配置
+	var (
+		sqlHint       string
+		wherePrefix   string
+		whereRange    string
+		enableSplit   bool
+		chunkStrategy string
+		chunkColumn   string
+	)
+	if val, ok := rule.tableMigrateRule[common.StringUPPER(t)]; ok {
+		sqlHint = val.SQLHint
+		wherePrefix = val.Range
+		enableSplit = val.EnableSplit
+		chunkStrategy = val.ChunkStrategy
+		chunkColumn = val.ChunkColumn
+	} else {
+		sqlHint = r.Cfg.FullConfig.SQLHint
+	}
+	if strings.EqualFold(chunkStrategy, "") {
+		chunkStrategy = common.ChunkStrategyRowID
+	}
+
+	sourceColumnInfo, err := r.AdjustTableSelectColumn(t, rule.oracleCollation)
+	if err != nil {
+		return nil, err
+	}
+
+	chunk := &tableChunk{
+		TableNameT:    common.StringUPPER(targetTableName),
+		ChunkStrategy: common.StringUPPER(chunkStrategy),
+	}
+	if common.IsContainString(rule.partitionTables, common.StringUPPER(t)) {
+		chunk.IsPartition = "YES"
+	} else {
+		chunk.IsPartition = "NO"
+	}
+
+	chunk.TableRows, err = r.Oracle.GetOracleTableRowsByStatistics(r.Cfg.SchemaConfig.SourceSchema, t)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1、统计信息数据行数 0，直接全表扫
+	// 2、基于数据切分策略，获取指定数据迁移表的查询范围
+	var chunkRes []map[string]string
+	if chunk.TableRows > 0 {
+		switch {
+		case strings.EqualFold(chunkStrategy, common.ChunkStrategyRowID):
+			chunkRes, err = r.getTableChunksByRowID(t)
+			if err != nil {
+				return nil, err
+			}
+		case strings.EqualFold(chunkStrategy, common.ChunkStrategyPK):
+			// 基于主键或者唯一索引字段 NTILE 切分，不依赖 DBMS_PARALLEL_EXECUTE 以及 CREATE JOB 权限
+			columnName, dataType, err := r.Oracle.GetOracleTableChunkColumn(r.Cfg.SchemaConfig.SourceSchema, t, chunkColumn)
+			if err != nil {
+				return nil, err
+			}
+			chunkRes, err = r.Oracle.GetOracleTableChunksByNTILE(r.Cfg.SchemaConfig.SourceSchema, t, columnName, dataType, chunk.TableRows, r.Cfg.FullConfig.ChunkSize)
+			if err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("oracle table [%s.%s] migrate config chunk-strategy [%s] isn't support, support [rowid pk]", r.Cfg.SchemaConfig.SourceSchema, t, chunkStrategy)
+		}
+	}
+
+	// 判断数据是否存在
+	if len(chunkRes) == 0 {
+		chunk.FullScan = true
+		chunkRes = append(chunkRes, map[string]string{"CMD": `1 = 1`})
+	}
+
+	for _, res := range chunkRes {
+		switch {
+		case enableSplit && !strings.EqualFold(wherePrefix, ""):
+			whereRange = common.StringsBuilder(res["CMD"], ` AND `, wherePrefix)
+		default:
+			whereRange = res["CMD"]
+		}
+		chunk.FullMetas = append(chunk.FullMetas, meta.FullSyncMeta{
+			DBTypeS:        r.Cfg.DBTypeS,
+			DBTypeT:        r.Cfg.DBTypeT,
+			SchemaNameS:    common.StringUPPER(r.Cfg.SchemaConfig.SourceSchema),
+			TableNameS:     common.StringUPPER(t),
+			SchemaNameT:    common.StringUPPER(r.Cfg.SchemaConfig.TargetSchema),
+			TableNameT:     chunk.TableNameT,
+			GlobalScnS:     rule.globalSCN,
+			ConsistentRead: rule.isConsistentRead,
+			SQLHint:        sqlHint,
+			ColumnDetailS:  sourceColumnInfo,
+			ChunkDetailS:   whereRange,
+			TaskMode:       r.Cfg.TaskMode,
+			TaskStatus:     common.TaskStatusWaiting,
+		})
+	}
+	return chunk, nil
+}
+
+// ResetRetryableFailedTable 失败表 chunk 错误均为瞬时错误（RETRYABLE），清理 [chunk_error_detail] 记录并重置表状态 RUNNING，重新运行时断点续传失败 chunk
+func (r *Migrate) ResetRetryableFailedTable() error {
+	failedTables, err := meta.NewWaitSyncMetaModel(r.MetaDB).DetailWaitSyncMeta(r.Ctx, &meta.WaitSyncMeta{
+		DBTypeS:     r.Cfg.DBTypeS,
+		DBTypeT:     r.Cfg.DBTypeT,
+		SchemaNameS: common.StringUPPER(r.Cfg.SchemaConfig.SourceSchema),
+		TaskMode:    r.Cfg.TaskMode,
+		TaskStatus:  common.TaskStatusFailed,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, t := range failedTables {
+		errDetails, err := meta.NewChunkErrorDetailModel(r.MetaDB).DetailChunkErrorDetailBySchemaTable(r.Ctx, &meta.ChunkErrorDetail{
+			DBTypeS:     t.DBTypeS,
+			DBTypeT:     t.DBTypeT,
+			SchemaNameS: t.SchemaNameS,
+			TableNameS:  t.TableNameS,
+			TaskMode:    t.TaskMode,
+		})
+		if err != nil {
+			return err
+		}
+		if len(errDetails) == 0 {
+			continue
+		}
+
+		isRetryable := true
+		for _, e := range errDetails {
+			if !strings.EqualFold(e.ErrorClass, common.ErrorClassRetryable) {
+				isRetryable = false
+				break
+			}
+		}
+		if !isRetryable {
+			continue
+		}
+
+		err = meta.NewChunkErrorDetailModel(r.MetaDB).DeleteChunkErrorDetailBySchemaTable(r.Ctx, &meta.ChunkErrorDetail{
+			DBTypeS:     t.DBTypeS,
+			DBTypeT:     t.DBTypeT,
+			SchemaNameS: t.SchemaNameS,
+			TableNameS:  t.TableNameS,
+			TaskMode:    t.TaskMode,
+		})
+		if err != nil {
+			return err
+		}
+		err = meta.NewWaitSyncMetaModel(r.MetaDB).UpdateWaitSyncMeta(r.Ctx, &meta.WaitSyncMeta{
+			DBTypeS:     t.DBTypeS,
+			DBTypeT:     t.DBTypeT,
+			SchemaNameS: t.SchemaNameS,
+			TableNameS:  t.TableNameS,
+			TaskMode:    t.TaskMode,
+		}, map[string]interface{}{
+			"TaskStatus": common.TaskStatusRunning,
+		})
+		if err != nil {
+			return err
+		}
+		zap.L().Warn("reset failed table with retryable chunk error",
+			zap.String("schema", t.SchemaNameS),
+			zap.String("table", t.TableNameS),
+			zap.String("mode", t.TaskMode),
+			zap.Int("failed chunks", len(errDetails)))
+	}
+	return nil
+}
+
 func (r *Migrate) GetCustomMigrateConfig() map[string]config.MigrateConfig {
 	tableMigrateMap := make(map[string]config.MigrateConfig)
 	for _, t := range r.Cfg.SchemaConfig.MigrateConfig {
@@ -901,7 +1091,16 @@ func (r *Migrate) AdjustTableSelectColumn(sourceTable string, oracleCollation bo
 
 	var columnNames []string
 
+	columnMapper := r.Mapper.Table(sourceTable)
 	for _, rowCol := range columnsINFO {
+		// 字段映射规则，排除字段不抽取，EXPR 字段按源端表达式计算并保持源端字段名别名
+		if columnMapper.IsExclude(rowCol["COLUMN_NAME"]) {
+			continue
+		}
+		if expr, ok := columnMapper.ColumnExpr(rowCol["COLUMN_NAME"]); ok {
+			columnNames = append(columnNames, common.StringsBuilder(`(`, expr, `) AS "`, rowCol["COLUMN_NAME"], `"`))
+			continue
+		}
 		switch strings.ToUpper(rowCol["DATA_TYPE"]) {
 		// 数字
 		case "NUMBER":
@@ -914,6 +1113,9 @@ func (r *Migrate) AdjustTableSelectColumn(sourceTable string, oracleCollation bo
 		// XMLTYPE
 		case "XMLTYPE":
 			columnNames = append(columnNames, fmt.Sprintf(` XMLSERIALIZE(CONTENT "%s" AS CLOB) AS "%s"`, rowCol["COLUMN_NAME"], rowCol["COLUMN_NAME"]))
+		// 空间
+		case "SDO_GEOMETRY":
+			columnNames = append(columnNames, oracle.GenOracleGeometrySelectColumn(rowCol["COLUMN_NAME"]))
 		// 二进制
 		case "BLOB", "LONG RAW", "RAW":
 			columnNames = append(columnNames, common.StringsBuilder(`"`, rowCol["COLUMN_NAME"], `"`))
@@ -947,3 +1149,26 @@ func (r *Migrate) AdjustTableSelectColumn(sourceTable string, oracleCollation bo
 
 	return strings.Join(columnNames, ","), nil
 }
+
+// sourceReader 数据抽取数据库引擎，开启 ADG 备库基于备库抽取，否则基于主库
+func (r *Migrate) sourceReader() *oracle.Oracle {
+	if r.OracleStandby != nil {
+		return r.OracleStandby
+	}
+	return r.Oracle
+}
+
+// waitStandbyApply ADG 备库抽取，等待备库应用 SCN 追上 chunk 主库 GlobalScnS，备库闪回查询与主库一致
+func (r *Migrate) waitStandbyApply(fullMetas []meta.FullSyncMeta) error {
+	if r.OracleStandby == nil {
+		return nil
+	}
+	var globalSCN uint64
+	for _, m := range fullMetas {
+		if m.GlobalScnS > globalSCN {
+			globalSCN = m.GlobalScnS
+		}
+	}
+	return r.OracleStandby.WaitOracleStandbyApplySCN(globalSCN,
+		time.Duration(r.Cfg.StandbyConfig.ApplyWaitTimeout)*time.Second, time.Duration(r.Cfg.StandbyConfig.ApplyWaitInterval)*time.Second)
+}
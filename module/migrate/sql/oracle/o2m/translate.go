@@ -21,7 +21,9 @@ import (
 	"github.com/wentaojin/transferdb/common"
 	"github.com/wentaojin/transferdb/database/meta"
 	"github.com/wentaojin/transferdb/database/mysql"
+	"github.com/wentaojin/transferdb/mapping"
 	"github.com/wentaojin/transferdb/module/migrate/sql/oracle/public"
+	"github.com/wentaojin/transferdb/transform"
 	"go.uber.org/zap"
 	"math"
 	"strings"
@@ -146,7 +148,7 @@ func GenMySQLPrepareBindVarStmt(columns, bindVarBatch int) string {
 
 // Oracle SQL 转换
 // ORACLE 数据库同步需要开附加日志且表需要捕获字段列日志，Logminer 内容 UPDATE/DELETE/INSERT 语句会带所有字段信息
-func translateAndAddOracleIncrRecord(dbTypeS, dbTypeT, taskMode, sourceSchema, sourceTable string, metaDB *meta.Meta, mysql *mysql.MySQL, logminers []public.Logminer, taskQueue chan IncrTask) error {
+func translateAndAddOracleIncrRecord(dbTypeS, dbTypeT, taskMode, sourceSchema, sourceTable string, metaDB *meta.Meta, mysql *mysql.MySQL, transformer *transform.TableTransformer, columnMapper *mapping.TableMapper, logminers []public.Logminer, taskQueue chan IncrTask) error {
 
 	startTime := time.Now()
 	zap.L().Info("oracle table increment log apply start",
@@ -182,11 +184,17 @@ func translateAndAddOracleIncrRecord(dbTypeS, dbTypeT, taskMode, sourceSchema, s
 		// 比如：UPDATE MARVIN.MARVIN1 SET ID = 2 , NAME = 'marvin' WHERE ID = 2 AND NAME = 'pty'
 		// 比如: drop table marvin.marvin7
 		// 比如: truncate table marvin.marvin7
-		mysqlRedo, operationType, err := translateOracleToMySQLSQL(rows.SQLRedo, rows.SQLUndo, common.StringUPPER(rows.TargetSchema), common.StringUPPER(rows.TargetTable))
+		mysqlRedo, operationType, err := translateOracleToMySQLSQL(rows.SQLRedo, rows.SQLUndo, common.StringUPPER(rows.TargetSchema), common.StringUPPER(rows.TargetTable), transformer, columnMapper)
 		if err != nil {
 			return err
 		}
 
+		// 表存在字段转换（脱敏）规则，避免原始数据通过报错信息输出至日志
+		oracleRedo := rows.SQLRedo
+		if transformer != nil {
+			oracleRedo = "<transformed>"
+		}
+
 		// 注册任务到 Job 队列
 		lp := IncrTask{
 			Ctx:            mysql.Ctx,
@@ -201,7 +209,7 @@ func translateAndAddOracleIncrRecord(dbTypeS, dbTypeT, taskMode, sourceSchema, s
 			SourceTable:    rows.SourceTable,
 			TargetSchema:   rows.TargetSchema,
 			TargetTable:    rows.TargetTable,
-			OracleRedo:     rows.SQLRedo,
+			OracleRedo:     oracleRedo,
 			MySQLRedo:      mysqlRedo,
 			Operation:      rows.Operation,
 			OperationType:  operationType}
@@ -229,7 +237,9 @@ func translateAndAddOracleIncrRecord(dbTypeS, dbTypeT, taskMode, sourceSchema, s
 // Oracle SQL 转换
 // 1、INSERT INTO / REPLACE INTO
 // 2、UPDATE / DELETE、REPLACE INTO
-func translateOracleToMySQLSQL(oracleSQLRedo, oracleSQLUndo, targetSchema, targetTable string) ([]string, string, error) {
+// 3、字段转换（脱敏）规则基于语法树字面值转换，与全量保持一致
+// 4、字段映射规则排除字段、重命名字段基于语法树改写，与全量保持一致
+func translateOracleToMySQLSQL(oracleSQLRedo, oracleSQLUndo, targetSchema, targetTable string, transformer *transform.TableTransformer, columnMapper *mapping.TableMapper) ([]string, string, error) {
 	var (
 		sqls          []string
 		operationType string
@@ -238,6 +248,12 @@ func translateOracleToMySQLSQL(oracleSQLRedo, oracleSQLUndo, targetSchema, targe
 	if err != nil {
 		return []string{}, operationType, fmt.Errorf("parse error: %v\n", err.Error())
 	}
+	if err = public.TransformStmt(astNode, transformer); err != nil {
+		return []string{}, operationType, err
+	}
+	if err = public.MappingStmt(astNode, columnMapper); err != nil {
+		return []string{}, operationType, err
+	}
 
 	stmt := public.ExtractStmt(astNode)
 
@@ -252,6 +268,12 @@ func translateOracleToMySQLSQL(oracleSQLRedo, oracleSQLUndo, targetSchema, targe
 		if err != nil {
 			return []string{}, operationType, fmt.Errorf("parse error: %v\n", err.Error())
 		}
+		if err = public.TransformStmt(astUndoNode, transformer); err != nil {
+			return []string{}, operationType, err
+		}
+		if err = public.MappingStmt(astUndoNode, columnMapper); err != nil {
+			return []string{}, operationType, err
+		}
 		undoStmt := public.ExtractStmt(astUndoNode)
 
 		stmt.Data = undoStmt.Before
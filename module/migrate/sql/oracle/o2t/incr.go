@@ -23,7 +23,10 @@ import (
 	"github.com/wentaojin/transferdb/database/meta"
 	"github.com/wentaojin/transferdb/database/mysql"
 	"github.com/wentaojin/transferdb/database/oracle"
+	"github.com/wentaojin/transferdb/mapping"
 	"github.com/wentaojin/transferdb/module/migrate/sql/oracle/public"
+	"github.com/wentaojin/transferdb/selector"
+	"github.com/wentaojin/transferdb/transform"
 	"go.uber.org/zap"
 	"strconv"
 	"strings"
@@ -39,6 +42,11 @@ func NewIncr(ctx context.Context, cfg *config.Config) (*Migrate, error) {
 	if err != nil {
 		return nil, err
 	}
+	// ADG 备库，用于全量阶段数据抽取，logminer 基于主库
+	standbyDB, err := oracle.NewOracleStandbyEngine(ctx, cfg.OracleConfig, cfg.StandbyConfig, cfg.SchemaConfig.SourceSchema)
+	if err != nil {
+		return nil, err
+	}
 	mysqlDB, err := mysql.NewMySQLDBEngine(ctx, cfg.MySQLConfig)
 	if err != nil {
 		return nil, err
@@ -49,12 +57,13 @@ func NewIncr(ctx context.Context, cfg *config.Config) (*Migrate, error) {
 	}
 
 	return &Migrate{
-		Ctx:         ctx,
-		Cfg:         cfg,
-		Oracle:      oracleDB,
-		OracleMiner: oracleMiner,
-		Mysql:       mysqlDB,
-		MetaDB:      metaDB,
+		Ctx:           ctx,
+		Cfg:           cfg,
+		Oracle:        oracleDB,
+		OracleMiner:   oracleMiner,
+		OracleStandby: standbyDB,
+		Mysql:         mysqlDB,
+		MetaDB:        metaDB,
 	}, nil
 }
 
@@ -92,10 +101,33 @@ func (r *Migrate) Incr() error {
 	}
 
 	// 获取配置文件待同步表列表
-	exporters, err := public.FilterCFGTable(r.Cfg, r.Oracle)
+	exporters, err := selector.SelectOracleTable(r.Cfg, r.Oracle)
+	if err != nil {
+		return err
+	}
+
+	// 字段转换（脱敏）规则，增量与全量使用相同规则
+	transformer, err := transform.NewTransformer(r.Ctx, r.MetaDB, r.Cfg.DBTypeS, r.Cfg.DBTypeT, r.Cfg.SchemaConfig.SourceSchema)
 	if err != nil {
 		return err
 	}
+	r.Transformer = transformer
+
+	// 字段映射规则，增量日志仅包含源端字段值，EXPR 规则无法计算，需使用字段转换规则 EXPR 替代
+	mapper, err := mapping.NewMapper(r.Ctx, r.MetaDB, r.Cfg.DBTypeS, r.Cfg.DBTypeT, r.Cfg.SchemaConfig.SourceSchema)
+	if err != nil {
+		return err
+	}
+	if exprTables := common.FilterIntersectionStringItems(mapper.ExprTables(), exporters); len(exprTables) > 0 {
+		return fmt.Errorf("oracle schema [%s] tables %v column mapping rule [%s] isn't support increment sync, please use column transform rule [%s] instead or exclude tables",
+			r.Cfg.SchemaConfig.SourceSchema, exprTables, common.MappingRuleExpr, common.TransformRuleExpr)
+	}
+	r.Mapper = mapper
+
+	// 失败表 chunk 错误均为瞬时错误，自动重置断点续传
+	if err = r.ResetRetryableFailedTable(); err != nil {
+		return err
+	}
 
 	// 判断 [wait_sync_meta] 是否存在错误记录，是否可进行 ALL
 	errTotals, err := meta.NewWaitSyncMetaModel(r.MetaDB).CountsErrWaitSyncMetaBySchema(r.Ctx, &meta.WaitSyncMeta{
@@ -106,7 +138,7 @@ func (r *Migrate) Incr() error {
 		TaskStatus:  common.TaskStatusFailed,
 	})
 	if errTotals > 0 || err != nil {
-		return fmt.Errorf(`csv schema [%s] mode [%s] table task failed: %v, meta table [wait_sync_meta] exist failed error, please firstly check log and deal, secondly clear or update meta table [wait_sync_meta] column [task_status] table status WAITING (Need UPPER), thirdly clear meta table [full_sync_meta] error table record, fively clear target schema error table record, finally rerunning`, strings.ToUpper(r.Cfg.SchemaConfig.SourceSchema), r.Cfg.TaskMode, err)
+		return fmt.Errorf(`csv schema [%s] mode [%s] table task failed: %v, meta table [wait_sync_meta] exist failed error, please: firstly check failed table by [-mode repair -repair-mode all -repair-action list]; secondly if need resume, reset failed table by [-mode repair -repair-mode all -repair-action reset], or restart table by [-repair-action truncate -repair-table ${table}], or mark table done by [-repair-action done -repair-table ${table}]; finally rerunning`, strings.ToUpper(r.Cfg.SchemaConfig.SourceSchema), r.Cfg.TaskMode, err)
 	}
 
 	// 全量数据导出导入，初始化全量元数据表以及导入完成初始化增量元数据表
@@ -392,7 +424,7 @@ func (r *Migrate) syncTableIncrRecord() error {
 
 				if len(logminerContentMap) > 0 {
 					// 数据应用
-					if err := applyOracleIncrRecord(r.MetaDB, r.Mysql, r.Cfg, logminerContentMap); err != nil {
+					if err := applyOracleIncrRecord(r.MetaDB, r.Mysql, r.Cfg, r.Transformer, r.Mapper, logminerContentMap); err != nil {
 						return err
 					}
 					if logFileStartSCN == currentRedoLogFirstChange && log["LOG_FILE"] == currentRedoLogFileName {
@@ -439,7 +471,7 @@ func (r *Migrate) syncTableIncrRecord() error {
 			}
 			if len(logminerContentMap) > 0 {
 				// 数据应用
-				if err := applyOracleIncrRecord(r.MetaDB, r.Mysql, r.Cfg, logminerContentMap); err != nil {
+				if err := applyOracleIncrRecord(r.MetaDB, r.Mysql, r.Cfg, r.Transformer, r.Mapper, logminerContentMap); err != nil {
 					return err
 				}
 				// 当前所有日志文件内容应用完毕，直接更新 GLOBAL_SCN 至日志文件结束 SCN
@@ -23,6 +23,10 @@ import (
 	"github.com/wentaojin/transferdb/database/meta"
 	"github.com/wentaojin/transferdb/database/mysql"
 	"github.com/wentaojin/transferdb/database/oracle"
+	"github.com/wentaojin/transferdb/mapping"
+	"github.com/wentaojin/transferdb/module/migrate/sql/oracle/public"
+	"github.com/wentaojin/transferdb/throttle"
+	"github.com/wentaojin/transferdb/transform"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 	"strconv"
@@ -39,18 +43,24 @@ type Rows struct {
 	TargetDBCharset string
 	ApplyThreads    int
 	BatchSize       int
+	BatchBytes      int
+	MaxLOBSize      int64
 	SafeMode        bool
 	ColumnNameS     []string
+	ColumnNameT     []string
 	ReadChannel     chan []map[string]string
-	WriteChannel    chan string
+	WriteChannel    chan public.RowsBatch
+	Limiter         *throttle.TableLimiter
+	Transformer     *transform.TableTransformer
+	Quarantine      *public.Quarantine
 }
 
 func NewRows(ctx context.Context, syncMeta meta.FullSyncMeta,
-	oracle *oracle.Oracle, mysql *mysql.MySQL, sourceDBCharset string, targetDBCharset string, applyThreads, batchSize int, safeMode bool,
-	columnNameS []string) *Rows {
+	oracle *oracle.Oracle, mysql *mysql.MySQL, sourceDBCharset string, targetDBCharset string, applyThreads, batchSize, batchBytes int, maxLOBSize int64, safeMode bool,
+	columnNameS []string, limiter *throttle.TableLimiter, transformer *transform.TableTransformer, columnMapper *mapping.TableMapper, quarantine *public.Quarantine) *Rows {
 
 	readChannel := make(chan []map[string]string, common.ChannelBufferSize)
-	writeChannel := make(chan string, common.ChannelBufferSize)
+	writeChannel := make(chan public.RowsBatch, common.ChannelBufferSize)
 
 	return &Rows{
 		Ctx:             ctx,
@@ -62,31 +72,44 @@ func NewRows(ctx context.Context, syncMeta meta.FullSyncMeta,
 		ApplyThreads:    applyThreads,
 		SafeMode:        safeMode,
 		BatchSize:       batchSize,
+		BatchBytes:      batchBytes,
+		MaxLOBSize:      maxLOBSize,
 		ColumnNameS:     columnNameS,
+		ColumnNameT:     columnMapper.ColumnNamesT(columnNameS),
 		ReadChannel:     readChannel,
 		WriteChannel:    writeChannel,
+		Limiter:         limiter,
+		Transformer:     transformer,
+		Quarantine:      quarantine,
 	}
 }
 
 func (t *Rows) ReadData() error {
 	startTime := time.Now()
+
+	// 开启数据隔离，附带源端 ROWID 用于定位隔离数据
+	columnDetailS := t.SyncMeta.ColumnDetailS
+	if t.Quarantine != nil {
+		columnDetailS = common.StringsBuilder(columnDetailS, `,`, public.QuarantineRowidColumn)
+	}
+
 	var querySQL string
 	switch {
 	case strings.EqualFold(t.SyncMeta.ConsistentRead, "YES") && strings.EqualFold(t.SyncMeta.SQLHint, ""):
-		querySQL = common.StringsBuilder(`SELECT `, t.SyncMeta.ColumnDetailS, ` FROM `, t.SyncMeta.SchemaNameS, `.`, t.SyncMeta.TableNameS, ` AS OF SCN `, strconv.FormatUint(t.SyncMeta.GlobalScnS, 10), ` WHERE `, t.SyncMeta.ChunkDetailS)
+		querySQL = common.StringsBuilder(`SELECT `, columnDetailS, ` FROM `, t.SyncMeta.SchemaNameS, `.`, t.SyncMeta.TableNameS, ` AS OF SCN `, strconv.FormatUint(t.SyncMeta.GlobalScnS, 10), ` WHERE `, t.SyncMeta.ChunkDetailS)
 	case strings.EqualFold(t.SyncMeta.ConsistentRead, "YES") && !strings.EqualFold(t.SyncMeta.SQLHint, ""):
-		querySQL = common.StringsBuilder(`SELECT `, t.SyncMeta.SQLHint, ` `, t.SyncMeta.ColumnDetailS, ` FROM `, t.SyncMeta.SchemaNameS, `.`, t.SyncMeta.TableNameS, ` AS OF SCN `, strconv.FormatUint(t.SyncMeta.GlobalScnS, 10), ` WHERE `, t.SyncMeta.ChunkDetailS)
+		querySQL = common.StringsBuilder(`SELECT `, t.SyncMeta.SQLHint, ` `, columnDetailS, ` FROM `, t.SyncMeta.SchemaNameS, `.`, t.SyncMeta.TableNameS, ` AS OF SCN `, strconv.FormatUint(t.SyncMeta.GlobalScnS, 10), ` WHERE `, t.SyncMeta.ChunkDetailS)
 	case strings.EqualFold(t.SyncMeta.ConsistentRead, "NO") && !strings.EqualFold(t.SyncMeta.SQLHint, ""):
-		querySQL = common.StringsBuilder(`SELECT `, t.SyncMeta.SQLHint, ` `, t.SyncMeta.ColumnDetailS, ` FROM `, t.SyncMeta.SchemaNameS, `.`, t.SyncMeta.TableNameS, ` WHERE `, t.SyncMeta.ChunkDetailS)
+		querySQL = common.StringsBuilder(`SELECT `, t.SyncMeta.SQLHint, ` `, columnDetailS, ` FROM `, t.SyncMeta.SchemaNameS, `.`, t.SyncMeta.TableNameS, ` WHERE `, t.SyncMeta.ChunkDetailS)
 	default:
-		querySQL = common.StringsBuilder(`SELECT `, t.SyncMeta.ColumnDetailS, ` FROM `, t.SyncMeta.SchemaNameS, `.`, t.SyncMeta.TableNameS, ` WHERE `, t.SyncMeta.ChunkDetailS)
+		querySQL = common.StringsBuilder(`SELECT `, columnDetailS, ` FROM `, t.SyncMeta.SchemaNameS, `.`, t.SyncMeta.TableNameS, ` WHERE `, t.SyncMeta.ChunkDetailS)
 	}
 
-	err := t.Oracle.GetOracleTableRowsData(querySQL, t.BatchSize, t.SourceDBCharset, t.TargetDBCharset, t.ReadChannel)
+	err := t.Oracle.GetOracleTableRowsData(querySQL, t.BatchSize, t.BatchBytes, t.MaxLOBSize, t.SourceDBCharset, t.TargetDBCharset, t.Limiter, t.Transformer, t.ReadChannel)
 	if err != nil {
 		// 通道关闭
 		close(t.ReadChannel)
-		return fmt.Errorf("source sql [%v] execute failed: %v", querySQL, err)
+		return fmt.Errorf("source sql [%v] execute failed: %w", querySQL, err)
 	}
 
 	endTime := time.Now()
@@ -105,8 +128,10 @@ func (t *Rows) ReadData() error {
 
 func (t *Rows) ProcessData() error {
 
+	rowidColumn := common.StringsBuilder("`", public.QuarantineRowidColumn, "`")
+
 	for dataC := range t.ReadChannel {
-		var batchRows []string
+		var batch public.RowsBatch
 
 		for _, dMap := range dataC {
 			// 按字段名顺序遍历获取对应值
@@ -125,16 +150,15 @@ func (t *Rows) ProcessData() error {
 				return fmt.Errorf("source schema table column counts vs data counts isn't match")
 
 			} else {
-				batchRows = append(batchRows, common.StringsBuilder("(", exstrings.Join(rowsTMP, ","), ")"))
+				batch.Rows = append(batch.Rows, common.StringsBuilder("(", exstrings.Join(rowsTMP, ","), ")"))
+				if t.Quarantine != nil {
+					batch.RowIDs = append(batch.RowIDs, strings.Trim(dMap[rowidColumn], "'"))
+				}
 			}
 		}
 
 		// 数据输入
-		t.WriteChannel <- common.StringsBuilder(GenMySQLInsertSQLStmtPrefix(
-			t.SyncMeta.SchemaNameT,
-			t.SyncMeta.TableNameT,
-			t.ColumnNameS,
-			t.SafeMode), exstrings.Join(batchRows, ","))
+		t.WriteChannel <- batch
 	}
 
 	// 通道关闭
@@ -149,14 +173,16 @@ func (t *Rows) ApplyData() error {
 	g := &errgroup.Group{}
 	g.SetLimit(t.ApplyThreads)
 
+	prefixSQL := GenMySQLInsertSQLStmtPrefix(
+		t.SyncMeta.SchemaNameT,
+		t.SyncMeta.TableNameT,
+		t.ColumnNameT,
+		t.SafeMode)
+
 	for dataC := range t.WriteChannel {
-		querySql := dataC
+		batch := dataC
 		g.Go(func() error {
-			err := t.MySQL.WriteMySQLTable(querySql)
-			if err != nil {
-				return fmt.Errorf("target sql [%v] execute failed: %v", querySql, err)
-			}
-			return nil
+			return t.applyBatch(prefixSQL, batch)
 		})
 	}
 
@@ -173,3 +199,28 @@ func (t *Rows) ApplyData() error {
 
 	return nil
 }
+
+// applyBatch 批次写入，开启数据隔离时单行数据错误二分定位错误行写入隔离表，其余数据正常写入
+func (t *Rows) applyBatch(prefixSQL string, batch public.RowsBatch) error {
+	querySql := common.StringsBuilder(prefixSQL, exstrings.Join(batch.Rows, ","))
+	err := t.MySQL.WriteMySQLTable(querySql)
+	if err == nil {
+		return nil
+	}
+	if t.Quarantine == nil || !public.IsQuarantineError(err) {
+		return fmt.Errorf("target sql [%v] execute failed: %w", querySql, err)
+	}
+
+	if len(batch.Rows) == 1 {
+		if errq := t.Quarantine.Record(t.SyncMeta, t.ColumnNameT, batch.RowIDs[0], batch.Rows[0], err); errq != nil {
+			return fmt.Errorf("target sql [%v] execute failed: %v", querySql, errq)
+		}
+		return nil
+	}
+
+	mid := len(batch.Rows) / 2
+	if err = t.applyBatch(prefixSQL, public.RowsBatch{Rows: batch.Rows[:mid], RowIDs: batch.RowIDs[:mid]}); err != nil {
+		return err
+	}
+	return t.applyBatch(prefixSQL, public.RowsBatch{Rows: batch.Rows[mid:], RowIDs: batch.RowIDs[mid:]})
+}
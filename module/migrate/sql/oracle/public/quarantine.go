@@ -0,0 +1,146 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package public
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-sql-driver/mysql"
+	"github.com/wentaojin/transferdb/database/meta"
+	"go.uber.org/zap"
+	"strings"
+	"sync/atomic"
+)
+
+// 源端 ROWID 查询字段，开启数据隔离时随数据一起查询，不写入下游
+const QuarantineRowidColumn = "ROWID"
+
+// 单行数据错误，可隔离，其余错误（例如连接断开、权限）不隔离，chunk 直接失败
+var quarantineMySQLErrors = map[uint16]struct{}{
+	1048: {}, // Column cannot be null
+	1062: {}, // Duplicate entry
+	1264: {}, // Out of range value
+	1265: {}, // Data truncated
+	1292: {}, // Incorrect value
+	1366: {}, // Incorrect string value
+	1367: {}, // Illegal value
+	1406: {}, // Data too long
+	1411: {}, // Incorrect value for function
+	1452: {}, // Foreign key constraint fails
+	3819: {}, // Check constraint is violated
+}
+
+// RowsBatch 写入批次，开启数据隔离时 RowIDs 与 Rows 一一对应
+type RowsBatch struct {
+	Rows   []string
+	RowIDs []string
+}
+
+// Quarantine 表级别数据隔离，同一张表所有 chunk 共享错误行数预算，预算包含元数据表 [row_error_detail] 已有隔离记录
+type Quarantine struct {
+	Ctx     context.Context
+	MetaDB  *meta.Meta
+	MaxRows int64
+	rows    int64
+}
+
+// NewQuarantine maxRows 小于等于 0 代表不限制隔离行数，已隔离行数基于表历史隔离记录初始化（断点续传）
+func NewQuarantine(ctx context.Context, metaDB *meta.Meta, maxRows int64, countS *meta.RowErrorDetail) (*Quarantine, error) {
+	rows, err := meta.NewRowErrorDetailModel(metaDB).CountsRowErrorDetailBySchemaTable(ctx, countS)
+	if err != nil {
+		return nil, err
+	}
+	return &Quarantine{
+		Ctx:     ctx,
+		MetaDB:  metaDB,
+		MaxRows: maxRows,
+		rows:    rows,
+	}, nil
+}
+
+// IsQuarantineError 是否单行数据错误
+func IsQuarantineError(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		_, ok := quarantineMySQLErrors[mysqlErr.Number]
+		return ok
+	}
+	return false
+}
+
+// ResetChunk chunk 重新写入前清理历史隔离记录，已隔离行数同步扣减清理记录数，避免重试重复占用预算
+func (q *Quarantine) ResetChunk(syncMeta meta.FullSyncMeta) error {
+	if q == nil {
+		return nil
+	}
+	deleteRows, err := meta.NewRowErrorDetailModel(q.MetaDB).DeleteRowErrorDetailByChunk(q.Ctx, &meta.RowErrorDetail{
+		DBTypeS:      syncMeta.DBTypeS,
+		DBTypeT:      syncMeta.DBTypeT,
+		SchemaNameS:  syncMeta.SchemaNameS,
+		TableNameS:   syncMeta.TableNameS,
+		TaskMode:     syncMeta.TaskMode,
+		ChunkDetailS: syncMeta.ChunkDetailS,
+	})
+	if err != nil {
+		return err
+	}
+	atomic.AddInt64(&q.rows, -deleteRows)
+	return nil
+}
+
+// Record 记录隔离数据，超过表级别隔离行数预算返回错误
+func (q *Quarantine) Record(syncMeta meta.FullSyncMeta, columnNameT []string, rowid, row string, rowErr error) error {
+	rows := atomic.AddInt64(&q.rows, 1)
+	if q.MaxRows > 0 && rows > q.MaxRows {
+		return fmt.Errorf("target table [%s.%s] quarantine rows exceed max-quarantine-rows [%d], last error: %v",
+			syncMeta.SchemaNameT, syncMeta.TableNameT, q.MaxRows, rowErr)
+	}
+
+	if err := meta.NewRowErrorDetailModel(q.MetaDB).CreateRowErrorDetail(q.Ctx, &meta.RowErrorDetail{
+		DBTypeS:       syncMeta.DBTypeS,
+		DBTypeT:       syncMeta.DBTypeT,
+		SchemaNameS:   syncMeta.SchemaNameS,
+		TableNameS:    syncMeta.TableNameS,
+		SchemaNameT:   syncMeta.SchemaNameT,
+		TableNameT:    syncMeta.TableNameT,
+		TaskMode:      syncMeta.TaskMode,
+		ChunkDetailS:  syncMeta.ChunkDetailS,
+		RowidS:        rowid,
+		ColumnDetailT: strings.Join(columnNameT, ","),
+		RowDetailT:    row,
+		ErrorDetail:   rowErr.Error(),
+	}); err != nil {
+		return err
+	}
+
+	zap.L().Warn("target table row quarantine",
+		zap.String("schema", syncMeta.SchemaNameT),
+		zap.String("table", syncMeta.TableNameT),
+		zap.String("chunk", syncMeta.ChunkDetailS),
+		zap.String("rowid", rowid),
+		zap.Int64("quarantine rows", rows),
+		zap.Error(rowErr))
+	return nil
+}
+
+// Rows 当前运行已隔离行数
+func (q *Quarantine) Rows() int64 {
+	if q == nil {
+		return 0
+	}
+	return atomic.LoadInt64(&q.rows)
+}
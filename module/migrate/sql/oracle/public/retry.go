@@ -0,0 +1,157 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package public
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"github.com/go-sql-driver/mysql"
+	"github.com/godror/godror"
+	"github.com/wentaojin/transferdb/common"
+	"go.uber.org/zap"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// 重试最大退避时间
+const RetryMaxBackoff = 5 * time.Minute
+
+// 下游瞬时错误
+var retryableMySQLErrors = map[uint16]struct{}{
+	1040: {}, // Too many connections
+	1053: {}, // Server shutdown in progress
+	1205: {}, // Lock wait timeout exceeded
+	1213: {}, // Deadlock found
+	8022: {}, // TiDB transaction retry
+	8027: {}, // TiDB information schema is out of date
+	8028: {}, // TiDB information schema is changed
+	9001: {}, // TiDB PD server timeout
+	9002: {}, // TiDB TiKV server timeout
+	9003: {}, // TiDB TiKV server is busy
+	9005: {}, // TiDB region is unavailable
+	9007: {}, // TiDB write conflict
+}
+
+// 上游瞬时错误
+var retryableOracleErrors = map[int]struct{}{
+	60:    {}, // ORA-00060 deadlock detected
+	3113:  {}, // ORA-03113 end-of-file on communication channel
+	3114:  {}, // ORA-03114 not connected to ORACLE
+	3135:  {}, // ORA-03135 connection lost contact
+	12170: {}, // ORA-12170 connect timeout occurred
+	12514: {}, // ORA-12514 listener does not currently know of service
+	12528: {}, // ORA-12528 instance is blocking new connections
+	12537: {}, // ORA-12537 connection closed
+	12541: {}, // ORA-12541 no listener
+	12547: {}, // ORA-12547 lost contact
+	25408: {}, // ORA-25408 can not safely replay call
+}
+
+// 无法解析具体错误类型时，基于错误信息判断是否连接类瞬时错误
+var retryableErrorMessages = []string{
+	"invalid connection",
+	"bad connection",
+	"broken pipe",
+	"connection reset by peer",
+	"connection refused",
+	"i/o timeout",
+	"information schema is changed",
+}
+
+// 源端数据转换错误
+var dataErrorMessages = []string{
+	"strconv failed",
+	"charset convert failed",
+	"column counts vs data counts isn't match",
+}
+
+// ClassifyError 错误分类：RETRYABLE 瞬时错误、DATA 数据错误、FATAL 其他错误
+func ClassifyError(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		if _, ok := retryableMySQLErrors[mysqlErr.Number]; ok {
+			return common.ErrorClassRetryable
+		}
+		if _, ok := quarantineMySQLErrors[mysqlErr.Number]; ok {
+			return common.ErrorClassData
+		}
+		return common.ErrorClassFatal
+	}
+	if oraErr, ok := godror.AsOraErr(err); ok {
+		if _, ok = retryableOracleErrors[oraErr.Code()]; ok {
+			return common.ErrorClassRetryable
+		}
+		return common.ErrorClassFatal
+	}
+
+	if errors.Is(err, mysql.ErrInvalidConn) || errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.EPIPE) {
+		return common.ErrorClassRetryable
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return common.ErrorClassRetryable
+	}
+
+	errMsg := strings.ToLower(err.Error())
+	for _, m := range retryableErrorMessages {
+		if strings.Contains(errMsg, m) {
+			return common.ErrorClassRetryable
+		}
+	}
+	for _, m := range dataErrorMessages {
+		if strings.Contains(errMsg, m) {
+			return common.ErrorClassData
+		}
+	}
+	return common.ErrorClassFatal
+}
+
+// Retry 瞬时错误按指数退避重试，retryTimes 小于等于 0 代表不重试，数据错误以及其他错误直接返回
+func Retry(ctx context.Context, retryTimes int, backoff time.Duration, desc string, fn func() error) error {
+	for i := 0; ; i++ {
+		err := fn()
+		if err == nil || i >= retryTimes || !strings.EqualFold(ClassifyError(err), common.ErrorClassRetryable) {
+			return err
+		}
+
+		wait := backoff << uint(i)
+		if i >= 32 || wait < 0 || wait > RetryMaxBackoff {
+			wait = RetryMaxBackoff
+		}
+		zap.L().Warn("retryable error, waiting retry",
+			zap.String("task", desc),
+			zap.Int("retry", i+1),
+			zap.Int("retry times", retryTimes),
+			zap.String("backoff", wait.String()),
+			zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+	}
+}
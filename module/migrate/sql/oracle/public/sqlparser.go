@@ -17,9 +17,12 @@ package public
 
 import (
 	"encoding/json"
+	"fmt"
 	"strings"
 
 	"github.com/wentaojin/transferdb/common"
+	"github.com/wentaojin/transferdb/mapping"
+	"github.com/wentaojin/transferdb/transform"
 
 	"go.uber.org/zap"
 
@@ -28,7 +31,8 @@ import (
 	"github.com/pingcap/tidb/parser"
 
 	"github.com/pingcap/tidb/parser/ast"
-	_ "github.com/pingcap/tidb/types/parser_driver"
+	"github.com/pingcap/tidb/parser/model"
+	driver "github.com/pingcap/tidb/types/parser_driver"
 )
 
 func ParseSQL(sql string) (*ast.StmtNode, error) {
@@ -41,6 +45,209 @@ func ParseSQL(sql string) (*ast.StmtNode, error) {
 	return &stmtNodes[0], nil
 }
 
+// TransformStmt 基于字段转换（脱敏）规则改写 INSERT VALUES、UPDATE SET 以及 WHERE 等值条件字面值，需在 ExtractStmt 之前调用
+// 字面值转换与全量保持一致，WHERE 条件同步转换保证下游能够匹配已转换数据
+// 日期时间函数（TO_DATE、TO_TIMESTAMP、TO_CHAR 等）转换首个字面值参数，转换为 NULL 时整个函数改写为 NULL
+func TransformStmt(rootNode *ast.StmtNode, transformer *transform.TableTransformer) error {
+	if transformer == nil {
+		return nil
+	}
+	v := &transformVisitor{transformer: transformer}
+	node, _ := (*rootNode).Accept(v)
+	if v.err != nil {
+		return v.err
+	}
+	*rootNode = node.(ast.StmtNode)
+	return nil
+}
+
+// transformFuncNames 源端 redo SQL 字面值包裹函数，首个参数为字段值
+var transformFuncNames = map[string]struct{}{
+	"TO_DATE":         {},
+	"TO_TIMESTAMP":    {},
+	"TO_TIMESTAMP_TZ": {},
+	"TO_CHAR":         {},
+	"TO_NUMBER":       {},
+}
+
+type transformVisitor struct {
+	transformer *transform.TableTransformer
+	err         error
+}
+
+func (v *transformVisitor) Enter(in ast.Node) (ast.Node, bool) {
+	if v.err != nil {
+		return in, true
+	}
+	switch node := in.(type) {
+	case *ast.InsertStmt:
+		for i, col := range node.Columns {
+			for _, lists := range node.Lists {
+				if i < len(lists) {
+					lists[i] = v.transformExpr(col.Name.O, lists[i])
+				}
+			}
+		}
+	case *ast.Assignment:
+		node.Expr = v.transformExpr(node.Column.Name.O, node.Expr)
+	case *ast.BinaryOperationExpr:
+		if node.Op.String() == ast.EQ {
+			if col, ok := node.L.(*ast.ColumnNameExpr); ok {
+				node.R = v.transformExpr(col.Name.Name.O, node.R)
+			}
+		}
+	}
+	return in, false
+}
+
+// Leave 等值条件字面值转换为 NULL（NULLIFY）时改写为 IS NULL，保证下游能够匹配
+func (v *transformVisitor) Leave(in ast.Node) (ast.Node, bool) {
+	if node, ok := in.(*ast.BinaryOperationExpr); ok && node.Op.String() == ast.EQ {
+		if col, ok := node.L.(*ast.ColumnNameExpr); ok && v.transformer.IsTransform(col.Name.Name.O) {
+			if valueExpr, ok := node.R.(*driver.ValueExpr); ok && valueExpr.Datum.IsNull() {
+				return &ast.IsNullExpr{Expr: node.L}, true
+			}
+		}
+	}
+	return in, true
+}
+
+// transformExpr 字段值转换，返回改写后的表达式
+func (v *transformVisitor) transformExpr(columnName string, expr ast.ExprNode) ast.ExprNode {
+	if !v.transformer.IsTransform(columnName) {
+		return expr
+	}
+	switch node := expr.(type) {
+	case *driver.ValueExpr:
+		v.transformValueExpr(columnName, node)
+	case *ast.FuncCallExpr:
+		if _, ok := transformFuncNames[common.StringUPPER(node.FnName.O)]; !ok || len(node.Args) == 0 {
+			return expr
+		}
+		valueExpr, ok := node.Args[0].(*driver.ValueExpr)
+		if !ok {
+			return expr
+		}
+		v.transformValueExpr(columnName, valueExpr)
+		if valueExpr.Datum.IsNull() {
+			return valueExpr
+		}
+	}
+	return expr
+}
+
+func (v *transformVisitor) transformValueExpr(columnName string, valueExpr *driver.ValueExpr) {
+	if valueExpr.Datum.IsNull() {
+		return
+	}
+	value, err := valueExpr.Datum.ToString()
+	if err != nil {
+		v.err = fmt.Errorf("sql parser column [%s] value transform failed: %v", columnName, err)
+		return
+	}
+	value, isNull := v.transformer.Transform(columnName, value, false)
+	if isNull {
+		valueExpr.Datum.SetNull()
+		return
+	}
+	valueExpr.Datum.SetString(value, valueExpr.Datum.Collation())
+}
+
+// MappingStmt 基于字段映射规则改写 INSERT 字段、UPDATE SET 以及 WHERE 等值条件，需在 ExtractStmt 之前调用
+// 排除字段对应的写入值以及 WHERE 条件移除，重命名字段转换为目标端字段名，与全量保持一致
+func MappingStmt(rootNode *ast.StmtNode, mapper *mapping.TableMapper) error {
+	if mapper == nil {
+		return nil
+	}
+	v := &mappingVisitor{mapper: mapper}
+	node, _ := (*rootNode).Accept(v)
+	if v.err != nil {
+		return v.err
+	}
+	*rootNode = node.(ast.StmtNode)
+	return nil
+}
+
+type mappingVisitor struct {
+	mapper *mapping.TableMapper
+	err    error
+}
+
+func (v *mappingVisitor) Enter(in ast.Node) (ast.Node, bool) {
+	switch node := in.(type) {
+	case *ast.InsertStmt:
+		var (
+			columns []*ast.ColumnName
+			lists   = make([][]ast.ExprNode, len(node.Lists))
+		)
+		for i, col := range node.Columns {
+			if v.mapper.IsExclude(col.Name.O) {
+				continue
+			}
+			columns = append(columns, col)
+			for j, list := range node.Lists {
+				if i < len(list) {
+					lists[j] = append(lists[j], list[i])
+				}
+			}
+		}
+		node.Columns, node.Lists = columns, lists
+	case *ast.UpdateStmt:
+		var assignments []*ast.Assignment
+		for _, a := range node.List {
+			if !v.mapper.IsExclude(a.Column.Name.O) {
+				assignments = append(assignments, a)
+			}
+		}
+		node.List = assignments
+	}
+	return in, false
+}
+
+// Leave 排除字段 WHERE 条件随 AND 移除，WHERE 条件仅存在排除字段无法定位数据行报错
+func (v *mappingVisitor) Leave(in ast.Node) (ast.Node, bool) {
+	switch node := in.(type) {
+	case *ast.ColumnName:
+		if columnNameT := v.mapper.ColumnNameT(node.Name.O); !strings.EqualFold(columnNameT, node.Name.O) {
+			node.Name = model.NewCIStr(columnNameT)
+		}
+	case *ast.BinaryOperationExpr:
+		if node.Op.String() == ast.LogicAnd {
+			if v.isExcludePredicate(node.L) {
+				return node.R, true
+			}
+			if v.isExcludePredicate(node.R) {
+				return node.L, true
+			}
+		}
+	case *ast.UpdateStmt:
+		if node.Where != nil && v.isExcludePredicate(node.Where) {
+			v.err = fmt.Errorf("sql parser update where condition only exist column mapping rule exclude column, can't locate row")
+		}
+	case *ast.DeleteStmt:
+		if node.Where != nil && v.isExcludePredicate(node.Where) {
+			v.err = fmt.Errorf("sql parser delete where condition only exist column mapping rule exclude column, can't locate row")
+		}
+	}
+	return in, true
+}
+
+func (v *mappingVisitor) isExcludePredicate(expr ast.ExprNode) bool {
+	switch node := expr.(type) {
+	case *ast.BinaryOperationExpr:
+		if node.Op.String() == ast.EQ {
+			if col, ok := node.L.(*ast.ColumnNameExpr); ok {
+				return v.mapper.IsExclude(col.Name.Name.O)
+			}
+		}
+	case *ast.IsNullExpr:
+		if col, ok := node.Expr.(*ast.ColumnNameExpr); ok {
+			return v.mapper.IsExclude(col.Name.Name.O)
+		}
+	}
+	return false
+}
+
 func ExtractStmt(rootNode *ast.StmtNode) *Stmt {
 	v := &Stmt{}
 	(*rootNode).Accept(v)
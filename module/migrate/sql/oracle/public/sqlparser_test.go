@@ -0,0 +1,98 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package public
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pingcap/tidb/parser/format"
+	"github.com/wentaojin/transferdb/common"
+	"github.com/wentaojin/transferdb/database/meta"
+	"github.com/wentaojin/transferdb/transform"
+)
+
+func TestTransformStmt(t *testing.T) {
+	cases := []struct {
+		name     string
+		column   string
+		ruleType string
+		value    string
+		sql      string
+		expected string
+	}{
+		{
+			name:     "insert literal",
+			column:   "NAME",
+			ruleType: common.TransformRuleFixed,
+			value:    "***",
+			sql:      "INSERT INTO MARVIN.T(ID,NAME) VALUES (1,'marvin')",
+			expected: "INSERT INTO `MARVIN`.`T` (`ID`,`NAME`) VALUES (1,_UTF8MB4'***')",
+		},
+		{
+			name:     "update date set and where",
+			column:   "BIRTH",
+			ruleType: common.TransformRuleFixed,
+			value:    "1970-01-01 00:00:00",
+			sql:      "UPDATE MARVIN.T SET BIRTH = TO_DATE('2020-01-01 00:00:00','YYYY-MM-DD HH24:MI:SS') WHERE ID = 1 AND BIRTH = TO_DATE('2019-12-31 00:00:00','YYYY-MM-DD HH24:MI:SS')",
+			expected: "UPDATE `MARVIN`.`T` SET `BIRTH`=TO_DATE(_UTF8MB4'1970-01-01 00:00:00', _UTF8MB4'YYYY-MM-DD HH24:MI:SS') WHERE `ID`=1 AND `BIRTH`=TO_DATE(_UTF8MB4'1970-01-01 00:00:00', _UTF8MB4'YYYY-MM-DD HH24:MI:SS')",
+		},
+		{
+			name:     "update date nullify",
+			column:   "BIRTH",
+			ruleType: common.TransformRuleNullify,
+			sql:      "UPDATE MARVIN.T SET BIRTH = TO_DATE('2020-01-01 00:00:00','YYYY-MM-DD HH24:MI:SS') WHERE ID = 1 AND BIRTH = TO_DATE('2019-12-31 00:00:00','YYYY-MM-DD HH24:MI:SS')",
+			expected: "UPDATE `MARVIN`.`T` SET `BIRTH`=NULL WHERE `ID`=1 AND `BIRTH` IS NULL",
+		},
+		{
+			name:     "column without rule",
+			column:   "BIRTH",
+			ruleType: common.TransformRuleFixed,
+			value:    "***",
+			sql:      "DELETE FROM MARVIN.T WHERE ID = 1 AND CREATED = TO_DATE('2020-01-01 00:00:00','YYYY-MM-DD HH24:MI:SS')",
+			expected: "DELETE FROM `MARVIN`.`T` WHERE `ID`=1 AND `CREATED`=TO_DATE(_UTF8MB4'2020-01-01 00:00:00', _UTF8MB4'YYYY-MM-DD HH24:MI:SS')",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			transformer, err := transform.NewTransformerByRules([]meta.ColumnTransformRule{{
+				SchemaNameS: "MARVIN",
+				TableNameS:  "T",
+				ColumnNameS: c.column,
+				RuleType:    c.ruleType,
+				RuleValue:   c.value,
+			}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			astNode, err := ParseSQL(c.sql)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err = TransformStmt(astNode, transformer.Table("T")); err != nil {
+				t.Fatal(err)
+			}
+			var sb strings.Builder
+			if err = (*astNode).Restore(format.NewRestoreCtx(format.DefaultRestoreFlags, &sb)); err != nil {
+				t.Fatal(err)
+			}
+			if sb.String() != c.expected {
+				t.Fatalf("transform sql\n got: %s\nwant: %s", sb.String(), c.expected)
+			}
+		})
+	}
+}
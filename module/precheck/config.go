@@ -0,0 +1,179 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package precheck
+
+import (
+	"fmt"
+	"github.com/wentaojin/transferdb/common"
+	"os"
+	"strings"
+)
+
+// checkConfig 配置文件校验，任务链路不支持返回 false
+func (p *Precheck) checkConfig() bool {
+	if !(p.isOracleSource() || p.isMySQLSource()) ||
+		(strings.EqualFold(p.TaskMode, common.TaskModeCSV) && !p.isOracleSource()) {
+		p.fail(categoryConfig, "task link", fmt.Sprintf("mode [%s] source [%s] target [%s] isn't support", p.TaskMode, p.Cfg.DBTypeS, p.Cfg.DBTypeT))
+		return false
+	}
+	p.pass(categoryConfig, "task link", fmt.Sprintf("mode [%s] source [%s] target [%s]", p.TaskMode, p.Cfg.DBTypeS, p.Cfg.DBTypeT))
+
+	// 配置项拼写错误或者配置层级错误，toml 解析忽略导致配置不生效
+	if len(p.Cfg.UndecodedKeys) > 0 {
+		p.warn(categoryConfig, "unknown item", fmt.Sprintf("config file unknown items [%s] don't take effect, please check spelling or section", strings.Join(p.Cfg.UndecodedKeys, ",")))
+	} else {
+		p.pass(categoryConfig, "unknown item", "config file hasn't unknown items")
+	}
+
+	p.checkConfigSchema()
+	p.checkConfigParams()
+	if p.isDataMode() {
+		p.checkConfigCharset()
+	}
+	return true
+}
+
+func (p *Precheck) checkConfigSchema() {
+	var nullItems []string
+	if strings.EqualFold(p.Cfg.SchemaConfig.SourceSchema, "") {
+		nullItems = append(nullItems, "source-schema")
+	}
+	switch p.TaskMode {
+	case common.TaskModeAssess, common.TaskModeCSV:
+	default:
+		if strings.EqualFold(p.Cfg.SchemaConfig.TargetSchema, "") {
+			nullItems = append(nullItems, "target-schema")
+		}
+	}
+	if len(nullItems) > 0 {
+		p.fail(categoryConfig, "[schema-config]", fmt.Sprintf("config items [%s] can't be null", strings.Join(nullItems, ",")))
+		return
+	}
+	p.pass(categoryConfig, "[schema-config]", fmt.Sprintf("source schema [%s] target schema [%s]", p.Cfg.SchemaConfig.SourceSchema, p.Cfg.SchemaConfig.TargetSchema))
+}
+
+// configParam 任务参数名以及取值
+type configParam struct {
+	Name  string
+	Value int
+}
+
+// checkConfigParams 任务模式并发、批次等参数需大于 0，否则任务无法运行
+func (p *Precheck) checkConfigParams() {
+	var (
+		section      string
+		params       []configParam
+		invalidItems []string
+	)
+	switch p.TaskMode {
+	case common.TaskModeReverse:
+		section = "[reverse]"
+		params = []configParam{
+			{"reverse-threads", p.Cfg.ReverseConfig.ReverseThreads},
+		}
+	case common.TaskModeCheck:
+		section = "[check]"
+		params = []configParam{
+			{"check-threads", p.Cfg.CheckConfig.CheckThreads},
+		}
+	case common.TaskModeCompare:
+		section = "[compare]"
+		params = []configParam{
+			{"chunk-size", p.Cfg.DiffConfig.ChunkSize},
+			{"diff-threads", p.Cfg.DiffConfig.DiffThreads},
+		}
+	case common.TaskModeCSV:
+		section = "[csv]"
+		params = []configParam{
+			{"rows", p.Cfg.CSVConfig.Rows},
+			{"task-threads", p.Cfg.CSVConfig.TaskThreads},
+			{"table-threads", p.Cfg.CSVConfig.TableThreads},
+			{"sql-threads", p.Cfg.CSVConfig.SQLThreads},
+		}
+		if strings.EqualFold(p.Cfg.CSVConfig.OutputDir, "") {
+			invalidItems = append(invalidItems, "output-dir can't be null")
+		}
+	case common.TaskModeFull, common.TaskModeAll:
+		section = "[app] [full]"
+		params = []configParam{
+			{"insert-batch-size", p.Cfg.AppConfig.InsertBatchSize},
+			{"chunk-size", p.Cfg.FullConfig.ChunkSize},
+			{"task-threads", p.Cfg.FullConfig.TaskThreads},
+			{"table-threads", p.Cfg.FullConfig.TableThreads},
+			{"sql-threads", p.Cfg.FullConfig.SQLThreads},
+			{"apply-threads", p.Cfg.FullConfig.ApplyThreads},
+		}
+		if strings.EqualFold(p.TaskMode, common.TaskModeAll) {
+			section = "[app] [full] [all]"
+			switch {
+			case p.isOracleSource():
+				params = append(params, configParam{"logminer-query-timeout", p.Cfg.AllConfig.LogminerQueryTimeout})
+				params = append(params, configParam{"filter-threads", p.Cfg.AllConfig.FilterThreads})
+				params = append(params, configParam{"all apply-threads", p.Cfg.AllConfig.ApplyThreads})
+				params = append(params, configParam{"worker-queue", p.Cfg.AllConfig.WorkerQueue})
+				params = append(params, configParam{"worker-threads", p.Cfg.AllConfig.WorkerThreads})
+			case p.isMySQLSource():
+				// MySQL/TiDB 增量基于 canal-json 消息文件
+				if strings.EqualFold(p.Cfg.AllConfig.IncrSourceDir, "") {
+					invalidItems = append(invalidItems, "incr-source-dir can't be null")
+				} else if fi, err := os.Stat(p.Cfg.AllConfig.IncrSourceDir); err != nil || !fi.IsDir() {
+					invalidItems = append(invalidItems, fmt.Sprintf("incr-source-dir [%s] isn't exist directory", p.Cfg.AllConfig.IncrSourceDir))
+				}
+			}
+		}
+	default:
+		return
+	}
+
+	for _, param := range params {
+		if param.Value <= 0 {
+			invalidItems = append(invalidItems, fmt.Sprintf("%s [%d] must be greater than 0", param.Name, param.Value))
+		}
+	}
+	if len(invalidItems) > 0 {
+		p.fail(categoryConfig, section, strings.Join(invalidItems, "; "))
+		return
+	}
+	p.pass(categoryConfig, section, "task params valid")
+}
+
+// checkConfigCharset 字符集配置需为数据迁移支持字符集
+func (p *Precheck) checkConfigCharset() {
+	var invalidItems []string
+	if _, ok := common.MigrateOracleCharsetStringConvertMapping[common.StringUPPER(p.Cfg.OracleConfig.Charset)]; !ok {
+		invalidItems = append(invalidItems, fmt.Sprintf("[oracle] charset [%s] isn't support", p.Cfg.OracleConfig.Charset))
+	}
+	switch {
+	case strings.EqualFold(p.TaskMode, common.TaskModeCSV):
+		if !strings.EqualFold(p.Cfg.CSVConfig.Charset, "") && !strings.EqualFold(p.Cfg.CSVConfig.Charset, common.MYSQLCharsetUTF8) &&
+			!common.IsContainString(common.MigrateDataSupportCharset, common.StringUPPER(p.Cfg.CSVConfig.Charset)) {
+			invalidItems = append(invalidItems, fmt.Sprintf("[csv] charset [%s] isn't support, support charset %v", p.Cfg.CSVConfig.Charset, common.MigrateDataSupportCharset))
+		}
+	case p.isOracleSource():
+		if !common.IsContainString(common.MigrateDataSupportCharset, common.StringUPPER(p.Cfg.MySQLConfig.Charset)) {
+			invalidItems = append(invalidItems, fmt.Sprintf("[mysql] charset [%s] isn't support, support charset %v", p.Cfg.MySQLConfig.Charset, common.MigrateDataSupportCharset))
+		}
+	case p.isMySQLSource():
+		if _, ok := common.MigrateMYSQLCompatibleCharsetStringConvertMapping[common.StringUPPER(p.Cfg.MySQLConfig.Charset)]; !ok {
+			invalidItems = append(invalidItems, fmt.Sprintf("[mysql] charset [%s] isn't support", p.Cfg.MySQLConfig.Charset))
+		}
+	}
+	if len(invalidItems) > 0 {
+		p.fail(categoryConfig, "charset", strings.Join(invalidItems, "; "))
+		return
+	}
+	p.pass(categoryConfig, "charset", fmt.Sprintf("oracle charset [%s] mysql charset [%s]", p.Cfg.OracleConfig.Charset, p.Cfg.MySQLConfig.Charset))
+}
@@ -0,0 +1,34 @@
+//go:build !windows
+// +build !windows
+
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package precheck
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// getDiskFreeBytes 目录所在文件系统非 root 用户可用空间
+func getDiskFreeBytes(dir string) (uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(dir, &stat); err != nil {
+		return 0, fmt.Errorf("dir [%s] statfs failed: %v", dir, err)
+	}
+	// 不同平台 Statfs_t 字段类型不同，统一转换
+	return uint64(stat.Bavail) * uint64(stat.Bsize), nil
+}
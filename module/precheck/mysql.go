@@ -0,0 +1,200 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package precheck
+
+import (
+	"fmt"
+	"github.com/wentaojin/transferdb/common"
+	"github.com/wentaojin/transferdb/database/mysql"
+	"regexp"
+	"strings"
+)
+
+// SHOW GRANTS 权限对象，例如 GRANT SELECT, INSERT ON `db`.* TO `user`@`%`
+var mysqlGrantRegexp = regexp.MustCompile(`(?i)^GRANT\s+(.+?)\s+ON\s+(.+?)\s+TO\s+`)
+
+// checkMySQL MySQL/TiDB 连通性、版本、字符集、sql_mode、schema 以及权限
+func (p *Precheck) checkMySQL(isSource bool) {
+	dbType, schemaName := p.Cfg.DBTypeT, p.Cfg.SchemaConfig.TargetSchema
+	category := fmt.Sprintf("TARGET %s", dbType)
+	if isSource {
+		dbType, schemaName = p.Cfg.DBTypeS, p.Cfg.SchemaConfig.SourceSchema
+		category = fmt.Sprintf("SOURCE %s", dbType)
+	}
+
+	mysqlDB, err := mysql.NewMySQLDBEngine(p.Ctx, p.Cfg.MySQLConfig)
+	if err != nil {
+		p.fail(category, "connect", err.Error())
+		return
+	}
+	p.pass(category, "connect", fmt.Sprintf("%s:%d", p.Cfg.MySQLConfig.Host, p.Cfg.MySQLConfig.Port))
+
+	p.checkMySQLVersion(category, dbType, mysqlDB)
+	p.checkMySQLCharsetAndSQLMode(category, mysqlDB, isSource)
+	p.checkMySQLSchema(category, mysqlDB, schemaName, isSource)
+	p.checkMySQLPrivilege(category, mysqlDB, schemaName, isSource)
+}
+
+func (p *Precheck) checkMySQLVersion(category, dbType string, mysqlDB *mysql.MySQL) {
+	mysqlVersion, err := mysqlDB.GetMySQLDBVersion()
+	if err != nil {
+		p.fail(category, "version", err.Error())
+		return
+	}
+	isTiDB := strings.Contains(common.StringUPPER(mysqlVersion), common.DatabaseTypeTiDB)
+	switch {
+	case strings.EqualFold(dbType, common.DatabaseTypeTiDB) && !isTiDB:
+		p.fail(category, "version", fmt.Sprintf("db type [%s] but db version [%s] isn't tidb, please adjust db type", dbType, mysqlVersion))
+		return
+	case strings.EqualFold(dbType, common.DatabaseTypeMySQL) && isTiDB:
+		p.warn(category, "version", fmt.Sprintf("db type [%s] but db version [%s] is tidb, please adjust db type [%s]", dbType, mysqlVersion, common.DatabaseTypeTiDB))
+		return
+	}
+
+	dbVersion := mysqlVersion
+	if strings.Contains(mysqlVersion, common.MySQLVersionDelimiter) {
+		dbVersion = strings.Split(mysqlVersion, common.MySQLVersionDelimiter)[0]
+	}
+	if common.VersionOrdinal(dbVersion) < common.VersionOrdinal(common.RequireMySQLDBVersion) {
+		p.warn(category, "version", fmt.Sprintf("db version [%s] is less than %s, compatibility isn't verified", mysqlVersion, common.RequireMySQLDBVersion))
+		return
+	}
+	p.pass(category, "version", mysqlVersion)
+}
+
+// checkMySQLCharsetAndSQLMode 数据写入基于反斜杠转义，目标端 NO_BACKSLASH_ESCAPES 会导致数据错误
+func (p *Precheck) checkMySQLCharsetAndSQLMode(category string, mysqlDB *mysql.MySQL, isSource bool) {
+	charset, sqlMode, err := mysqlDB.GetMySQLCharsetAndSQLMode()
+	if err != nil {
+		p.fail(category, "charset", err.Error())
+		return
+	}
+	if p.isDataMode() && !strings.EqualFold(charset, p.Cfg.MySQLConfig.Charset) {
+		p.warn(category, "charset", fmt.Sprintf("server charset [%s] and mysql config charset [%s] aren't equal, data is written by config charset", charset, p.Cfg.MySQLConfig.Charset))
+	} else {
+		p.pass(category, "charset", charset)
+	}
+
+	if isSource || !common.IsContainString([]string{common.TaskModeFull, common.TaskModeAll, common.TaskModeReverse}, p.TaskMode) {
+		return
+	}
+	sqlModes := strings.Split(common.StringUPPER(sqlMode), ",")
+	switch {
+	case common.IsContainString(sqlModes, "NO_BACKSLASH_ESCAPES"):
+		p.fail(category, "sql_mode", fmt.Sprintf("session sql_mode [%s] contains NO_BACKSLASH_ESCAPES, backslash escaped data will be written incorrectly", sqlMode))
+	case common.IsContainString(sqlModes, "ANSI_QUOTES"):
+		p.warn(category, "sql_mode", fmt.Sprintf("session sql_mode [%s] contains ANSI_QUOTES, double quoted string will be parsed as identifier", sqlMode))
+	case !common.IsContainString(sqlModes, "STRICT_TRANS_TABLES") && !common.IsContainString(sqlModes, "STRICT_ALL_TABLES"):
+		p.warn(category, "sql_mode", fmt.Sprintf("session sql_mode [%s] isn't strict mode, data truncation or invalid value won't be reported", sqlMode))
+	default:
+		p.pass(category, "sql_mode", sqlMode)
+	}
+}
+
+func (p *Precheck) checkMySQLSchema(category string, mysqlDB *mysql.MySQL, schemaName string, isSource bool) {
+	if strings.EqualFold(schemaName, "") {
+		return
+	}
+	isExist, err := mysqlDB.IsExistMySQLSchema(schemaName)
+	if err != nil {
+		p.fail(category, "schema", err.Error())
+		return
+	}
+	switch {
+	case isExist:
+		p.pass(category, "schema", fmt.Sprintf("schema [%s] exist", schemaName))
+	case !isSource && strings.EqualFold(p.TaskMode, common.TaskModeReverse) && p.Cfg.ReverseConfig.DirectWrite:
+		p.pass(category, "schema", fmt.Sprintf("schema [%s] isn't exist, reverse direct write will create it", schemaName))
+	case !isSource && strings.EqualFold(p.TaskMode, common.TaskModeReverse):
+		p.warn(category, "schema", fmt.Sprintf("schema [%s] isn't exist, please create it before running reverse ddl file", schemaName))
+	default:
+		p.fail(category, "schema", fmt.Sprintf("schema [%s] isn't exist", schemaName))
+	}
+}
+
+// checkMySQLPrivilege 基于 SHOW GRANTS 校验全局或者 schema 级别权限，角色授权无法展开，缺失仅告警
+func (p *Precheck) checkMySQLPrivilege(category string, mysqlDB *mysql.MySQL, schemaName string, isSource bool) {
+	requirePrivs := []string{"SELECT"}
+	if !isSource {
+		switch p.TaskMode {
+		case common.TaskModeReverse:
+			if p.Cfg.ReverseConfig.DirectWrite {
+				requirePrivs = append(requirePrivs, "CREATE", "ALTER", "INDEX", "DROP")
+			}
+		case common.TaskModeFull, common.TaskModeAll:
+			// TRUNCATE 需要 DROP 权限
+			requirePrivs = append(requirePrivs, "INSERT", "UPDATE", "DELETE", "DROP")
+		}
+	}
+	p.checkMySQLGrants(category, mysqlDB, schemaName, requirePrivs)
+}
+
+// checkMySQLGrants 校验当前用户全局或者 schema 级别是否授予指定权限
+func (p *Precheck) checkMySQLGrants(category string, mysqlDB *mysql.MySQL, schemaName string, requirePrivs []string) {
+	grants, err := mysqlDB.GetMySQLCurrentUserGrants()
+	if err != nil {
+		p.fail(category, "privilege", err.Error())
+		return
+	}
+	privs, hasRole := parseMySQLGrants(grants, schemaName)
+
+	var noPrivs []string
+	if !common.IsContainString(privs, "ALL") {
+		for _, priv := range requirePrivs {
+			if !common.IsContainString(privs, priv) {
+				noPrivs = append(noPrivs, priv)
+			}
+		}
+	}
+	switch {
+	case len(noPrivs) > 0 && hasRole:
+		p.warn(category, "privilege", fmt.Sprintf("privileges [%s] on schema [%s] aren't found, please make sure granted roles contain them", strings.Join(noPrivs, ","), schemaName))
+	case len(noPrivs) > 0:
+		p.fail(category, "privilege", fmt.Sprintf("privileges [%s] on schema [%s] aren't granted", strings.Join(noPrivs, ","), schemaName))
+	default:
+		p.pass(category, "privilege", strings.Join(requirePrivs, ","))
+	}
+}
+
+// parseMySQLGrants 解析全局（*.*）以及 schema 级别（`db`.*）权限，ALL PRIVILEGES 统一为 ALL，返回是否存在角色授权
+func parseMySQLGrants(grants []string, schemaName string) ([]string, bool) {
+	var (
+		privs   []string
+		hasRole bool
+	)
+	for _, grant := range grants {
+		matches := mysqlGrantRegexp.FindStringSubmatch(strings.TrimSpace(grant))
+		if len(matches) != 3 {
+			// GRANT `role`@`%` TO `user`@`%`
+			if strings.HasPrefix(common.StringUPPER(strings.TrimSpace(grant)), "GRANT ") {
+				hasRole = true
+			}
+			continue
+		}
+		object := strings.NewReplacer("`", "", "'", "", `"`, "", `\`, "").Replace(matches[2])
+		if !strings.EqualFold(object, "*.*") && !strings.EqualFold(object, fmt.Sprintf("%s.*", schemaName)) {
+			continue
+		}
+		for _, priv := range strings.Split(matches[1], ",") {
+			priv = common.StringUPPER(strings.TrimSpace(priv))
+			if strings.EqualFold(priv, "ALL PRIVILEGES") {
+				priv = "ALL"
+			}
+			privs = append(privs, priv)
+		}
+	}
+	return privs, hasRole
+}
@@ -0,0 +1,341 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package precheck
+
+import (
+	"fmt"
+	"github.com/wentaojin/transferdb/common"
+	"github.com/wentaojin/transferdb/database/oracle"
+	"github.com/wentaojin/transferdb/selector"
+	"strings"
+)
+
+// 权限检查基于 docs/transferdb_privs.md，视图通过查询探测，系统包通过 ALL_OBJECTS 判断是否可执行
+var (
+	oracleCommonViews  = []string{"DBA_USERS", "DBA_TABLES", "DBA_TAB_COLUMNS", "DBA_OBJECTS", "NLS_DATABASE_PARAMETERS", "V$DATABASE"}
+	oracleReverseViews = []string{"DBA_CONSTRAINTS", "DBA_CONS_COLUMNS", "DBA_INDEXES", "DBA_IND_COLUMNS", "DBA_TAB_COMMENTS", "DBA_COL_COMMENTS", "DBA_PART_TABLES"}
+	oracleAssessViews  = []string{"DBA_SEGMENTS", "DBA_SOURCE", "DBA_INDEXES", "DBA_CONSTRAINTS", "V$INSTANCE", "V$PARAMETER"}
+	oracleLogminerView = []string{"V$LOG", "V$LOGFILE", "V$ARCHIVED_LOG", "DBA_LOG_GROUPS"}
+)
+
+// 附加日志 tables 展示上限
+const precheckTableDisplayLimit = 10
+
+// checkOracle Oracle 连通性、版本、字符集、schema、权限以及 all 模式归档与附加日志
+func (p *Precheck) checkOracle(isSource bool) {
+	category, schemaName := "TARGET ORACLE", p.Cfg.SchemaConfig.TargetSchema
+	if isSource {
+		category, schemaName = "SOURCE ORACLE", p.Cfg.SchemaConfig.SourceSchema
+	}
+
+	// schema 不存在 ALTER SESSION SET CURRENT_SCHEMA 失败，连接不指定 schema，schema 单独检查
+	oracleDB, err := oracle.NewOracleDBEngine(p.Ctx, p.Cfg.OracleConfig, "")
+	if err != nil {
+		p.fail(category, "connect", err.Error())
+		return
+	}
+	p.pass(category, "connect", oracleConnectDetail(p.Cfg.OracleConfig.ConnectString, p.Cfg.OracleConfig.Host, p.Cfg.OracleConfig.Port, p.Cfg.OracleConfig.ServiceName))
+	if isSource {
+		p.Oracle = oracleDB
+	}
+
+	oracleDBVersion, err := oracleDB.GetOracleDBVersion()
+	if err != nil {
+		p.fail(category, "version", err.Error())
+	} else if common.VersionOrdinal(oracleDBVersion) < common.VersionOrdinal(common.RequireOracleDBVersion) {
+		p.fail(category, "version", fmt.Sprintf("oracle db version [%s] is less than 11g, can't be using transferdb tools", oracleDBVersion))
+	} else {
+		p.pass(category, "version", oracleDBVersion)
+	}
+
+	p.checkOracleCharset(category, oracleDB)
+
+	schemaExist := p.checkOracleSchema(category, oracleDB, schemaName, isSource)
+
+	p.checkOraclePrivilege(category, oracleDB, oracleDBVersion, schemaName, isSource)
+
+	if isSource && strings.EqualFold(p.TaskMode, common.TaskModeAll) {
+		p.checkOracleLogminer(category, oracleDB, schemaExist)
+	}
+}
+
+func (p *Precheck) checkOracleCharset(category string, oracleDB *oracle.Oracle) {
+	// AMERICAN_AMERICA.AL32UTF8
+	charset, err := oracleDB.GetOracleDBCharacterSet()
+	if err != nil {
+		p.fail(category, "charset", err.Error())
+		return
+	}
+	dbCharset := charset
+	if strings.Contains(charset, ".") {
+		dbCharset = strings.Split(charset, ".")[1]
+	}
+	if p.isDataMode() && !strings.EqualFold(p.Cfg.OracleConfig.Charset, dbCharset) {
+		p.fail(category, "charset", fmt.Sprintf("oracle charset [%s] and oracle config charset [%s] aren't equal, please adjust oracle config charset", dbCharset, p.Cfg.OracleConfig.Charset))
+		return
+	}
+	p.pass(category, "charset", charset)
+}
+
+func (p *Precheck) checkOracleSchema(category string, oracleDB *oracle.Oracle, schemaName string, isSource bool) bool {
+	if strings.EqualFold(schemaName, "") {
+		return false
+	}
+	schemas, err := oracleDB.GetOracleSchemas()
+	if err != nil {
+		p.fail(category, "schema", err.Error())
+		return false
+	}
+	if common.IsContainString(schemas, common.StringUPPER(schemaName)) {
+		p.pass(category, "schema", fmt.Sprintf("schema [%s] exist", schemaName))
+		return true
+	}
+	// 目标端 reverse 非直接写入，仅生成表结构文件
+	if !isSource && strings.EqualFold(p.TaskMode, common.TaskModeReverse) && !p.Cfg.ReverseConfig.DirectWrite {
+		p.warn(category, "schema", fmt.Sprintf("schema [%s] isn't exist, please create it before running reverse ddl file", schemaName))
+		return false
+	}
+	p.fail(category, "schema", fmt.Sprintf("schema [%s] isn't exist", schemaName))
+	return false
+}
+
+func (p *Precheck) checkOraclePrivilege(category string, oracleDB *oracle.Oracle, oracleDBVersion, schemaName string, isSource bool) {
+	roles, err := oracleDB.GetOracleSessionRoles()
+	if err != nil {
+		p.fail(category, "role", err.Error())
+		return
+	}
+	if common.IsContainString(roles, "DBA") {
+		p.pass(category, "role", "granted role [DBA]")
+	} else {
+		p.warn(category, "role", fmt.Sprintf("not granted role [DBA], granted roles [%s], please refer to docs/transferdb_privs.md", strings.Join(roles, ",")))
+	}
+
+	// 视图查询权限
+	views := append([]string{}, oracleCommonViews...)
+	var packages []string
+	switch p.TaskMode {
+	case common.TaskModeReverse, common.TaskModeCheck:
+		views = append(views, oracleReverseViews...)
+		packages = append(packages, "DBMS_METADATA")
+	case common.TaskModeAssess:
+		views = append(views, oracleAssessViews...)
+		switch p.Cfg.AssessConfig.SQLSource {
+		case common.AssessSQLSourceSQLArea:
+			views = append(views, "V$SQLAREA")
+		case common.AssessSQLSourceAWR:
+			views = append(views, "DBA_HIST_SQLSTAT", "DBA_HIST_SQLTEXT")
+		}
+	case common.TaskModeFull, common.TaskModeCSV, common.TaskModeCompare, common.TaskModeAll:
+		if isSource {
+			packages = append(packages, "DBMS_PARALLEL_EXECUTE")
+			if p.Cfg.ThrottleConfig.EnableAdaptive {
+				views = append(views, "GV$SESSION")
+			}
+			if strings.EqualFold(p.TaskMode, common.TaskModeAll) {
+				views = append(views, oracleLogminerView...)
+				packages = append(packages, "DBMS_LOGMNR")
+			}
+		}
+	}
+
+	var noPrivViews []string
+	for _, v := range views {
+		if err = oracleDB.ProbeOracleObjectSelect(v); err != nil {
+			noPrivViews = append(noPrivViews, v)
+		}
+	}
+	if len(noPrivViews) > 0 {
+		p.fail(category, "view privilege", fmt.Sprintf("views [%s] query failed, please grant select privilege", strings.Join(noPrivViews, ",")))
+	} else {
+		p.pass(category, "view privilege", strings.Join(views, ","))
+	}
+
+	if len(packages) > 0 {
+		var noPrivPackages []string
+		for _, pkg := range packages {
+			executable, err := oracleDB.IsOracleSysPackageExecutable(pkg)
+			if err != nil {
+				p.fail(category, "package privilege", err.Error())
+				return
+			}
+			if !executable {
+				noPrivPackages = append(noPrivPackages, pkg)
+			}
+		}
+		if len(noPrivPackages) > 0 {
+			p.fail(category, "package privilege", fmt.Sprintf("packages [%s] aren't executable, please grant execute privilege", strings.Join(noPrivPackages, ",")))
+		} else {
+			p.pass(category, "package privilege", strings.Join(packages, ","))
+		}
+	}
+
+	p.checkOracleSystemPrivilege(category, oracleDB, oracleDBVersion, schemaName, isSource)
+}
+
+// checkOracleSystemPrivilege 系统权限，跨 schema 读写需要 ANY 权限或者对象授权，对象授权无法逐表校验，缺失仅告警
+func (p *Precheck) checkOracleSystemPrivilege(category string, oracleDB *oracle.Oracle, oracleDBVersion, schemaName string, isSource bool) {
+	var (
+		requirePrivs []string
+		mustPrivs    []string
+	)
+	crossSchema := !strings.EqualFold(p.Cfg.OracleConfig.Username, schemaName)
+	switch {
+	case isSource && common.IsContainString([]string{common.TaskModeFull, common.TaskModeCSV, common.TaskModeAll}, p.TaskMode):
+		// 全量数据基于 SCN 闪回查询
+		if crossSchema {
+			requirePrivs = append(requirePrivs, "SELECT ANY TABLE", "FLASHBACK ANY TABLE")
+		}
+		if strings.EqualFold(p.TaskMode, common.TaskModeAll) &&
+			common.VersionOrdinal(oracleDBVersion) >= common.VersionOrdinal(common.RequireOracleLogminingVersion) {
+			mustPrivs = append(mustPrivs, "LOGMINING")
+		}
+	case isSource && strings.EqualFold(p.TaskMode, common.TaskModeCompare):
+		if crossSchema {
+			requirePrivs = append(requirePrivs, "SELECT ANY TABLE", "FLASHBACK ANY TABLE")
+		}
+	case !isSource && common.IsContainString([]string{common.TaskModeFull, common.TaskModeAll}, p.TaskMode):
+		if crossSchema {
+			requirePrivs = append(requirePrivs, "INSERT ANY TABLE", "UPDATE ANY TABLE", "DELETE ANY TABLE", "DROP ANY TABLE")
+		}
+	case !isSource && strings.EqualFold(p.TaskMode, common.TaskModeReverse) && p.Cfg.ReverseConfig.DirectWrite:
+		if crossSchema {
+			requirePrivs = append(requirePrivs, "CREATE ANY TABLE", "CREATE ANY INDEX", "ALTER ANY TABLE")
+		}
+	case !isSource && common.IsContainString([]string{common.TaskModeCheck, common.TaskModeCompare}, p.TaskMode):
+		if crossSchema {
+			requirePrivs = append(requirePrivs, "SELECT ANY TABLE")
+		}
+	}
+	if len(requirePrivs) == 0 && len(mustPrivs) == 0 {
+		return
+	}
+
+	privs, err := oracleDB.GetOracleSessionPrivileges()
+	if err != nil {
+		p.fail(category, "system privilege", err.Error())
+		return
+	}
+	var noMustPrivs, noRequirePrivs []string
+	for _, priv := range mustPrivs {
+		if !common.IsContainString(privs, priv) {
+			noMustPrivs = append(noMustPrivs, priv)
+		}
+	}
+	for _, priv := range requirePrivs {
+		if !common.IsContainString(privs, priv) {
+			noRequirePrivs = append(noRequirePrivs, priv)
+		}
+	}
+	switch {
+	case len(noMustPrivs) > 0:
+		p.fail(category, "system privilege", fmt.Sprintf("system privileges [%s] aren't granted", strings.Join(noMustPrivs, ",")))
+	case len(noRequirePrivs) > 0:
+		p.warn(category, "system privilege", fmt.Sprintf("system privileges [%s] aren't granted, please make sure schema [%s] tables object privileges are granted", strings.Join(noRequirePrivs, ","), schemaName))
+	default:
+		p.pass(category, "system privilege", strings.Join(append(mustPrivs, requirePrivs...), ","))
+	}
+}
+
+// checkOracleLogminer all 模式归档以及附加日志，数据库级别未开启所有字段附加日志需同步表开启表级别附加日志
+func (p *Precheck) checkOracleLogminer(category string, oracleDB *oracle.Oracle, schemaExist bool) {
+	logMode, err := oracleDB.GetOracleLogModeAndSupplementalLog()
+	if err != nil {
+		p.fail(category, "archive log", err.Error())
+		return
+	}
+	if !strings.EqualFold(logMode["LOG_MODE"], "ARCHIVELOG") {
+		p.fail(category, "archive log", fmt.Sprintf("database log mode [%s], please enable archivelog", logMode["LOG_MODE"]))
+	} else {
+		p.pass(category, "archive log", logMode["LOG_MODE"])
+	}
+
+	if strings.EqualFold(logMode["SUPPLEMENTAL_LOG_DATA_MIN"], "NO") {
+		p.fail(category, "min supplemental log", "database minimal supplemental log isn't enable, please run [ALTER DATABASE ADD SUPPLEMENTAL LOG DATA]")
+	} else {
+		p.pass(category, "min supplemental log", logMode["SUPPLEMENTAL_LOG_DATA_MIN"])
+	}
+
+	if strings.EqualFold(logMode["SUPPLEMENTAL_LOG_DATA_ALL"], "YES") {
+		p.pass(category, "all column supplemental log", "database level all column supplemental log enable")
+		return
+	}
+	if !schemaExist {
+		return
+	}
+	exporters, err := selector.SelectOracleTable(p.Cfg, oracleDB)
+	if err != nil {
+		p.fail(category, "all column supplemental log", err.Error())
+		return
+	}
+	logTables, err := oracleDB.GetOracleSchemaTableAllColumnSupplementalLog(p.Cfg.SchemaConfig.SourceSchema)
+	if err != nil {
+		p.fail(category, "all column supplemental log", err.Error())
+		return
+	}
+	noLogTables := common.FilterDifferenceStringItems(exporters, logTables)
+	if len(noLogTables) > 0 {
+		p.fail(category, "all column supplemental log", fmt.Sprintf("database level all column supplemental log isn't enable, and schema [%s] tables [%s] table level all column supplemental log aren't enable",
+			p.Cfg.SchemaConfig.SourceSchema, displayItems(noLogTables, precheckTableDisplayLimit)))
+		return
+	}
+	p.pass(category, "all column supplemental log", fmt.Sprintf("schema [%s] tables [%d] table level all column supplemental log enable", p.Cfg.SchemaConfig.SourceSchema, len(exporters)))
+}
+
+// checkStandby ADG 备库角色、打开模式以及应用 SCN，仅适用于开启备库的数据抽取模式
+func (p *Precheck) checkStandby() {
+	if !p.Cfg.StandbyConfig.Enable || !common.IsContainString([]string{common.TaskModeFull, common.TaskModeCSV, common.TaskModeAll, common.TaskModeCompare}, p.TaskMode) {
+		return
+	}
+	standbyDB, err := oracle.NewOracleStandbyEngine(p.Ctx, p.Cfg.OracleConfig, p.Cfg.StandbyConfig, "")
+	if err != nil {
+		p.fail(categoryStandby, "connect", err.Error())
+		return
+	}
+	databaseRole, openMode, err := standbyDB.GetOracleDatabaseRole()
+	if err != nil {
+		p.fail(categoryStandby, "connect", err.Error())
+		return
+	}
+	p.pass(categoryStandby, "connect", fmt.Sprintf("%s database role [%s] open mode [%s]",
+		oracleConnectDetail(p.Cfg.StandbyConfig.ConnectString, p.Cfg.StandbyConfig.Host, p.Cfg.StandbyConfig.Port, p.Cfg.StandbyConfig.ServiceName), databaseRole, openMode))
+
+	if p.Oracle == nil {
+		return
+	}
+	applySCN, err := standbyDB.GetOracleStandbyApplySCN()
+	if err != nil {
+		p.fail(categoryStandby, "apply scn", err.Error())
+		return
+	}
+	currentSCN, err := p.Oracle.GetOracleCurrentSnapshotSCN()
+	if err != nil {
+		p.fail(categoryStandby, "apply scn", err.Error())
+		return
+	}
+	if !strings.Contains(common.StringUPPER(openMode), "APPLY") {
+		p.warn(categoryStandby, "apply scn", fmt.Sprintf("standby redo apply isn't running, apply scn [%d] primary current scn [%d]", applySCN, currentSCN))
+		return
+	}
+	p.pass(categoryStandby, "apply scn", fmt.Sprintf("apply scn [%d] primary current scn [%d]", applySCN, currentSCN))
+}
+
+func oracleConnectDetail(connectString, host string, port int, serviceName string) string {
+	if !strings.EqualFold(connectString, "") {
+		return connectString
+	}
+	return fmt.Sprintf("%s:%d/%s", host, port, serviceName)
+}
@@ -0,0 +1,250 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package precheck
+
+import (
+	"context"
+	"fmt"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/wentaojin/transferdb/common"
+	"github.com/wentaojin/transferdb/config"
+	"github.com/wentaojin/transferdb/database/meta"
+	"github.com/wentaojin/transferdb/database/mysql"
+	"github.com/wentaojin/transferdb/database/oracle"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+// 检查项分类
+const (
+	categoryConfig  = "CONFIG"
+	categoryMeta    = "META"
+	categoryStandby = "ORACLE STANDBY"
+	categoryStorage = "STORAGE"
+)
+
+// 检查结果详情展示最大宽度
+const precheckDetailDisplayWidth = 100
+
+// Precheck 任务预检查，任务运行前校验配置文件、数据库连通性、权限以及运行环境，替代任务运行过程中才暴露的配置错误
+type Precheck struct {
+	Ctx      context.Context
+	Cfg      *config.Config
+	TaskMode string
+	// 源端 Oracle 连接，用于 csv 输出目录空间预估
+	Oracle *oracle.Oracle
+	Items  []Item
+}
+
+// Item 检查项结果
+type Item struct {
+	Category string
+	Name     string
+	Status   string
+	Detail   string
+}
+
+func NewPrecheck(ctx context.Context, cfg *config.Config) *Precheck {
+	return &Precheck{
+		Ctx:      ctx,
+		Cfg:      cfg,
+		TaskMode: common.StringUPPER(cfg.PrecheckConfig.TaskMode),
+	}
+}
+
+func IPrecheck(ctx context.Context, cfg *config.Config) error {
+	startTime := time.Now()
+
+	switch common.StringUPPER(cfg.PrecheckConfig.TaskMode) {
+	case common.TaskModeAssess, common.TaskModeReverse, common.TaskModeCheck, common.TaskModeCompare,
+		common.TaskModeFull, common.TaskModeCSV, common.TaskModeAll:
+	default:
+		return fmt.Errorf("precheck mode [%s] isn't support, support precheck mode [assess reverse check compare full csv all]", cfg.PrecheckConfig.TaskMode)
+	}
+
+	p := NewPrecheck(ctx, cfg)
+	p.Check()
+
+	fmt.Println(p.Render())
+
+	passCounts, warnCounts, failCounts := p.Counts()
+	if failCounts > 0 {
+		return fmt.Errorf("precheck mode [%s] source [%s] target [%s] exist [%d] failed items, please fix failed items and rerun precheck",
+			p.TaskMode, cfg.DBTypeS, cfg.DBTypeT, failCounts)
+	}
+
+	zap.L().Info("precheck task finished",
+		zap.String("schema", cfg.SchemaConfig.SourceSchema),
+		zap.String("mode", p.TaskMode),
+		zap.Int("pass", passCounts),
+		zap.Int("warn", warnCounts),
+		zap.Int("fail", failCounts),
+		zap.String("cost", time.Now().Sub(startTime).String()))
+	return nil
+}
+
+// Check 依次检查配置文件、元数据库、源端以及目标端数据库，配置文件任务链路不支持直接结束
+func (p *Precheck) Check() {
+	if !p.checkConfig() {
+		return
+	}
+	p.checkMeta()
+
+	switch {
+	case p.isOracleSource():
+		p.checkOracle(true)
+		p.checkStandby()
+		switch p.TaskMode {
+		case common.TaskModeAssess:
+		case common.TaskModeCSV:
+			p.checkCSVOutput()
+		default:
+			p.checkMySQL(false)
+		}
+	case p.isMySQLSource():
+		p.checkMySQL(true)
+		if !strings.EqualFold(p.TaskMode, common.TaskModeAssess) {
+			p.checkOracle(false)
+		}
+	}
+}
+
+// checkMeta 元数据库连通性、权限以及元数据表，普通连接只读探测，不创建元数据库以及元数据表，元数据表需 prepare 模式初始化
+func (p *Precheck) checkMeta() {
+	metaConn, err := meta.NewMetaDBConn(p.Ctx, p.Cfg.MetaConfig)
+	if err != nil {
+		p.fail(categoryMeta, "connect", err.Error())
+		return
+	}
+	defer metaConn.Close()
+	p.pass(categoryMeta, "connect", fmt.Sprintf("%s:%d/%s", p.Cfg.MetaConfig.Host, p.Cfg.MetaConfig.Port, p.Cfg.MetaConfig.MetaSchema))
+
+	metaDB := &mysql.MySQL{Ctx: p.Ctx, MySQLDB: metaConn}
+	p.checkMySQLGrants(categoryMeta, metaDB, p.Cfg.MetaConfig.MetaSchema, []string{"SELECT", "INSERT", "UPDATE", "DELETE"})
+
+	_, res, err := mysql.Query(p.Ctx, metaConn, fmt.Sprintf(`SELECT TABLE_NAME AS TABLE_NAME FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_SCHEMA = '%s'`, p.Cfg.MetaConfig.MetaSchema))
+	if err != nil {
+		p.fail(categoryMeta, "meta table", err.Error())
+		return
+	}
+	if len(res) == 0 {
+		p.fail(categoryMeta, "meta table", fmt.Sprintf("meta schema [%s] isn't exist or hasn't meta tables, please run [-mode prepare] firstly", p.Cfg.MetaConfig.MetaSchema))
+		return
+	}
+	existTables := make(map[string]struct{}, len(res))
+	for _, r := range res {
+		existTables[strings.ToLower(r["TABLE_NAME"])] = struct{}{}
+	}
+	tableNames, err := meta.MetaTableNames()
+	if err != nil {
+		p.fail(categoryMeta, "meta table", err.Error())
+		return
+	}
+	var notExistTables []string
+	for _, t := range tableNames {
+		if _, ok := existTables[strings.ToLower(t)]; !ok {
+			notExistTables = append(notExistTables, t)
+		}
+	}
+	if len(notExistTables) > 0 {
+		p.fail(categoryMeta, "meta table", fmt.Sprintf("meta tables %v aren't exist, please run [-mode prepare] firstly", notExistTables))
+		return
+	}
+	p.pass(categoryMeta, "meta table", "all meta tables exist")
+}
+
+// Render 检查结果输出
+func (p *Precheck) Render() string {
+	passCounts, warnCounts, failCounts := p.Counts()
+
+	tw := table.NewWriter()
+	tw.SetStyle(table.StyleLight)
+	tw.SetTitle(fmt.Sprintf("PRECHECK MODE [%s] SOURCE [%s] TARGET [%s]", p.TaskMode, p.Cfg.DBTypeS, p.Cfg.DBTypeT))
+	tw.AppendHeader(table.Row{"#", "CATEGORY", "CHECK ITEM", "STATUS", "DETAIL"})
+	tw.SetColumnConfigs([]table.ColumnConfig{{Name: "DETAIL", WidthMax: precheckDetailDisplayWidth}})
+	for i, item := range p.Items {
+		tw.AppendRow(table.Row{i + 1, item.Category, item.Name, item.Status, item.Detail})
+	}
+	tw.AppendFooter(table.Row{"", "", "TOTAL", len(p.Items), fmt.Sprintf("PASS [%d] WARN [%d] FAIL [%d]", passCounts, warnCounts, failCounts)})
+	return tw.Render()
+}
+
+// Counts 检查结果统计
+func (p *Precheck) Counts() (int, int, int) {
+	var passCounts, warnCounts, failCounts int
+	for _, item := range p.Items {
+		switch item.Status {
+		case common.PrecheckStatusPass:
+			passCounts++
+		case common.PrecheckStatusWarn:
+			warnCounts++
+		case common.PrecheckStatusFail:
+			failCounts++
+		}
+	}
+	return passCounts, warnCounts, failCounts
+}
+
+func (p *Precheck) isOracleSource() bool {
+	return strings.EqualFold(p.Cfg.DBTypeS, common.DatabaseTypeOracle) &&
+		(strings.EqualFold(p.Cfg.DBTypeT, common.DatabaseTypeMySQL) || strings.EqualFold(p.Cfg.DBTypeT, common.DatabaseTypeTiDB))
+}
+
+func (p *Precheck) isMySQLSource() bool {
+	return (strings.EqualFold(p.Cfg.DBTypeS, common.DatabaseTypeMySQL) || strings.EqualFold(p.Cfg.DBTypeS, common.DatabaseTypeTiDB)) &&
+		strings.EqualFold(p.Cfg.DBTypeT, common.DatabaseTypeOracle)
+}
+
+// isDataMode 数据抽取模式，需校验字符集
+func (p *Precheck) isDataMode() bool {
+	return common.IsContainString([]string{common.TaskModeFull, common.TaskModeCSV, common.TaskModeAll, common.TaskModeCompare}, p.TaskMode)
+}
+
+func (p *Precheck) pass(category, name, detail string) {
+	p.add(category, name, common.PrecheckStatusPass, detail)
+}
+
+func (p *Precheck) warn(category, name, detail string) {
+	p.add(category, name, common.PrecheckStatusWarn, detail)
+}
+
+func (p *Precheck) fail(category, name, detail string) {
+	p.add(category, name, common.PrecheckStatusFail, detail)
+}
+
+func (p *Precheck) add(category, name, status, detail string) {
+	p.Items = append(p.Items, Item{
+		Category: category,
+		Name:     name,
+		Status:   status,
+		Detail:   detail,
+	})
+	switch status {
+	case common.PrecheckStatusFail:
+		zap.L().Error("precheck item failed", zap.String("category", category), zap.String("item", name), zap.String("detail", detail))
+	case common.PrecheckStatusWarn:
+		zap.L().Warn("precheck item warning", zap.String("category", category), zap.String("item", name), zap.String("detail", detail))
+	}
+}
+
+// displayItems 列表展示，超过展示上限截断
+func displayItems(items []string, limit int) string {
+	if len(items) <= limit {
+		return strings.Join(items, ",")
+	}
+	return fmt.Sprintf("%s ... total [%d]", strings.Join(items[:limit], ","), len(items))
+}
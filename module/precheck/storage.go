@@ -0,0 +1,90 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package precheck
+
+import (
+	"fmt"
+	"github.com/wentaojin/transferdb/selector"
+	"github.com/wentaojin/transferdb/storage"
+	"os"
+	"strings"
+)
+
+// CSV 输出目录剩余空间需大于源端表段大小倍数，低于该倍数告警（CSV 文本与段大小存在差异）
+const csvDiskSpaceWarnRatio = 1.5
+
+// checkCSVOutput csv 输出目录可写以及剩余空间，S3 对象存储仅校验存储连接
+func (p *Precheck) checkCSVOutput() {
+	if strings.EqualFold(p.Cfg.CSVConfig.OutputDir, "") {
+		return
+	}
+	s, err := storage.New(p.Ctx, p.Cfg.CSVConfig.OutputDir, p.Cfg.StorageConfig)
+	if err != nil {
+		p.fail(categoryStorage, "output-dir", fmt.Sprintf("csv output-dir [%s] storage init failed: %v", p.Cfg.CSVConfig.OutputDir, err))
+		return
+	}
+	local, ok := s.(*storage.LocalStorage)
+	if !ok {
+		p.pass(categoryStorage, "output-dir", s.URI())
+		return
+	}
+
+	tmpFile, err := os.CreateTemp(local.BaseDir, ".transferdb-precheck-*")
+	if err != nil {
+		p.fail(categoryStorage, "output-dir", fmt.Sprintf("csv output-dir [%s] isn't writable: %v", local.BaseDir, err))
+		return
+	}
+	if err = tmpFile.Close(); err != nil {
+		p.fail(categoryStorage, "output-dir", fmt.Sprintf("csv output-dir [%s] temp file close failed: %v", local.BaseDir, err))
+		return
+	}
+	if err = os.Remove(tmpFile.Name()); err != nil {
+		p.fail(categoryStorage, "output-dir", fmt.Sprintf("csv output-dir [%s] temp file remove failed: %v", local.BaseDir, err))
+		return
+	}
+	p.pass(categoryStorage, "output-dir", fmt.Sprintf("csv output-dir [%s] writable", local.BaseDir))
+
+	freeBytes, err := getDiskFreeBytes(local.BaseDir)
+	if err != nil {
+		p.warn(categoryStorage, "disk space", err.Error())
+		return
+	}
+	freeMB := float64(freeBytes) / 1024 / 1024
+	if p.Oracle == nil {
+		p.pass(categoryStorage, "disk space", fmt.Sprintf("free [%.2f MB]", freeMB))
+		return
+	}
+
+	// 基于源端待导出表段大小预估
+	tables, _, err := selector.ListOracleTable(p.Cfg, p.Oracle)
+	if err != nil {
+		p.warn(categoryStorage, "disk space", fmt.Sprintf("free [%.2f MB], source table size estimate failed: %v", freeMB, err))
+		return
+	}
+	var tableSize float64
+	for _, t := range tables {
+		tableSize += t.TableSize
+	}
+	detail := fmt.Sprintf("free [%.2f MB], source tables [%d] segment size [%.2f MB]", freeMB, len(tables), tableSize)
+	switch {
+	case freeMB < tableSize:
+		p.fail(categoryStorage, "disk space", detail)
+	case freeMB < tableSize*csvDiskSpaceWarnRatio:
+		p.warn(categoryStorage, "disk space", detail)
+	default:
+		p.pass(categoryStorage, "disk space", detail)
+	}
+}
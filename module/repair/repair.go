@@ -0,0 +1,460 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package repair
+
+import (
+	"context"
+	"fmt"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/wentaojin/transferdb/common"
+	"github.com/wentaojin/transferdb/config"
+	"github.com/wentaojin/transferdb/database/meta"
+	"github.com/wentaojin/transferdb/database/mysql"
+	"github.com/wentaojin/transferdb/database/oracle"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+// 错误详情展示最大字符数
+const repairErrorDisplayLength = 256
+
+// Repair 任务修复，替代手工修改元数据表 [wait_sync_meta]、[full_sync_meta]、[chunk_error_detail] 以及清理目标表
+type Repair struct {
+	Ctx      context.Context
+	Cfg      *config.Config
+	MetaDB   *meta.Meta
+	TaskMode string
+}
+
+func NewRepair(ctx context.Context, cfg *config.Config) (*Repair, error) {
+	metaDB, err := meta.NewMetaDBEngine(ctx, cfg.MetaConfig, cfg.AppConfig.SlowlogThreshold)
+	if err != nil {
+		return nil, err
+	}
+	return &Repair{
+		Ctx:      ctx,
+		Cfg:      cfg,
+		MetaDB:   metaDB,
+		TaskMode: common.StringUPPER(cfg.RepairConfig.TaskMode),
+	}, nil
+}
+
+func IRepair(ctx context.Context, cfg *config.Config) error {
+	startTime := time.Now()
+
+	switch common.StringUPPER(cfg.RepairConfig.TaskMode) {
+	case common.TaskModeFull, common.TaskModeCSV, common.TaskModeAll:
+	default:
+		return fmt.Errorf("repair mode [%s] isn't support, support repair mode [full csv all]", cfg.RepairConfig.TaskMode)
+	}
+	if !strings.EqualFold(cfg.RepairConfig.ChunkDetail, "") && strings.EqualFold(cfg.RepairConfig.TableNameS, "") {
+		return fmt.Errorf("repair chunk [%s] need specify flag [repair-table]", cfg.RepairConfig.ChunkDetail)
+	}
+
+	r, err := NewRepair(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	switch common.StringUPPER(cfg.RepairConfig.Action) {
+	case common.RepairActionList:
+		err = r.List()
+	case common.RepairActionReset:
+		err = r.Reset()
+	case common.RepairActionTruncate:
+		err = r.Truncate()
+	case common.RepairActionDone:
+		err = r.Done()
+	default:
+		return fmt.Errorf("repair action [%s] isn't support, support repair action [list reset truncate done]", cfg.RepairConfig.Action)
+	}
+	if err != nil {
+		return err
+	}
+
+	zap.L().Info("repair task finished",
+		zap.String("schema", r.Cfg.SchemaConfig.SourceSchema),
+		zap.String("mode", r.TaskMode),
+		zap.String("action", common.StringUPPER(cfg.RepairConfig.Action)),
+		zap.String("table", cfg.RepairConfig.TableNameS),
+		zap.String("chunk", cfg.RepairConfig.ChunkDetail),
+		zap.String("cost", time.Now().Sub(startTime).String()))
+	return nil
+}
+
+// List 列出失败表（指定表时不区分表状态）、失败 chunk 错误、隔离行数以及 [error_log_detail] 错误
+func (r *Repair) List() error {
+	tables, err := r.repairTables(false)
+	if err != nil {
+		return err
+	}
+
+	tw := table.NewWriter()
+	tw.SetStyle(table.StyleLight)
+	tw.SetTitle("FAILED TABLE")
+	tw.AppendHeader(table.Row{"#", "SCHEMA", "TABLE", "MODE", "STATUS", "CHUNK TOTAL", "CHUNK SUCCESS", "CHUNK FAILED", "QUARANTINE ROWS"})
+
+	cw := table.NewWriter()
+	cw.SetStyle(table.StyleLight)
+	cw.SetTitle("FAILED CHUNK")
+	cw.AppendHeader(table.Row{"#", "TABLE", "CHUNK", "ERROR CLASS", "ERROR DETAIL"})
+
+	for i, t := range tables {
+		quarantineRows, err := meta.NewRowErrorDetailModel(r.MetaDB).CountsRowErrorDetailBySchemaTable(r.Ctx, &meta.RowErrorDetail{
+			DBTypeS:     t.DBTypeS,
+			DBTypeT:     t.DBTypeT,
+			SchemaNameS: t.SchemaNameS,
+			TableNameS:  t.TableNameS,
+			TaskMode:    t.TaskMode,
+		})
+		if err != nil {
+			return err
+		}
+		tw.AppendRow(table.Row{i + 1, t.SchemaNameS, t.TableNameS, t.TaskMode, t.TaskStatus, t.ChunkTotalNums, t.ChunkSuccessNums, t.ChunkFailedNums, quarantineRows})
+
+		chunkErrs, err := meta.NewChunkErrorDetailModel(r.MetaDB).DetailChunkErrorDetailBySchemaTable(r.Ctx, &meta.ChunkErrorDetail{
+			DBTypeS:     t.DBTypeS,
+			DBTypeT:     t.DBTypeT,
+			SchemaNameS: t.SchemaNameS,
+			TableNameS:  t.TableNameS,
+			TaskMode:    t.TaskMode,
+		})
+		if err != nil {
+			return err
+		}
+		for _, c := range chunkErrs {
+			cw.AppendRow(table.Row{cw.Length() + 1, c.TableNameS, c.ChunkDetailS, c.ErrorClass, truncateErrorDetail(c.ErrorDetail)})
+		}
+	}
+
+	ew := table.NewWriter()
+	ew.SetStyle(table.StyleLight)
+	ew.SetTitle("ERROR LOG")
+	ew.AppendHeader(table.Row{"#", "TABLE", "STATUS", "ERROR DETAIL"})
+	errLogs, err := meta.NewErrorLogDetailModel(r.MetaDB).DetailErrorLog(r.Ctx, &meta.ErrorLogDetail{
+		DBTypeS:     r.Cfg.DBTypeS,
+		DBTypeT:     r.Cfg.DBTypeT,
+		SchemaNameS: r.Cfg.SchemaConfig.SourceSchema,
+		TaskMode:    r.TaskMode,
+	})
+	if err != nil {
+		return err
+	}
+	for _, e := range errLogs {
+		if !strings.EqualFold(r.Cfg.RepairConfig.TableNameS, "") && !strings.EqualFold(e.TableNameS, r.Cfg.RepairConfig.TableNameS) {
+			continue
+		}
+		ew.AppendRow(table.Row{ew.Length() + 1, e.TableNameS, e.TaskStatus, truncateErrorDetail(e.ErrorDetail)})
+	}
+
+	fmt.Println(tw.Render())
+	fmt.Println(cw.Render())
+	fmt.Println(ew.Render())
+	fmt.Println(`more detail please see meta table [chunk_error_detail], [row_error_detail] and [error_log_detail]`)
+	return nil
+}
+
+// Reset 重置失败 chunk（指定 chunk、指定表或者任务所有失败表），表状态重置 RUNNING，重新运行时断点续传
+// chunk 切分记录不完整的表无法断点续传，需使用 truncate 全量重跑
+func (r *Repair) Reset() error {
+	tables, err := r.repairTables(true)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range tables {
+		chunkCounts, err := meta.NewFullSyncMetaModel(r.MetaDB).CountsFullSyncMetaByTaskTable(r.Ctx, &meta.FullSyncMeta{
+			DBTypeS:     t.DBTypeS,
+			DBTypeT:     t.DBTypeT,
+			SchemaNameS: t.SchemaNameS,
+			TableNameS:  t.TableNameS,
+			TaskMode:    t.TaskMode,
+		})
+		if err != nil {
+			return err
+		}
+		if t.ChunkTotalNums <= 0 || chunkCounts != t.ChunkTotalNums {
+			return fmt.Errorf("repair table [%s.%s] mode [%s] chunk meta isn't consistent (chunk total [%d], full_sync_meta [%d]), can't be reset, please use repair-action truncate",
+				t.SchemaNameS, t.TableNameS, t.TaskMode, t.ChunkTotalNums, chunkCounts)
+		}
+
+		if !strings.EqualFold(r.Cfg.RepairConfig.ChunkDetail, "") {
+			chunks, err := meta.NewFullSyncMetaModel(r.MetaDB).DetailFullSyncMeta(r.Ctx, &meta.FullSyncMeta{
+				DBTypeS:      t.DBTypeS,
+				DBTypeT:      t.DBTypeT,
+				SchemaNameS:  t.SchemaNameS,
+				TableNameS:   t.TableNameS,
+				TaskMode:     t.TaskMode,
+				ChunkDetailS: r.Cfg.RepairConfig.ChunkDetail,
+			})
+			if err != nil {
+				return err
+			}
+			if len(chunks) == 0 {
+				return fmt.Errorf("repair table [%s.%s] mode [%s] chunk [%s] isn't exist in meta table [full_sync_meta]",
+					t.SchemaNameS, t.TableNameS, t.TaskMode, r.Cfg.RepairConfig.ChunkDetail)
+			}
+		}
+
+		err = meta.NewCommonModel(r.MetaDB).ResetFullSyncMetaChunkAndUpdateWaitSyncMeta(r.Ctx, &meta.FullSyncMeta{
+			DBTypeS:      t.DBTypeS,
+			DBTypeT:      t.DBTypeT,
+			SchemaNameS:  t.SchemaNameS,
+			TableNameS:   t.TableNameS,
+			TaskMode:     t.TaskMode,
+			ChunkDetailS: r.Cfg.RepairConfig.ChunkDetail,
+		}, &meta.WaitSyncMeta{
+			DBTypeS:     t.DBTypeS,
+			DBTypeT:     t.DBTypeT,
+			SchemaNameS: t.SchemaNameS,
+			TableNameS:  t.TableNameS,
+			TaskMode:    t.TaskMode,
+		})
+		if err != nil {
+			return err
+		}
+		zap.L().Info("repair table reset finished, rerunning task will resume failed chunk",
+			zap.String("schema", t.SchemaNameS),
+			zap.String("table", t.TableNameS),
+			zap.String("mode", t.TaskMode),
+			zap.String("chunk", r.Cfg.RepairConfig.ChunkDetail))
+	}
+	return nil
+}
+
+// Truncate 清理目标表数据（csv 模式不清理）以及表元数据，表状态重置 WAITING，重新运行时重新切分 chunk 全量重跑
+// 先重置表元数据再清理目标表，避免目标表已清理而元数据重置失败，重新运行时只断点续传失败 chunk 导致目标表数据缺失
+func (r *Repair) Truncate() error {
+	tables, err := r.repairSpecifiedTable()
+	if err != nil {
+		return err
+	}
+	t := tables[0]
+
+	// 重置元数据前校验目标表，目标表不存在直接拒绝，不修改元数据
+	var truncateTarget func() error
+	if !strings.EqualFold(r.TaskMode, common.TaskModeCSV) {
+		truncateTarget, err = r.prepareTruncateTarget(t.TableNameS)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = meta.NewCommonModel(r.MetaDB).DeleteTableFullSyncMetaAndResetWaitSyncMeta(r.Ctx, &meta.FullSyncMeta{
+		DBTypeS:     t.DBTypeS,
+		DBTypeT:     t.DBTypeT,
+		SchemaNameS: t.SchemaNameS,
+		TableNameS:  t.TableNameS,
+		TaskMode:    t.TaskMode,
+	}, &meta.WaitSyncMeta{
+		DBTypeS:     t.DBTypeS,
+		DBTypeT:     t.DBTypeT,
+		SchemaNameS: t.SchemaNameS,
+		TableNameS:  t.TableNameS,
+		TaskMode:    t.TaskMode,
+	})
+	if err != nil {
+		return err
+	}
+
+	if truncateTarget != nil {
+		if err = truncateTarget(); err != nil {
+			return fmt.Errorf("repair table [%s.%s] meta has been reset, but %v, please rerun repair truncate before rerunning task", t.SchemaNameS, t.TableNameS, err)
+		}
+	}
+	zap.L().Info("repair table truncate finished, rerunning task will restart table",
+		zap.String("schema", t.SchemaNameS),
+		zap.String("table", t.TableNameS),
+		zap.String("mode", t.TaskMode))
+	return nil
+}
+
+// prepareTruncateTarget 解析并校验目标表，与迁移一致按表名规则以及不区分大小写匹配目标端实际表名，匹配失败拒绝清理
+func (r *Repair) prepareTruncateTarget(tableNameS string) (func() error, error) {
+	tableNameRule, err := r.getTableNameRule()
+	if err != nil {
+		return nil, err
+	}
+	targetTable := common.StringUPPER(tableNameS)
+	if val, ok := tableNameRule[common.StringUPPER(tableNameS)]; ok {
+		targetTable = val
+	}
+
+	var truncateTable func() error
+	if strings.EqualFold(r.Cfg.DBTypeT, common.DatabaseTypeOracle) {
+		oracleDB, err := oracle.NewOracleDBEngine(r.Ctx, r.Cfg.OracleConfig, r.Cfg.SchemaConfig.TargetSchema)
+		if err != nil {
+			return nil, err
+		}
+		columns, err := oracleDB.GetOracleTableColumnDataType(r.Cfg.SchemaConfig.TargetSchema, targetTable)
+		if err != nil {
+			return nil, err
+		}
+		if len(columns) == 0 {
+			return nil, fmt.Errorf("repair table [%s.%s] target table [%s.%s] isn't exist, refuse to truncate, please check table name rule",
+				r.Cfg.SchemaConfig.SourceSchema, tableNameS, r.Cfg.SchemaConfig.TargetSchema, targetTable)
+		}
+		targetTable = columns[0]["TABLE_NAME"]
+		truncateTable = func() error {
+			return oracleDB.TruncateOracleTable(common.StringUPPER(r.Cfg.SchemaConfig.TargetSchema), targetTable)
+		}
+	} else {
+		mysqlDB, err := mysql.NewMySQLDBEngine(r.Ctx, r.Cfg.MySQLConfig)
+		if err != nil {
+			return nil, err
+		}
+		existTables, err := mysqlDB.FilterIntersectionMySQLTable(r.Cfg.SchemaConfig.TargetSchema, []string{targetTable})
+		if err != nil {
+			return nil, err
+		}
+		if len(existTables) == 0 {
+			return nil, fmt.Errorf("repair table [%s.%s] target table [%s.%s] isn't exist, refuse to truncate, please check table name rule",
+				r.Cfg.SchemaConfig.SourceSchema, tableNameS, r.Cfg.SchemaConfig.TargetSchema, targetTable)
+		}
+		truncateTable = func() error {
+			return mysqlDB.TruncateMySQLTable(r.Cfg.SchemaConfig.TargetSchema, targetTable)
+		}
+	}
+
+	return func() error {
+		if err := truncateTable(); err != nil {
+			return fmt.Errorf("repair truncate target table [%s.%s] failed: %v", r.Cfg.SchemaConfig.TargetSchema, targetTable, err)
+		}
+		zap.L().Info("truncate table",
+			zap.String("schema", r.Cfg.SchemaConfig.TargetSchema),
+			zap.String("table", targetTable),
+			zap.String("status", "success"))
+		return nil
+	}, nil
+}
+
+// Done 人工确认表数据已处理完成（例如手工补齐失败 chunk 数据），清理表元数据，表状态标记 SUCCESS
+func (r *Repair) Done() error {
+	tables, err := r.repairSpecifiedTable()
+	if err != nil {
+		return err
+	}
+
+	t := tables[0]
+	err = meta.NewCommonModel(r.MetaDB).DeleteTableFullSyncMetaAndMarkWaitSyncMeta(r.Ctx, &meta.FullSyncMeta{
+		DBTypeS:     t.DBTypeS,
+		DBTypeT:     t.DBTypeT,
+		SchemaNameS: t.SchemaNameS,
+		TableNameS:  t.TableNameS,
+		TaskMode:    t.TaskMode,
+	}, &meta.WaitSyncMeta{
+		DBTypeS:     t.DBTypeS,
+		DBTypeT:     t.DBTypeT,
+		SchemaNameS: t.SchemaNameS,
+		TableNameS:  t.TableNameS,
+		TaskMode:    t.TaskMode,
+	})
+	if err != nil {
+		return err
+	}
+	zap.L().Warn("repair table marked as done, table data need be ensured manually",
+		zap.String("schema", t.SchemaNameS),
+		zap.String("table", t.TableNameS),
+		zap.String("mode", t.TaskMode))
+	return nil
+}
+
+// repairTables 指定表返回对应表记录，否则返回任务所有失败表；onlyFailed 代表指定表必须处于 FAILED 状态
+func (r *Repair) repairTables(onlyFailed bool) ([]meta.WaitSyncMeta, error) {
+	queryS := &meta.WaitSyncMeta{
+		DBTypeS:     r.Cfg.DBTypeS,
+		DBTypeT:     r.Cfg.DBTypeT,
+		SchemaNameS: common.StringUPPER(r.Cfg.SchemaConfig.SourceSchema),
+		TaskMode:    r.TaskMode,
+	}
+	if strings.EqualFold(r.Cfg.RepairConfig.TableNameS, "") {
+		queryS.TaskStatus = common.TaskStatusFailed
+	} else {
+		queryS.TableNameS = common.StringUPPER(r.Cfg.RepairConfig.TableNameS)
+	}
+
+	tables, err := meta.NewWaitSyncMetaModel(r.MetaDB).DetailWaitSyncMeta(r.Ctx, queryS)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(r.Cfg.RepairConfig.TableNameS, "") {
+		if len(tables) == 0 {
+			return nil, fmt.Errorf("repair table [%s.%s] mode [%s] isn't exist in meta table [wait_sync_meta]",
+				r.Cfg.SchemaConfig.SourceSchema, r.Cfg.RepairConfig.TableNameS, r.TaskMode)
+		}
+		if onlyFailed && !strings.EqualFold(tables[0].TaskStatus, common.TaskStatusFailed) {
+			return nil, fmt.Errorf("repair table [%s.%s] mode [%s] task status [%s] isn't FAILED, skip reset",
+				r.Cfg.SchemaConfig.SourceSchema, r.Cfg.RepairConfig.TableNameS, r.TaskMode, tables[0].TaskStatus)
+		}
+	}
+	if len(tables) == 0 {
+		zap.L().Info("repair task isn't exist failed table",
+			zap.String("schema", r.Cfg.SchemaConfig.SourceSchema),
+			zap.String("mode", r.TaskMode))
+	}
+	return tables, nil
+}
+
+// repairSpecifiedTable truncate、done 动作只允许指定单表
+func (r *Repair) repairSpecifiedTable() ([]meta.WaitSyncMeta, error) {
+	if strings.EqualFold(r.Cfg.RepairConfig.TableNameS, "") {
+		return nil, fmt.Errorf("repair action [%s] need specify flag [repair-table]", r.Cfg.RepairConfig.Action)
+	}
+	return r.repairTables(false)
+}
+
+// getTableNameRule 目标表名规则，与迁移一致，优先自定义表名规则 [table_name_rule]
+// 源端 MySQL/TiDB 未配置表名规则的表按正向迁移 Oracle -> MySQL/TiDB 表名规则反向映射
+func (r *Repair) getTableNameRule() (map[string]string, error) {
+	tableNameRules, err := meta.NewTableNameRuleModel(r.MetaDB).DetailTableNameRule(r.Ctx, &meta.TableNameRule{
+		DBTypeS:     r.Cfg.DBTypeS,
+		DBTypeT:     r.Cfg.DBTypeT,
+		SchemaNameS: r.Cfg.SchemaConfig.SourceSchema,
+		SchemaNameT: r.Cfg.SchemaConfig.TargetSchema,
+	})
+	if err != nil {
+		return nil, err
+	}
+	tableNameRuleMap := make(map[string]string)
+
+	if strings.EqualFold(r.Cfg.DBTypeT, common.DatabaseTypeOracle) {
+		reverseNameRules, err := meta.NewTableNameRuleModel(r.MetaDB).DetailTableNameRule(r.Ctx, &meta.TableNameRule{
+			DBTypeS:     common.DatabaseTypeOracle,
+			DBTypeT:     r.Cfg.DBTypeS,
+			SchemaNameS: r.Cfg.SchemaConfig.TargetSchema,
+			SchemaNameT: r.Cfg.SchemaConfig.SourceSchema,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, tr := range reverseNameRules {
+			tableNameRuleMap[common.StringUPPER(tr.TableNameT)] = common.StringUPPER(tr.TableNameS)
+		}
+	}
+	for _, tr := range tableNameRules {
+		tableNameRuleMap[common.StringUPPER(tr.TableNameS)] = common.StringUPPER(tr.TableNameT)
+	}
+	return tableNameRuleMap, nil
+}
+
+func truncateErrorDetail(errDetail string) string {
+	runes := []rune(errDetail)
+	if len(runes) > repairErrorDisplayLength {
+		return common.StringsBuilder(string(runes[:repairErrorDisplayLength]), "...")
+	}
+	return errDetail
+}
@@ -477,6 +477,7 @@ func (r *Rule) GenTableColumn() (columnMetas []string, err error) {
 		} else {
 			return columnMetas, fmt.Errorf("mysql table [%s.%s] column [%s] data type isn't exist", r.SourceSchemaName, r.SourceTableName, columnName)
 		}
+		columnName = r.Mapper.Table(r.SourceTableName).ColumnNameT(columnName)
 
 		// 字段名大小写
 		if strings.EqualFold(r.LowerCaseFieldName, common.MigrateTableStructFieldNameLowerCase) {
@@ -543,7 +544,7 @@ func (r *Rule) GenTableColumnComment() (columnComments []string, err error) {
 				}
 
 				// 字段名大小写
-				columnName := rowCol["COLUMN_NAME"]
+				columnName := r.Mapper.Table(r.SourceTableName).ColumnNameT(rowCol["COLUMN_NAME"])
 				if strings.EqualFold(r.LowerCaseFieldName, common.MigrateTableStructFieldNameLowerCase) {
 					columnName = strings.ToLower(columnName)
 				}
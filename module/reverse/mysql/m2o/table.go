@@ -25,6 +25,7 @@ import (
 	"github.com/wentaojin/transferdb/database/meta"
 	"github.com/wentaojin/transferdb/database/mysql"
 	"github.com/wentaojin/transferdb/database/oracle"
+	"github.com/wentaojin/transferdb/mapping"
 	"github.com/wentaojin/transferdb/module/reverse"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
@@ -54,6 +55,7 @@ type Table struct {
 	Oracle                          *oracle.Oracle    `json:"-"`
 	MySQL                           *mysql.MySQL      `json:"-"`
 	MetaDB                          *meta.Meta        `json:"-"`
+	Mapper                          *mapping.Mapper   `json:"-"`
 }
 
 func PreCheckCompatibility(cfg *config.Config, mysql *mysql.MySQL, exporters []string, oracleDBVersion, oracleDBCharset string, isExtended bool) ([]string, map[string][]map[string]string, map[string][]map[string]string, map[string]string, map[string]string, error) {
@@ -347,6 +349,7 @@ func GenReverseTableTask(r *Reverse, lowerCaseFieldName string, tableNameRule ma
 					MySQL:                           r.mysql,
 					Oracle:                          r.oracle,
 					MetaDB:                          r.metaDB,
+					Mapper:                          r.mapper,
 				}
 				tableChan <- tbl
 				return nil
@@ -480,6 +483,17 @@ func (t *Table) GetTableInfo() (interface{}, error) {
 		return nil, err
 	}
 
+	// 字段映射规则，排除字段以及引用排除字段的约束、索引不迁移，重命名字段转换为目标端字段名
+	columnMapper := t.Mapper.Table(t.SourceTableName)
+	primaryKey = columnMapper.ChangeKeyINFO(primaryKey)
+	uniqueKey = columnMapper.ChangeKeyINFO(uniqueKey)
+	foreignKey = t.Mapper.ChangeForeignKeyINFO(t.SourceSchemaName, t.SourceTableName, foreignKey)
+	checkKey = columnMapper.ChangeCheckKeyINFO(checkKey)
+	uniqueIndex = columnMapper.ChangeKeyINFO(uniqueIndex)
+	normalIndex = columnMapper.ChangeKeyINFO(normalIndex)
+	columnMeta = columnMapper.FilterColumnINFO(columnMeta)
+	columnComment = columnMapper.FilterColumnINFO(columnComment)
+
 	return &Info{
 		SourceTableDDL:       ddl,
 		PrimaryKeyINFO:       primaryKey,
@@ -23,11 +23,13 @@ import (
 	"github.com/wentaojin/transferdb/database/meta"
 	"github.com/wentaojin/transferdb/database/mysql"
 	"github.com/wentaojin/transferdb/database/oracle"
+	"github.com/wentaojin/transferdb/mapping"
 	"github.com/wentaojin/transferdb/module/reverse"
 	"github.com/wentaojin/transferdb/module/reverse/mysql/public"
+	"github.com/wentaojin/transferdb/selector"
+	"github.com/wentaojin/transferdb/storage"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
-	"path/filepath"
 	"strings"
 	"time"
 )
@@ -38,6 +40,7 @@ type Reverse struct {
 	mysql  *mysql.MySQL
 	oracle *oracle.Oracle
 	metaDB *meta.Meta
+	mapper *mapping.Mapper
 }
 
 func NewReverse(ctx context.Context, cfg *config.Config) (*Reverse, error) {
@@ -75,7 +78,7 @@ func (r *Reverse) Reverse() error {
 		zap.String("schema", r.cfg.SchemaConfig.SourceSchema))
 
 	// 获取配置文件待同步表列表
-	exporters, viewTables, err := public.FilterCFGTable(r.cfg, r.mysql)
+	exporters, viewTables, err := selector.SelectMySQLTableAndView(r.cfg, r.mysql)
 	if err != nil {
 		return err
 	}
@@ -145,24 +148,23 @@ func (r *Reverse) Reverse() error {
 		zap.String("schema", r.cfg.SchemaConfig.SourceSchema),
 		zap.String("cost", time.Now().Sub(ruleTime).String()))
 
-	tables, err := GenReverseTableTask(r, r.cfg.ReverseConfig.LowerCaseFieldName, tableNameRuleMap, tableColumnRuleMap, tableDefaultRuleSource, tableDefaultRuleMap, reverseTaskTables, oracleDBVersion, isExtended, tableCharSetMap, tableCollationMap)
+	// 字段映射规则
+	mapper, err := mapping.NewMapper(r.ctx, r.metaDB, r.cfg.DBTypeS, r.cfg.DBTypeT, r.cfg.SchemaConfig.SourceSchema)
 	if err != nil {
 		return err
 	}
+	r.mapper = mapper
 
-	// file writer
-	err = common.PathExist(r.cfg.ReverseConfig.DDLReverseDir)
-	if err != nil {
-		return err
-	}
-	err = common.PathExist(r.cfg.ReverseConfig.DDLCompatibleDir)
+	tables, err := GenReverseTableTask(r, r.cfg.ReverseConfig.LowerCaseFieldName, tableNameRuleMap, tableColumnRuleMap, tableDefaultRuleSource, tableDefaultRuleMap, reverseTaskTables, oracleDBVersion, isExtended, tableCharSetMap, tableCollationMap)
 	if err != nil {
 		return err
 	}
-	reverseFile := filepath.Join(r.cfg.ReverseConfig.DDLReverseDir, fmt.Sprintf("reverse_%s.sql", r.cfg.SchemaConfig.SourceSchema))
-	compFile := filepath.Join(r.cfg.ReverseConfig.DDLCompatibleDir, fmt.Sprintf("compatibility_%s.sql", r.cfg.SchemaConfig.SourceSchema))
 
-	f, err := reverse.NewWriter(r.cfg, r.mysql, r.oracle, reverseFile, compFile)
+	// file writer
+	reverseFile := storage.JoinPath(r.cfg.ReverseConfig.DDLReverseDir, fmt.Sprintf("reverse_%s.sql", r.cfg.SchemaConfig.SourceSchema))
+	compFile := storage.JoinPath(r.cfg.ReverseConfig.DDLCompatibleDir, fmt.Sprintf("compatibility_%s.sql", r.cfg.SchemaConfig.SourceSchema))
+
+	f, err := reverse.NewWriter(r.ctx, r.cfg, r.mysql, r.oracle, reverseFile, compFile)
 	if err != nil {
 		return err
 	}
@@ -286,10 +288,10 @@ func (r *Reverse) Reverse() error {
 
 	endTime := time.Now()
 	if !r.cfg.ReverseConfig.DirectWrite {
-		zap.L().Info("reverse", zap.String("create table and index output", filepath.Join(r.cfg.ReverseConfig.DDLReverseDir,
+		zap.L().Info("reverse", zap.String("create table and index output", storage.JoinPath(r.cfg.ReverseConfig.DDLReverseDir,
 			fmt.Sprintf("reverse_%s.sql", r.cfg.SchemaConfig.SourceSchema))))
 	}
-	zap.L().Info("compatibility", zap.String("maybe exist compatibility output", filepath.Join(r.cfg.ReverseConfig.DDLCompatibleDir,
+	zap.L().Info("compatibility", zap.String("maybe exist compatibility output", storage.JoinPath(r.cfg.ReverseConfig.DDLCompatibleDir,
 		fmt.Sprintf("compatibility_%s.sql", r.cfg.SchemaConfig.SourceSchema))))
 	if errTotals == 0 {
 		zap.L().Info("reverse table tidb to oracle finished",
@@ -824,7 +824,7 @@ func (r *Rule) GenTableColumn() (tableColumns []string, err error) {
 		}
 
 		// 字段名大小写
-		columnName := rowCol["COLUMN_NAME"]
+		columnName := r.Mapper.Table(r.SourceTableName).ColumnNameT(rowCol["COLUMN_NAME"])
 		if strings.EqualFold(r.LowerCaseFieldName, common.MigrateTableStructFieldNameLowerCase) {
 			columnName = strings.ToLower(columnName)
 		}
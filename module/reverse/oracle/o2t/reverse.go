@@ -23,11 +23,13 @@ import (
 	"github.com/wentaojin/transferdb/database/meta"
 	"github.com/wentaojin/transferdb/database/mysql"
 	"github.com/wentaojin/transferdb/database/oracle"
+	"github.com/wentaojin/transferdb/mapping"
 	"github.com/wentaojin/transferdb/module/reverse"
 	"github.com/wentaojin/transferdb/module/reverse/oracle/public"
+	"github.com/wentaojin/transferdb/selector"
+	"github.com/wentaojin/transferdb/storage"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
-	"path/filepath"
 	"strings"
 	"time"
 )
@@ -38,6 +40,7 @@ type Reverse struct {
 	Mysql  *mysql.MySQL
 	Oracle *oracle.Oracle
 	MetaDB *meta.Meta
+	Mapper *mapping.Mapper
 }
 
 func NewReverse(ctx context.Context, cfg *config.Config) (*Reverse, error) {
@@ -75,7 +78,7 @@ func (r *Reverse) Reverse() error {
 		zap.String("schema", r.Cfg.SchemaConfig.SourceSchema))
 
 	// 获取配置文件待同步表列表
-	exporters, err := public.FilterCFGTable(r.Cfg, r.Oracle)
+	exporters, err := selector.SelectOracleTable(r.Cfg, r.Oracle)
 	if err != nil {
 		return err
 	}
@@ -162,25 +165,24 @@ func (r *Reverse) Reverse() error {
 		zap.String("schema", r.Cfg.SchemaConfig.SourceSchema),
 		zap.String("cost", time.Now().Sub(ruleTime).String()))
 
-	// 获取 reverse 表任务列表
-	tables, err := GenReverseTableTask(r, tableNameRuleMap, tableColumnRuleMap, tableDefaultRuleSourceMap, tableDefaultRuleMap, oracleDBVersion, oracleDBCharset, r.Cfg.MySQLConfig.Charset, oracleCollation, r.Cfg.ReverseConfig.LowerCaseFieldName, exporterTables, nlsSort, nlsComp)
+	// 字段映射规则
+	mapper, err := mapping.NewMapper(r.Ctx, r.MetaDB, r.Cfg.DBTypeS, r.Cfg.DBTypeT, r.Cfg.SchemaConfig.SourceSchema)
 	if err != nil {
 		return err
 	}
+	r.Mapper = mapper
 
-	// file writer
-	err = common.PathExist(r.Cfg.ReverseConfig.DDLReverseDir)
-	if err != nil {
-		return err
-	}
-	err = common.PathExist(r.Cfg.ReverseConfig.DDLCompatibleDir)
+	// 获取 reverse 表任务列表
+	tables, err := GenReverseTableTask(r, tableNameRuleMap, tableColumnRuleMap, tableDefaultRuleSourceMap, tableDefaultRuleMap, oracleDBVersion, oracleDBCharset, r.Cfg.MySQLConfig.Charset, oracleCollation, r.Cfg.ReverseConfig.LowerCaseFieldName, exporterTables, nlsSort, nlsComp)
 	if err != nil {
 		return err
 	}
-	reverseFile := filepath.Join(r.Cfg.ReverseConfig.DDLReverseDir, fmt.Sprintf("reverse_%s.sql", r.Cfg.SchemaConfig.SourceSchema))
-	compFile := filepath.Join(r.Cfg.ReverseConfig.DDLCompatibleDir, fmt.Sprintf("compatibility_%s.sql", r.Cfg.SchemaConfig.SourceSchema))
 
-	f, err := reverse.NewWriter(r.Cfg, r.Mysql, r.Oracle, reverseFile, compFile)
+	// file writer
+	reverseFile := storage.JoinPath(r.Cfg.ReverseConfig.DDLReverseDir, fmt.Sprintf("reverse_%s.sql", r.Cfg.SchemaConfig.SourceSchema))
+	compFile := storage.JoinPath(r.Cfg.ReverseConfig.DDLCompatibleDir, fmt.Sprintf("compatibility_%s.sql", r.Cfg.SchemaConfig.SourceSchema))
+
+	f, err := reverse.NewWriter(r.Ctx, r.Cfg, r.Mysql, r.Oracle, reverseFile, compFile)
 	if err != nil {
 		return err
 	}
@@ -306,10 +308,10 @@ func (r *Reverse) Reverse() error {
 
 	endTime := time.Now()
 	if !r.Cfg.ReverseConfig.DirectWrite {
-		zap.L().Info("reverse", zap.String("create table and index output", filepath.Join(r.Cfg.ReverseConfig.DDLReverseDir,
+		zap.L().Info("reverse", zap.String("create table and index output", storage.JoinPath(r.Cfg.ReverseConfig.DDLReverseDir,
 			fmt.Sprintf("reverse_%s.sql", r.Cfg.SchemaConfig.SourceSchema))))
 	}
-	zap.L().Info("compatibility", zap.String("maybe exist compatibility output", filepath.Join(r.Cfg.ReverseConfig.DDLCompatibleDir,
+	zap.L().Info("compatibility", zap.String("maybe exist compatibility output", storage.JoinPath(r.Cfg.ReverseConfig.DDLCompatibleDir,
 		fmt.Sprintf("compatibility_%s.sql", r.Cfg.SchemaConfig.SourceSchema))))
 	if errTotals == 0 {
 		zap.L().Info("reverse table oracle to tidb finished",
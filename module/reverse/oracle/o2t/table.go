@@ -24,6 +24,7 @@ import (
 	"github.com/wentaojin/transferdb/database/meta"
 	"github.com/wentaojin/transferdb/database/mysql"
 	"github.com/wentaojin/transferdb/database/oracle"
+	"github.com/wentaojin/transferdb/mapping"
 	"github.com/wentaojin/transferdb/module/reverse"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
@@ -56,6 +57,7 @@ type Table struct {
 	Oracle                          *oracle.Oracle    `json:"-"`
 	MySQL                           *mysql.MySQL      `json:"-"`
 	MetaDB                          *meta.Meta        `json:"-"`
+	Mapper                          *mapping.Mapper   `json:"-"`
 }
 
 func GenReverseTableTask(r *Reverse, tableNameRule map[string]string, tableColumnRule map[string]map[string]string, tableDefaultSourceRule map[string]map[string]bool, tableDefaultRule map[string]map[string]string, oracleDBVersion string, oracleDBCharset, targetDBCharset string, oracleCollation bool, lowerCaseFieldName string, exporters []string, nlsSort, nlsComp string) ([]*Table, error) {
@@ -162,6 +164,7 @@ func GenReverseTableTask(r *Reverse, tableNameRule map[string]string, tableColum
 					Oracle:                          r.Oracle,
 					MySQL:                           r.Mysql,
 					MetaDB:                          r.MetaDB,
+					Mapper:                          r.Mapper,
 				}
 				tbl.OracleCollation = oracleCollation
 				if oracleCollation {
@@ -286,6 +289,17 @@ func (t *Table) GetTableInfo() (interface{}, error) {
 		return nil, err
 	}
 
+	// 字段映射规则，排除字段以及引用排除字段的约束、索引不迁移，重命名字段转换为目标端字段名
+	columnMapper := t.Mapper.Table(t.SourceTableName)
+	primaryKey = columnMapper.ChangeKeyINFO(primaryKey)
+	uniqueKey = columnMapper.ChangeKeyINFO(uniqueKey)
+	foreignKey = t.Mapper.ChangeForeignKeyINFO(t.SourceSchemaName, t.SourceTableName, foreignKey)
+	checkKey = columnMapper.ChangeCheckKeyINFO(checkKey)
+	uniqueIndex = columnMapper.ChangeKeyINFO(uniqueIndex)
+	normalIndex = columnMapper.ChangeKeyINFO(normalIndex)
+	columnMeta = columnMapper.FilterColumnINFO(columnMeta)
+	columnComment = columnMapper.FilterColumnINFO(columnComment)
+
 	return &Info{
 		SourceTableDDL:    ddl,
 		PrimaryKeyINFO:    primaryKey,
@@ -182,9 +182,10 @@ func (r *Change) ChangeTableColumnDatatype() (map[string]map[string]string, erro
 
 			for _, rowCol := range tableColumnINFO {
 				originColumnType, buildInColumnType, err := OracleTableColumnMapMySQLRule(r.SourceSchemaName, sourceTable, Column{
-					DataType:   rowCol["DATA_TYPE"],
-					CharUsed:   rowCol["CHAR_USED"],
-					CharLength: rowCol["CHAR_LENGTH"],
+					DataType:     rowCol["DATA_TYPE"],
+					DataTypeCode: rowCol["DATA_TYPE_CODE"],
+					CharUsed:     rowCol["CHAR_USED"],
+					CharLength:   rowCol["CHAR_LENGTH"],
 					ColumnInfo: ColumnInfo{
 						DataLength:    rowCol["DATA_LENGTH"],
 						DataPrecision: rowCol["DATA_PRECISION"],
@@ -25,6 +25,7 @@ import (
 
 type Column struct {
 	DataType                string
+	DataTypeCode            string // 自定义数据类型 DBA_TYPES.TYPECODE（OBJECT、COLLECTION），内置数据类型 NONE
 	CharLength              string
 	CharUsed                string
 	CharacterSet            string
@@ -443,6 +444,14 @@ func OracleTableColumnMapMySQLRule(sourceSchema, sourceTable string, column Colu
 		} else {
 			return originColumnType, buildInColumnType, fmt.Errorf("oracle table column type [%s] map mysql column type rule isn't exist, please checkin", common.BuildInOracleDatatypeXmltype)
 		}
+	case common.BuildInOracleDatatypeSdoGeometry:
+		originColumnType = common.BuildInOracleDatatypeSdoGeometry
+		if val, ok := buildinDatatypeMap[common.BuildInOracleDatatypeSdoGeometry]; ok {
+			buildInColumnType = fmt.Sprintf("%s", common.StringUPPER(val))
+			return originColumnType, buildInColumnType, nil
+		} else {
+			return originColumnType, buildInColumnType, fmt.Errorf("oracle table column type [%s] map mysql column type rule isn't exist, please checkin", common.BuildInOracleDatatypeSdoGeometry)
+		}
 	default:
 		if strings.Contains(column.DataType, "INTERVAL YEAR") {
 			originColumnType = column.DataType
@@ -480,6 +489,13 @@ func OracleTableColumnMapMySQLRule(sourceSchema, sourceTable string, column Colu
 		} else {
 			originColumnType = column.DataType
 			buildInColumnType = "TEXT"
+			// 仅已确认的自定义对象类型、VARRAY 以及嵌套表（DBA_TYPES.TYPECODE）采用 OBJECT 规则，其他未知类型保持 TEXT
+			switch common.StringUPPER(column.DataTypeCode) {
+			case "OBJECT", "COLLECTION":
+				if val, ok := buildinDatatypeMap[common.BuildInOracleDatatypeObject]; ok {
+					buildInColumnType = common.StringUPPER(val)
+				}
+			}
 		}
 		return originColumnType, buildInColumnType, nil
 	}
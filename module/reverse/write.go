@@ -17,19 +17,21 @@ package reverse
 
 import (
 	"bufio"
+	"context"
 	"github.com/wentaojin/transferdb/common"
 	"github.com/wentaojin/transferdb/config"
 	"github.com/wentaojin/transferdb/database/mysql"
 	"github.com/wentaojin/transferdb/database/oracle"
-	"os"
+	"github.com/wentaojin/transferdb/storage"
+	"io"
 	"strings"
 	"sync"
 )
 
 type Write struct {
 	Cfg     *config.Config
-	RFile   *os.File
-	CFile   *os.File
+	RFile   io.WriteCloser
+	CFile   io.WriteCloser
 	RWriter *bufio.Writer
 	CWriter *bufio.Writer
 	Mutex   *sync.Mutex
@@ -38,17 +40,17 @@ type Write struct {
 	Oracle *oracle.Oracle
 }
 
-func NewWriter(cfg *config.Config, mysql *mysql.MySQL, oracle *oracle.Oracle, reverseFile, compFile string) (*Write, error) {
+func NewWriter(ctx context.Context, cfg *config.Config, mysql *mysql.MySQL, oracle *oracle.Oracle, reverseFile, compFile string) (*Write, error) {
 	w := &Write{}
 
 	if !cfg.ReverseConfig.DirectWrite {
-		err := w.initOutReverseFile(reverseFile)
+		err := w.initOutReverseFile(ctx, cfg.StorageConfig, reverseFile)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	err := w.initOutCompatibleFile(compFile)
+	err := w.initOutCompatibleFile(ctx, cfg.StorageConfig, compFile)
 	if err != nil {
 		return nil, err
 	}
@@ -97,8 +99,8 @@ func (w *Write) CWriteFile(s string) (nn int, err error) {
 	return w.CWriter.WriteString(s)
 }
 
-func (w *Write) initOutReverseFile(reverseFile string) error {
-	outReverseFile, err := os.OpenFile(reverseFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND|os.O_TRUNC, 0666)
+func (w *Write) initOutReverseFile(ctx context.Context, storageCfg config.StorageConfig, reverseFile string) error {
+	outReverseFile, err := storage.NewWriter(ctx, storageCfg, reverseFile)
 	if err != nil {
 		return err
 	}
@@ -106,8 +108,8 @@ func (w *Write) initOutReverseFile(reverseFile string) error {
 	return nil
 }
 
-func (w *Write) initOutCompatibleFile(compFile string) error {
-	outCompFile, err := os.OpenFile(compFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND|os.O_TRUNC, 0666)
+func (w *Write) initOutCompatibleFile(ctx context.Context, storageCfg config.StorageConfig, compFile string) error {
+	outCompFile, err := storage.NewWriter(ctx, storageCfg, compFile)
 	if err != nil {
 		return err
 	}
@@ -119,6 +121,7 @@ func (w *Write) Close() error {
 	if w.RFile != nil {
 		err := w.RWriter.Flush()
 		if err != nil {
+			_ = storage.Abort(w.RFile, err)
 			return err
 		}
 		err = w.RFile.Close()
@@ -129,6 +132,7 @@ func (w *Write) Close() error {
 	if w.CFile != nil {
 		err := w.CWriter.Flush()
 		if err != nil {
+			_ = storage.Abort(w.CFile, err)
 			return err
 		}
 		err = w.CFile.Close()
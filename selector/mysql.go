@@ -0,0 +1,120 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package selector
+
+import (
+	"fmt"
+	"github.com/wentaojin/transferdb/config"
+	"github.com/wentaojin/transferdb/database/mysql"
+	"go.uber.org/zap"
+	"time"
+)
+
+// SelectMySQLTable 按 [schema] 表选择规则获取 mysql schema 待处理表
+func SelectMySQLTable(cfg *config.Config, mysql *mysql.MySQL) ([]string, error) {
+	startTime := time.Now()
+	tables, allTableCounts, err := selectMySQLTable(cfg, mysql, false)
+	if err != nil {
+		return nil, err
+	}
+	exporters := TableNames(tables)
+	if len(exporters) == 0 {
+		return nil, fmt.Errorf("exporter tables aren't exist, please check config [schema] table selection params, or run list mode to show the selected tables")
+	}
+
+	zap.L().Info("select mysql schema tables",
+		zap.String("schema", cfg.SchemaConfig.SourceSchema),
+		zap.Strings("exporter tables list", exporters),
+		zap.Int("include table counts", len(exporters)),
+		zap.Int("exclude table counts", allTableCounts-len(exporters)),
+		zap.Int("all table counts", allTableCounts),
+		zap.String("cost", time.Now().Sub(startTime).String()))
+	return exporters, nil
+}
+
+// SelectMySQLTableAndView 按 [schema] 表选择规则获取 mysql schema 待处理表以及视图，视图仅适用表名规则
+func SelectMySQLTableAndView(cfg *config.Config, mysql *mysql.MySQL) ([]string, []string, error) {
+	startTime := time.Now()
+	tables, allTableCounts, err := selectMySQLTable(cfg, mysql, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	s, err := NewSelector(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	views, err := mysql.GetMySQLViewTable(cfg.SchemaConfig.SourceSchema)
+	if err != nil {
+		return nil, nil, err
+	}
+	var viewTables []string
+	for _, v := range views {
+		if s.MatchName(v) {
+			viewTables = append(viewTables, v)
+		}
+	}
+	exporters := TableNames(tables)
+	if len(exporters) == 0 && len(viewTables) == 0 {
+		return nil, nil, fmt.Errorf("exporter tables aren't exist, please check config [schema] table selection params, or run list mode to show the selected tables")
+	}
+
+	zap.L().Info("select mysql schema tables and views",
+		zap.String("schema", cfg.SchemaConfig.SourceSchema),
+		zap.Strings("exporter tables list", exporters),
+		zap.Strings("exporter views list", viewTables),
+		zap.Int("all table counts", allTableCounts),
+		zap.Int("all view counts", len(views)),
+		zap.String("cost", time.Now().Sub(startTime).String()))
+	return exporters, viewTables, nil
+}
+
+// ListMySQLTable 获取 mysql schema 待处理表以及表对象属性，用于 list 模式
+func ListMySQLTable(cfg *config.Config, mysql *mysql.MySQL) ([]Table, int, error) {
+	return selectMySQLTable(cfg, mysql, true)
+}
+
+func selectMySQLTable(cfg *config.Config, mysql *mysql.MySQL, withAttribute bool) ([]Table, int, error) {
+	s, err := NewSelector(cfg)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ok, err := mysql.IsExistMySQLSchema(cfg.SchemaConfig.SourceSchema)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !ok {
+		return nil, 0, fmt.Errorf("mysql schema [%s] isn't exist in the database", cfg.SchemaConfig.SourceSchema)
+	}
+
+	tableNames, err := mysql.GetMySQLNormalTable(cfg.SchemaConfig.SourceSchema)
+	if err != nil {
+		return nil, 0, err
+	}
+	tables := newTables(tableNames)
+	if withAttribute || s.NeedAttribute() {
+		res, err := mysql.GetMySQLSchemaTableAttribute(cfg.SchemaConfig.SourceSchema)
+		if err != nil {
+			return nil, 0, err
+		}
+		attrTables, err := newAttributeTables(res)
+		if err != nil {
+			return nil, 0, err
+		}
+		tables = mergeAttributeTables(tableNames, attrTables)
+	}
+	return s.Select(tables), len(tables), nil
+}
@@ -0,0 +1,85 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package selector
+
+import (
+	"fmt"
+	"github.com/wentaojin/transferdb/common"
+	"github.com/wentaojin/transferdb/config"
+	"github.com/wentaojin/transferdb/database/oracle"
+	"go.uber.org/zap"
+	"time"
+)
+
+// SelectOracleTable 按 [schema] 表选择规则获取 oracle schema 待处理表
+func SelectOracleTable(cfg *config.Config, oracle *oracle.Oracle) ([]string, error) {
+	startTime := time.Now()
+	tables, allTableCounts, err := selectOracleTable(cfg, oracle, false)
+	if err != nil {
+		return nil, err
+	}
+	exporters := TableNames(tables)
+	if len(exporters) == 0 {
+		return nil, fmt.Errorf("exporter tables aren't exist, please check config [schema] table selection params, or run list mode to show the selected tables")
+	}
+
+	zap.L().Info("select oracle schema tables",
+		zap.String("schema", cfg.SchemaConfig.SourceSchema),
+		zap.Strings("exporter tables list", exporters),
+		zap.Int("include table counts", len(exporters)),
+		zap.Int("exclude table counts", allTableCounts-len(exporters)),
+		zap.Int("all table counts", allTableCounts),
+		zap.String("cost", time.Now().Sub(startTime).String()))
+	return exporters, nil
+}
+
+// ListOracleTable 获取 oracle schema 待处理表以及表对象属性，用于 list 模式
+func ListOracleTable(cfg *config.Config, oracle *oracle.Oracle) ([]Table, int, error) {
+	return selectOracleTable(cfg, oracle, true)
+}
+
+func selectOracleTable(cfg *config.Config, oracle *oracle.Oracle, withAttribute bool) ([]Table, int, error) {
+	s, err := NewSelector(cfg)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	schemas, err := oracle.GetOracleSchemas()
+	if err != nil {
+		return nil, 0, err
+	}
+	if !common.IsContainString(schemas, common.StringUPPER(cfg.SchemaConfig.SourceSchema)) {
+		return nil, 0, fmt.Errorf("oracle schema [%s] isn't exist in the database", cfg.SchemaConfig.SourceSchema)
+	}
+
+	tableNames, err := oracle.GetOracleSchemaTable(common.StringUPPER(cfg.SchemaConfig.SourceSchema))
+	if err != nil {
+		return nil, 0, err
+	}
+	tables := newTables(tableNames)
+	if withAttribute || s.NeedAttribute() {
+		res, err := oracle.GetOracleSchemaTableAttribute(common.StringUPPER(cfg.SchemaConfig.SourceSchema))
+		if err != nil {
+			return nil, 0, err
+		}
+		attrTables, err := newAttributeTables(res)
+		if err != nil {
+			return nil, 0, err
+		}
+		tables = mergeAttributeTables(tableNames, attrTables)
+	}
+	return s.Select(tables), len(tables), nil
+}
@@ -0,0 +1,232 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package selector
+
+import (
+	"fmt"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/wentaojin/transferdb/common"
+	"github.com/wentaojin/transferdb/config"
+	"github.com/wentaojin/transferdb/filter"
+	"strconv"
+	"strings"
+)
+
+// Table 候选表以及表对象属性，对象属性仅在配置对象类型、大小或者行数过滤以及 list 模式下获取
+type Table struct {
+	TableName     string
+	IsPartitioned bool
+	IsTemporary   bool
+	IsIOT         bool
+	HasLOB        bool
+	HasPK         bool
+	// 表行数，基于统计信息
+	TableRows int64
+	// 表大小，单位 MB
+	TableSize float64
+}
+
+// ObjectTypes 表对象类型列表
+func (t Table) ObjectTypes() []string {
+	var objectTypes []string
+	if t.IsPartitioned {
+		objectTypes = append(objectTypes, common.TableObjectTypePartitioned)
+	}
+	if t.IsTemporary {
+		objectTypes = append(objectTypes, common.TableObjectTypeTemporary)
+	}
+	if t.IsIOT {
+		objectTypes = append(objectTypes, common.TableObjectTypeIOT)
+	}
+	if t.HasLOB {
+		objectTypes = append(objectTypes, common.TableObjectTypeHasLOB)
+	}
+	if !t.HasPK {
+		objectTypes = append(objectTypes, common.TableObjectTypeNoPK)
+	}
+	return objectTypes
+}
+
+// Selector 表选择规则，source-include-table 与 source-exclude-table 同时配置时先 include 后 exclude
+// 规则格式参见 filter 包，支持通配符、/regexp/ 正则以及 schema.table
+type Selector struct {
+	schemaName         string
+	include            filter.Filter
+	exclude            filter.Filter
+	includeObjectTypes []string
+	excludeObjectTypes []string
+	minTableSize       float64
+	maxTableSize       float64
+	minTableRows       int64
+	maxTableRows       int64
+}
+
+func NewSelector(cfg *config.Config) (*Selector, error) {
+	s := &Selector{
+		schemaName:         cfg.SchemaConfig.SourceSchema,
+		includeObjectTypes: cfg.SchemaConfig.SourceIncludeObjectType,
+		excludeObjectTypes: cfg.SchemaConfig.SourceExcludeObjectType,
+		minTableSize:       cfg.SchemaConfig.SourceMinTableSize,
+		maxTableSize:       cfg.SchemaConfig.SourceMaxTableSize,
+		minTableRows:       cfg.SchemaConfig.SourceMinTableRows,
+		maxTableRows:       cfg.SchemaConfig.SourceMaxTableRows,
+	}
+	if len(cfg.SchemaConfig.SourceIncludeTable) != 0 {
+		f, err := filter.Parse(cfg.SchemaConfig.SourceIncludeTable)
+		if err != nil {
+			return nil, fmt.Errorf("config [schema] source-include-table parse failed: %v", err)
+		}
+		s.include = f
+	}
+	if len(cfg.SchemaConfig.SourceExcludeTable) != 0 {
+		f, err := filter.Parse(cfg.SchemaConfig.SourceExcludeTable)
+		if err != nil {
+			return nil, fmt.Errorf("config [schema] source-exclude-table parse failed: %v", err)
+		}
+		s.exclude = f
+	}
+	return s, nil
+}
+
+// NeedAttribute 是否配置对象类型、大小或者行数过滤，需获取表对象属性
+func (s *Selector) NeedAttribute() bool {
+	return len(s.includeObjectTypes) != 0 || len(s.excludeObjectTypes) != 0 ||
+		s.minTableSize > 0 || s.maxTableSize > 0 || s.minTableRows > 0 || s.maxTableRows > 0
+}
+
+// MatchName 表名是否匹配 include/exclude 规则，未配置 include 代表匹配所有表
+func (s *Selector) MatchName(tableName string) bool {
+	if s.include != nil && !s.include.MatchSchemaTable(s.schemaName, tableName) {
+		return false
+	}
+	if s.exclude != nil && s.exclude.MatchSchemaTable(s.schemaName, tableName) {
+		return false
+	}
+	return true
+}
+
+// Match 表是否匹配表名、对象类型、大小以及行数规则
+func (s *Selector) Match(t Table) bool {
+	if !s.MatchName(t.TableName) {
+		return false
+	}
+	objectTypes := t.ObjectTypes()
+	if len(s.includeObjectTypes) != 0 && len(common.FilterIntersectionStringItems(objectTypes, s.includeObjectTypes)) == 0 {
+		return false
+	}
+	if len(common.FilterIntersectionStringItems(objectTypes, s.excludeObjectTypes)) != 0 {
+		return false
+	}
+	if s.minTableSize > 0 && t.TableSize < s.minTableSize {
+		return false
+	}
+	if s.maxTableSize > 0 && t.TableSize > s.maxTableSize {
+		return false
+	}
+	if s.minTableRows > 0 && t.TableRows < s.minTableRows {
+		return false
+	}
+	if s.maxTableRows > 0 && t.TableRows > s.maxTableRows {
+		return false
+	}
+	return true
+}
+
+// Select 选择匹配规则的表
+func (s *Selector) Select(tables []Table) []Table {
+	var selected []Table
+	for _, t := range tables {
+		if s.Match(t) {
+			selected = append(selected, t)
+		}
+	}
+	return selected
+}
+
+// TableNames 表名列表
+func TableNames(tables []Table) []string {
+	var tableNames []string
+	for _, t := range tables {
+		tableNames = append(tableNames, t.TableName)
+	}
+	return tableNames
+}
+
+// Render list 模式表格输出
+func Render(schemaName string, tables []Table, allTableCounts int) string {
+	tw := table.NewWriter()
+	tw.SetStyle(table.StyleLight)
+	tw.SetTitle(fmt.Sprintf("SCHEMA [%s] SELECTED TABLE [%d/%d]", schemaName, len(tables), allTableCounts))
+	tw.AppendHeader(table.Row{"#", "TABLE", "OBJECT TYPE", "TABLE ROWS", "TABLE SIZE(MB)"})
+	for i, t := range tables {
+		tw.AppendRow(table.Row{i + 1, t.TableName, strings.Join(t.ObjectTypes(), ","), t.TableRows, t.TableSize})
+	}
+	return tw.Render()
+}
+
+// newTables 表名列表转换，未配置对象属性相关过滤规则无需查询对象属性
+func newTables(tableNames []string) []Table {
+	var tables []Table
+	for _, t := range tableNames {
+		tables = append(tables, Table{TableName: t})
+	}
+	return tables
+}
+
+// mergeAttributeTables 以普通表列表为候选集合以及表名，叠加表对象属性，保证是否配置属性过滤条件候选表以及表名大小写一致
+// 属性查询不存在的表保留默认属性
+func mergeAttributeTables(tableNames []string, attrTables []Table) []Table {
+	attrs := make(map[string]Table, len(attrTables))
+	for _, t := range attrTables {
+		attrs[common.StringUPPER(t.TableName)] = t
+	}
+	var tables []Table
+	for _, name := range tableNames {
+		t, ok := attrs[common.StringUPPER(name)]
+		if !ok {
+			t = Table{}
+		}
+		t.TableName = name
+		tables = append(tables, t)
+	}
+	return tables
+}
+
+// newAttributeTables 表对象属性查询结果转换
+func newAttributeTables(res []map[string]string) ([]Table, error) {
+	var tables []Table
+	for _, r := range res {
+		tableRows, err := strconv.ParseInt(r["TABLE_ROWS"], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("table [%s] rows [%s] strconv failed: %v", r["TABLE_NAME"], r["TABLE_ROWS"], err)
+		}
+		tableSize, err := strconv.ParseFloat(r["TABLE_SIZE"], 64)
+		if err != nil {
+			return nil, fmt.Errorf("table [%s] size [%s] strconv failed: %v", r["TABLE_NAME"], r["TABLE_SIZE"], err)
+		}
+		tables = append(tables, Table{
+			TableName:     r["TABLE_NAME"],
+			IsPartitioned: strings.EqualFold(r["IS_PARTITIONED"], "Y"),
+			IsTemporary:   strings.EqualFold(r["IS_TEMPORARY"], "Y"),
+			IsIOT:         strings.EqualFold(r["IS_IOT"], "Y"),
+			HasLOB:        strings.EqualFold(r["HAS_LOB"], "Y"),
+			HasPK:         strings.EqualFold(r["HAS_PK"], "Y"),
+			TableRows:     tableRows,
+			TableSize:     tableSize,
+		})
+	}
+	return tables, nil
+}
@@ -0,0 +1,89 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package selector
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/wentaojin/transferdb/common"
+	"github.com/wentaojin/transferdb/config"
+)
+
+func TestSelect(t *testing.T) {
+	tableNames := []string{"orders", "Order_Item", "CUSTOMER", "tmp_orders"}
+	// 属性查询包含普通表列表不存在的表，且表名大小写不同
+	attrTables := []Table{
+		{TableName: "ORDERS", HasPK: true, TableRows: 1000, TableSize: 10},
+		{TableName: "ORDER_ITEM", HasPK: true, IsPartitioned: true, TableRows: 5000, TableSize: 50},
+		{TableName: "CUSTOMER", TableRows: 10, TableSize: 1},
+		{TableName: "TMP_ORDERS", HasPK: true, TableRows: 1, TableSize: 0.1},
+		{TableName: "BIN$ABC", TableRows: 1, TableSize: 0.1},
+	}
+
+	cases := []struct {
+		name     string
+		schema   config.SchemaConfig
+		expected []string
+	}{
+		{
+			name:     "no rule",
+			expected: []string{"orders", "Order_Item", "CUSTOMER", "tmp_orders"},
+		},
+		{
+			name:     "include wildcard",
+			schema:   config.SchemaConfig{SourceIncludeTable: []string{"ORDER*"}},
+			expected: []string{"orders", "Order_Item"},
+		},
+		{
+			name:     "include and exclude",
+			schema:   config.SchemaConfig{SourceIncludeTable: []string{"*ORDER*"}, SourceExcludeTable: []string{"TMP_*"}},
+			expected: []string{"orders", "Order_Item"},
+		},
+		{
+			name:     "exclude no pk",
+			schema:   config.SchemaConfig{SourceExcludeObjectType: []string{common.TableObjectTypeNoPK}},
+			expected: []string{"orders", "Order_Item", "tmp_orders"},
+		},
+		{
+			name:     "include partitioned",
+			schema:   config.SchemaConfig{SourceIncludeObjectType: []string{common.TableObjectTypePartitioned}},
+			expected: []string{"Order_Item"},
+		},
+		{
+			name:     "table rows range",
+			schema:   config.SchemaConfig{SourceMinTableRows: 10, SourceMaxTableRows: 1000},
+			expected: []string{"orders", "CUSTOMER"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.schema.SourceSchema = "MARVIN"
+			s, err := NewSelector(&config.Config{SchemaConfig: c.schema})
+			if err != nil {
+				t.Fatal(err)
+			}
+			tables := newTables(tableNames)
+			if s.NeedAttribute() {
+				tables = mergeAttributeTables(tableNames, attrTables)
+			}
+			if got := TableNames(s.Select(tables)); !reflect.DeepEqual(got, c.expected) {
+				t.Fatalf("select tables %v, want %v", got, c.expected)
+			}
+		})
+	}
+}
@@ -17,11 +17,17 @@ package server
 
 import (
 	"context"
+	"fmt"
 	"github.com/wentaojin/transferdb/common"
 	"github.com/wentaojin/transferdb/config"
+	"github.com/wentaojin/transferdb/database/meta"
 	"github.com/wentaojin/transferdb/module/assess"
+	"github.com/wentaojin/transferdb/module/assess/mysql/m2o"
+	"github.com/wentaojin/transferdb/module/assess/mysql/t2o"
 	"github.com/wentaojin/transferdb/module/assess/oracle/o2m"
 	"github.com/wentaojin/transferdb/module/assess/oracle/o2t"
+	"github.com/wentaojin/transferdb/module/assess/oracle/public"
+	"os"
 
 	"strings"
 )
@@ -31,6 +37,19 @@ func IAssess(ctx context.Context, cfg *config.Config) error {
 		a   assess.Assesser
 		err error
 	)
+	// 指定两次评估运行编号，仅对比已保存的评估结果，不重新评估
+	if cfg.BaselineConfig.BaseRunID != "" && cfg.BaselineConfig.TargetRunID != "" {
+		metaDB, err := meta.NewMetaDBEngine(ctx, cfg.MetaConfig, cfg.AppConfig.SlowlogThreshold)
+		if err != nil {
+			return err
+		}
+		pwdDir, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		_, err = public.GenAssessBaselineReport(ctx, metaDB, cfg.BaselineConfig.BaseRunID, cfg.BaselineConfig.TargetRunID, cfg.AssessConfig.ReportFormat, pwdDir)
+		return err
+	}
 	switch {
 	case strings.EqualFold(cfg.DBTypeS, common.DatabaseTypeOracle) && strings.EqualFold(cfg.DBTypeT, common.DatabaseTypeMySQL):
 		a, err = o2m.NewAssess(ctx, cfg)
@@ -42,6 +61,18 @@ func IAssess(ctx context.Context, cfg *config.Config) error {
 		if err != nil {
 			return err
 		}
+	case strings.EqualFold(cfg.DBTypeS, common.DatabaseTypeMySQL) && strings.EqualFold(cfg.DBTypeT, common.DatabaseTypeOracle):
+		a, err = m2o.NewAssess(ctx, cfg)
+		if err != nil {
+			return err
+		}
+	case strings.EqualFold(cfg.DBTypeS, common.DatabaseTypeTiDB) && strings.EqualFold(cfg.DBTypeT, common.DatabaseTypeOracle):
+		a, err = t2o.NewAssess(ctx, cfg)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("assess mode source db type [%s] and target db type [%s] isn't support", cfg.DBTypeS, cfg.DBTypeT)
 	}
 
 	err = a.Assess()
@@ -17,9 +17,12 @@ package server
 
 import (
 	"context"
+	"fmt"
 	"github.com/wentaojin/transferdb/common"
 	"github.com/wentaojin/transferdb/config"
 	"github.com/wentaojin/transferdb/module/compare"
+	"github.com/wentaojin/transferdb/module/compare/mysql/m2o"
+	"github.com/wentaojin/transferdb/module/compare/mysql/t2o"
 	"github.com/wentaojin/transferdb/module/compare/oracle/o2m"
 	"github.com/wentaojin/transferdb/module/compare/oracle/o2t"
 	"strings"
@@ -41,6 +44,18 @@ func ICompare(ctx context.Context, cfg *config.Config) error {
 		if err != nil {
 			return err
 		}
+	case strings.EqualFold(cfg.DBTypeS, common.DatabaseTypeMySQL) && strings.EqualFold(cfg.DBTypeT, common.DatabaseTypeOracle):
+		c, err = m2o.NewCompare(ctx, cfg)
+		if err != nil {
+			return err
+		}
+	case strings.EqualFold(cfg.DBTypeS, common.DatabaseTypeTiDB) && strings.EqualFold(cfg.DBTypeT, common.DatabaseTypeOracle):
+		c, err = t2o.NewCompare(ctx, cfg)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("compare mode source db type [%s] and target db type [%s] isn't support", cfg.DBTypeS, cfg.DBTypeT)
 	}
 	err = c.NewCompare()
 	if err != nil {
@@ -0,0 +1,60 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package server
+
+import (
+	"context"
+	"fmt"
+	"github.com/wentaojin/transferdb/common"
+	"github.com/wentaojin/transferdb/config"
+	"github.com/wentaojin/transferdb/database/mysql"
+	"github.com/wentaojin/transferdb/database/oracle"
+	"github.com/wentaojin/transferdb/selector"
+	"strings"
+)
+
+// IList 按 [schema] 表选择规则输出源端待处理表以及表对象属性，不执行任何迁移任务
+func IList(ctx context.Context, cfg *config.Config) error {
+	var (
+		tables         []selector.Table
+		allTableCounts int
+	)
+	switch {
+	case strings.EqualFold(cfg.DBTypeS, common.DatabaseTypeOracle):
+		oracleDB, err := oracle.NewOracleDBEngine(ctx, cfg.OracleConfig, cfg.SchemaConfig.SourceSchema)
+		if err != nil {
+			return err
+		}
+		tables, allTableCounts, err = selector.ListOracleTable(cfg, oracleDB)
+		if err != nil {
+			return err
+		}
+	case strings.EqualFold(cfg.DBTypeS, common.DatabaseTypeMySQL) || strings.EqualFold(cfg.DBTypeS, common.DatabaseTypeTiDB):
+		mysqlDB, err := mysql.NewMySQLDBEngine(ctx, cfg.MySQLConfig)
+		if err != nil {
+			return err
+		}
+		tables, allTableCounts, err = selector.ListMySQLTable(cfg, mysqlDB)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("list mode source db type [%s] isn't support", cfg.DBTypeS)
+	}
+
+	fmt.Println(selector.Render(cfg.SchemaConfig.SourceSchema, tables, allTableCounts))
+	return nil
+}
@@ -17,15 +17,21 @@ package server
 
 import (
 	"context"
+	"fmt"
 	"github.com/wentaojin/transferdb/common"
 	"github.com/wentaojin/transferdb/config"
 	"github.com/wentaojin/transferdb/module/migrate"
+	"github.com/wentaojin/transferdb/module/migrate/sql/mysql/m2o"
+	"github.com/wentaojin/transferdb/module/migrate/sql/mysql/t2o"
 	"github.com/wentaojin/transferdb/module/migrate/sql/oracle/o2m"
 	"github.com/wentaojin/transferdb/module/migrate/sql/oracle/o2t"
 	"strings"
 )
 
 func IMigrateFull(ctx context.Context, cfg *config.Config) error {
+	if cfg.DryRun {
+		return IMigrateDryRun(ctx, cfg)
+	}
 	var (
 		f   migrate.Fuller
 		err error
@@ -41,6 +47,18 @@ func IMigrateFull(ctx context.Context, cfg *config.Config) error {
 		if err != nil {
 			return err
 		}
+	case strings.EqualFold(cfg.DBTypeS, common.DatabaseTypeMySQL) && strings.EqualFold(cfg.DBTypeT, common.DatabaseTypeOracle):
+		f, err = m2o.NewFuller(ctx, cfg)
+		if err != nil {
+			return err
+		}
+	case strings.EqualFold(cfg.DBTypeS, common.DatabaseTypeTiDB) && strings.EqualFold(cfg.DBTypeT, common.DatabaseTypeOracle):
+		f, err = t2o.NewFuller(ctx, cfg)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("full mode source db type [%s] and target db type [%s] isn't support", cfg.DBTypeS, cfg.DBTypeT)
 	}
 	err = f.Full()
 	if err != nil {
@@ -50,6 +68,9 @@ func IMigrateFull(ctx context.Context, cfg *config.Config) error {
 }
 
 func IMigrateIncr(ctx context.Context, cfg *config.Config) error {
+	if cfg.DryRun {
+		return IMigrateDryRun(ctx, cfg)
+	}
 	var (
 		i   migrate.Increr
 		err error
@@ -65,6 +86,18 @@ func IMigrateIncr(ctx context.Context, cfg *config.Config) error {
 		if err != nil {
 			return err
 		}
+	case strings.EqualFold(cfg.DBTypeS, common.DatabaseTypeMySQL) && strings.EqualFold(cfg.DBTypeT, common.DatabaseTypeOracle):
+		i, err = m2o.NewIncr(ctx, cfg)
+		if err != nil {
+			return err
+		}
+	case strings.EqualFold(cfg.DBTypeS, common.DatabaseTypeTiDB) && strings.EqualFold(cfg.DBTypeT, common.DatabaseTypeOracle):
+		i, err = t2o.NewIncr(ctx, cfg)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("all mode source db type [%s] and target db type [%s] isn't support", cfg.DBTypeS, cfg.DBTypeT)
 	}
 	err = i.Incr()
 	if err != nil {
@@ -72,3 +105,41 @@ func IMigrateIncr(ctx context.Context, cfg *config.Config) error {
 	}
 	return nil
 }
+
+// IMigrateDryRun full/all 模式执行计划输出，不清理目标表、不写入目标端以及元数据库
+func IMigrateDryRun(ctx context.Context, cfg *config.Config) error {
+	var (
+		d   migrate.DryRunner
+		err error
+	)
+	switch {
+	case strings.EqualFold(cfg.DBTypeS, common.DatabaseTypeOracle) && strings.EqualFold(cfg.DBTypeT, common.DatabaseTypeMySQL):
+		d, err = o2m.NewFuller(ctx, cfg)
+		if err != nil {
+			return err
+		}
+	case strings.EqualFold(cfg.DBTypeS, common.DatabaseTypeOracle) && strings.EqualFold(cfg.DBTypeT, common.DatabaseTypeTiDB):
+		d, err = o2t.NewFuller(ctx, cfg)
+		if err != nil {
+			return err
+		}
+	case strings.EqualFold(cfg.DBTypeS, common.DatabaseTypeMySQL) && strings.EqualFold(cfg.DBTypeT, common.DatabaseTypeOracle):
+		d, err = m2o.NewFuller(ctx, cfg)
+		if err != nil {
+			return err
+		}
+	case strings.EqualFold(cfg.DBTypeS, common.DatabaseTypeTiDB) && strings.EqualFold(cfg.DBTypeT, common.DatabaseTypeOracle):
+		d, err = t2o.NewFuller(ctx, cfg)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%s mode dry-run source db type [%s] and target db type [%s] isn't support", strings.ToLower(cfg.TaskMode), cfg.DBTypeS, cfg.DBTypeT)
+	}
+	p, err := d.DryRun()
+	if err != nil {
+		return err
+	}
+	fmt.Println(p.Render())
+	return nil
+}
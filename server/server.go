@@ -20,7 +20,9 @@ import (
 	"fmt"
 	"github.com/wentaojin/transferdb/common"
 	"github.com/wentaojin/transferdb/config"
+	"github.com/wentaojin/transferdb/module/precheck"
 	"github.com/wentaojin/transferdb/module/prepare"
+	"github.com/wentaojin/transferdb/module/repair"
 	"strings"
 )
 
@@ -63,6 +65,12 @@ func Run(ctx context.Context, cfg *config.Config) error {
 		if err != nil {
 			return err
 		}
+	case common.TaskModeVerify:
+		// csv 数据文件校验 - 基于 csv 模式生成的 manifest
+		err := IVerify(ctx, cfg)
+		if err != nil {
+			return err
+		}
 	case common.TaskModeFull:
 		// 全量数据 ETL 非一致性（基于某个时间点，而是直接基于现有 SCN）抽取，离线环境提供与原库一致性
 		err := IMigrateFull(ctx, cfg)
@@ -75,6 +83,24 @@ func Run(ctx context.Context, cfg *config.Config) error {
 		if err != nil {
 			return err
 		}
+	case common.TaskModeRepair:
+		// 任务修复 - 失败表、chunk 列出以及重置
+		err := repair.IRepair(ctx, cfg)
+		if err != nil {
+			return err
+		}
+	case common.TaskModeList:
+		// 表选择 - 输出源端待处理表，不执行任务
+		err := IList(ctx, cfg)
+		if err != nil {
+			return err
+		}
+	case common.TaskModePrecheck:
+		// 任务预检查 - 配置文件、连通性、权限以及运行环境，不执行任务
+		err := precheck.IPrecheck(ctx, cfg)
+		if err != nil {
+			return err
+		}
 	default:
 		return fmt.Errorf("flag [mode] can not null or value configure error")
 	}
@@ -0,0 +1,74 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/wentaojin/transferdb/common"
+)
+
+// LocalStorage 本地磁盘存储
+type LocalStorage struct {
+	BaseDir string
+}
+
+func NewLocalStorage(baseDir string) (*LocalStorage, error) {
+	if err := common.PathExist(baseDir); err != nil {
+		return nil, err
+	}
+	return &LocalStorage{BaseDir: baseDir}, nil
+}
+
+func (l *LocalStorage) Create(ctx context.Context, name string) (io.WriteCloser, error) {
+	fileName := filepath.Join(l.BaseDir, name)
+	if err := common.PathExist(filepath.Dir(fileName)); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(fileName, os.O_WRONLY|os.O_CREATE|os.O_APPEND|os.O_TRUNC, 0666)
+	if err != nil {
+		return nil, fmt.Errorf("local storage create file [%s] failed: %v", fileName, err)
+	}
+	return file, nil
+}
+
+func (l *LocalStorage) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	fileName := filepath.Join(l.BaseDir, name)
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("local storage open file [%s] failed: %v", fileName, err)
+	}
+	return file, nil
+}
+
+func (l *LocalStorage) FileExists(ctx context.Context, name string) (bool, error) {
+	_, err := os.Stat(filepath.Join(l.BaseDir, name))
+	if err == nil {
+		return true, nil
+	}
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return false, err
+}
+
+func (l *LocalStorage) URI() string {
+	return l.BaseDir
+}
@@ -0,0 +1,213 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/wentaojin/transferdb/config"
+)
+
+// S3 默认参数
+const (
+	// S3 分片上传最小分片 5MiB
+	S3MinPartSize = 5 * 1024 * 1024
+	// 默认分片 64MiB
+	S3DefaultPartSize = 64 * 1024 * 1024
+	// 默认单文件分片并发上传数
+	S3DefaultConcurrency = 4
+	// 默认 region
+	S3DefaultRegion = "us-east-1"
+)
+
+// S3Storage S3 兼容对象存储（AWS S3/MinIO/Ceph 等）
+type S3Storage struct {
+	Bucket   string
+	Prefix   string
+	Client   *s3.S3
+	Uploader *s3manager.Uploader
+	Cfg      config.StorageConfig
+}
+
+func NewS3Storage(ctx context.Context, bucket, prefix string, storageCfg config.StorageConfig) (*S3Storage, error) {
+	awsCfg := aws.NewConfig()
+
+	region := storageCfg.Region
+	if strings.EqualFold(region, "") {
+		region = S3DefaultRegion
+	}
+	awsCfg.WithRegion(region)
+
+	// 自定义 endpoint，用于 MinIO 等 S3 兼容存储
+	if !strings.EqualFold(storageCfg.Endpoint, "") {
+		awsCfg.WithEndpoint(storageCfg.Endpoint)
+	}
+	awsCfg.WithS3ForcePathStyle(storageCfg.ForcePathStyle)
+
+	// 未配置 access-key/secret-key，则使用 AWS 默认凭证链（环境变量、共享配置文件、实例角色）
+	if !strings.EqualFold(storageCfg.AccessKey, "") && !strings.EqualFold(storageCfg.SecretKey, "") {
+		awsCfg.WithCredentials(credentials.NewStaticCredentials(storageCfg.AccessKey, storageCfg.SecretKey, storageCfg.SessionToken))
+	}
+
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            *awsCfg,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("s3 storage bucket [%s] create session failed: %v", bucket, err)
+	}
+
+	client := s3.New(sess)
+
+	partSize := storageCfg.PartSize * 1024 * 1024
+	switch {
+	case partSize == 0:
+		partSize = S3DefaultPartSize
+	case partSize < S3MinPartSize:
+		partSize = S3MinPartSize
+	}
+	concurrency := storageCfg.Concurrency
+	if concurrency <= 0 {
+		concurrency = S3DefaultConcurrency
+	}
+
+	uploader := s3manager.NewUploaderWithClient(client, func(u *s3manager.Uploader) {
+		u.PartSize = partSize
+		u.Concurrency = concurrency
+		u.LeavePartsOnError = false
+	})
+
+	return &S3Storage{
+		Bucket:   bucket,
+		Prefix:   strings.Trim(prefix, "/"),
+		Client:   client,
+		Uploader: uploader,
+		Cfg:      storageCfg,
+	}, nil
+}
+
+// Create 基于 io.Pipe 流式写入，数据超过分片大小自动采用 multipart upload 上传
+func (s *S3Storage) Create(ctx context.Context, name string) (io.WriteCloser, error) {
+	pr, pw := io.Pipe()
+	w := &s3Writer{
+		pw:   pw,
+		done: make(chan error, 1),
+	}
+
+	key := s.objectKey(name)
+	go func() {
+		_, err := s.Uploader.UploadWithContext(ctx, &s3manager.UploadInput{
+			Bucket: aws.String(s.Bucket),
+			Key:    aws.String(key),
+			Body:   pr,
+		})
+		if err != nil {
+			err = fmt.Errorf("s3 storage upload object [s3://%s/%s] failed: %v", s.Bucket, key, err)
+		}
+		// 上传失败，写端返回错误，避免写入阻塞
+		_ = pr.CloseWithError(err)
+		w.done <- err
+	}()
+
+	return w, nil
+}
+
+func (s *S3Storage) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	key := s.objectKey(name)
+	out, err := s.Client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.Bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("s3 storage get object [s3://%s/%s] failed: %v", s.Bucket, key, err)
+	}
+	return out.Body, nil
+}
+
+func (s *S3Storage) FileExists(ctx context.Context, name string) (bool, error) {
+	key := s.objectKey(name)
+	_, err := s.Client.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.Bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.RequestFailure); ok && aerr.StatusCode() == 404 {
+			return false, nil
+		}
+		return false, fmt.Errorf("s3 storage head object [s3://%s/%s] failed: %v", s.Bucket, key, err)
+	}
+	return true, nil
+}
+
+func (s *S3Storage) URI() string {
+	return joinURL(fmt.Sprintf("%s://%s", SchemeS3, s.Bucket), s.Prefix)
+}
+
+func (s *S3Storage) objectKey(name string) string {
+	if strings.EqualFold(s.Prefix, "") {
+		return strings.TrimLeft(name, "/")
+	}
+	return s.Prefix + "/" + strings.TrimLeft(name, "/")
+}
+
+// s3Writer 写入端，Close 等待上传完成并返回上传结果，Abort 中止上传不生成对象
+type s3Writer struct {
+	pw     *io.PipeWriter
+	done   chan error
+	closed bool
+	err    error
+}
+
+func (w *s3Writer) Write(p []byte) (int, error) {
+	return w.pw.Write(p)
+}
+
+func (w *s3Writer) Close() error {
+	if w.closed {
+		return w.err
+	}
+	w.closed = true
+	if err := w.pw.Close(); err != nil {
+		w.err = err
+		return err
+	}
+	w.err = <-w.done
+	return w.err
+}
+
+// Abort 写入失败中止上传，读端返回错误使 uploader 放弃上传（multipart upload 自动清理已上传分片），避免生成截断对象
+func (w *s3Writer) Abort(err error) error {
+	if w.closed {
+		return w.err
+	}
+	w.closed = true
+	if err == nil {
+		err = fmt.Errorf("s3 storage writer aborted")
+	}
+	_ = w.pw.CloseWithError(err)
+	<-w.done
+	w.err = err
+	return nil
+}
@@ -0,0 +1,242 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/wentaojin/transferdb/config"
+)
+
+// fakeS3 S3 兼容存储替身，支持 PutObject、GetObject、HeadObject 以及 multipart upload
+type fakeS3 struct {
+	mu        sync.Mutex
+	objects   map[string][]byte
+	uploads   map[string]map[int][]byte
+	uploadSeq int
+	completed int
+	aborted   int
+}
+
+func newFakeS3() *fakeS3 {
+	return &fakeS3{
+		objects: make(map[string][]byte),
+		uploads: make(map[string]map[int][]byte),
+	}
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	// path-style：/bucket/key
+	key := strings.TrimPrefix(r.URL.Path, "/")
+	query := r.URL.Query()
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		f.uploadSeq++
+		uploadID := fmt.Sprintf("upload-%d", f.uploadSeq)
+		f.uploads[uploadID] = make(map[int][]byte)
+		fmt.Fprintf(w, `<InitiateMultipartUploadResult><Key>%s</Key><UploadId>%s</UploadId></InitiateMultipartUploadResult>`, key, uploadID)
+	case r.Method == http.MethodPut && query.Has("uploadId"):
+		parts, ok := f.uploads[query.Get("uploadId")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		num, _ := strconv.Atoi(query.Get("partNumber"))
+		parts[num] = body
+		w.Header().Set("ETag", fmt.Sprintf(`"part-%d"`, num))
+	case r.Method == http.MethodPost && query.Has("uploadId"):
+		parts, ok := f.uploads[query.Get("uploadId")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var nums []int
+		for n := range parts {
+			nums = append(nums, n)
+		}
+		sort.Ints(nums)
+		var data []byte
+		for _, n := range nums {
+			data = append(data, parts[n]...)
+		}
+		f.objects[key] = data
+		delete(f.uploads, query.Get("uploadId"))
+		f.completed++
+		fmt.Fprintf(w, `<CompleteMultipartUploadResult><Key>%s</Key><ETag>"complete"</ETag></CompleteMultipartUploadResult>`, key)
+	case r.Method == http.MethodDelete && query.Has("uploadId"):
+		delete(f.uploads, query.Get("uploadId"))
+		f.aborted++
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodPut:
+		f.objects[key] = body
+		w.Header().Set("ETag", `"object"`)
+	case r.Method == http.MethodHead, r.Method == http.MethodGet:
+		data, ok := f.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			if r.Method == http.MethodGet {
+				fmt.Fprint(w, `<Error><Code>NoSuchKey</Code><Message>not found</Message></Error>`)
+			}
+			return
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(data)
+		}
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func newTestS3Storage(t *testing.T) (*fakeS3, ExternalStorage) {
+	fake := newFakeS3()
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	s, err := New(context.Background(), "s3://bucket/prefix", config.StorageConfig{
+		Endpoint:       server.URL,
+		AccessKey:      "access",
+		SecretKey:      "secret",
+		ForcePathStyle: true,
+		PartSize:       5,
+		Concurrency:    2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fake, s
+}
+
+func TestS3StorageUpload(t *testing.T) {
+	cases := []struct {
+		name      string
+		size      int
+		completed int
+	}{
+		{name: "single part", size: 1024},
+		{name: "multipart", size: 2*S3MinPartSize + 1024, completed: 1},
+	}
+	for _, c := range cases {
+		fake, s := newTestS3Storage(t)
+		ctx := context.Background()
+		data := bytes.Repeat([]byte("0123456789"), c.size/10+1)[:c.size]
+
+		w, err := s.Create(ctx, "SCHEMA/T1.0.csv")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = w.Write(data); err != nil {
+			t.Fatalf("%s: write failed: %v", c.name, err)
+		}
+		if err = w.Close(); err != nil {
+			t.Fatalf("%s: close failed: %v", c.name, err)
+		}
+
+		if !bytes.Equal(fake.objects["bucket/prefix/SCHEMA/T1.0.csv"], data) {
+			t.Errorf("%s: object content isn't equal", c.name)
+		}
+		if fake.completed != c.completed {
+			t.Errorf("%s: expected %d completed multipart upload, got %d", c.name, c.completed, fake.completed)
+		}
+
+		exist, err := s.FileExists(ctx, "SCHEMA/T1.0.csv")
+		if err != nil || !exist {
+			t.Errorf("%s: expected file exists, got %v, %v", c.name, exist, err)
+		}
+
+		r, err := s.Open(ctx, "SCHEMA/T1.0.csv")
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := io.ReadAll(r)
+		_ = r.Close()
+		if err != nil || !bytes.Equal(got, data) {
+			t.Errorf("%s: open content isn't equal, %v", c.name, err)
+		}
+	}
+}
+
+func TestS3StorageAbort(t *testing.T) {
+	fake, s := newTestS3Storage(t)
+	ctx := context.Background()
+
+	w, err := s.Create(ctx, "SCHEMA/T1.0.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 超过一个分片，multipart upload 已创建
+	if _, err = w.Write(bytes.Repeat([]byte("a"), S3MinPartSize+1024)); err != nil {
+		t.Fatal(err)
+	}
+	if err = Abort(w, fmt.Errorf("source read failed")); err != nil {
+		t.Fatal(err)
+	}
+	if err = w.Close(); err == nil {
+		t.Errorf("expected close error after abort")
+	}
+
+	if fake.aborted != 1 || fake.completed != 0 || len(fake.uploads) != 0 {
+		t.Errorf("expected multipart upload aborted, got aborted %d completed %d uploads %d", fake.aborted, fake.completed, len(fake.uploads))
+	}
+	exist, err := s.FileExists(ctx, "SCHEMA/T1.0.csv")
+	if err != nil || exist {
+		t.Errorf("expected file not exists after abort, got %v, %v", exist, err)
+	}
+}
+
+func TestS3StorageFileExists(t *testing.T) {
+	fake, s := newTestS3Storage(t)
+	ctx := context.Background()
+	fake.objects["bucket/prefix/SCHEMA/manifest.json"] = []byte("{}")
+
+	cases := []struct {
+		name     string
+		file     string
+		expected bool
+	}{
+		{name: "exists", file: "SCHEMA/manifest.json", expected: true},
+		{name: "not exists", file: "SCHEMA/checksum.sha256", expected: false},
+	}
+	for _, c := range cases {
+		exist, err := s.FileExists(ctx, c.file)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", c.name, err)
+			continue
+		}
+		if exist != c.expected {
+			t.Errorf("%s: expected %v, got %v", c.name, c.expected, exist)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/wentaojin/transferdb/config"
+)
+
+// 外部存储类型
+const (
+	SchemeLocal = "local"
+	SchemeFile  = "file"
+	SchemeS3    = "s3"
+)
+
+// ExternalStorage 外部存储接口，用于 csv 数据文件以及 reverse/check/compare 输出文件
+type ExternalStorage interface {
+	// Create 创建文件写入，文件关闭 Close 时数据才保证落盘或者上传完成
+	Create(ctx context.Context, name string) (io.WriteCloser, error)
+	// Open 打开文件读取
+	Open(ctx context.Context, name string) (io.ReadCloser, error)
+	// FileExists 判断文件是否存在
+	FileExists(ctx context.Context, name string) (bool, error)
+	// URI 存储路径
+	URI() string
+}
+
+// Aborter 写入端支持中止，写入失败时放弃文件而不是提交已写入的部分数据
+type Aborter interface {
+	Abort(err error) error
+}
+
+// Abort 写入失败中止写入，对象存储中止上传，本地文件直接关闭
+func Abort(w io.WriteCloser, err error) error {
+	if a, ok := w.(Aborter); ok {
+		return a.Abort(err)
+	}
+	return w.Close()
+}
+
+// New 根据 URL 创建外部存储
+// 本地目录：/data/transferdb、file:///data/transferdb
+// S3 兼容存储：s3://bucket/prefix
+func New(ctx context.Context, rawURL string, storageCfg config.StorageConfig) (ExternalStorage, error) {
+	if strings.EqualFold(rawURL, "") {
+		return nil, fmt.Errorf("external storage url can't be null")
+	}
+	scheme, host, prefix, err := parseURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	switch scheme {
+	case SchemeLocal, SchemeFile:
+		return NewLocalStorage(prefix)
+	case SchemeS3:
+		return NewS3Storage(ctx, host, prefix, storageCfg)
+	default:
+		return nil, fmt.Errorf("external storage url [%s] scheme [%s] isn't support, support scheme [local file s3]", rawURL, scheme)
+	}
+}
+
+// NewWriter 根据完整文件路径创建文件写入
+func NewWriter(ctx context.Context, storageCfg config.StorageConfig, fileURL string) (io.WriteCloser, error) {
+	dir, name := SplitPath(fileURL)
+	s, err := New(ctx, dir, storageCfg)
+	if err != nil {
+		return nil, err
+	}
+	return s.Create(ctx, name)
+}
+
+// IsLocal 判断是否本地存储路径
+func IsLocal(rawURL string) bool {
+	scheme, _, _, err := parseURL(rawURL)
+	if err != nil {
+		return false
+	}
+	return scheme == SchemeLocal || scheme == SchemeFile
+}
+
+// JoinPath 存储路径拼接，S3 路径保持 scheme 不变，本地路径同 filepath.Join
+func JoinPath(base string, elems ...string) string {
+	if IsLocal(base) {
+		return filepath.Join(append([]string{strings.TrimPrefix(base, "file://")}, elems...)...)
+	}
+	return joinURL(base, path.Join(elems...))
+}
+
+// SplitPath 存储路径拆分，返回目录以及文件名
+func SplitPath(fileURL string) (string, string) {
+	if IsLocal(fileURL) {
+		dir, name := filepath.Split(strings.TrimPrefix(fileURL, "file://"))
+		if strings.EqualFold(dir, "") {
+			dir = "."
+		}
+		return dir, name
+	}
+	idx := strings.LastIndex(fileURL, "/")
+	if idx < 0 {
+		return "", fileURL
+	}
+	return fileURL[:idx], fileURL[idx+1:]
+}
+
+// RelPath 文件完整路径相对于存储路径的文件名，用于同一外部存储 Create/Open，文件不在存储路径下则报错
+func RelPath(base, fileURL string) (string, error) {
+	schemeB, hostB, prefixB, err := parseURL(base)
+	if err != nil {
+		return "", err
+	}
+	schemeF, hostF, prefixF, err := parseURL(fileURL)
+	if err != nil {
+		return "", err
+	}
+	localB := schemeB == SchemeLocal || schemeB == SchemeFile
+	localF := schemeF == SchemeLocal || schemeF == SchemeFile
+	if localB != localF || (!localB && (schemeB != schemeF || hostB != hostF)) {
+		return "", fmt.Errorf("file [%s] isn't under external storage [%s]", fileURL, base)
+	}
+	if localB {
+		rel, err := filepath.Rel(prefixB, prefixF)
+		if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return "", fmt.Errorf("file [%s] isn't under external storage [%s]", fileURL, base)
+		}
+		return rel, nil
+	}
+	prefixB = strings.Trim(prefixB, "/")
+	prefixF = strings.Trim(prefixF, "/")
+	if strings.EqualFold(prefixB, "") && !strings.EqualFold(prefixF, "") {
+		return prefixF, nil
+	}
+	if !strings.HasPrefix(prefixF, prefixB+"/") {
+		return "", fmt.Errorf("file [%s] isn't under external storage [%s]", fileURL, base)
+	}
+	return strings.TrimPrefix(prefixF, prefixB+"/"), nil
+}
+
+func joinURL(base, elem string) string {
+	if strings.EqualFold(elem, "") {
+		return base
+	}
+	return strings.TrimRight(base, "/") + "/" + strings.TrimLeft(elem, "/")
+}
+
+func parseURL(rawURL string) (string, string, string, error) {
+	// 本地目录（包括 windows 盘符路径）
+	if !strings.Contains(rawURL, "://") {
+		return SchemeLocal, "", rawURL, nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", "", "", fmt.Errorf("external storage url [%s] parse failed: %v", rawURL, err)
+	}
+	scheme := strings.ToLower(u.Scheme)
+	switch scheme {
+	case SchemeFile, SchemeLocal:
+		return scheme, "", filepath.Join(u.Host, u.Path), nil
+	case SchemeS3:
+		if strings.EqualFold(u.Host, "") {
+			return "", "", "", fmt.Errorf("external storage url [%s] bucket can't be null", rawURL)
+		}
+		return scheme, u.Host, strings.Trim(u.Path, "/"), nil
+	default:
+		return scheme, u.Host, u.Path, nil
+	}
+}
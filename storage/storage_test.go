@@ -0,0 +1,56 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package storage
+
+import (
+	"testing"
+)
+
+func TestRelPath(t *testing.T) {
+	cases := []struct {
+		name     string
+		base     string
+		file     string
+		expected string
+		hasErr   bool
+	}{
+		{name: "local", base: "/data/csv", file: "/data/csv/MARVIN/T1/MARVIN.T1.0.csv", expected: "MARVIN/T1/MARVIN.T1.0.csv"},
+		{name: "local relative", base: "./csv", file: "csv/MARVIN/T1/MARVIN.T1.0.csv", expected: "MARVIN/T1/MARVIN.T1.0.csv"},
+		{name: "local file scheme", base: "file:///data/csv", file: "/data/csv/MARVIN/manifest.json", expected: "MARVIN/manifest.json"},
+		{name: "local outside", base: "/data/csv", file: "/data/other/T1.0.csv", hasErr: true},
+		{name: "s3", base: "s3://bucket/prefix", file: "s3://bucket/prefix/MARVIN/T1/MARVIN.T1.0.csv", expected: "MARVIN/T1/MARVIN.T1.0.csv"},
+		{name: "s3 bucket root", base: "s3://bucket", file: "s3://bucket/MARVIN/T1.0.csv", expected: "MARVIN/T1.0.csv"},
+		{name: "s3 other bucket", base: "s3://bucket/prefix", file: "s3://other/prefix/T1.0.csv", hasErr: true},
+		{name: "s3 prefix sibling", base: "s3://bucket/prefix", file: "s3://bucket/prefix2/T1.0.csv", hasErr: true},
+		{name: "s3 vs local", base: "s3://bucket/prefix", file: "/prefix/T1.0.csv", hasErr: true},
+	}
+	for _, c := range cases {
+		rel, err := RelPath(c.base, c.file)
+		if c.hasErr {
+			if err == nil {
+				t.Errorf("%s: expected error, got %q", c.name, rel)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", c.name, err)
+			continue
+		}
+		if rel != c.expected {
+			t.Errorf("%s: expected %q, got %q", c.name, c.expected, rel)
+		}
+	}
+}
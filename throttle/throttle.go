@@ -0,0 +1,318 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package throttle
+
+import (
+	"context"
+	"fmt"
+	"github.com/wentaojin/transferdb/common"
+	"github.com/wentaojin/transferdb/config"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// 自适应采样间隔默认值，单位秒
+	DefaultSampleInterval = 30
+	// 自适应限速最低比例默认值
+	DefaultMinRatio = 0.1
+	// 源端负载超过阈值，限速比例减半；低于阈值，限速比例逐步恢复
+	adaptiveBackoffFactor = 0.5
+	adaptiveRecoverFactor = 1.25
+	// 时间窗口检查间隔
+	scheduleCheckInterval = time.Minute
+)
+
+// SampleFunc 源端负载采样，返回活跃会话数以及非空闲等待平均等待时间（毫秒）
+type SampleFunc func() (int, float64, error)
+
+// Throttler 源端抽取限速，包含全局 rows/bytes 限速以及表级别 rows/bytes 限速
+// 全局限速受时间窗口以及自适应比例影响，表级别限速受自适应比例影响
+type Throttler struct {
+	Cfg config.ThrottleConfig
+
+	mutex        sync.Mutex
+	rowsLimiter  *rate.Limiter
+	bytesLimiter *rate.Limiter
+	tables       map[string]*TableLimiter
+	// 当前生效时间窗口下标，-1 代表不在任何时间窗口
+	schedule int
+	ratio    float64
+}
+
+// TableLimiter 表级别限速，同一张表的所有 chunk 共享
+type TableLimiter struct {
+	throttler      *Throttler
+	rowsPerSecond  int64
+	bytesPerSecond int64
+	rowsLimiter    *rate.Limiter
+	bytesLimiter   *rate.Limiter
+}
+
+func NewThrottler(cfg config.ThrottleConfig) (*Throttler, error) {
+	if cfg.RowsPerSecond < 0 || cfg.BytesPerSecond < 0 || cfg.TableRowsPerSecond < 0 || cfg.TableBytesPerSecond < 0 {
+		return nil, fmt.Errorf("throttle config paramter rows-per-second/bytes-per-second can't be less than 0")
+	}
+	for _, s := range cfg.Schedule {
+		if _, err := parseClock(s.Start); err != nil {
+			return nil, err
+		}
+		if _, err := parseClock(s.End); err != nil {
+			return nil, err
+		}
+		if s.RowsPerSecond < 0 || s.BytesPerSecond < 0 {
+			return nil, fmt.Errorf("throttle schedule [%s-%s] paramter rows-per-second/bytes-per-second can't be less than 0", s.Start, s.End)
+		}
+	}
+	if cfg.EnableAdaptive {
+		if cfg.MaxActiveSessions <= 0 && cfg.MaxWaitTime <= 0 {
+			return nil, fmt.Errorf("throttle config paramter enable-adaptive is true, max-active-sessions or max-wait-time must be configured")
+		}
+		if cfg.SampleInterval <= 0 {
+			cfg.SampleInterval = DefaultSampleInterval
+		}
+		if cfg.MinRatio <= 0 || cfg.MinRatio > 1 {
+			cfg.MinRatio = DefaultMinRatio
+		}
+	}
+
+	t := &Throttler{
+		Cfg:          cfg,
+		rowsLimiter:  rate.NewLimiter(rate.Inf, 0),
+		bytesLimiter: rate.NewLimiter(rate.Inf, 0),
+		tables:       make(map[string]*TableLimiter),
+		schedule:     -1,
+		ratio:        1,
+	}
+	t.mutex.Lock()
+	t.schedule = t.currentSchedule(time.Now())
+	t.adjustLimit()
+	t.mutex.Unlock()
+
+	return t, nil
+}
+
+// IsEnable 是否配置限速，未配置限速无需启动后台任务
+func (t *Throttler) IsEnable() bool {
+	if t.Cfg.RowsPerSecond > 0 || t.Cfg.BytesPerSecond > 0 || t.Cfg.TableRowsPerSecond > 0 || t.Cfg.TableBytesPerSecond > 0 {
+		return true
+	}
+	if t.Cfg.EnableAdaptive || len(t.Cfg.Schedule) > 0 {
+		return true
+	}
+	return false
+}
+
+// Run 后台定时切换时间窗口以及自适应采样调整限速比例，ctx 取消后退出
+func (t *Throttler) Run(ctx context.Context, sample SampleFunc) {
+	if len(t.Cfg.Schedule) == 0 && !t.Cfg.EnableAdaptive {
+		return
+	}
+
+	scheduleTicker := time.NewTicker(scheduleCheckInterval)
+	defer scheduleTicker.Stop()
+
+	var sampleC <-chan time.Time
+	if t.Cfg.EnableAdaptive {
+		sampleTicker := time.NewTicker(time.Duration(t.Cfg.SampleInterval) * time.Second)
+		defer sampleTicker.Stop()
+		sampleC = sampleTicker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-scheduleTicker.C:
+			t.mutex.Lock()
+			schedule := t.currentSchedule(now)
+			if schedule != t.schedule {
+				t.schedule = schedule
+				t.adjustLimit()
+				zap.L().Info("source throttle schedule switch",
+					zap.Int("schedule", schedule),
+					zap.Float64("rows limit", float64(t.rowsLimiter.Limit())),
+					zap.Float64("bytes limit", float64(t.bytesLimiter.Limit())))
+			}
+			t.mutex.Unlock()
+		case <-sampleC:
+			activeSessions, waitTime, err := sample()
+			if err != nil {
+				// 采样失败不影响数据抽取，保持当前限速
+				zap.L().Warn("source throttle adaptive sample failed", zap.Error(err))
+				continue
+			}
+			t.adaptive(activeSessions, waitTime)
+		}
+	}
+}
+
+// Table 获取表级别限速，表级别 migrate-config rows-per-second/bytes-per-second 优先，未配置限速返回 nil
+func (t *Throttler) Table(tableName string, migrateCfg config.MigrateConfig) *TableLimiter {
+	if t == nil || (!t.IsEnable() && migrateCfg.RowsPerSecond <= 0 && migrateCfg.BytesPerSecond <= 0) {
+		return nil
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	tableName = common.StringUPPER(tableName)
+	if tl, ok := t.tables[tableName]; ok {
+		return tl
+	}
+
+	tl := &TableLimiter{
+		throttler:      t,
+		rowsPerSecond:  t.Cfg.TableRowsPerSecond,
+		bytesPerSecond: t.Cfg.TableBytesPerSecond,
+		rowsLimiter:    rate.NewLimiter(rate.Inf, 0),
+		bytesLimiter:   rate.NewLimiter(rate.Inf, 0),
+	}
+	if migrateCfg.RowsPerSecond > 0 {
+		tl.rowsPerSecond = migrateCfg.RowsPerSecond
+	}
+	if migrateCfg.BytesPerSecond > 0 {
+		tl.bytesPerSecond = migrateCfg.BytesPerSecond
+	}
+	setLimit(tl.rowsLimiter, tl.rowsPerSecond, t.ratio)
+	setLimit(tl.bytesLimiter, tl.bytesPerSecond, t.ratio)
+
+	t.tables[tableName] = tl
+	return tl
+}
+
+// Wait 抽取数据批次写入通道前等待，先表级别后全局，nil 代表不限速
+func (tl *TableLimiter) Wait(ctx context.Context, rows, bytes int) error {
+	if tl == nil {
+		return nil
+	}
+	if err := waitN(ctx, tl.rowsLimiter, rows); err != nil {
+		return err
+	}
+	if err := waitN(ctx, tl.bytesLimiter, bytes); err != nil {
+		return err
+	}
+	if err := waitN(ctx, tl.throttler.rowsLimiter, rows); err != nil {
+		return err
+	}
+	return waitN(ctx, tl.throttler.bytesLimiter, bytes)
+}
+
+func (t *Throttler) adaptive(activeSessions int, waitTime float64) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	ratio := t.ratio
+	if (t.Cfg.MaxActiveSessions > 0 && activeSessions > t.Cfg.MaxActiveSessions) ||
+		(t.Cfg.MaxWaitTime > 0 && waitTime > float64(t.Cfg.MaxWaitTime)) {
+		ratio = math.Max(ratio*adaptiveBackoffFactor, t.Cfg.MinRatio)
+	} else {
+		ratio = math.Min(ratio*adaptiveRecoverFactor, 1)
+	}
+	if ratio == t.ratio {
+		return
+	}
+
+	zap.L().Info("source throttle adaptive ratio adjust",
+		zap.Int("active sessions", activeSessions),
+		zap.Int("max active sessions", t.Cfg.MaxActiveSessions),
+		zap.Float64("wait time", waitTime),
+		zap.Int("max wait time", t.Cfg.MaxWaitTime),
+		zap.Float64("origin ratio", t.ratio),
+		zap.Float64("current ratio", ratio))
+
+	t.ratio = ratio
+	t.adjustLimit()
+}
+
+// adjustLimit 根据时间窗口以及自适应比例重新设置全局以及表级别限速，调用方需持有锁
+func (t *Throttler) adjustLimit() {
+	rowsPerSecond, bytesPerSecond := t.Cfg.RowsPerSecond, t.Cfg.BytesPerSecond
+	if t.schedule >= 0 {
+		rowsPerSecond = t.Cfg.Schedule[t.schedule].RowsPerSecond
+		bytesPerSecond = t.Cfg.Schedule[t.schedule].BytesPerSecond
+	}
+	setLimit(t.rowsLimiter, rowsPerSecond, t.ratio)
+	setLimit(t.bytesLimiter, bytesPerSecond, t.ratio)
+
+	for _, tl := range t.tables {
+		setLimit(tl.rowsLimiter, tl.rowsPerSecond, t.ratio)
+		setLimit(tl.bytesLimiter, tl.bytesPerSecond, t.ratio)
+	}
+}
+
+// currentSchedule 返回当前时间所在时间窗口下标，多个时间窗口重叠以第一个为准，支持跨天 22:00-06:00
+func (t *Throttler) currentSchedule(now time.Time) int {
+	minutes := now.Hour()*60 + now.Minute()
+	for i, s := range t.Cfg.Schedule {
+		start, _ := parseClock(s.Start)
+		end, _ := parseClock(s.End)
+		switch {
+		case start == end:
+			return i
+		case start < end && minutes >= start && minutes < end:
+			return i
+		case start > end && (minutes >= start || minutes < end):
+			return i
+		}
+	}
+	return -1
+}
+
+// setLimit limit 小于等于 0 代表不限速，burst 取每秒限速值，保证单个批次可拆分等待
+func setLimit(l *rate.Limiter, limit int64, ratio float64) {
+	if limit <= 0 {
+		l.SetLimit(rate.Inf)
+		return
+	}
+	r := math.Max(float64(limit)*ratio, 1)
+	l.SetBurst(int(r))
+	l.SetLimit(rate.Limit(r))
+}
+
+// waitN 单个批次超过 burst 时拆分等待，避免 WaitN 超过 burst 直接报错
+func waitN(ctx context.Context, l *rate.Limiter, n int) error {
+	for n > 0 {
+		if l.Limit() == rate.Inf {
+			return nil
+		}
+		burst := l.Burst()
+		if burst <= 0 {
+			burst = 1
+		}
+		m := n
+		if m > burst {
+			m = burst
+		}
+		if err := l.WaitN(ctx, m); err != nil {
+			return err
+		}
+		n -= m
+	}
+	return nil
+}
+
+// parseClock 解析 HH:MM 格式时间，返回当天分钟数
+func parseClock(clock string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(clock))
+	if err != nil {
+		return 0, fmt.Errorf("throttle schedule time [%s] format isn't HH:MM, parse failed: %v", clock, err)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
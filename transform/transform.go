@@ -0,0 +1,358 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package transform
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"github.com/wentaojin/transferdb/common"
+	"github.com/wentaojin/transferdb/database/meta"
+	"go.uber.org/zap"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Transformer schema 字段转换（脱敏）规则，规则来源于元数据表 [column_transform_rule]
+// 转换基于源端字符集转换为 UTF8 之后的原始值，full、csv 以及增量保持一致
+type Transformer struct {
+	tables map[string]*TableTransformer
+}
+
+// TableTransformer 表级别字段转换规则，nil 代表表不存在转换规则
+type TableTransformer struct {
+	columns map[string]*columnRule
+}
+
+type columnRule struct {
+	ruleType  string
+	ruleValue string
+	maxLength int
+	// MASK 规则参数
+	keepPrefix int
+	keepSuffix int
+	maskChar   string
+	// EXPR 规则模板
+	segments []segment
+}
+
+// segment EXPR 模板片段，placeholder 为空代表普通文本
+type segment struct {
+	text        string
+	placeholder string
+	n           int
+}
+
+func NewTransformer(ctx context.Context, metaDB *meta.Meta, dbTypeS, dbTypeT, schemaNameS string) (*Transformer, error) {
+	rules, err := meta.NewColumnTransformRuleModel(metaDB).DetailColumnTransformRuleBySchema(ctx, &meta.ColumnTransformRule{
+		DBTypeS:     dbTypeS,
+		DBTypeT:     dbTypeT,
+		SchemaNameS: schemaNameS,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := NewTransformerByRules(rules)
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) > 0 {
+		zap.L().Info("column transform rule load finished",
+			zap.String("schema", schemaNameS),
+			zap.Int("tables", len(t.tables)),
+			zap.Int("rules", len(rules)))
+	}
+	return t, nil
+}
+
+// NewTransformerByRules 基于字段转换规则初始化，不依赖元数据库
+func NewTransformerByRules(rules []meta.ColumnTransformRule) (*Transformer, error) {
+	t := &Transformer{tables: make(map[string]*TableTransformer)}
+	for _, r := range rules {
+		rule, err := newColumnRule(r)
+		if err != nil {
+			return nil, err
+		}
+		tableName := common.StringUPPER(r.TableNameS)
+		if _, ok := t.tables[tableName]; !ok {
+			t.tables[tableName] = &TableTransformer{columns: make(map[string]*columnRule)}
+		}
+		t.tables[tableName].columns[common.StringUPPER(r.ColumnNameS)] = rule
+	}
+	return t, nil
+}
+
+// Table 获取表字段转换规则，表不存在转换规则返回 nil
+func (t *Transformer) Table(tableName string) *TableTransformer {
+	if t == nil {
+		return nil
+	}
+	if tt, ok := t.tables[common.StringUPPER(tableName)]; ok {
+		return tt
+	}
+	return nil
+}
+
+// IsTransform 字段是否存在转换规则
+func (tt *TableTransformer) IsTransform(columnName string) bool {
+	if tt == nil {
+		return false
+	}
+	_, ok := tt.columns[trimColumnName(columnName)]
+	return ok
+}
+
+// Transform 字段值转换，返回转换后的值以及是否为 NULL，NULL 值保持 NULL 不转换
+func (tt *TableTransformer) Transform(columnName, value string, isNull bool) (string, bool) {
+	if tt == nil || isNull {
+		return value, isNull
+	}
+	rule, ok := tt.columns[trimColumnName(columnName)]
+	if !ok {
+		return value, isNull
+	}
+
+	var result string
+	switch rule.ruleType {
+	case common.TransformRuleNullify:
+		return "", true
+	case common.TransformRuleHash:
+		result = hashValue(rule.ruleValue, value)
+	case common.TransformRuleMask:
+		result = maskValue(value, rule.keepPrefix, rule.keepSuffix, rule.maskChar)
+	case common.TransformRuleFixed:
+		result = rule.ruleValue
+	case common.TransformRuleFPE:
+		result = fpeValue(rule.ruleValue, value)
+	case common.TransformRuleExpr:
+		result = exprValue(rule.segments, value)
+	default:
+		return value, isNull
+	}
+
+	if rule.maxLength > 0 && utf8.RuneCountInString(result) > rule.maxLength {
+		result = string([]rune(result)[:rule.maxLength])
+	}
+	return result, false
+}
+
+func newColumnRule(r meta.ColumnTransformRule) (*columnRule, error) {
+	rule := &columnRule{
+		ruleType:  common.StringUPPER(strings.TrimSpace(r.RuleType)),
+		ruleValue: r.RuleValue,
+		maxLength: r.MaxLength,
+	}
+	switch rule.ruleType {
+	case common.TransformRuleHash, common.TransformRuleFixed, common.TransformRuleFPE, common.TransformRuleNullify:
+	case common.TransformRuleMask:
+		// 格式: 保留前缀字符数,保留后缀字符数[,掩码字符]，例如 3,4,*
+		rule.maskChar = "*"
+		params := strings.Split(r.RuleValue, ",")
+		if len(params) < 2 || len(params) > 3 {
+			return nil, fmt.Errorf("column transform rule [%s.%s.%s] rule_type [%s] rule_value [%s] format isn't keepPrefix,keepSuffix[,maskChar]",
+				r.SchemaNameS, r.TableNameS, r.ColumnNameS, r.RuleType, r.RuleValue)
+		}
+		prefix, err := strconv.Atoi(strings.TrimSpace(params[0]))
+		if err != nil || prefix < 0 {
+			return nil, fmt.Errorf("column transform rule [%s.%s.%s] rule_value [%s] keepPrefix isn't non-negative integer",
+				r.SchemaNameS, r.TableNameS, r.ColumnNameS, r.RuleValue)
+		}
+		suffix, err := strconv.Atoi(strings.TrimSpace(params[1]))
+		if err != nil || suffix < 0 {
+			return nil, fmt.Errorf("column transform rule [%s.%s.%s] rule_value [%s] keepSuffix isn't non-negative integer",
+				r.SchemaNameS, r.TableNameS, r.ColumnNameS, r.RuleValue)
+		}
+		rule.keepPrefix, rule.keepSuffix = prefix, suffix
+		if len(params) == 3 && !strings.EqualFold(params[2], "") {
+			rule.maskChar = params[2]
+		}
+	case common.TransformRuleExpr:
+		segments, err := parseExpr(r.RuleValue)
+		if err != nil {
+			return nil, fmt.Errorf("column transform rule [%s.%s.%s] rule_value [%s] parse failed: %v",
+				r.SchemaNameS, r.TableNameS, r.ColumnNameS, r.RuleValue, err)
+		}
+		rule.segments = segments
+	default:
+		return nil, fmt.Errorf("column transform rule [%s.%s.%s] rule_type [%s] isn't support, support rule_type [%s %s %s %s %s %s]",
+			r.SchemaNameS, r.TableNameS, r.ColumnNameS, r.RuleType,
+			common.TransformRuleHash, common.TransformRuleMask, common.TransformRuleFixed,
+			common.TransformRuleFPE, common.TransformRuleNullify, common.TransformRuleExpr)
+	}
+	return rule, nil
+}
+
+// parseExpr 解析 EXPR 模板，支持占位符:
+// {value} 原值、{upper} 大写、{lower} 小写、{len} 字符长度、{hash} sha256、{hash:N} sha256 前 N 位、
+// {fpe} 保留格式替换、{left:N} 前 N 个字符、{right:N} 后 N 个字符
+func parseExpr(expr string) ([]segment, error) {
+	var segments []segment
+	for len(expr) > 0 {
+		start := strings.Index(expr, "{")
+		if start < 0 {
+			segments = append(segments, segment{text: expr})
+			break
+		}
+		end := strings.Index(expr[start:], "}")
+		if end < 0 {
+			return nil, fmt.Errorf("placeholder [%s] isn't closed", expr[start:])
+		}
+		end += start
+		if start > 0 {
+			segments = append(segments, segment{text: expr[:start]})
+		}
+
+		name, param, hasParam := strings.Cut(expr[start+1:end], ":")
+		name = strings.ToLower(strings.TrimSpace(name))
+		seg := segment{placeholder: name}
+		switch name {
+		case "value", "upper", "lower", "len", "fpe":
+			if hasParam {
+				return nil, fmt.Errorf("placeholder [%s] doesn't support parameter", expr[start:end+1])
+			}
+		case "hash", "left", "right":
+			if hasParam {
+				n, err := strconv.Atoi(strings.TrimSpace(param))
+				if err != nil || n <= 0 {
+					return nil, fmt.Errorf("placeholder [%s] parameter isn't positive integer", expr[start:end+1])
+				}
+				seg.n = n
+			} else if !strings.EqualFold(name, "hash") {
+				return nil, fmt.Errorf("placeholder [%s] need parameter, for example {%s:4}", expr[start:end+1], name)
+			}
+		default:
+			return nil, fmt.Errorf("placeholder [%s] isn't support", expr[start:end+1])
+		}
+		segments = append(segments, seg)
+		expr = expr[end+1:]
+	}
+	return segments, nil
+}
+
+func exprValue(segments []segment, value string) string {
+	var b strings.Builder
+	runes := []rune(value)
+	for _, s := range segments {
+		switch s.placeholder {
+		case "":
+			b.WriteString(s.text)
+		case "value":
+			b.WriteString(value)
+		case "upper":
+			b.WriteString(strings.ToUpper(value))
+		case "lower":
+			b.WriteString(strings.ToLower(value))
+		case "len":
+			b.WriteString(strconv.Itoa(len(runes)))
+		case "fpe":
+			b.WriteString(fpeValue("", value))
+		case "hash":
+			h := hashValue("", value)
+			if s.n > 0 && s.n < len(h) {
+				h = h[:s.n]
+			}
+			b.WriteString(h)
+		case "left":
+			if s.n < len(runes) {
+				b.WriteString(string(runes[:s.n]))
+			} else {
+				b.WriteString(value)
+			}
+		case "right":
+			if s.n < len(runes) {
+				b.WriteString(string(runes[len(runes)-s.n:]))
+			} else {
+				b.WriteString(value)
+			}
+		}
+	}
+	return b.String()
+}
+
+func hashValue(salt, value string) string {
+	h := sha256.Sum256([]byte(common.StringsBuilder(salt, value)))
+	return hex.EncodeToString(h[:])
+}
+
+// maskValue 按字符保留前后缀，字符数不足时全部掩码
+func maskValue(value string, keepPrefix, keepSuffix int, maskChar string) string {
+	runes := []rune(value)
+	if keepPrefix+keepSuffix >= len(runes) {
+		return strings.Repeat(maskChar, len(runes))
+	}
+	return common.StringsBuilder(string(runes[:keepPrefix]),
+		strings.Repeat(maskChar, len(runes)-keepPrefix-keepSuffix),
+		string(runes[len(runes)-keepSuffix:]))
+}
+
+// fpeValue 基于 HMAC-SHA256(盐值, 原值) 确定性替换，数字替换为数字（首位非 0 保持非 0）、字母替换为同大小写字母，其他字符不变
+func fpeValue(salt, value string) string {
+	var (
+		b       strings.Builder
+		stream  []byte
+		counter uint32
+		idx     int
+	)
+	nextByte := func() byte {
+		if idx >= len(stream) {
+			mac := hmac.New(sha256.New, []byte(salt))
+			mac.Write([]byte(value))
+			var c [4]byte
+			binary.BigEndian.PutUint32(c[:], counter)
+			mac.Write(c[:])
+			stream = mac.Sum(nil)
+			counter++
+			idx = 0
+		}
+		v := stream[idx]
+		idx++
+		return v
+	}
+
+	firstDigit := true
+	for _, r := range value {
+		switch {
+		case r >= '0' && r <= '9':
+			if firstDigit && r != '0' {
+				b.WriteRune(rune('1' + nextByte()%9))
+			} else {
+				b.WriteRune(rune('0' + nextByte()%10))
+			}
+			firstDigit = false
+		case r >= 'a' && r <= 'z':
+			b.WriteRune(rune('a' + nextByte()%26))
+		case r >= 'A' && r <= 'Z':
+			b.WriteRune(rune('A' + nextByte()%26))
+		default:
+			// 小数点等分隔符之后数字按非首位处理
+			if r != '-' && r != '+' {
+				firstDigit = false
+			}
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// trimColumnName 字段名去除反引号以及双引号，统一大写匹配
+func trimColumnName(columnName string) string {
+	return common.StringUPPER(strings.Trim(columnName, "`\""))
+}